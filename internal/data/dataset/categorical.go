@@ -0,0 +1,94 @@
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/wraperr"
+	"sort"
+)
+
+// SetCategorical marks given input columns as categorical. Values of categorical columns must be integer codes of
+// categories in [0; categories count), e.g. to be mapped to dense vectors by embedding layer. Columns are stored in
+// ascending order, no columns unmarks all the columns.
+//
+// Throws ErrCreate error.
+func (d *Data) SetCategorical(columns ...int) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if d == nil || d.X == nil {
+		return fmt.Errorf("no data provided: %v", d)
+	}
+
+	sorted := append([]int(nil), columns...)
+	sort.Ints(sorted)
+	for i, col := range sorted {
+		if col < 0 || col >= d.X.Cols() {
+			return fmt.Errorf("categorical column must be in [0; %d): %d", d.X.Cols(), col)
+		} else if i > 0 && sorted[i-1] == col {
+			return fmt.Errorf("categorical column is duplicated: %d", col)
+		}
+		for row := 0; row < d.X.Rows(); row++ {
+			value, err := d.X.Get(row, col)
+			if err != nil {
+				return err
+			} else if value < 0 || value != math.Trunc(value) {
+				return fmt.Errorf("value of categorical column %d is not a category code: %v", col, value)
+			}
+		}
+	}
+
+	if len(sorted) == 0 {
+		sorted = nil
+	}
+	logger.Debugf("mark columns %v as categorical", sorted)
+	d.Categorical = sorted
+	return nil
+}
+
+// Categories return categories count of each categorical column (max code + 1) in order of Data.Categorical.
+//
+// Throws ErrCategories error.
+func (d *Data) Categories() (counts []int, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCategories, &err)
+
+	if d == nil || d.X == nil {
+		return nil, fmt.Errorf("no data provided: %v", d)
+	}
+
+	counts = make([]int, len(d.Categorical))
+	for i, col := range d.Categorical {
+		values, err := d.X.GetCol(col)
+		if err != nil {
+			return nil, fmt.Errorf("error getting categorical column %d: %w", col, err)
+		}
+		for _, value := range values.Raw() {
+			if value < 0 || value != math.Trunc(value) {
+				return nil, fmt.Errorf("value of categorical column %d is not a category code: %v", col, value)
+			} else if int(value) >= counts[i] {
+				counts[i] = int(value) + 1
+			}
+		}
+	}
+	return counts, nil
+}
+
+// Categories return categories count of each categorical column over train, tests and valid Data.
+//
+// Throws ErrCategories error.
+func (d *Dataset) Categories() ([]int, error) {
+	return d.Combine().Categories()
+}
+
+func equalColumns(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,377 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CSVParameters describe layout of CSV file:
+//     * Delimiter separates fields, ',' is used by default;
+//     * Header tells first record holds column names;
+//     * Inputs and Outputs select columns by header names or 0-based indices, names take precedence over indices.
+//       Outputs are required on reading, no Inputs selects all the columns except Outputs. On writing they are used
+//       as header names, x0, x1, ... and y0, y1, ... are used by default;
+//     * Weights selects column of rows' weights (see Data.SetWeights) by header name or index on reading, no Weights
+//       reads no weights. On writing it is used as header name of weights of weighted Data, w is used by default;
+//     * SkipMalformed tells to skip records with broken quoting, wrong fields count, non-numeric values or negative
+//       weights on reading instead of returning error.
+type CSVParameters struct {
+	Delimiter     rune
+	Header        bool
+	Inputs        []string
+	Outputs       []string
+	Weights       string
+	SkipMalformed bool
+}
+
+func (p *CSVParameters) delimiter() rune {
+	if p.Delimiter == 0 {
+		return ','
+	}
+	return p.Delimiter
+}
+
+// columns return indices of given columns: names are looked up in header, other columns must be indices in
+// [0; count).
+func columns(selected []string, header []string, count int) ([]int, error) {
+	indices := make([]int, len(selected))
+	used := make(map[int]bool, len(selected))
+	for i, column := range selected {
+		indices[i] = -1
+		for j, name := range header {
+			if name == column {
+				indices[i] = j
+				break
+			}
+		}
+		if indices[i] < 0 {
+			index, err := strconv.Atoi(column)
+			if err != nil {
+				return nil, fmt.Errorf("unknown column: %q", column)
+			} else if index < 0 || index >= count {
+				return nil, fmt.Errorf("column index must be in [0; %d): %d", count, index)
+			}
+			indices[i] = index
+		}
+		if used[indices[i]] {
+			return nil, fmt.Errorf("column is selected twice: %q", column)
+		}
+		used[indices[i]] = true
+	}
+	return indices, nil
+}
+
+// csvRecords reads rows of selected input and output columns from CSV records one by one.
+type csvRecords struct {
+	reader   *csv.Reader
+	p        *CSVParameters
+	count    int   // fields count of each record
+	inputs   int   // count of input columns in the beginning of selected
+	outputs  int   // count of output columns following input columns in selected
+	selected []int // indices of input columns followed by indices of output columns and weights column if any
+	first    []string
+}
+
+// newCSVRecords reads the first record of r to resolve selected columns, see CSVParameters.
+func newCSVRecords(r io.Reader, p *CSVParameters) (*csvRecords, error) {
+	if r == nil {
+		return nil, fmt.Errorf("no reader provided: %v", r)
+	} else if p == nil {
+		return nil, fmt.Errorf("no csv parameters provided: %v", p)
+	} else if len(p.Outputs) == 0 {
+		return nil, fmt.Errorf("no output columns provided: %v", p.Outputs)
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = p.delimiter()
+	reader.FieldsPerRecord = -1 // fields count is checked by next to skip malformed records
+	reader.TrimLeadingSpace = true
+
+	record, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("no records in csv")
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading csv: %w", err)
+	}
+	count := len(record)
+	var header []string
+	if p.Header {
+		header = record
+	}
+
+	outputs, err := columns(p.Outputs, header, count)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting output columns: %w", err)
+	}
+	var weights []int
+	if p.Weights != "" {
+		if weights, err = columns([]string{p.Weights}, header, count); err != nil {
+			return nil, fmt.Errorf("error selecting weights column: %w", err)
+		} else if contains(outputs, weights[0]) {
+			return nil, fmt.Errorf("column %d is selected as output and weights", weights[0])
+		}
+	}
+	var inputs []int
+	if len(p.Inputs) > 0 {
+		if inputs, err = columns(p.Inputs, header, count); err != nil {
+			return nil, fmt.Errorf("error selecting input columns: %w", err)
+		}
+	} else {
+		for i := 0; i < count; i++ {
+			if !contains(outputs, i) && !contains(weights, i) {
+				inputs = append(inputs, i)
+			}
+		}
+	}
+	for _, input := range inputs {
+		if contains(outputs, input) {
+			return nil, fmt.Errorf("column %d is selected as input and output", input)
+		} else if contains(weights, input) {
+			return nil, fmt.Errorf("column %d is selected as input and weights", input)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no input columns selected")
+	}
+	logger.Debugf("read csv: inputs %v, outputs %v, weights %v", inputs, outputs, weights)
+
+	records := &csvRecords{
+		reader:   reader,
+		p:        p,
+		count:    count,
+		inputs:   len(inputs),
+		outputs:  len(outputs),
+		selected: append(append(inputs, outputs...), weights...),
+	}
+	if !p.Header {
+		records.first = record // the first record is data
+	}
+	return records, nil
+}
+
+// weighted tells records have weights column
+func (r *csvRecords) weighted() bool {
+	return len(r.selected) > r.inputs+r.outputs
+}
+
+// next return inputs, outputs and weight (1 if there is no weights column) of the next well-formed record, malformed
+// records are skipped or reported depending on CSVParameters.SkipMalformed. It returns io.EOF after the last record.
+func (r *csvRecords) next() (x []float64, y []float64, w float64, err error) {
+	for {
+		record := r.first
+		if record != nil {
+			r.first = nil
+		} else if record, err = r.reader.Read(); err == io.EOF {
+			return nil, nil, 0, err
+		} else if parseErr := (*csv.ParseError)(nil); errors.As(err, &parseErr) && r.p.SkipMalformed {
+			logger.Warnf("skip malformed csv record on line %d: %v", parseErr.Line, err)
+			continue
+		} else if err != nil {
+			return nil, nil, 0, fmt.Errorf("error reading csv: %w", err)
+		}
+
+		line, _ := r.reader.FieldPos(0)
+		row, err := r.parse(record)
+		if err != nil && r.p.SkipMalformed {
+			logger.Warnf("skip malformed csv record on line %d: %v", line, err)
+			continue
+		} else if err != nil {
+			return nil, nil, 0, fmt.Errorf("malformed csv record on line %d: %w", line, err)
+		}
+		w = 1
+		if r.weighted() {
+			w = row[len(row)-1]
+		}
+		return row[:r.inputs], row[r.inputs : r.inputs+r.outputs], w, nil
+	}
+}
+
+func (r *csvRecords) parse(record []string) ([]float64, error) {
+	if len(record) != r.count {
+		return nil, fmt.Errorf("wrong fields count: %d != %d", len(record), r.count)
+	}
+	row := make([]float64, len(r.selected))
+	for i, index := range r.selected {
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[index]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing column %d: %w", index, err)
+		}
+		row[i] = value
+	}
+	if r.weighted() {
+		if w := row[len(row)-1]; w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("weight is not non-negative finite: %v", w)
+		}
+	}
+	return row, nil
+}
+
+// ReadCSV creates Data from CSV records read from r, see CSVParameters. Each record is one row of inputs and outputs
+// and, if weights column is selected, its weight.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     ReadCSV(`a,b,y   , &CSVParameters{Header: true, Outputs: []string{"y"}}) = {X: | 1 2 |, Y: | 3 |}
+//              1,2,3                                                                 | 4 5 |     | 6 |
+//              4,5,6`
+func ReadCSV(r io.Reader, p *CSVParameters) (data *Data, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	records, err := newCSVRecords(r, p)
+	if err != nil {
+		return nil, err
+	}
+	var x, y [][]float64
+	var w []float64
+	for {
+		rowX, rowY, rowW, err := records.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		x, y = append(x, rowX), append(y, rowY)
+		if records.weighted() {
+			w = append(w, rowW)
+		}
+	}
+	if len(x) == 0 {
+		return nil, fmt.Errorf("no data records in csv")
+	}
+	return newDataRaw(x, y, w)
+}
+
+// ReadCSVFile creates Data from CSV file by given path, see ReadCSV.
+//
+// Throws ErrCreate error.
+func ReadCSVFile(path string, p *CSVParameters) (data *Data, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, err)
+	}
+	defer func() { _ = file.Close() }()
+	return ReadCSV(file, p)
+}
+
+// WriteCSV writes Data to w as CSV records of inputs followed by outputs, weights if Data is weighted and, if provided,
+// predictions of network (prediction columns are named as outputs with "_pred" suffix). Predictions must match outputs
+// shape.
+//
+// Throws ErrExport error.
+//
+// Example:
+//     {X: | 1 2 |, Y: | 3 |}.WriteCSV(w, | 2.5 |, &CSVParameters{Header: true}) writes `x0,x1,y0,y0_pred
+//         | 4 5 |     | 6 |              | 6.5 |                                       1,2,3,2.5
+//                                                                                       4,5,6,6.5`
+func (d *Data) WriteCSV(w io.Writer, predictions *matrix.Matrix, p *CSVParameters) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExport, &err)
+
+	if d == nil || d.X == nil || d.Y == nil {
+		return fmt.Errorf("no data provided: %v", d)
+	} else if w == nil {
+		return fmt.Errorf("no writer provided: %v", w)
+	} else if predictions != nil {
+		if err = d.Y.CheckEqualShape(predictions); err != nil {
+			return fmt.Errorf("predictions don't match outputs: %w", err)
+		}
+	}
+	if p == nil {
+		p = &CSVParameters{}
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = p.delimiter()
+	if p.Header {
+		inputs, err := names(p.Inputs, "x", d.X.Cols())
+		if err != nil {
+			return err
+		}
+		outputs, err := names(p.Outputs, "y", d.Y.Cols())
+		if err != nil {
+			return err
+		}
+		header := append(inputs, outputs...)
+		if d.W != nil {
+			if p.Weights != "" {
+				header = append(header, p.Weights)
+			} else {
+				header = append(header, "w")
+			}
+		}
+		if predictions != nil {
+			for _, name := range outputs {
+				header = append(header, name+"_pred")
+			}
+		}
+		if err = writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	x, y, weights, pred := d.X.Raw(), d.Y.Raw(), []float64(nil), [][]float64(nil)
+	if d.W != nil {
+		weights = d.W.Raw()
+	}
+	if predictions != nil {
+		pred = predictions.Raw()
+	}
+	record := make([]string, 0, d.X.Cols()+2*d.Y.Cols()+1)
+	for i := range x {
+		record = record[:0]
+		for _, row := range [][]float64{x[i], y[i]} {
+			record = appendFormatted(record, row)
+		}
+		if weights != nil {
+			record = appendFormatted(record, weights[i:i+1])
+		}
+		if pred != nil {
+			record = appendFormatted(record, pred[i])
+		}
+		if err = writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// names return given column names or generated ones with given prefix if no names provided.
+func names(given []string, prefix string, count int) ([]string, error) {
+	if len(given) == 0 {
+		res := make([]string, count)
+		for i := range res {
+			res[i] = prefix + strconv.Itoa(i)
+		}
+		return res, nil
+	} else if len(given) != count {
+		return nil, fmt.Errorf("wrong column names count: %d != %d", len(given), count)
+	}
+	return append([]string(nil), given...), nil
+}
+
+func appendFormatted(record []string, values []float64) []string {
+	for _, value := range values {
+		record = append(record, strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	return record
+}
+
+func contains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package dataset
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	const table = "a,b,c,y\n1,2,3,4\n5,6,7,8\n"
+	tests := []struct {
+		testutils.Base
+		in         string
+		parameters *CSVParameters
+		expected   DataParameters
+	}{
+		{
+			Base:       testutils.Base{Name: "header, output by name"},
+			in:         table,
+			parameters: &CSVParameters{Header: true, Outputs: []string{"y"}},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1, 2, 3, 5, 6, 7}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{4, 8}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "selected columns by names and indices"},
+			in:         table,
+			parameters: &CSVParameters{Header: true, Inputs: []string{"c", "0"}, Outputs: []string{"y", "b"}},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{3, 1, 7, 5}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{4, 2, 8, 6}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "no header, delimiter"},
+			in:         "1; 2;3\n4;5; 6\n",
+			parameters: &CSVParameters{Delimiter: ';', Outputs: []string{"0"}},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{2, 3, 5, 6}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{1, 4}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "skip malformed"},
+			in:         "a,y\n1,2\n3\nx,4\n5,6,7\n8,9\n",
+			parameters: &CSVParameters{Header: true, Outputs: []string{"y"}, SkipMalformed: true},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{1, 8}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{2, 9}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "skip broken quoting"},
+			in:         "a,y\n1,2\n3,4\"x\n5,6\n7,\"8\n",
+			parameters: &CSVParameters{Header: true, Outputs: []string{"y"}, SkipMalformed: true},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{1, 5}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{2, 6}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "broken quoting, error", Err: ErrCreate},
+			in:         "a,y\n1,2\n3,4\"x\n",
+			parameters: &CSVParameters{Header: true, Outputs: []string{"y"}},
+		},
+		{
+			Base:       testutils.Base{Name: "malformed, error", Err: ErrCreate},
+			in:         "a,y\n1,2\nx,4\n",
+			parameters: &CSVParameters{Header: true, Outputs: []string{"y"}},
+		},
+		{
+			Base:       testutils.Base{Name: "unknown column, error", Err: ErrCreate},
+			in:         table,
+			parameters: &CSVParameters{Header: true, Outputs: []string{"z"}},
+		},
+		{
+			Base:       testutils.Base{Name: "column index out of range, error", Err: ErrCreate},
+			in:         table,
+			parameters: &CSVParameters{Outputs: []string{"4"}},
+		},
+		{
+			Base:       testutils.Base{Name: "input is output, error", Err: ErrCreate},
+			in:         table,
+			parameters: &CSVParameters{Header: true, Inputs: []string{"a", "y"}, Outputs: []string{"y"}},
+		},
+		{
+			Base:       testutils.Base{Name: "no outputs, error", Err: ErrCreate},
+			in:         table,
+			parameters: &CSVParameters{Header: true},
+		},
+		{
+			Base:       testutils.Base{Name: "only header, error", Err: ErrCreate},
+			in:         "a,y\n",
+			parameters: &CSVParameters{Header: true, Outputs: []string{"y"}},
+		},
+		{
+			Base:       testutils.Base{Name: "empty, error", Err: ErrCreate},
+			parameters: &CSVParameters{Outputs: []string{"0"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			data, err := ReadCSV(strings.NewReader(tc.in), tc.parameters)
+			if tc.Err != nil {
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, newData(t, tc.expected).Equal(data), data.String())
+		})
+	}
+}
+
+func TestData_WriteCSV(t *testing.T) {
+	data := newData(t, DataParameters{
+		X: testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{1, 2.5, 4, 5}},
+		Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{3, 6}},
+	})
+	predictions := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{2.5, 6.5}})
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, data.WriteCSV(buf, nil, &CSVParameters{Header: true}))
+	require.Equal(t, "x0,x1,y0\n1,2.5,3\n4,5,6\n", buf.String())
+
+	buf.Reset()
+	parameters := &CSVParameters{Delimiter: '\t', Header: true, Inputs: []string{"a", "b"}, Outputs: []string{"y"}}
+	require.NoError(t, data.WriteCSV(buf, predictions, parameters))
+	require.Equal(t, "a\tb\ty\ty_pred\n1\t2.5\t3\t2.5\n4\t5\t6\t6.5\n", buf.String())
+
+	// written data is read back
+	path := filepath.Join(t.TempDir(), "data.csv")
+	buf.Reset()
+	require.NoError(t, data.WriteCSV(buf, nil, parameters))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	read, err := ReadCSVFile(path, parameters)
+	require.NoError(t, err)
+	require.True(t, data.Equal(read))
+	_, err = ReadCSVFile(filepath.Join(t.TempDir(), "missing.csv"), parameters)
+	require.ErrorIs(t, err, ErrCreate)
+
+	require.ErrorIs(t, data.WriteCSV(buf, data.X, nil), ErrExport)
+	require.ErrorIs(t, data.WriteCSV(buf, nil, &CSVParameters{Header: true, Outputs: []string{"y", "z"}}), ErrExport)
+	require.ErrorIs(t, (*Data)(nil).WriteCSV(buf, nil, nil), ErrExport)
+}
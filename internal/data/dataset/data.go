@@ -6,13 +6,16 @@ import (
 	"math/rand"
 	"nn/internal/utils"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
 	"nn/pkg/wraperr"
 )
 
 // Data holds inputs and corresponding outputs
 type Data struct {
-	X *matrix.Matrix
-	Y *matrix.Matrix
+	X           *matrix.Matrix
+	Y           *matrix.Matrix
+	W           *vector.Vector // optional per-sample weights of rows, nil if all the rows weigh the same (see SetWeights)
+	Categorical []int          // ascending indices of inputs' categorical columns (see SetCategorical)
 }
 
 // NewData checks given Matrix and creates Data. Inputs and outputs must have same rows count.
@@ -43,16 +46,33 @@ func (d *Data) Copy() *Data {
 	if err != nil {
 		panic(err)
 	}
+	if d.W != nil {
+		data.W = d.W.Copy()
+	}
 
-	return data
+	return d.withCategorical(data)
 }
 
 func (d *Data) toMap(stringer func(spStringer utils.SPStringer) string) map[string]string {
-	return map[string]string{
+	res := map[string]string{
 		"x": stringer(d.X),
 		"y": stringer(d.Y),
 	}
+	if d.W != nil {
+		res["w"] = stringer(d.W)
+	}
+	if len(d.Categorical) > 0 {
+		res["categorical"] = fmt.Sprintf("%v", d.Categorical)
+	}
+	return res
+}
 
+// withCategorical marks the same categorical columns in <data> as in Data and return <data>.
+func (d *Data) withCategorical(data *Data) *Data {
+	if len(d.Categorical) > 0 {
+		data.Categorical = append([]int(nil), d.Categorical...)
+	}
+	return data
 }
 
 func (d *Data) String() string {
@@ -76,7 +96,8 @@ func (d *Data) ShortString() string {
 	return utils.FormatObject(d.toMap(utils.ShortString), utils.ShortFormat)
 }
 
-// Merge merges Data with another Data. Another Data entries will be written to the end of this Data.
+// Merge merges Data with another Data. Another Data entries will be written to the end of this Data. If only one Data
+// has weights, rows of another one weigh 1.
 func (d *Data) Merge(another *Data) (res *Data, err error) {
 	defer logger.CatchErr(&err)
 	defer wraperr.WrapError(ErrMerge, &err)
@@ -85,6 +106,8 @@ func (d *Data) Merge(another *Data) (res *Data, err error) {
 		return nil, fmt.Errorf("call Merge on nil data")
 	} else if another == nil {
 		return d, nil
+	} else if !equalColumns(d.Categorical, another.Categorical) {
+		return nil, fmt.Errorf("categorical columns mismatches: %v != %v", d.Categorical, another.Categorical)
 	}
 
 	X, err := d.X.VStack([]*matrix.Matrix{another.X})
@@ -97,26 +120,38 @@ func (d *Data) Merge(another *Data) (res *Data, err error) {
 		return nil, err
 	}
 
-	return NewData(X, Y)
+	if res, err = NewData(X, Y); err != nil {
+		return nil, err
+	}
+	if d.W != nil || another.W != nil {
+		if res.W, err = d.weights().Concatenate(another.weights()); err != nil {
+			return nil, err
+		}
+	}
+	return d.withCategorical(res), nil
 }
 
-// Shuffle mixes data. Shuffle is row-based. Inputs and outputs reordering using the same random permutation.
-// Shuffle creates new Data, source Data stay untouched.
+// Shuffle mixes data. Shuffle is row-based. Inputs, outputs and weights reordering using the same random permutation.
+// Shuffle creates new Data, source Data stay untouched. Permutation is drawn from utils.Rand, see ShuffleRand.
 //
 // Example:
 //     {X: | 1 |, Y: | 4 |}.Shuffle() = {X: | 3 |, Y: | 6 |}, [2 0 1]
 //         | 2 |     | 5 |                  | 1 |     | 4 |
 //         | 3 |     | 6 |                  | 2 |     | 5 |
 func (d *Data) Shuffle() (data *Data, perm []int) {
-	logger.Tracef("shuffle data: %s", d.ShortString())
-	perm = rand.Perm(d.X.Rows())
-	logger.Tracef("permutation: %v", perm)
+	return d.ShuffleRand(utils.Rand)
+}
 
+// ShuffleRand mixes data as Shuffle does drawing permutation from given random generator, so shuffling with seeded
+// generator is reproducible. Generator must not be nil.
+func (d *Data) ShuffleRand(rng *rand.Rand) (data *Data, perm []int) {
+	logger.Tracef("shuffle data: %s", d.ShortString())
 	var err error
 	var xOrdered, yOrdered *matrix.Matrix
-	if xOrdered, err = d.X.Order(perm); err != nil {
+	if xOrdered, perm, err = matrix.RandPermutation(rng, d.X); err != nil {
 		panic(err)
 	}
+	logger.Tracef("permutation: %v", perm)
 	if yOrdered, err = d.Y.Order(perm); err != nil {
 		panic(err)
 	}
@@ -124,11 +159,44 @@ func (d *Data) Shuffle() (data *Data, perm []int) {
 	if data, err = NewData(xOrdered, yOrdered); err != nil {
 		panic(err)
 	} else {
+		data.W = d.orderWeights(perm)
+		d.withCategorical(data)
 		logger.Tracef("shuffled data: %s", data.ShortString())
 		return data, perm
 	}
 }
 
+// Order return Data with rows reordered by given permutation of [0; Rows()): i'th row of result is perm[i]'th row of
+// Data. Inputs, outputs and weights are reordered together, source Data stay untouched.
+//
+// Throws ErrOrder error.
+//
+// Example:
+//     {X: | 1 |, Y: | 4 |}.Order([2 0 1]) = {X: | 3 |, Y: | 6 |}
+//         | 2 |     | 5 |                       | 1 |     | 4 |
+//         | 3 |     | 6 |                       | 2 |     | 5 |
+func (d *Data) Order(perm []int) (data *Data, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrOrder, &err)
+
+	if d == nil || d.X == nil {
+		return nil, fmt.Errorf("no data provided: %v", d)
+	}
+	X, err := d.X.Order(perm)
+	if err != nil {
+		return nil, fmt.Errorf("error ordering inputs: %w", err)
+	}
+	Y, err := d.Y.Order(perm)
+	if err != nil {
+		return nil, fmt.Errorf("error ordering outputs: %w", err)
+	}
+	if data, err = NewData(X, Y); err != nil {
+		return nil, err
+	}
+	data.W = d.orderWeights(perm)
+	return d.withCategorical(data), nil
+}
+
 func (d *Data) Equal(data *Data) bool {
 	if d == nil || data == nil {
 		if (d != nil && data == nil) || (d == nil && data != nil) {
@@ -140,9 +208,13 @@ func (d *Data) Equal(data *Data) bool {
 		return false
 	} else if !d.X.Equal(data.X) {
 		return false
+	} else if !d.W.Equal(data.W) {
+		return false
+	} else if !equalColumns(d.Categorical, data.Categorical) {
+		return false
 	}
 
-	return d.X.Equal(data.X)
+	return d.Y.Equal(data.Y)
 }
 
 func (d *Data) EqualApprox(data *Data) bool {
@@ -156,6 +228,10 @@ func (d *Data) EqualApprox(data *Data) bool {
 		return false
 	} else if !d.X.EqualApprox(data.X) {
 		return false
+	} else if !d.W.EqualApprox(data.W) {
+		return false
+	} else if !equalColumns(d.Categorical, data.Categorical) {
+		return false
 	}
 
 	return d.Y.EqualApprox(data.Y)
@@ -204,7 +280,15 @@ func (d *Data) Split(pivot int) (first *Data, second *Data, err error) {
 	if second, err = NewData(secondX, secondY); err != nil {
 		return nil, nil, fmt.Errorf("error getting data from second parts of inputs and outputs: %w", err)
 	}
+	if first.W, err = d.sliceWeights(0, pivot); err != nil {
+		return nil, nil, fmt.Errorf("error getting first part from weights: %w", err)
+	}
+	if second.W, err = d.sliceWeights(pivot, d.X.Rows()); err != nil {
+		return nil, nil, fmt.Errorf("error getting second part from weights: %w", err)
+	}
 
+	d.withCategorical(first)
+	d.withCategorical(second)
 	logger.Tracef("first part: %s", first.ShortString())
 	logger.Tracef("second part: %s", second.ShortString())
 
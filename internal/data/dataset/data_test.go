@@ -2,6 +2,7 @@ package dataset
 
 import (
 	"github.com/stretchr/testify/require"
+	"math/rand"
 	"nn/internal/testutils"
 	"nn/internal/testutils/testfactories"
 	"testing"
@@ -106,6 +107,10 @@ func TestData_Equal(t *testing.T) {
 	require.True(t, data.Equal(cp))
 	require.True(t, cp.Equal(data))
 	require.True(t, data.Equal(data))
+
+	cp.Y.MulNumInPlace(2)
+	require.False(t, data.Equal(cp))
+	require.False(t, data.EqualApprox(cp))
 }
 
 func TestData_Shuffle(t *testing.T) {
@@ -132,6 +137,12 @@ func TestData_Shuffle(t *testing.T) {
 
 		require.True(t, actualY.Equal(expectedY))
 	}
+
+	// the same seed gives the same permutation
+	shuffled, indices = data.ShuffleRand(rand.New(rand.NewSource(1)))
+	again, againIndices := data.ShuffleRand(rand.New(rand.NewSource(1)))
+	require.Equal(t, indices, againIndices)
+	require.True(t, shuffled.Equal(again))
 }
 
 func TestData_Split(t *testing.T) {
@@ -349,3 +360,139 @@ func TestData_Batches(t *testing.T) {
 		})
 	}
 }
+
+func TestData_KMeans(t *testing.T) {
+	data := newData(t, DataParameters{
+		X: testfactories.MatrixParameters{Rows: 6, Cols: 2, Values: []float64{
+			0, 0,
+			10, 10,
+			0, 1,
+			10, 11,
+			1, 0.5,
+			11, 10.5,
+		}},
+		Y: testfactories.MatrixParameters{Rows: 6, Cols: 1},
+	})
+
+	for i := 0; i < 10; i++ {
+		centers, err := data.KMeans(2, 100)
+		require.NoError(t, err)
+		require.Equal(t, 2, centers.Rows())
+		require.Equal(t, 2, centers.Cols())
+
+		raw := centers.Raw()
+		if raw[0][0] > raw[1][0] {
+			raw[0], raw[1] = raw[1], raw[0]
+		}
+		require.InDeltaSlice(t, []float64{1.0 / 3, 0.5}, raw[0], 1e-9)
+		require.InDeltaSlice(t, []float64{31.0 / 3, 10.5}, raw[1], 1e-9)
+	}
+
+	_, err := data.KMeans(7, 10)
+	require.ErrorIs(t, err, ErrCluster)
+	_, err = data.KMeans(0, 10)
+	require.ErrorIs(t, err, ErrCluster)
+	_, err = data.KMeans(2, 0)
+	require.ErrorIs(t, err, ErrCluster)
+}
+
+func TestData_SetCategorical(t *testing.T) {
+	x := testfactories.MatrixParameters{Rows: 4, Cols: 3, Values: []float64{
+		0, 0.5, 2,
+		3, 0.1, 0,
+		1, 0.7, 2,
+		0, 0.2, 1,
+	}}
+	y := testfactories.MatrixParameters{Rows: 4, Cols: 1, Values: []float64{1, 2, 3, 4}}
+	testcases := []struct {
+		testutils.Base
+		columns    []int
+		expected   []int
+		categories []int
+	}{
+		{Base: testutils.Base{Name: "two columns"}, columns: []int{2, 0}, expected: []int{0, 2}, categories: []int{4, 3}},
+		{Base: testutils.Base{Name: "no columns"}, categories: []int{}},
+		{Base: testutils.Base{Name: "column out of inputs", Err: ErrCreate}, columns: []int{3}},
+		{Base: testutils.Base{Name: "duplicated column", Err: ErrCreate}, columns: []int{0, 0}},
+		{Base: testutils.Base{Name: "not a category code", Err: ErrCreate}, columns: []int{1}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			data := newData(t, DataParameters{X: x, Y: y})
+			err := data.SetCategorical(tc.columns...)
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				require.Nil(t, data.Categorical)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, data.Categorical)
+			categories, err := data.Categories()
+			require.NoError(t, err)
+			require.Equal(t, tc.categories, categories)
+		})
+	}
+}
+
+func TestData_CategoricalPropagation(t *testing.T) {
+	data := newData(t, DataParameters{
+		X: testfactories.MatrixParameters{Rows: 4, Cols: 2, Values: []float64{0, 0.5, 1, 0.1, 2, 0.7, 0, 0.2}},
+		Y: testfactories.MatrixParameters{Rows: 4, Cols: 1, Values: []float64{1, 2, 3, 4}},
+	})
+	require.NoError(t, data.SetCategorical(0))
+
+	require.Equal(t, []int{0}, data.Copy().Categorical)
+	shuffled, _ := data.Shuffle()
+	require.Equal(t, []int{0}, shuffled.Categorical)
+
+	first, second, err := data.Split(1)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, first.Categorical)
+	require.Equal(t, []int{0}, second.Categorical)
+	merged, err := first.Merge(second)
+	require.NoError(t, err)
+	require.True(t, data.Equal(merged))
+
+	second.Categorical = nil
+	_, err = first.Merge(second)
+	require.ErrorIs(t, err, ErrMerge)
+
+	data.Categorical = []int{1} // set bypassing SetCategorical
+	_, err = data.Categories()
+	require.ErrorIs(t, err, ErrCategories)
+	data.Categorical = []int{2}
+	_, err = data.Categories()
+	require.ErrorIs(t, err, ErrCategories)
+}
+
+func TestData_Folds(t *testing.T) {
+	data := newData(t, DataParameters{
+		X: testfactories.MatrixParameters{Rows: 5, Cols: 1, Values: []float64{1, 2, 3, 4, 5}},
+		Y: testfactories.MatrixParameters{Rows: 5, Cols: 1, Values: []float64{6, 7, 8, 9, 10}},
+	})
+
+	folds, err := data.Folds(3)
+	require.NoError(t, err)
+	require.Len(t, folds, 3)
+	for i, expected := range [][]float64{{1, 2}, {3, 4}, {5}} {
+		require.Equal(t, expected, folds[i].X.RawFlat())
+	}
+
+	merged, err := MergeFolds(folds, 1)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 2, 5}, merged.X.RawFlat())
+	require.Equal(t, []float64{6, 7, 10}, merged.Y.RawFlat())
+
+	folds, err = data.Folds(1)
+	require.NoError(t, err)
+	require.True(t, data.Equal(folds[0]))
+	_, err = MergeFolds(folds, 0)
+	require.ErrorIs(t, err, ErrMerge)
+
+	_, err = data.Folds(6)
+	require.ErrorIs(t, err, ErrSplit)
+	_, err = data.Folds(0)
+	require.ErrorIs(t, err, ErrSplit)
+}
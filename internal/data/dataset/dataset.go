@@ -94,7 +94,8 @@ func (d *Dataset) Combine() *Data {
 }
 
 // Shuffle shuffles all Data. First it merges all train, tests and valid Data, then Data is being shuffled,
-// and finally Data splits to Dataset again.
+// and finally Data splits to Dataset again. Train Data gets rows of the future, so time series Dataset (see
+// NewWindowDataset) must not be shuffled.
 func (d *Dataset) Shuffle() *Dataset {
 	combine := d.Combine()
 	parameters := parametersFromDataset(d)
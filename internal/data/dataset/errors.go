@@ -3,7 +3,11 @@ package dataset
 import "errors"
 
 var (
-	ErrCreate = errors.New("can not create data")
-	ErrSplit  = errors.New("can not split data")
-	ErrMerge  = errors.New("can not merge data")
+	ErrCreate     = errors.New("can not create data")
+	ErrSplit      = errors.New("can not split data")
+	ErrMerge      = errors.New("can not merge data")
+	ErrCluster    = errors.New("can not cluster data")
+	ErrExport     = errors.New("can not export data")
+	ErrOrder      = errors.New("can not order data")
+	ErrCategories = errors.New("can not count categories of data")
 )
@@ -0,0 +1,61 @@
+package dataset
+
+import (
+	"fmt"
+	"nn/pkg/wraperr"
+)
+
+// Folds splits Data into <k> row-based parts of nearly equal size in order of rows, the first Rows() % k parts have
+// one row more. Rows count must be at least <k>.
+//
+// Throws ErrSplit error.
+//
+// Example:
+//     {X: | 1 |, Y: | 5 |}.Folds(2) = [{X: | 1 |, Y: | 5 |}, {X: | 3 |, Y: | 7 |}]
+//         | 2 |     | 6 |                  | 2 |     | 6 |
+//         | 3 |     | 7 |
+func (d *Data) Folds(k int) (folds []*Data, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrSplit, &err)
+
+	if d == nil || d.X == nil {
+		return nil, fmt.Errorf("no data provided: %v", d)
+	} else if k < 1 || k > d.X.Rows() {
+		return nil, fmt.Errorf("folds count must be in [1; %d]: %d", d.X.Rows(), k)
+	}
+
+	rows := d.X.Rows()
+	folds = make([]*Data, k)
+	rest := d
+	for i := 0; i < k-1; i++ {
+		size := rows / k
+		if i < rows%k {
+			size++
+		}
+		if folds[i], rest, err = rest.Split(size); err != nil {
+			return nil, fmt.Errorf("error getting %d'th fold: %w", i, err)
+		}
+	}
+	folds[k-1] = rest
+	return folds, nil
+}
+
+// MergeFolds merges all the given folds except <skip>'th one in order (see Merge), e.g. to get train Data for
+// validation on <skip>'th fold.
+//
+// Throws ErrMerge error.
+func MergeFolds(folds []*Data, skip int) (res *Data, err error) {
+	for i, fold := range folds {
+		if i == skip {
+			continue
+		} else if res == nil {
+			res = fold
+		} else if res, err = res.Merge(fold); err != nil {
+			return nil, err
+		}
+	}
+	if res == nil {
+		return nil, wraperr.NewWrapErr(ErrMerge, fmt.Errorf("no folds to merge except %d'th of %d", skip, len(folds)))
+	}
+	return res, nil
+}
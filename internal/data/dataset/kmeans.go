@@ -0,0 +1,86 @@
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+// KMeans return centers of <k> clusters of inputs found by Lloyd's algorithm. Initial centers are distinct random rows
+// of inputs, algorithm stops after <iterations> or when no row changes its cluster. Center of a cluster, that became
+// empty, stays unchanged. Result is k x inputs cols Matrix, e.g. centers for radial basis function layer.
+//
+// Throws ErrCluster error.
+func (d *Data) KMeans(k, iterations int) (centers *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCluster, &err)
+
+	if d == nil || d.X == nil {
+		return nil, fmt.Errorf("no data provided: %v", d)
+	} else if k < 1 || k > d.X.Rows() {
+		return nil, fmt.Errorf("clusters count must be in [1; %d]: %d", d.X.Rows(), k)
+	} else if iterations < 1 {
+		return nil, fmt.Errorf("negative or zero iterations count: %d", iterations)
+	}
+
+	logger.Debugf("find %d clusters of data %q", k, d.ShortString())
+	rows := d.X.Raw()
+	cols := d.X.Cols()
+	shuffled, _, err := matrix.RandPermutation(utils.Rand, d.X)
+	if err != nil {
+		return nil, err
+	}
+	values := shuffled.Raw()[:k]
+
+	assignment := make([]int, len(rows))
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	for iteration := 0; iteration < iterations; iteration++ {
+		changed := false
+		for i, row := range rows {
+			nearest, best := 0, math.Inf(1)
+			for c, center := range values {
+				dist := 0.0
+				for j := range row {
+					dist += (row[j] - center[j]) * (row[j] - center[j])
+				}
+				if dist < best {
+					nearest, best = c, dist
+				}
+			}
+			if assignment[i] != nearest {
+				assignment[i] = nearest
+				changed = true
+			}
+		}
+		if !changed {
+			logger.Tracef("clusters converged after %d iterations", iteration)
+			break
+		}
+
+		sums := make([][]float64, k)
+		counts := make([]int, k)
+		for c := range sums {
+			sums[c] = make([]float64, cols)
+		}
+		for i, row := range rows {
+			counts[assignment[i]]++
+			for j, value := range row {
+				sums[assignment[i]][j] += value
+			}
+		}
+		for c := range values {
+			if counts[c] == 0 {
+				continue
+			}
+			for j := range values[c] {
+				values[c][j] = sums[c][j] / float64(counts[c])
+			}
+		}
+	}
+
+	return matrix.NewMatrixRaw(values)
+}
@@ -0,0 +1,311 @@
+package dataset
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+	"os"
+)
+
+// DataSource yields Data in batches, so whole Data need not to be held in memory, e.g. for training by epochs.
+type DataSource interface {
+	// Next return the next batch of at most <size> rows (all the rest rows if size < 1) or io.EOF after the last batch
+	Next(size int) (*Data, error)
+	// Reset rewinds DataSource to the first batch, e.g. before the next epoch
+	Reset() error
+}
+
+var (
+	_ DataSource = (*MemorySource)(nil)
+	_ DataSource = (*CSVSource)(nil)
+	_ DataSource = (*GeneratorSource)(nil)
+	_ DataSource = (*ShuffleBuffer)(nil)
+)
+
+// newDataRaw creates Data from rows of inputs and outputs and their weights if not nil
+func newDataRaw(x, y [][]float64, w []float64) (*Data, error) {
+	X, err := matrix.NewMatrixRaw(x)
+	if err != nil {
+		return nil, err
+	}
+	Y, err := matrix.NewMatrixRaw(y)
+	if err != nil {
+		return nil, err
+	}
+	data, err := NewData(X, Y)
+	if err != nil {
+		return nil, err
+	}
+	if w != nil {
+		if data.W, err = vector.NewVector(w); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// rows return Data of rows in [start; stop)
+func (d *Data) rows(start, stop int) (*Data, error) {
+	if start == 0 && stop == d.X.Rows() {
+		return d, nil
+	}
+	X, err := d.X.SubMatrix(start, stop, 1, 0, d.X.Cols(), 1)
+	if err != nil {
+		return nil, err
+	}
+	Y, err := d.Y.SubMatrix(start, stop, 1, 0, d.Y.Cols(), 1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := NewData(X, Y)
+	if err != nil {
+		return nil, err
+	}
+	if data.W, err = d.sliceWeights(start, stop); err != nil {
+		return nil, err
+	}
+	return d.withCategorical(data), nil
+}
+
+// MemorySource is DataSource over Data held in memory, optionally reshuffled on every Reset.
+type MemorySource struct {
+	data     *Data
+	current  *Data
+	position int
+	rng      *rand.Rand
+}
+
+// NewMemorySource creates DataSource over given Data. If random generator is provided, rows are shuffled by it on
+// creation and on every Reset.
+//
+// Throws ErrCreate error.
+func NewMemorySource(data *Data, rng *rand.Rand) (s *MemorySource, err error) {
+	if data == nil || data.X == nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("no data provided: %v", data))
+	}
+	s = &MemorySource{data: data, rng: rng}
+	return s, s.Reset()
+}
+
+func (s *MemorySource) Next(size int) (data *Data, err error) {
+	rows := s.current.X.Rows()
+	if s.position >= rows {
+		return nil, io.EOF
+	}
+	stop := rows
+	if size > 0 && s.position+size < rows {
+		stop = s.position + size
+	}
+	if data, err = s.current.rows(s.position, stop); err != nil {
+		return nil, wraperr.NewWrapErr(ErrSplit, err)
+	}
+	s.position = stop
+	return data, nil
+}
+
+func (s *MemorySource) Reset() error {
+	s.current, s.position = s.data, 0
+	if s.rng != nil {
+		s.current, _ = s.data.ShuffleRand(s.rng)
+	}
+	return nil
+}
+
+// CSVSource is DataSource reading CSV file lazily: only the current batch is held in memory.
+type CSVSource struct {
+	path    string
+	p       *CSVParameters
+	file    *os.File
+	records *csvRecords
+}
+
+// NewCSVSource creates DataSource over CSV file by given path, see CSVParameters. File is opened until Close.
+//
+// Throws ErrCreate error.
+func NewCSVSource(path string, p *CSVParameters) (s *CSVSource, err error) {
+	s = &CSVSource{path: path, p: p}
+	if err = s.Reset(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *CSVSource) Next(size int) (data *Data, err error) {
+	if s.records == nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("csv source is closed"))
+	}
+	var x, y [][]float64
+	var w []float64
+	for size < 1 || len(x) < size {
+		rowX, rowY, rowW, err := s.records.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, wraperr.NewWrapErr(ErrCreate, err)
+		}
+		x, y = append(x, rowX), append(y, rowY)
+		if s.records.weighted() {
+			w = append(w, rowW)
+		}
+	}
+	if len(x) == 0 {
+		return nil, io.EOF
+	}
+	if data, err = newDataRaw(x, y, w); err != nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, err)
+	}
+	return data, nil
+}
+
+// Reset reopens CSV file to read it from the beginning
+func (s *CSVSource) Reset() (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if err = s.Close(); err != nil {
+		return err
+	} else if s.file, err = os.Open(s.path); err != nil {
+		return err
+	} else if s.records, err = newCSVRecords(s.file, s.p); err != nil {
+		_ = s.Close()
+		return err
+	}
+	return nil
+}
+
+// Close closes CSV file
+func (s *CSVSource) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	file := s.file
+	s.file, s.records = nil, nil
+	return file.Close()
+}
+
+// GeneratorSource is DataSource over generator function producing batches, e.g. sampling Data on the fly.
+type GeneratorSource struct {
+	generate func(size int) (*Data, error)
+	batches  int
+	batch    int
+}
+
+// NewGeneratorSource creates DataSource yielding <batchesCount> batches produced by given function between Resets.
+// Function gets requested batch size.
+//
+// Throws ErrCreate error.
+func NewGeneratorSource(generate func(size int) (*Data, error), batchesCount int) (*GeneratorSource, error) {
+	if generate == nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("no generator function provided"))
+	} else if batchesCount < 1 {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("negative or zero batches count: %d", batchesCount))
+	}
+	return &GeneratorSource{generate: generate, batches: batchesCount}, nil
+}
+
+func (s *GeneratorSource) Next(size int) (data *Data, err error) {
+	if s.batch >= s.batches {
+		return nil, io.EOF
+	}
+	if data, err = s.generate(size); err != nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("error generating %d'th batch: %w", s.batch, err))
+	} else if data == nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("no data generated for %d'th batch", s.batch))
+	}
+	s.batch++
+	return data, nil
+}
+
+func (s *GeneratorSource) Reset() error {
+	s.batch = 0
+	return nil
+}
+
+// ShuffleBuffer is DataSource shuffling rows of another DataSource, which can't be shuffled as a whole, approximately:
+// it holds buffer of rows and yields randomly chosen ones, replacing them by the next rows of source.
+type ShuffleBuffer struct {
+	source      DataSource
+	size        int
+	rng         *rand.Rand
+	x, y        [][]float64
+	w           []float64 // nil until source yields weighted Data, rows of unweighted Data weigh 1
+	categorical []int
+	exhausted   bool
+}
+
+// NewShuffleBuffer creates DataSource yielding rows of given source in random order within window of <bufferSize>
+// rows. Bigger buffer shuffles better, buffer of source rows count shuffles uniformly. Random generator is utils.Rand
+// if not provided.
+//
+// Throws ErrCreate error.
+func NewShuffleBuffer(source DataSource, bufferSize int, rng *rand.Rand) (*ShuffleBuffer, error) {
+	if source == nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("no source provided: %v", source))
+	} else if bufferSize < 1 {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("negative or zero buffer size: %d", bufferSize))
+	}
+	if rng == nil {
+		rng = utils.Rand
+	}
+	return &ShuffleBuffer{source: source, size: bufferSize, rng: rng}, nil
+}
+
+// fill reads rows from source until buffer is full or source is exhausted
+func (b *ShuffleBuffer) fill() error {
+	for !b.exhausted && len(b.x) < b.size {
+		data, err := b.source.Next(b.size - len(b.x))
+		if err == io.EOF {
+			b.exhausted = true
+			break
+		} else if err != nil {
+			return err
+		}
+		if b.w != nil || data.W != nil {
+			b.w = append(padWeights(b.w, len(b.x)), data.weights().Raw()...)
+		}
+		b.x, b.y = append(b.x, data.X.Raw()...), append(b.y, data.Y.Raw()...)
+		b.categorical = data.Categorical
+	}
+	return nil
+}
+
+func (b *ShuffleBuffer) Next(size int) (data *Data, err error) {
+	var x, y [][]float64
+	var w []float64
+	for size < 1 || len(x) < size {
+		if err = b.fill(); err != nil {
+			return nil, err
+		} else if len(b.x) == 0 {
+			break
+		}
+		i, last := b.rng.Intn(len(b.x)), len(b.x)-1
+		x, y = append(x, b.x[i]), append(y, b.y[i])
+		b.x[i], b.y[i] = b.x[last], b.y[last]
+		b.x, b.y = b.x[:last], b.y[:last]
+		if b.w != nil {
+			w = append(padWeights(w, len(x)-1), b.w[i])
+			b.w[i] = b.w[last]
+			b.w = b.w[:last]
+		}
+	}
+	if len(x) == 0 {
+		return nil, io.EOF
+	}
+	if data, err = newDataRaw(x, y, w); err != nil {
+		return nil, wraperr.NewWrapErr(ErrCreate, err)
+	}
+	if len(b.categorical) > 0 {
+		data.Categorical = append([]int(nil), b.categorical...)
+	}
+	return data, nil
+}
+
+// Reset drops buffered rows and resets source
+func (b *ShuffleBuffer) Reset() error {
+	b.x, b.y, b.w, b.exhausted = nil, nil, nil, false
+	return b.source.Reset()
+}
@@ -0,0 +1,195 @@
+package dataset
+
+import (
+	"github.com/stretchr/testify/require"
+	"io"
+	"math/rand"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/vector"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// drain reads all batches of given size from source and return their sizes and merged Data
+func drain(t *testing.T, source DataSource, size int) ([]int, *Data) {
+	var sizes []int
+	var merged *Data
+	for {
+		batch, err := source.Next(size)
+		if err == io.EOF {
+			return sizes, merged
+		}
+		require.NoError(t, err)
+		sizes = append(sizes, batch.X.Rows())
+		if merged == nil {
+			merged = batch
+		} else {
+			merged, err = merged.Merge(batch)
+			require.NoError(t, err)
+		}
+	}
+}
+
+// sortedInputs return sorted inputs of single-input Data
+func sortedInputs(data *Data) []float64 {
+	values := data.X.RawFlat()
+	sort.Float64s(values)
+	return values
+}
+
+func newSourceData(t *testing.T) *Data {
+	return newData(t, DataParameters{
+		X: testfactories.MatrixParameters{Rows: 5, Cols: 1, Values: []float64{1, 2, 3, 4, 5}},
+		Y: testfactories.MatrixParameters{Rows: 5, Cols: 1, Values: []float64{10, 20, 30, 40, 50}},
+	})
+}
+
+func TestMemorySource(t *testing.T) {
+	data := newSourceData(t)
+	data.Categorical = []int{0}
+
+	source, err := NewMemorySource(data, nil)
+	require.NoError(t, err)
+	sizes, merged := drain(t, source, 2)
+	require.Equal(t, []int{2, 2, 1}, sizes)
+	require.True(t, data.Equal(merged))
+	_, err = source.Next(2)
+	require.ErrorIs(t, err, io.EOF)
+
+	require.NoError(t, source.Reset())
+	sizes, merged = drain(t, source, 0)
+	require.Equal(t, []int{5}, sizes)
+	require.True(t, data.Equal(merged))
+
+	// seeded source reshuffles rows on every Reset reproducibly
+	source, err = NewMemorySource(data, rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	_, first := drain(t, source, 2)
+	require.NoError(t, source.Reset())
+	_, second := drain(t, source, 2)
+	require.Equal(t, data.X.RawFlat(), sortedInputs(first))
+	require.Equal(t, data.X.RawFlat(), sortedInputs(second))
+	require.False(t, first.Equal(second))
+	for i, x := range first.X.RawFlat() {
+		require.Equal(t, 10*x, first.Y.RawFlat()[i]) // rows are kept together
+	}
+
+	another, err := NewMemorySource(data, rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	_, same := drain(t, another, 3)
+	require.True(t, first.Equal(same))
+
+	_, err = NewMemorySource(nil, nil)
+	require.ErrorIs(t, err, ErrCreate)
+}
+
+func TestCSVSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	require.NoError(t, os.WriteFile(path, []byte("x,y\n1,10\n2,20\nx,30\n3,30\n4,40\n5,50\n"), 0o600))
+
+	source, err := NewCSVSource(path, &CSVParameters{Header: true, Outputs: []string{"y"}, SkipMalformed: true})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, source.Close()) }()
+
+	for i := 0; i < 2; i++ {
+		sizes, merged := drain(t, source, 2)
+		require.Equal(t, []int{2, 2, 1}, sizes)
+		require.True(t, newSourceData(t).Equal(merged), merged.String())
+		require.NoError(t, source.Reset())
+	}
+
+	strict, err := NewCSVSource(path, &CSVParameters{Header: true, Outputs: []string{"y"}})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, strict.Close()) }()
+	_, err = strict.Next(2)
+	require.NoError(t, err)
+	_, err = strict.Next(2)
+	require.ErrorIs(t, err, ErrCreate)
+
+	_, err = NewCSVSource(filepath.Join(t.TempDir(), "missing.csv"), &CSVParameters{Outputs: []string{"0"}})
+	require.ErrorIs(t, err, ErrCreate)
+}
+
+func TestGeneratorSource(t *testing.T) {
+	calls := 0
+	source, err := NewGeneratorSource(func(size int) (*Data, error) {
+		calls++
+		return newData(t, DataParameters{
+			X: testfactories.MatrixParameters{Rows: size, Cols: 1, Values: make([]float64, size)},
+			Y: testfactories.MatrixParameters{Rows: size, Cols: 1, Values: make([]float64, size)},
+		}), nil
+	}, 3)
+	require.NoError(t, err)
+
+	sizes, _ := drain(t, source, 4)
+	require.Equal(t, []int{4, 4, 4}, sizes)
+	require.NoError(t, source.Reset())
+	sizes, _ = drain(t, source, 2)
+	require.Equal(t, []int{2, 2, 2}, sizes)
+	require.Equal(t, 6, calls)
+
+	_, err = NewGeneratorSource(nil, 3)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = NewGeneratorSource(source.generate, 0)
+	require.ErrorIs(t, err, ErrCreate)
+
+	failing, err := NewGeneratorSource(func(int) (*Data, error) { return nil, nil }, 1)
+	require.NoError(t, err)
+	_, err = failing.Next(1)
+	require.ErrorIs(t, err, ErrCreate)
+}
+
+func TestShuffleBuffer(t *testing.T) {
+	data := newSourceData(t)
+	data.Categorical = []int{0}
+	memory, err := NewMemorySource(data, nil)
+	require.NoError(t, err)
+
+	buffer, err := NewShuffleBuffer(memory, 3, rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	sizes, first := drain(t, buffer, 2)
+	require.Equal(t, []int{2, 2, 1}, sizes)
+	require.Equal(t, data.X.RawFlat(), sortedInputs(first)) // every row exactly once
+	require.Equal(t, data.Categorical, first.Categorical)
+	for i, x := range first.X.RawFlat() {
+		require.Equal(t, 10*x, first.Y.RawFlat()[i])
+	}
+
+	require.NoError(t, buffer.Reset())
+	sizes, second := drain(t, buffer, 0)
+	require.Equal(t, []int{5}, sizes)
+	require.Equal(t, data.X.RawFlat(), sortedInputs(second))
+
+	// rows of unweighted batches weigh 1 once source yields weighted ones
+	calls := 0
+	mixed, err := NewGeneratorSource(func(int) (*Data, error) {
+		calls++
+		x := []float64{float64(2*calls - 1), float64(2 * calls)}
+		data := newData(t, DataParameters{
+			X: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: x},
+			Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: x},
+		})
+		if calls == 2 {
+			w, err := vector.NewVector(x)
+			require.NoError(t, err)
+			require.NoError(t, data.SetWeights(w))
+		}
+		return data, nil
+	}, 3)
+	require.NoError(t, err)
+	buffer, err = NewShuffleBuffer(mixed, 3, rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	_, merged := drain(t, buffer, 2)
+	require.Equal(t, []float64{1, 2, 3, 4, 5, 6}, sortedInputs(merged))
+	weights := map[float64]float64{1: 1, 2: 1, 3: 3, 4: 4, 5: 1, 6: 1}
+	for i, x := range merged.X.RawFlat() {
+		require.Equal(t, weights[x], merged.W.Raw()[i], "weight of row %v", x)
+	}
+
+	_, err = NewShuffleBuffer(nil, 3, nil)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = NewShuffleBuffer(memory, 0, nil)
+	require.ErrorIs(t, err, ErrCreate)
+}
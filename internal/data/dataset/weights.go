@@ -0,0 +1,81 @@
+package dataset
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+)
+
+// SetWeights sets per-sample weights of Data: i'th weight scales loss of i'th row and its gradient (see loss.ILoss),
+// e.g. to emphasize regions of inputs where network is worst. Weights must be non-negative and finite, there must be
+// one weight per row. Nil weights unset weights, so all the rows weigh the same.
+//
+// Throws ErrCreate error.
+func (d *Data) SetWeights(w *vector.Vector) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if d == nil || d.X == nil {
+		return fmt.Errorf("no data provided: %v", d)
+	} else if w == nil {
+		d.W = nil
+		return nil
+	} else if w.Size() != d.X.Rows() {
+		return fmt.Errorf("weights count mismatches rows count: %d != %d", w.Size(), d.X.Rows())
+	}
+	for i, weight := range w.Raw() {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return fmt.Errorf("weight of %d'th row is not non-negative finite: %v", i, weight)
+		}
+	}
+
+	logger.Debugf("set weights %s", w.ShortString())
+	d.W = w.Copy()
+	return nil
+}
+
+// weights return weights of Data or ones if Data has no weights
+func (d *Data) weights() *vector.Vector {
+	if d.W != nil {
+		return d.W
+	}
+	w, err := vector.NewVectorOf(1, d.X.Rows())
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// padWeights appends weights of 1 to w up to given count, as rows of unweighted Data weigh 1 (see Merge)
+func padWeights(w []float64, count int) []float64 {
+	for len(w) < count {
+		w = append(w, 1)
+	}
+	return w
+}
+
+// sliceWeights return weights of rows in [start; stop) or nil if Data has no weights
+func (d *Data) sliceWeights(start, stop int) (*vector.Vector, error) {
+	if d.W == nil {
+		return nil, nil
+	}
+	return d.W.Slice(start, stop, 1)
+}
+
+// orderWeights return weights ordered by given permutation or nil if Data has no weights
+func (d *Data) orderWeights(perm []int) *vector.Vector {
+	if d.W == nil {
+		return nil
+	}
+	raw := d.W.Raw()
+	values := make([]float64, len(perm))
+	for i, index := range perm {
+		values[i] = raw[index]
+	}
+	w, err := vector.NewVector(values)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
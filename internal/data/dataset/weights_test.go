@@ -0,0 +1,144 @@
+package dataset
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+// newWeightedData creates Data with inputs 1, 2, ..., rows, outputs 10 times inputs and weights equal to inputs
+func newWeightedData(t *testing.T, rows int) *Data {
+	values := make([]float64, rows)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	data := newData(t, DataParameters{
+		X: testfactories.MatrixParameters{Rows: rows, Cols: 1, Values: values},
+		Y: testfactories.MatrixParameters{Rows: rows, Cols: 1, Values: values},
+	})
+	data.Y.MulNumInPlace(10)
+	w, err := vector.NewVector(values)
+	require.NoError(t, err)
+	require.NoError(t, data.SetWeights(w))
+	return data
+}
+
+// requireWeightsFollowRows checks each row of Data keeps its weight, see newWeightedData
+func requireWeightsFollowRows(t *testing.T, data *Data) {
+	require.NotNil(t, data.W)
+	require.Equal(t, data.X.RawFlat(), data.W.Raw())
+}
+
+func TestData_SetWeights(t *testing.T) {
+	tests := []struct {
+		testutils.Base
+		weights []float64
+	}{
+		{Base: testutils.Base{Name: "valid weights"}, weights: []float64{1, 0, 2.5}},
+		{Base: testutils.Base{Name: "no weights"}},
+		{Base: testutils.Base{Name: "wrong size, error", Err: ErrCreate}, weights: []float64{1, 2}},
+		{Base: testutils.Base{Name: "negative weight, error", Err: ErrCreate}, weights: []float64{1, -1, 2}},
+		{Base: testutils.Base{Name: "infinite weight, error", Err: ErrCreate}, weights: []float64{1, math.Inf(1), 2}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			data := newWeightedData(t, 3)
+			var w *vector.Vector
+			if tc.weights != nil {
+				var err error
+				w, err = vector.NewVector(tc.weights)
+				require.NoError(t, err)
+			}
+			err := data.SetWeights(w)
+			if tc.Err != nil {
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, w.Equal(data.W))
+		})
+	}
+}
+
+func TestData_WeightsPropagation(t *testing.T) {
+	data := newWeightedData(t, 5)
+
+	copied := data.Copy()
+	require.True(t, data.Equal(copied))
+	require.NotSame(t, data.W, copied.W)
+	unweighted := data.Copy()
+	require.NoError(t, unweighted.SetWeights(nil))
+	require.False(t, data.Equal(unweighted))
+
+	first, second, err := data.Split(2)
+	require.NoError(t, err)
+	requireWeightsFollowRows(t, first)
+	requireWeightsFollowRows(t, second)
+
+	merged, err := first.Merge(second)
+	require.NoError(t, err)
+	require.True(t, data.Equal(merged))
+	_, unweightedSecond, err := unweighted.Split(2)
+	require.NoError(t, err)
+	merged, err = first.Merge(unweightedSecond)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 2, 1, 1, 1}, merged.W.Raw()) // rows of unweighted Data weigh 1
+
+	shuffled, _ := data.ShuffleRand(rand.New(rand.NewSource(42)))
+	requireWeightsFollowRows(t, shuffled)
+
+	ordered, err := data.Order([]int{4, 2, 0, 1, 3})
+	require.NoError(t, err)
+	require.Equal(t, []float64{5, 3, 1, 2, 4}, ordered.X.RawFlat())
+	require.Equal(t, []float64{50, 30, 10, 20, 40}, ordered.Y.RawFlat())
+	requireWeightsFollowRows(t, ordered)
+	_, err = data.Order([]int{0, 0, 1, 2, 3})
+	require.ErrorIs(t, err, ErrOrder)
+
+	batches, count, err := data.Batches(2)
+	require.NoError(t, err)
+	for i := 0; i < count; i++ {
+		batch, err := batches(i)
+		require.NoError(t, err)
+		requireWeightsFollowRows(t, batch)
+	}
+
+	folds, err := data.Folds(2)
+	require.NoError(t, err)
+	requireWeightsFollowRows(t, folds[1])
+
+	source, err := NewMemorySource(data, rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	buffer, err := NewShuffleBuffer(source, 3, rand.New(rand.NewSource(42)))
+	require.NoError(t, err)
+	_, drained := drain(t, buffer, 2)
+	requireWeightsFollowRows(t, drained)
+}
+
+func TestData_WeightsCSV(t *testing.T) {
+	data := newWeightedData(t, 3)
+	parameters := &CSVParameters{Header: true, Inputs: []string{"x"}, Outputs: []string{"y"}, Weights: "weight"}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, data.WriteCSV(buf, nil, parameters))
+	require.Equal(t, "x,y,weight\n1,10,1\n2,20,2\n3,30,3\n", buf.String())
+
+	read, err := ReadCSV(buf, &CSVParameters{Header: true, Outputs: []string{"y"}, Weights: "weight"})
+	require.NoError(t, err)
+	require.True(t, data.Equal(read))
+
+	in := "x,y,w\n1,10,1\n2,20,-2\n3,30,3\n"
+	read, err = ReadCSV(bytes.NewBufferString(in), &CSVParameters{Header: true, Outputs: []string{"y"}, Weights: "w", SkipMalformed: true})
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 3}, read.W.Raw())
+	_, err = ReadCSV(bytes.NewBufferString(in), &CSVParameters{Header: true, Outputs: []string{"y"}, Weights: "w"})
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = ReadCSV(bytes.NewBufferString(in), &CSVParameters{Header: true, Outputs: []string{"y"}, Weights: "y"})
+	require.ErrorIs(t, err, ErrCreate)
+}
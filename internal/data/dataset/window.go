@@ -0,0 +1,207 @@
+package dataset
+
+import (
+	"fmt"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+// WindowParameters describe how time series (Matrix of steps in rows and variables in cols) is cut in windows of
+// supervised Data:
+//     * Lookback is count of the latest steps in inputs of each window;
+//     * Horizon is count of the next steps to forecast in outputs of each window, 1 for one-step forecaster;
+//     * Stride is count of steps between starts of neighbouring windows, 1 by default;
+//     * Targets are columns to forecast, all the columns except Exogenous by default;
+//     * Exogenous are columns used in inputs only, e.g. calendar features or external drivers;
+//     * FutureExogenous tells exogenous values of horizon steps are known at forecast time, so they are added to
+//       inputs too.
+// Inputs hold Targets followed by Exogenous columns step by step over lookback, then (if FutureExogenous) Exogenous
+// columns step by step over horizon. Outputs hold Targets columns step by step over horizon.
+type WindowParameters struct {
+	Lookback        int
+	Horizon         int
+	Stride          int
+	Targets         []int
+	Exogenous       []int
+	FutureExogenous bool
+}
+
+// window is WindowParameters checked for series with known columns count
+type window struct {
+	*WindowParameters
+	stride  int
+	targets []int
+	inputs  []int // targets followed by exogenous columns
+}
+
+func (p *WindowParameters) window(cols int) (*window, error) {
+	if p == nil {
+		return nil, fmt.Errorf("no window parameters provided: %v", p)
+	} else if p.Lookback < 1 {
+		return nil, fmt.Errorf("negative or zero lookback: %d", p.Lookback)
+	} else if p.Horizon < 1 {
+		return nil, fmt.Errorf("negative or zero horizon: %d", p.Horizon)
+	} else if p.Stride < 0 {
+		return nil, fmt.Errorf("negative stride: %d", p.Stride)
+	}
+
+	w := &window{WindowParameters: p, stride: p.Stride, targets: p.Targets}
+	if w.stride == 0 {
+		w.stride = 1
+	}
+	if len(w.targets) == 0 {
+		for i := 0; i < cols; i++ {
+			if !contains(p.Exogenous, i) {
+				w.targets = append(w.targets, i)
+			}
+		}
+	}
+	if len(w.targets) == 0 {
+		return nil, fmt.Errorf("no target columns selected")
+	}
+	w.inputs = append(append([]int(nil), w.targets...), p.Exogenous...)
+	used := make(map[int]bool, len(w.inputs))
+	for _, column := range w.inputs {
+		if column < 0 || column >= cols {
+			return nil, fmt.Errorf("column index must be in [0; %d): %d", cols, column)
+		} else if used[column] {
+			return nil, fmt.Errorf("column is selected twice: %d", column)
+		}
+		used[column] = true
+	}
+	return w, nil
+}
+
+// count return windows count in series of given steps count
+func (w *window) count(steps int) int {
+	if steps < w.Lookback+w.Horizon {
+		return 0
+	}
+	return (steps-w.Lookback-w.Horizon)/w.stride + 1
+}
+
+// purge return count of windows to drop between chronological parts, so that outputs of the last window of a part
+// don't overlap outputs of the first window of the next one
+func (w *window) purge() int {
+	return (w.Horizon - 1) / w.stride
+}
+
+// rows appends inputs and outputs of windows in [start; stop) of series to x and y
+func (w *window) rows(series [][]float64, start, stop int, x, y [][]float64) ([][]float64, [][]float64) {
+	for i := start; i < stop; i++ {
+		first := i * w.stride
+		inputs := make([]float64, 0, w.Lookback*len(w.inputs)+w.Horizon*len(w.Exogenous))
+		for _, step := range series[first : first+w.Lookback] {
+			inputs = appendColumns(inputs, step, w.inputs)
+		}
+		future := series[first+w.Lookback : first+w.Lookback+w.Horizon]
+		if w.FutureExogenous {
+			for _, step := range future {
+				inputs = appendColumns(inputs, step, w.Exogenous)
+			}
+		}
+		outputs := make([]float64, 0, w.Horizon*len(w.targets))
+		for _, step := range future {
+			outputs = appendColumns(outputs, step, w.targets)
+		}
+		x, y = append(x, inputs), append(y, outputs)
+	}
+	return x, y
+}
+
+func appendColumns(values []float64, row []float64, columns []int) []float64 {
+	for _, column := range columns {
+		values = append(values, row[column])
+	}
+	return values
+}
+
+// checkSeries checks all the series have the same columns count and return checked window for them
+func checkSeries(series []*matrix.Matrix, p *WindowParameters) (*window, error) {
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no series provided")
+	}
+	for i, s := range series {
+		if s == nil {
+			return nil, fmt.Errorf("no %d'th series provided: %v", i, s)
+		} else if s.Cols() != series[0].Cols() {
+			return nil, fmt.Errorf("cols count of %d'th series mismatches: %d != %d", i, s.Cols(), series[0].Cols())
+		}
+	}
+	return p.window(series[0].Cols())
+}
+
+// NewWindowData cuts each of given time series in windows (see WindowParameters) and return Data of all the windows
+// in chronological order, series after series. Windows never span two series.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     NewWindowData([| 1 |], &WindowParameters{Lookback: 2, Horizon: 1}) = {X: | 1 2 |, Y: | 3 |}
+//                    | 2 |                                                    | 2 3 |     | 4 |
+//                    | 3 |
+//                    | 4 |
+func NewWindowData(series []*matrix.Matrix, p *WindowParameters) (data *Data, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	w, err := checkSeries(series, p)
+	if err != nil {
+		return nil, err
+	}
+	var x, y [][]float64
+	for _, s := range series {
+		x, y = w.rows(s.Raw(), 0, w.count(s.Rows()), x, y)
+	}
+	if len(x) == 0 {
+		return nil, fmt.Errorf("series are too short for lookback %d and horizon %d", p.Lookback, p.Horizon)
+	}
+	return newDataRaw(x, y, nil)
+}
+
+// NewWindowDataset cuts each of given time series in windows as NewWindowData does and splits windows of each series
+// chronologically: the earliest ones go to train Data, the next ones - to tests Data and the latest ones - to valid
+// Data, so no part sees the future of the next one. Train and tests sizes are taken by DataSplitParameters, valid Data
+// gets the rest. Windows which outputs overlap outputs of the next part are dropped. Don't use Dataset.Shuffle on
+// result, it mixes the parts.
+//
+// Throws ErrCreate error.
+func NewWindowDataset(series []*matrix.Matrix, p *WindowParameters, split *DataSplitParameters) (ds *Dataset, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if split == nil {
+		return nil, fmt.Errorf("no parameters provided for splitting: %v", split)
+	}
+	w, err := checkSeries(series, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var parts [3]struct{ x, y [][]float64 }
+	purge := w.purge()
+	for i, s := range series {
+		count := w.count(s.Rows())
+		usable := count - 2*purge
+		trainSize, testsSize := split.TrainPercent.GetI(usable), split.TestsPercent.GetI(usable)
+		if trainSize < 1 || testsSize < 1 || usable-trainSize-testsSize < 1 {
+			return nil, fmt.Errorf("not enough windows in %d'th series to split: %d", i, count)
+		}
+		logger.Debugf("split %d windows of %d'th series: train %d, tests %d, valid %d, purged %d", count, i,
+			trainSize, testsSize, usable-trainSize-testsSize, 2*purge)
+
+		bounds := []int{0, trainSize, trainSize + purge, trainSize + purge + testsSize, trainSize + 2*purge + testsSize, count}
+		raw := s.Raw()
+		for j := range parts {
+			parts[j].x, parts[j].y = w.rows(raw, bounds[2*j], bounds[2*j+1], parts[j].x, parts[j].y)
+		}
+	}
+
+	var data [3]*Data
+	for j, part := range parts {
+		if data[j], err = newDataRaw(part.x, part.y, nil); err != nil {
+			return nil, err
+		}
+	}
+	return NewDataset(data[0], data[1], data[2])
+}
@@ -0,0 +1,179 @@
+package dataset
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/percent"
+	"testing"
+)
+
+// newSeries creates series of one column with values 1, 2, ..., steps
+func newSeries(t *testing.T, steps int) *matrix.Matrix {
+	values := make([]float64, steps)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	return testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: steps, Cols: 1, Values: values})
+}
+
+func TestNewWindowData(t *testing.T) {
+	exogenous := testfactories.MatrixParameters{Rows: 4, Cols: 2, Values: []float64{1, 10, 2, 20, 3, 30, 4, 40}}
+	tests := []struct {
+		testutils.Base
+		series     []testfactories.MatrixParameters
+		parameters *WindowParameters
+		expected   DataParameters
+	}{
+		{
+			Base: testutils.Base{Name: "one step"},
+			series: []testfactories.MatrixParameters{
+				{Rows: 4, Cols: 1, Values: []float64{1, 2, 3, 4}},
+			},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{1, 2, 2, 3}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{3, 4}},
+			},
+		},
+		{
+			Base: testutils.Base{Name: "multi step, stride"},
+			series: []testfactories.MatrixParameters{
+				{Rows: 10, Cols: 1, Values: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+			},
+			parameters: &WindowParameters{Lookback: 3, Horizon: 2, Stride: 2},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 3, Cols: 3, Values: []float64{1, 2, 3, 3, 4, 5, 5, 6, 7}},
+				Y: testfactories.MatrixParameters{Rows: 3, Cols: 2, Values: []float64{4, 5, 6, 7, 8, 9}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "all columns are targets"},
+			series:     []testfactories.MatrixParameters{exogenous},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 4, Values: []float64{1, 10, 2, 20, 2, 20, 3, 30}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{3, 30, 4, 40}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "exogenous"},
+			series:     []testfactories.MatrixParameters{exogenous},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1, Exogenous: []int{1}},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 4, Values: []float64{1, 10, 2, 20, 2, 20, 3, 30}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{3, 4}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "future exogenous, targets"},
+			series:     []testfactories.MatrixParameters{exogenous},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1, Targets: []int{0}, Exogenous: []int{1}, FutureExogenous: true},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 5, Values: []float64{1, 10, 2, 20, 30, 2, 20, 3, 30, 40}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{3, 4}},
+			},
+		},
+		{
+			Base: testutils.Base{Name: "several series, too short series"},
+			series: []testfactories.MatrixParameters{
+				{Rows: 3, Cols: 1, Values: []float64{1, 2, 3}},
+				{Rows: 2, Cols: 1, Values: []float64{5, 6}},
+				{Rows: 3, Cols: 1, Values: []float64{10, 20, 30}},
+			},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1},
+			expected: DataParameters{
+				X: testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{1, 2, 10, 20}},
+				Y: testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{3, 30}},
+			},
+		},
+		{
+			Base:       testutils.Base{Name: "too short series, error", Err: ErrCreate},
+			series:     []testfactories.MatrixParameters{{Rows: 2, Cols: 1, Values: []float64{1, 2}}},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1},
+		},
+		{
+			Base: testutils.Base{Name: "cols mismatch, error", Err: ErrCreate},
+			series: []testfactories.MatrixParameters{
+				{Rows: 3, Cols: 1, Values: []float64{1, 2, 3}},
+				exogenous,
+			},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1},
+		},
+		{
+			Base:       testutils.Base{Name: "zero horizon, error", Err: ErrCreate},
+			series:     []testfactories.MatrixParameters{exogenous},
+			parameters: &WindowParameters{Lookback: 2},
+		},
+		{
+			Base:       testutils.Base{Name: "target is exogenous, error", Err: ErrCreate},
+			series:     []testfactories.MatrixParameters{exogenous},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1, Targets: []int{0}, Exogenous: []int{0}},
+		},
+		{
+			Base:       testutils.Base{Name: "no targets, error", Err: ErrCreate},
+			series:     []testfactories.MatrixParameters{exogenous},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1, Exogenous: []int{0, 1}},
+		},
+		{
+			Base:       testutils.Base{Name: "column out of range, error", Err: ErrCreate},
+			series:     []testfactories.MatrixParameters{exogenous},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1, Exogenous: []int{2}},
+		},
+		{
+			Base:       testutils.Base{Name: "no series, error", Err: ErrCreate},
+			parameters: &WindowParameters{Lookback: 2, Horizon: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			series := make([]*matrix.Matrix, len(tc.series))
+			for i, parameters := range tc.series {
+				series[i] = testfactories.NewMatrix(t, parameters)
+			}
+			data, err := NewWindowData(series, tc.parameters)
+			if tc.Err != nil {
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			expected := newData(t, tc.expected)
+			require.True(t, expected.X.Equal(data.X), data.String())
+			require.True(t, expected.Y.Equal(data.Y), data.String())
+		})
+	}
+}
+
+func TestNewWindowDataset(t *testing.T) {
+	split := &DataSplitParameters{TrainPercent: percent.Percent50, TestsPercent: percent.Percent30}
+	parameters := &WindowParameters{Lookback: 2, Horizon: 3}
+
+	// 20 windows of each series: 2 windows dropped between parts, 8 train, 4 tests, 4 valid windows
+	ds, err := NewWindowDataset([]*matrix.Matrix{newSeries(t, 24), newSeries(t, 24)}, parameters, split)
+	require.NoError(t, err)
+	require.Equal(t, 16, ds.Train.X.Rows())
+	require.Equal(t, 8, ds.Tests.X.Rows())
+	require.Equal(t, 8, ds.Valid.X.Rows())
+
+	row := func(m *matrix.Matrix, i int) []float64 {
+		return m.Raw()[i]
+	}
+	require.Equal(t, []float64{1, 2}, row(ds.Train.X, 0))
+	require.Equal(t, []float64{10, 11, 12}, row(ds.Train.Y, 7))
+	require.Equal(t, []float64{11, 12}, row(ds.Tests.X, 0))
+	require.Equal(t, []float64{16, 17, 18}, row(ds.Tests.Y, 3))
+	require.Equal(t, []float64{17, 18}, row(ds.Valid.X, 0))
+	require.Equal(t, []float64{22, 23, 24}, row(ds.Valid.Y, 3))
+	require.Equal(t, []float64{1, 2}, row(ds.Train.X, 8)) // the second series
+
+	// the latest outputs of each part precede the earliest outputs of the next one
+	require.Less(t, ds.Train.Y.Max(), ds.Tests.Y.Min())
+	require.Less(t, ds.Tests.Y.Max(), ds.Valid.Y.Min())
+
+	_, err = NewWindowDataset([]*matrix.Matrix{newSeries(t, 24), newSeries(t, 8)}, parameters, split)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = NewWindowDataset([]*matrix.Matrix{newSeries(t, 24)}, parameters, nil)
+	require.ErrorIs(t, err, ErrCreate)
+}
@@ -0,0 +1,157 @@
+package scaler
+
+import (
+	"fmt"
+	"nn/internal/data/dataset"
+	"nn/internal/nn"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+var _ nn.Transform = (*DataScaler)(nil)
+
+// DataScaler holds scalers of Data inputs and outputs, nil scaler leaves corresponding values unchanged. Categorical
+// inputs (see dataset.Data.SetCategorical) are never scaled. DataScaler implements nn.Transform, so it can be attached
+// to network (see net.Network.SetScaler) and stored alongside it by WriteJSON.
+type DataScaler struct {
+	Inputs  *Scaler `json:"inputs,omitempty"`
+	Outputs *Scaler `json:"outputs,omitempty"`
+}
+
+// Fit fits inputs and outputs scalers by given Data.
+//
+// Throws ErrFit error.
+func (s *DataScaler) Fit(data *dataset.Data) (err error) {
+	if s == nil {
+		return wraperr.NewWrapErr(ErrFit, fmt.Errorf("no scaler provided: %v", s))
+	} else if data == nil {
+		return wraperr.NewWrapErr(ErrFit, fmt.Errorf("no data provided: %v", data))
+	}
+
+	if s.Inputs != nil {
+		if err = s.Inputs.Fit(data.X, data.Categorical...); err != nil {
+			return fmt.Errorf("error fitting inputs scaler: %w", err)
+		}
+	}
+	if s.Outputs != nil {
+		if err = s.Outputs.Fit(data.Y); err != nil {
+			return fmt.Errorf("error fitting outputs scaler: %w", err)
+		}
+	}
+	return nil
+}
+
+// TransformInputs return scaled copy of given inputs.
+//
+// Throws ErrTransform error.
+func (s *DataScaler) TransformInputs(x *matrix.Matrix) (*matrix.Matrix, error) {
+	if s == nil || s.Inputs == nil {
+		return x.Copy(), nil
+	}
+	return s.Inputs.Transform(x)
+}
+
+// TransformOutputs return scaled copy of given outputs.
+//
+// Throws ErrTransform error.
+func (s *DataScaler) TransformOutputs(y *matrix.Matrix) (*matrix.Matrix, error) {
+	if s == nil || s.Outputs == nil {
+		return y.Copy(), nil
+	}
+	return s.Outputs.Transform(y)
+}
+
+// InverseOutputs return copy of given scaled outputs (e.g. network predictions) restored to source values.
+//
+// Throws ErrTransform error.
+func (s *DataScaler) InverseOutputs(y *matrix.Matrix) (*matrix.Matrix, error) {
+	if s == nil || s.Outputs == nil {
+		return y.Copy(), nil
+	}
+	return s.Outputs.InverseTransform(y)
+}
+
+// Transform return Data with scaled inputs and outputs and the same weights, source Data stay untouched.
+//
+// Throws ErrTransform error.
+func (s *DataScaler) Transform(data *dataset.Data) (res *dataset.Data, err error) {
+	if data == nil {
+		return nil, wraperr.NewWrapErr(ErrTransform, fmt.Errorf("no data provided: %v", data))
+	}
+
+	x, err := s.TransformInputs(data.X)
+	if err != nil {
+		return nil, fmt.Errorf("error scaling inputs: %w", err)
+	}
+	y, err := s.TransformOutputs(data.Y)
+	if err != nil {
+		return nil, fmt.Errorf("error scaling outputs: %w", err)
+	}
+	if res, err = dataset.NewData(x, y); err != nil {
+		return nil, wraperr.NewWrapErr(ErrTransform, err)
+	}
+	if data.W != nil {
+		res.W = data.W.Copy()
+	}
+	res.Categorical = append([]int(nil), data.Categorical...)
+	if len(res.Categorical) == 0 {
+		res.Categorical = nil
+	}
+	return res, nil
+}
+
+// FitDataset fits DataScaler by train Data of given Dataset and return Dataset with scaled train, tests and valid
+// Data, so tests and valid Data don't leak into fitted values.
+//
+// Throws ErrFit and ErrTransform errors.
+func (s *DataScaler) FitDataset(ds *dataset.Dataset) (res *dataset.Dataset, err error) {
+	defer logger.CatchErr(&err)
+
+	if ds == nil {
+		return nil, wraperr.NewWrapErr(ErrFit, fmt.Errorf("no dataset provided: %v", ds))
+	} else if err = s.Fit(ds.Train); err != nil {
+		return nil, err
+	}
+
+	res = &dataset.Dataset{}
+	for _, part := range []struct {
+		name      string
+		data      *dataset.Data
+		processed **dataset.Data
+	}{{"train", ds.Train, &res.Train}, {"tests", ds.Tests, &res.Tests}, {"valid", ds.Valid, &res.Valid}} {
+		if *part.processed, err = s.Transform(part.data); err != nil {
+			return nil, fmt.Errorf("error scaling %s data: %w", part.name, err)
+		}
+	}
+	return res, nil
+}
+
+// Copy return deep copy of DataScaler
+func (s *DataScaler) Copy() *DataScaler {
+	if s == nil {
+		return nil
+	}
+	return &DataScaler{Inputs: s.Inputs.Copy(), Outputs: s.Outputs.Copy()}
+}
+
+// Equal return true if given nn.Transform is DataScaler with equal inputs and outputs scalers (see Scaler.Equal)
+func (s *DataScaler) Equal(transform nn.Transform) bool {
+	scaler, ok := transform.(*DataScaler)
+	if !ok && transform != nil {
+		return false
+	} else if s == nil || scaler == nil {
+		return s == scaler
+	}
+	return s.Inputs.Equal(scaler.Inputs) && s.Outputs.Equal(scaler.Outputs)
+}
+
+// EqualApprox same as Equal, but it compares fitted values using some epsilon
+func (s *DataScaler) EqualApprox(transform nn.Transform) bool {
+	scaler, ok := transform.(*DataScaler)
+	if !ok && transform != nil {
+		return false
+	} else if s == nil || scaler == nil {
+		return s == scaler
+	}
+	return s.Inputs.EqualApprox(scaler.Inputs) && s.Outputs.EqualApprox(scaler.Outputs)
+}
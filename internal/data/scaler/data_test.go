@@ -0,0 +1,74 @@
+package scaler
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"nn/internal/data/dataset"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+func newData(t *testing.T, rows int, x, y []float64) *dataset.Data {
+	data, err := dataset.NewData(
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: rows, Cols: len(x) / rows, Values: x}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: rows, Cols: len(y) / rows, Values: y}),
+	)
+	require.NoError(t, err)
+	return data
+}
+
+func TestDataScaler_FitDataset(t *testing.T) {
+	train := newData(t, 3, []float64{0, 1, 1, 2, 2, 0}, []float64{10, 20, 30})
+	require.NoError(t, train.SetCategorical(1))
+	weights, err := vector.NewVector([]float64{1, 2, 3})
+	require.NoError(t, err)
+	require.NoError(t, train.SetWeights(weights))
+	ds, err := dataset.NewDataset(train, newData(t, 1, []float64{4, 1}, []float64{40}), newData(t, 1, []float64{-2, 0}, []float64{0}))
+	require.NoError(t, err)
+
+	inputs, err := NewMinMax(0, 1)
+	require.NoError(t, err)
+	s := &DataScaler{Inputs: inputs}
+	scaled, err := s.FitDataset(ds)
+	require.NoError(t, err)
+
+	// scaler is fitted by train data only, categorical column, outputs and weights are not scaled
+	require.Equal(t, []int{1}, scaled.Train.Categorical)
+	require.True(t, weights.Equal(scaled.Train.W))
+	require.Equal(t, []float64{0, 1, 0.5, 2, 1, 0}, scaled.Train.X.RawFlat())
+	require.Equal(t, []float64{10, 20, 30}, scaled.Train.Y.RawFlat())
+	require.Equal(t, []float64{2, 1}, scaled.Tests.X.RawFlat())
+	require.Equal(t, []float64{-1, 0}, scaled.Valid.X.RawFlat())
+	require.Equal(t, []float64{0, 1, 1, 2, 2, 0}, ds.Train.X.RawFlat())
+
+	outputs, err := New(Standard)
+	require.NoError(t, err)
+	s.Outputs = outputs
+	scaled, err = s.FitDataset(ds)
+	require.NoError(t, err)
+	restored, err := s.InverseOutputs(scaled.Tests.Y)
+	require.NoError(t, err)
+	require.True(t, ds.Tests.Y.EqualApprox(restored))
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	decoded := &DataScaler{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.True(t, s.Equal(decoded))
+	require.True(t, s.EqualApprox(s.Copy()))
+	require.False(t, s.Equal(&DataScaler{Inputs: inputs}))
+	require.False(t, s.Equal(nil))
+	require.True(t, (*DataScaler)(nil).Equal(nil))
+
+	// fitted values are compared with epsilon by EqualApprox only
+	shifted := s.Copy()
+	shifted.Inputs.Center = shifted.Inputs.Center.AddNum(1e-12)
+	require.False(t, s.Equal(shifted))
+	require.True(t, s.EqualApprox(shifted))
+
+	_, err = s.FitDataset(nil)
+	require.ErrorIs(t, err, ErrFit)
+	_, err = s.Transform(nil)
+	require.ErrorIs(t, err, ErrTransform)
+}
@@ -0,0 +1,62 @@
+package scaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"nn/pkg/wraperr"
+)
+
+// WriteJSON writes fitted DataScaler to w as JSON of its fields, so it can be stored alongside the network it is
+// attached to and restored by ReadJSON.
+//
+// Throws ErrExport error.
+func (s *DataScaler) WriteJSON(w io.Writer) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExport, &err)
+
+	if s == nil {
+		return fmt.Errorf("no scaler provided: %v", s)
+	} else if w == nil {
+		return fmt.Errorf("no writer provided: %v", w)
+	}
+	if err = s.checkFitted(); err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(s)
+}
+
+// ReadJSON creates DataScaler from JSON read from r (see WriteJSON) and checks its fitted values.
+//
+// Throws ErrCreate error.
+func ReadJSON(r io.Reader) (s *DataScaler, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if r == nil {
+		return nil, fmt.Errorf("no reader provided: %v", r)
+	}
+	s = &DataScaler{}
+	if err = json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+	if err = s.checkFitted(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkFitted checks fitted values of not nil inputs and outputs scalers
+func (s *DataScaler) checkFitted() error {
+	for _, part := range []struct {
+		name   string
+		scaler *Scaler
+	}{{"inputs", s.Inputs}, {"outputs", s.Outputs}} {
+		if part.scaler == nil {
+			continue
+		} else if err := part.scaler.checkFitted(); err != nil {
+			return fmt.Errorf("error checking %s scaler: %w", part.name, err)
+		}
+	}
+	return nil
+}
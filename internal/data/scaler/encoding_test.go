@@ -0,0 +1,63 @@
+package scaler
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"nn/internal/testutils"
+	"testing"
+)
+
+func TestDataScaler_JSON(t *testing.T) {
+	data := newData(t, 3, []float64{1, 10, 2, 20, 4, 30}, []float64{5, 6, 7})
+	inputs, err := New(Robust)
+	require.NoError(t, err)
+	outputs, err := NewMinMax(-1, 1)
+	require.NoError(t, err)
+	s := &DataScaler{Inputs: inputs, Outputs: outputs}
+	require.NoError(t, s.Fit(data))
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, s.WriteJSON(buf))
+	read, err := ReadJSON(buf)
+	require.NoError(t, err)
+	require.True(t, s.Equal(read))
+	expected, err := s.Transform(data)
+	require.NoError(t, err)
+	actual, err := read.Transform(data)
+	require.NoError(t, err)
+	require.True(t, expected.Equal(actual))
+
+	inputsOnly := &DataScaler{Inputs: inputs.Copy()}
+	buf.Reset()
+	require.NoError(t, inputsOnly.WriteJSON(buf))
+	read, err = ReadJSON(buf)
+	require.NoError(t, err)
+	require.True(t, inputsOnly.Equal(read))
+
+	notFitted, err := New(Standard)
+	require.NoError(t, err)
+	require.ErrorIs(t, (&DataScaler{Inputs: notFitted}).WriteJSON(new(bytes.Buffer)), ErrExport)
+	require.ErrorIs(t, s.WriteJSON(nil), ErrExport)
+
+	tests := []struct {
+		testutils.Base
+		in string
+	}{
+		{Base: testutils.Base{Name: "not json", Err: ErrCreate}, in: "scaler"},
+		{Base: testutils.Base{Name: "unknown kind", Err: ErrCreate},
+			in: `{"inputs":{"kind":"log","center":[0],"scale":[1]}}`},
+		{Base: testutils.Base{Name: "not fitted", Err: ErrCreate}, in: `{"inputs":{"kind":"standard"}}`},
+		{Base: testutils.Base{Name: "sizes mismatch", Err: ErrCreate},
+			in: `{"inputs":{"kind":"standard","center":[0,1],"scale":[1]}}`},
+		{Base: testutils.Base{Name: "zero scale", Err: ErrCreate},
+			in: `{"outputs":{"kind":"standard","center":[0],"scale":[0]}}`},
+		{Base: testutils.Base{Name: "wrong bounds", Err: ErrCreate},
+			in: `{"outputs":{"kind":"min-max","low":1,"high":1,"center":[0],"scale":[1]}}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := ReadJSON(bytes.NewBufferString(tc.in))
+			require.ErrorIs(t, err, tc.Err)
+		})
+	}
+}
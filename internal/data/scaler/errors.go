@@ -0,0 +1,10 @@
+package scaler
+
+import "errors"
+
+var (
+	ErrCreate    = errors.New("can not create scaler")
+	ErrFit       = errors.New("can not fit scaler")
+	ErrTransform = errors.New("can not transform data")
+	ErrExport    = errors.New("can not export scaler")
+)
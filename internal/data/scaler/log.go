@@ -0,0 +1,5 @@
+package scaler
+
+import "nn/pkg/mylog"
+
+var logger = mylog.NewLogger("internal/data/scaler")
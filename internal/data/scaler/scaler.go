@@ -0,0 +1,242 @@
+// Package scaler provides feature scaling of Matrix columns and Data: standardization, min-max normalization and
+// robust scaling by median and interquartile range.
+package scaler
+
+import (
+	"fmt"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+)
+
+// Kind of Scaler defines how Center and Scale are fitted
+type Kind string
+
+const (
+	Standard Kind = "standard" // Center is mean and Scale is standard deviation of column
+	MinMax   Kind = "min-max"  // column is mapped from [min; max] to [Low; High]
+	Robust   Kind = "robust"   // Center is median and Scale is interquartile range of column
+)
+
+// Scaler transforms each column x of Matrix to (x - Center) / Scale, where Center and Scale are fitted by Fit. Columns
+// of equal values are only shifted (their Scale is 1), MinMax Scaler shifts them to Low. Scaler is serialized to JSON
+// as its fields.
+type Scaler struct {
+	Kind   Kind           `json:"kind"`
+	Low    float64        `json:"low,omitempty"`
+	High   float64        `json:"high,omitempty"`
+	Center *vector.Vector `json:"center,omitempty"`
+	Scale  *vector.Vector `json:"scale,omitempty"`
+}
+
+// New creates not fitted Scaler of given kind, MinMax Scaler maps values to [0; 1] (see NewMinMax).
+//
+// Throws ErrCreate error.
+func New(kind Kind) (s *Scaler, err error) {
+	switch kind {
+	case Standard, Robust:
+		return &Scaler{Kind: kind}, nil
+	case MinMax:
+		return NewMinMax(0, 1)
+	}
+	return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("unknown scaler kind: %q", kind))
+}
+
+// NewMinMax creates not fitted MinMax Scaler mapping values of each column from [min; max] to [low; high].
+//
+// Throws ErrCreate error.
+func NewMinMax(low, high float64) (s *Scaler, err error) {
+	if low >= high {
+		return nil, wraperr.NewWrapErr(ErrCreate, fmt.Errorf("low bound must be less than high bound: %v >= %v", low, high))
+	}
+	return &Scaler{Kind: MinMax, Low: low, High: high}, nil
+}
+
+// Fit computes Center and Scale of each column of given Matrix. Given columns to skip are left unchanged by
+// transformations, e.g. categorical columns.
+//
+// Throws ErrFit error.
+//
+// Example:
+//     Standard.Fit(| 1 10 |) gives Center = [2 20], Scale = [0.816 8.16]
+//                  | 2 20 |
+//                  | 3 30 |
+func (s *Scaler) Fit(x *matrix.Matrix, skip ...int) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrFit, &err)
+
+	if s == nil {
+		return fmt.Errorf("no scaler provided: %v", s)
+	} else if x == nil {
+		return fmt.Errorf("no values provided: %v", x)
+	}
+
+	var center, scale *vector.Vector
+	switch s.Kind {
+	case Standard:
+		if center, err = x.AvgAxed(matrix.Vertical); err != nil {
+			return err
+		}
+		scale, err = x.StdAxed(matrix.Vertical)
+	case MinMax:
+		var max *vector.Vector
+		if center, err = x.MinAxed(matrix.Vertical); err != nil {
+			return err
+		} else if max, err = x.MaxAxed(matrix.Vertical); err != nil {
+			return err
+		}
+		scale, err = max.Sub(center)
+	case Robust:
+		var q25, q75 *vector.Vector
+		if center, err = x.MedianAxed(matrix.Vertical); err != nil {
+			return err
+		} else if q25, err = x.QuantileAxed(0.25, matrix.Vertical); err != nil {
+			return err
+		} else if q75, err = x.QuantileAxed(0.75, matrix.Vertical); err != nil {
+			return err
+		}
+		scale, err = q75.Sub(q25)
+	default:
+		return fmt.Errorf("unknown scaler kind: %q", s.Kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	centers, scales := center.Raw(), scale.Raw()
+	for i := range scales {
+		switch {
+		case scales[i] == 0 && s.Kind == MinMax:
+			scales[i] = 1
+			centers[i] -= s.Low
+		case scales[i] == 0:
+			scales[i] = 1
+		case s.Kind == MinMax:
+			// (x - min) / (max - min) * (high - low) + low = (x - (min - low * spread)) / spread
+			scales[i] /= s.High - s.Low
+			centers[i] -= s.Low * scales[i]
+		}
+	}
+	for _, col := range skip {
+		if col < 0 || col >= len(scales) {
+			return fmt.Errorf("skipped column must be in [0; %d): %d", len(scales), col)
+		}
+		centers[col], scales[col] = 0, 1
+	}
+
+	if s.Center, err = vector.NewVector(centers); err != nil {
+		return err
+	} else if s.Scale, err = vector.NewVector(scales); err != nil {
+		return err
+	}
+	logger.Debugf("fitted %s scaler: center %s, scale %s", s.Kind, s.Center.ShortString(), s.Scale.ShortString())
+	return nil
+}
+
+// checkFitted checks fitted values of Scaler, e.g. read from JSON
+func (s *Scaler) checkFitted() error {
+	switch s.Kind {
+	case Standard, Robust:
+	case MinMax:
+		if s.Low >= s.High {
+			return fmt.Errorf("low bound must be less than high bound: %v >= %v", s.Low, s.High)
+		}
+	default:
+		return fmt.Errorf("unknown scaler kind: %q", s.Kind)
+	}
+	if s.Center == nil || s.Scale == nil {
+		return fmt.Errorf("%s scaler is not fitted", s.Kind)
+	} else if s.Center.Size() != s.Scale.Size() {
+		return fmt.Errorf("center and scale sizes mismatch: %d != %d", s.Center.Size(), s.Scale.Size())
+	}
+	for i, scale := range s.Scale.Raw() {
+		if scale == 0 {
+			return fmt.Errorf("scale of %d'th column is zero", i)
+		}
+	}
+	return nil
+}
+
+func (s *Scaler) check(x *matrix.Matrix) error {
+	if s == nil {
+		return fmt.Errorf("no scaler provided: %v", s)
+	} else if s.Center == nil || s.Scale == nil {
+		return fmt.Errorf("%s scaler is not fitted", s.Kind)
+	} else if x == nil {
+		return fmt.Errorf("no values provided: %v", x)
+	} else if x.Cols() != s.Center.Size() || x.Cols() != s.Scale.Size() {
+		return fmt.Errorf("cols count mismatches fitted columns: %d != %d", x.Cols(), s.Center.Size())
+	}
+	return nil
+}
+
+// Transform return copy of given Matrix with each column x scaled to (x - Center) / Scale.
+//
+// Throws ErrTransform error.
+func (s *Scaler) Transform(x *matrix.Matrix) (res *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrTransform, &err)
+
+	if err = s.check(x); err != nil {
+		return nil, err
+	} else if res, err = x.SubRow(s.Center); err != nil {
+		return nil, err
+	}
+	return res.DivRow(s.Scale)
+}
+
+// InverseTransform return copy of given scaled Matrix with each column x restored to x * Scale + Center.
+//
+// Throws ErrTransform error.
+func (s *Scaler) InverseTransform(x *matrix.Matrix) (res *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrTransform, &err)
+
+	if err = s.check(x); err != nil {
+		return nil, err
+	} else if res, err = x.MulRow(s.Scale); err != nil {
+		return nil, err
+	}
+	return res.AddRow(s.Center)
+}
+
+// FitTransform fits Scaler by given Matrix and return transformed Matrix, see Fit and Transform.
+//
+// Throws ErrFit and ErrTransform errors.
+func (s *Scaler) FitTransform(x *matrix.Matrix, skip ...int) (*matrix.Matrix, error) {
+	if err := s.Fit(x, skip...); err != nil {
+		return nil, err
+	}
+	return s.Transform(x)
+}
+
+// Copy return deep copy of Scaler
+func (s *Scaler) Copy() *Scaler {
+	if s == nil {
+		return nil
+	}
+	res := *s
+	if s.Center != nil {
+		res.Center = s.Center.Copy()
+	}
+	if s.Scale != nil {
+		res.Scale = s.Scale.Copy()
+	}
+	return &res
+}
+
+// Equal return true if scalers have the same kind, bounds and fitted values
+func (s *Scaler) Equal(scaler *Scaler) bool {
+	return s.equal(scaler, (*vector.Vector).Equal)
+}
+
+// EqualApprox same as Equal, but it compares fitted values using some epsilon
+func (s *Scaler) EqualApprox(scaler *Scaler) bool {
+	return s.equal(scaler, (*vector.Vector).EqualApprox)
+}
+
+func (s *Scaler) equal(scaler *Scaler, equal func(a, b *vector.Vector) bool) bool {
+	if s == nil || scaler == nil {
+		return s == scaler
+	}
+	return s.Kind == scaler.Kind && s.Low == scaler.Low && s.High == scaler.High &&
+		equal(s.Center, scaler.Center) && equal(s.Scale, scaler.Scale)
+}
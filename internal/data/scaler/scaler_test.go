@@ -0,0 +1,113 @@
+package scaler
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"testing"
+)
+
+func TestScaler_Fit(t *testing.T) {
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 3, Values: []float64{
+		1, 10, 5,
+		2, 30, 5,
+		3, 20, 5,
+		10, 40, 5,
+	}})
+	tests := []struct {
+		testutils.Base
+		scaler      func() (*Scaler, error)
+		skip        []int
+		center      []float64
+		scale       []float64
+		transformed []float64
+	}{
+		{
+			Base:        testutils.Base{Name: "standard"},
+			scaler:      func() (*Scaler, error) { return New(Standard) },
+			center:      []float64{4, 25, 5},
+			scale:       []float64{3.5355339059327378, 11.180339887498949, 1},
+			transformed: []float64{-0.848528137423857, -1.341640786499874, 0},
+		},
+		{
+			Base:        testutils.Base{Name: "min-max"},
+			scaler:      func() (*Scaler, error) { return NewMinMax(-1, 1) },
+			center:      []float64{5.5, 25, 6},
+			scale:       []float64{4.5, 15, 1},
+			transformed: []float64{-1, -1, -1},
+		},
+		{
+			Base:        testutils.Base{Name: "robust"},
+			scaler:      func() (*Scaler, error) { return New(Robust) },
+			center:      []float64{2.5, 25, 5},
+			scale:       []float64{3, 15, 1},
+			transformed: []float64{-0.5, -1, 0},
+		},
+		{
+			Base:        testutils.Base{Name: "skip columns"},
+			scaler:      func() (*Scaler, error) { return New(Standard) },
+			skip:        []int{0, 2},
+			center:      []float64{0, 25, 0},
+			scale:       []float64{1, 11.180339887498949, 1},
+			transformed: []float64{1, -1.341640786499874, 5},
+		},
+		{
+			Base:   testutils.Base{Name: "wrong skip column", Err: ErrFit},
+			scaler: func() (*Scaler, error) { return New(Standard) },
+			skip:   []int{3},
+		},
+		{
+			Base:   testutils.Base{Name: "unknown kind", Err: ErrFit},
+			scaler: func() (*Scaler, error) { return &Scaler{Kind: "log"}, nil },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			s, err := tc.scaler()
+			require.NoError(t, err)
+			transformed, err := s.FitTransform(x, tc.skip...)
+			if tc.Err != nil {
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.InDeltaSlice(t, tc.center, s.Center.Raw(), 1e-12)
+			require.InDeltaSlice(t, tc.scale, s.Scale.Raw(), 1e-12)
+			first, err := transformed.GetRow(0)
+			require.NoError(t, err)
+			require.InDeltaSlice(t, tc.transformed, first.Raw(), 1e-12)
+
+			restored, err := s.InverseTransform(transformed)
+			require.NoError(t, err)
+			require.True(t, x.EqualApprox(restored))
+
+			// fitted scaler is serialized and restored
+			data, err := json.Marshal(s)
+			require.NoError(t, err)
+			decoded := &Scaler{}
+			require.NoError(t, json.Unmarshal(data, decoded))
+			require.True(t, s.Equal(decoded), string(data))
+			require.True(t, s.Equal(s.Copy()))
+		})
+	}
+}
+
+func TestScaler_Errors(t *testing.T) {
+	_, err := New("log")
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = NewMinMax(1, 1)
+	require.ErrorIs(t, err, ErrCreate)
+
+	s, err := New(Standard)
+	require.NoError(t, err)
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2})
+	_, err = s.Transform(x)
+	require.ErrorIs(t, err, ErrTransform) // not fitted
+	require.NoError(t, s.Fit(x))
+	_, err = s.InverseTransform(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}))
+	require.ErrorIs(t, err, ErrTransform)
+	require.ErrorIs(t, s.Fit(nil), ErrFit)
+	require.ErrorIs(t, (*Scaler)(nil).Fit(x), ErrFit)
+}
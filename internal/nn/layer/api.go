@@ -8,6 +8,8 @@ import (
 
 type ILayer interface {
 	nn.IModule
+	// Forward and Backward results may be owned by layer's operations (see operation.IOperation), so they are valid
+	// until the next call of the same method and must not be modified.
 	Forward(x *matrix.Matrix) (*matrix.Matrix, error)
 	Backward(dy *matrix.Matrix) (*matrix.Matrix, error)
 	ApplyOptim(optimizer operation.Optimizer) error
@@ -17,10 +19,19 @@ type ILayer interface {
 }
 
 var layers = map[nn.Kind]struct{}{
-	DenseLayer: {}, DenseDropLayer: {},
+	DenseLayer: {}, DenseDropLayer: {}, ResidualDenseLayer: {},
+	RNNLayer: {}, LSTMLayer: {}, GRULayer: {},
+	Conv1DLayer: {}, MaxPool1DLayer: {}, AvgPool1DLayer: {}, FlattenLayer: {},
+	RBFLayer: {}, MultiHeadAttentionLayer: {}, CategoricalEmbeddingLayer: {},
 }
 
 func IsLayer(kind nn.Kind) bool {
 	_, ok := layers[kind]
 	return ok
 }
+
+// IsRecurrent return true if kind is one of recurrent layers kinds (processing packed sequences).
+func IsRecurrent(kind nn.Kind) bool {
+	_, ok := cells[kind]
+	return ok
+}
@@ -0,0 +1,204 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"nn/internal/nn/operation"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+// attentionParams return query, key, value and output weights of multi-head attention layer.
+func attentionParams(t *testing.T, features, model, size int) []*matrix.Matrix {
+	return []*matrix.Matrix{
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: features, Cols: model}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: features, Cols: model}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: features, Cols: model}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: model, Cols: size}),
+	}
+}
+
+func newTestAttentionLayer(t *testing.T, params []*matrix.Matrix, attention *AttentionParameters) ILayer {
+	return newLayer(t, MultiHeadAttentionLayer, params[0], params[1], params[2], params[3], attention)
+}
+
+func TestNewMultiHeadAttentionLayer(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		params    []*matrix.Matrix
+		attention *AttentionParameters
+	}{
+		{
+			Base:      testutils.Base{Name: "valid"},
+			params:    attentionParams(t, 3, 4, 5),
+			attention: &AttentionParameters{Steps: 6, Heads: 2},
+		},
+		{
+			Base:   testutils.Base{Name: "no attention parameters", Err: ErrCreate},
+			params: attentionParams(t, 3, 4, 5),
+		},
+		{
+			Base:      testutils.Base{Name: "model size is not divisible by heads count", Err: ErrCreate},
+			params:    attentionParams(t, 3, 4, 5),
+			attention: &AttentionParameters{Steps: 6, Heads: 3},
+		},
+		{
+			Base: testutils.Base{Name: "key shape mismatch", Err: ErrCreate},
+			params: []*matrix.Matrix{
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2}),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 5}),
+			},
+			attention: &AttentionParameters{Steps: 6, Heads: 2},
+		},
+		{
+			Base: testutils.Base{Name: "output rows mismatch", Err: ErrCreate},
+			params: []*matrix.Matrix{
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 5}),
+			},
+			attention: &AttentionParameters{Steps: 6, Heads: 2},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := NewMultiHeadAttentionLayer(tc.params[0], tc.params[1], tc.params[2], tc.params[3], tc.attention)
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, l.Is(MultiHeadAttentionLayer))
+			require.Equal(t, 18, l.InputsCount())
+			require.Equal(t, 30, l.Size())
+		})
+	}
+}
+
+func TestAttentionLayer_Forward(t *testing.T) {
+	one := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{1}})
+	params := []*matrix.Matrix{one, one, one, one}
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{1, 2}})
+
+	// q = k = v = x, scores are | 1 2 |
+	//                           | 2 4 |
+	e := math.Exp
+	testcases := []struct {
+		testutils.Base
+		causal   bool
+		expected []float64
+	}{
+		{
+			Base:     testutils.Base{Name: "full attention"},
+			expected: []float64{(e(1) + 2*e(2)) / (e(1) + e(2)), (e(2) + 2*e(4)) / (e(2) + e(4))},
+		},
+		{
+			Base:     testutils.Base{Name: "causal attention"},
+			causal:   true,
+			expected: []float64{1, (e(2) + 2*e(4)) / (e(2) + e(4))},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l := newTestAttentionLayer(t, params, &AttentionParameters{Steps: 2, Heads: 1, Causal: tc.causal})
+			y, err := l.Forward(x)
+			require.NoError(t, err)
+			require.InDeltaSlice(t, tc.expected, y.RawFlat(), 1e-12)
+		})
+	}
+
+	l := newTestAttentionLayer(t, params, &AttentionParameters{Steps: 2, Heads: 1})
+	_, err := l.Forward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3}))
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestAttentionLayer_Backward(t *testing.T) {
+	const (
+		features, model, size, steps, batch = 3, 4, 2, 3, 2
+		eps                                 = 1e-6
+		tolerance                           = 1e-5
+	)
+
+	testcases := []struct {
+		testutils.Base
+		attention *AttentionParameters
+	}{
+		{Base: testutils.Base{Name: "single head"}, attention: &AttentionParameters{Steps: steps, Heads: 1}},
+		{Base: testutils.Base{Name: "two heads"}, attention: &AttentionParameters{Steps: steps, Heads: 2}},
+		{Base: testutils.Base{Name: "causal"}, attention: &AttentionParameters{Steps: steps, Heads: 2, Causal: true}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			params := attentionParams(t, features, model, size)
+			l := newTestAttentionLayer(t, params, tc.attention)
+			x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: steps * features})
+			r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: steps * size})
+
+			testutils.LinearLoss(t, l.Forward, x, r)
+			dx, err := l.Backward(r)
+			require.NoError(t, err)
+			grads := collectGradients(t, l)
+			require.Len(t, grads, len(params))
+
+			for i := range x.RawFlat() {
+				numeric := testutils.NumericGradient(t, func(x *matrix.Matrix) float64 {
+					return testutils.LinearLoss(t, l.Forward, x, r)
+				}, x, i, eps)
+				require.InDelta(t, numeric, dx.RawFlat()[i], tolerance, "input gradient %d", i)
+			}
+
+			for pi, p := range params {
+				for i := range p.RawFlat() {
+					numeric := testutils.NumericGradient(t, func(p *matrix.Matrix) float64 {
+						shifted := append([]*matrix.Matrix(nil), params...)
+						shifted[pi] = p
+						return testutils.LinearLoss(t, newTestAttentionLayer(t, shifted, tc.attention).Forward, x, r)
+					}, p, i, eps)
+					require.InDelta(t, numeric, grads[pi].RawFlat()[i], tolerance, "parameter %d, value %d", pi, i)
+				}
+			}
+		})
+	}
+}
+
+func TestAttentionLayer_Copy(t *testing.T) {
+	l := newTestAttentionLayer(t, attentionParams(t, 2, 2, 2), &AttentionParameters{Steps: 3, Heads: 2, Causal: true})
+	_, err := l.Forward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 6}))
+	require.NoError(t, err)
+
+	c := l.Copy()
+	require.True(t, l.Equal(c))
+	require.True(t, c.EqualApprox(l))
+
+	_, err = l.Backward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 6}))
+	require.NoError(t, err)
+	require.NoError(t, l.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad)
+	}))
+	require.False(t, l.Equal(c))
+}
+
+func TestBuilder_BuildAttention(t *testing.T) {
+	b, err := NewBuilder(MultiHeadAttentionLayer)
+	require.NoError(t, err)
+	l, err := b.InputsCount(3).NeuronsCount(4).ParamInitType(operation.GlorotInit).
+		Attention(&AttentionParameters{Steps: 5, Heads: 2}).Build()
+	require.NoError(t, err)
+	require.True(t, l.Is(MultiHeadAttentionLayer))
+	require.Equal(t, 15, l.InputsCount())
+	require.Equal(t, 20, l.Size())
+
+	b, err = NewBuilder(MultiHeadAttentionLayer)
+	require.NoError(t, err)
+	_, err = b.InputsCount(3).NeuronsCount(4).Build()
+	require.ErrorIs(t, err, ErrBuilder)
+}
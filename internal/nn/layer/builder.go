@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"nn/internal/nn"
 	"nn/internal/nn/operation"
+	"nn/pkg/mmath/matrix"
 	"nn/pkg/mmath/vector"
 	"nn/pkg/percent"
 	"nn/pkg/wraperr"
@@ -15,11 +16,26 @@ type Builder struct {
 	bias       operation.IOperation
 	activation operation.IOperation
 	dropout    operation.IOperation
+	projection operation.IOperation
+	gates      []*GateParameters
+	sequence   *SequenceParameters
+	window     *operation.WindowParameters
+	attention  *AttentionParameters
+	embedding  *EmbeddingParameters
+	centers    *matrix.Matrix
+	widths     *vector.Vector
+
+	inputsCount   int
+	neuronsCount  int
+	centersCount  int
+	paramInitType operation.ParamInitType
+	precision     nn.Precision
 
 	weightBuilder     *operation.Builder
 	biasBuilder       *operation.Builder
 	activationBuilder *operation.Builder
 	dropoutBuilder    *operation.Builder
+	projectionBuilder *operation.Builder
 
 	resetAfterBuild bool
 }
@@ -46,12 +62,17 @@ func NewBuilder(kind nn.Kind) (b *Builder, err error) {
 	if err != nil {
 		return nil, err
 	}
+	pb, err := operation.NewBuilder(operation.WeightMultiply)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Builder{
-		kind:           kind,
-		weightBuilder:  wb,
-		biasBuilder:    bb,
-		dropoutBuilder: db,
+		kind:              kind,
+		weightBuilder:     wb,
+		biasBuilder:       bb,
+		dropoutBuilder:    db,
+		projectionBuilder: pb,
 	}, nil
 }
 
@@ -65,6 +86,10 @@ func (b *Builder) Build() (l ILayer, err error) {
 			b.bias = nil
 			b.activation = nil
 			b.dropout = nil
+			b.projection = nil
+			b.gates = nil
+			b.centers = nil
+			b.widths = nil
 		}
 	}()
 
@@ -72,8 +97,36 @@ func (b *Builder) Build() (l ILayer, err error) {
 		return nil, ErrNil
 	}
 
+	if l, err = b.build(); err != nil || b.precision == nn.Float64 {
+		return l, err
+	}
+	logger.Debugf("convert layer %s to %s precision", b.kind, b.precision)
+	return ConvertPrecision(l, b.precision)
+}
+
+func (b *Builder) build() (ILayer, error) {
+	if IsRecurrent(b.kind) {
+		return b.buildRecurrent()
+	}
+	switch b.kind {
+	case Conv1DLayer:
+		return b.buildConv1D()
+	case MaxPool1DLayer:
+		return NewMaxPool1DLayer(b.window)
+	case AvgPool1DLayer:
+		return NewAvgPool1DLayer(b.window)
+	case FlattenLayer:
+		return NewFlattenLayer(b.inputsCount)
+	case RBFLayer:
+		return b.buildRBF()
+	case MultiHeadAttentionLayer:
+		return b.buildAttention()
+	case CategoricalEmbeddingLayer:
+		return b.buildEmbedding()
+	}
+
 	logger.Debugf("build layer %s", b.kind)
-	err = b.prepare()
+	err := b.prepare()
 	if err != nil {
 		return nil, err
 	}
@@ -87,15 +140,21 @@ func (b *Builder) Build() (l ILayer, err error) {
 		operations = []operation.IOperation{b.weight, b.bias, b.activation}
 	case DenseDropLayer:
 		operations = []operation.IOperation{b.weight, b.bias, b.activation, b.dropout}
+	case ResidualDenseLayer:
+		operations = []operation.IOperation{b.weight, b.bias, b.activation}
 	default:
 		return nil, fmt.Errorf("unknown layer: %s", b.kind)
 	}
-	return &Layer{
+	built := &Layer{
 		kind:        b.kind,
 		operations:  operations,
 		inputsCount: inputs,
 		size:        neurons,
-	}, nil
+	}
+	if b.kind == ResidualDenseLayer {
+		return &ResidualLayer{Layer: built, projection: b.projection}, nil
+	}
+	return built, nil
 }
 
 func (b *Builder) Weight(weight operation.IOperation) *Builder {
@@ -118,9 +177,66 @@ func (b *Builder) Dropout(dropout operation.IOperation) *Builder {
 	return b
 }
 
+func (b *Builder) Projection(projection operation.IOperation) *Builder {
+	b.projection = projection
+	return b
+}
+
+// Gates sets parameters of recurrent layer's gates, their count and order must match layer's constructor.
+func (b *Builder) Gates(gates ...*GateParameters) *Builder {
+	b.gates = gates
+	return b
+}
+
+func (b *Builder) Sequence(sequence *SequenceParameters) *Builder {
+	b.sequence = sequence
+	return b
+}
+
+// Window sets window parameters of convolution and pooling layers, inputs count of such layers is defined by window.
+func (b *Builder) Window(window *operation.WindowParameters) *Builder {
+	b.window = window
+	return b
+}
+
+// Attention sets attention parameters of multi-head attention layer. Inputs count is features count of single
+// step, neurons count is model size and output features count of single step.
+func (b *Builder) Attention(attention *AttentionParameters) *Builder {
+	b.attention = attention
+	return b
+}
+
+// Embedding sets categorical columns of embedding layer and sizes of their embeddings. Inputs count is count of all
+// the input columns, categorical and numeric.
+func (b *Builder) Embedding(embedding *EmbeddingParameters) *Builder {
+	b.embedding = embedding
+	return b
+}
+
+// Centers sets centers of radial basis function layer (centers count x inputs count), e.g. found by
+// dataset.Data.KMeans. Random centers are used if not provided (see CentersCount).
+func (b *Builder) Centers(centers *matrix.Matrix) *Builder {
+	b.centers = centers
+	return b
+}
+
+// CentersCount sets count of random centers of radial basis function layer, used if no centers provided.
+func (b *Builder) CentersCount(centersCount int) *Builder {
+	b.centersCount = centersCount
+	return b
+}
+
+// Widths sets widths of radial basis function layer, computed by RBFWidths if not provided.
+func (b *Builder) Widths(widths *vector.Vector) *Builder {
+	b.widths = widths
+	return b
+}
+
 func (b *Builder) InputsCount(inputsCount int) *Builder {
+	b.inputsCount = inputsCount
 	b.weightBuilder.InputsCount(inputsCount)
 	b.biasBuilder.InputsCount(inputsCount)
+	b.projectionBuilder.InputsCount(inputsCount)
 	if b.activationBuilder != nil {
 		b.activationBuilder.InputsCount(inputsCount)
 	}
@@ -128,8 +244,10 @@ func (b *Builder) InputsCount(inputsCount int) *Builder {
 }
 
 func (b *Builder) NeuronsCount(neuronsCount int) *Builder {
+	b.neuronsCount = neuronsCount
 	b.weightBuilder.NeuronsCount(neuronsCount)
 	b.biasBuilder.NeuronsCount(neuronsCount)
+	b.projectionBuilder.NeuronsCount(neuronsCount)
 	if b.activationBuilder != nil {
 		b.activationBuilder.NeuronsCount(neuronsCount)
 	}
@@ -162,8 +280,16 @@ func (b *Builder) SigmoidCoeffsRange(sigmoidCoeffsRange *operation.SigmoidCoeffs
 }
 
 func (b *Builder) ParamInitType(paramInitType operation.ParamInitType) *Builder {
+	b.paramInitType = paramInitType
 	b.weightBuilder.ParamInitType(paramInitType)
 	b.biasBuilder.ParamInitType(paramInitType)
+	b.projectionBuilder.ParamInitType(paramInitType)
+	return b
+}
+
+// Precision sets precision layer's operations are converted to after building (see ConvertPrecision).
+func (b *Builder) Precision(precision nn.Precision) *Builder {
+	b.precision = precision
 	return b
 }
 
@@ -177,6 +303,7 @@ func (b *Builder) SetResetAfterBuild(value bool) *Builder {
 	b.weightBuilder.SetResetAfterBuild(value)
 	b.biasBuilder.SetResetAfterBuild(value)
 	b.dropoutBuilder.SetResetAfterBuild(value)
+	b.projectionBuilder.SetResetAfterBuild(value)
 	return b
 }
 
@@ -193,6 +320,15 @@ func (b *Builder) prepare() (err error) {
 		if err != nil {
 			return err
 		}
+	case ResidualDenseLayer:
+		err = b.prepareWBA()
+		if err != nil {
+			return err
+		}
+		b.projection, err = b.getProjection()
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -267,9 +403,9 @@ func (b *Builder) getActivation() (operation.IOperation, error) {
 }
 
 func (b *Builder) getSizes() (inputsCount int, neuronsCount int, err error) {
-	weight, ok := b.weight.(*operation.ParamOperation)
+	weight, ok := b.weight.(operation.IParamOperation)
 	if !ok {
-		return 0, 0, fmt.Errorf("error downcasting weight to *operation.ParamOperation")
+		return 0, 0, fmt.Errorf("error downcasting weight to operation.IParamOperation")
 	}
 	inputs, neurons := weight.Parameter().Size()
 
@@ -277,13 +413,13 @@ func (b *Builder) getSizes() (inputsCount int, neuronsCount int, err error) {
 }
 
 func (b *Builder) checkSizes() (err error) {
-	weight, ok := b.weight.(*operation.ParamOperation)
+	weight, ok := b.weight.(operation.IParamOperation)
 	if !ok {
-		return fmt.Errorf("error casting weight to *operation.ParamOperation")
+		return fmt.Errorf("error casting weight to operation.IParamOperation")
 	}
-	bias, ok := b.bias.(*operation.ParamOperation)
+	bias, ok := b.bias.(operation.IParamOperation)
 	if !ok {
-		return fmt.Errorf("error casting bias to *operation.ParamOperation")
+		return fmt.Errorf("error casting bias to operation.IParamOperation")
 	}
 	neurons := weight.Parameter().Cols()
 	biasNeurons := bias.Parameter().Cols()
@@ -320,3 +456,266 @@ func (b *Builder) getDropout() (operation.IOperation, error) {
 	}
 	return b.dropout, nil
 }
+
+func (b *Builder) getProjection() (operation.IOperation, error) {
+	inputs, neurons, err := b.getSizes()
+	if err != nil {
+		return nil, err
+	}
+	if b.projection == nil || !b.projection.Is(operation.WeightMultiply) {
+		if inputs == neurons {
+			logger.Tracef("no projection provided or provided projection is not %s, using identity", operation.WeightMultiply)
+			return nil, nil
+		}
+		logger.Tracef("no projection provided or provided projection is not %s", operation.WeightMultiply)
+		projection, err := b.projectionBuilder.InputsCount(inputs).NeuronsCount(neurons).Build()
+		if err != nil {
+			return nil, err
+		}
+		if !projection.Is(operation.WeightMultiply) {
+			return nil, fmt.Errorf("built operation is not %s", operation.WeightMultiply)
+		}
+		return projection, nil
+	}
+	projection, ok := b.projection.(operation.IParamOperation)
+	if !ok {
+		return nil, fmt.Errorf("error casting projection to operation.IParamOperation")
+	}
+	if rows, cols := projection.Parameter().Size(); rows != inputs || cols != neurons {
+		return nil, fmt.Errorf("projection shape must match weight shape: %dx%d != %dx%d", rows, cols, inputs, neurons)
+	}
+	return b.projection, nil
+}
+
+func (b *Builder) buildRecurrent() (ILayer, error) {
+	if b.sequence == nil {
+		return nil, fmt.Errorf("no sequence parameters provided: %v", b.sequence)
+	}
+
+	gates := b.gates
+	count := cells[b.kind].gatesCount
+	if gates == nil {
+		logger.Tracef("no gates parameters provided, building %d gates", count)
+		gates = make([]*GateParameters, count)
+		for i := range gates {
+			gate, err := b.buildGateParameters()
+			if err != nil {
+				return nil, fmt.Errorf("error building %d'th gate: %w", i, err)
+			}
+			gates[i] = gate
+		}
+	} else if len(gates) != count {
+		return nil, fmt.Errorf("%s requires %d gates, provided %d", b.kind, count, len(gates))
+	}
+
+	switch b.kind {
+	case RNNLayer:
+		if b.activation == nil && b.activationBuilder == nil {
+			logger.Tracef("no activation provided, using %s", operation.TanhActivation)
+			b.activation = operation.NewTanhActivation()
+		}
+		activation, err := b.getActivation()
+		if err != nil {
+			return nil, err
+		}
+		return NewRNNLayer(gates[0], activation, b.sequence)
+	case LSTMLayer:
+		return NewLSTMLayer(gates[0], gates[1], gates[2], gates[3], b.sequence)
+	case GRULayer:
+		return NewGRULayer(gates[0], gates[1], gates[2], b.sequence)
+	}
+	return nil, fmt.Errorf("unknown layer: %s", b.kind)
+}
+
+func (b *Builder) buildGateParameters() (*GateParameters, error) {
+	build := func(kind nn.Kind, inputs int) (operation.IParamOperation, error) {
+		builder, err := operation.NewBuilder(kind)
+		if err != nil {
+			return nil, err
+		}
+		op, err := builder.InputsCount(inputs).NeuronsCount(b.neuronsCount).ParamInitType(b.paramInitType).Build()
+		if err != nil {
+			return nil, err
+		}
+		casted, ok := op.(operation.IParamOperation)
+		if !ok {
+			return nil, fmt.Errorf("error casting %s to operation.IParamOperation", kind)
+		}
+		return casted, nil
+	}
+
+	weight, err := build(operation.WeightMultiply, b.inputsCount)
+	if err != nil {
+		return nil, err
+	}
+	recurrent, err := build(operation.WeightMultiply, b.neuronsCount)
+	if err != nil {
+		return nil, err
+	}
+	bias, err := build(operation.BiasAdd, b.inputsCount)
+	if err != nil {
+		return nil, err
+	}
+	biasVec, err := bias.Parameter().GetRow(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GateParameters{
+		Weight:    weight.Parameter(),
+		Recurrent: recurrent.Parameter(),
+		Bias:      biasVec,
+	}, nil
+}
+
+func (b *Builder) buildConv1D() (ILayer, error) {
+	if err := b.window.Check(); err != nil {
+		return nil, err
+	}
+	windowCols := b.window.Kernel * b.window.Channels
+	b.weightBuilder.InputsCount(windowCols)
+	b.biasBuilder.InputsCount(windowCols)
+
+	if err := b.prepareWBA(); err != nil {
+		return nil, err
+	}
+	weight, ok := b.weight.(operation.IParamOperation)
+	if !ok {
+		return nil, fmt.Errorf("error casting weight to operation.IParamOperation")
+	}
+	bias, ok := b.bias.(operation.IParamOperation)
+	if !ok {
+		return nil, fmt.Errorf("error casting bias to operation.IParamOperation")
+	}
+	biasVec, err := bias.Parameter().GetRow(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConv1DLayer(weight.Parameter(), biasVec, b.activation, b.window)
+}
+
+func (b *Builder) buildRBF() (ILayer, error) {
+	centers := b.centers
+	if centers == nil {
+		logger.Tracef("no centers provided, building %d random centers", b.centersCount)
+		cb, err := operation.NewBuilder(operation.SquaredDistance)
+		if err != nil {
+			return nil, err
+		}
+		distance, err := cb.InputsCount(b.inputsCount).NeuronsCount(b.centersCount).Build()
+		if err != nil {
+			return nil, err
+		}
+		casted, ok := distance.(operation.IParamOperation)
+		if !ok {
+			return nil, fmt.Errorf("error casting centers to operation.IParamOperation")
+		}
+		centers = casted.Parameter()
+	}
+
+	widths := b.widths
+	if widths == nil {
+		var err error
+		if widths, err = RBFWidths(centers); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.activation == nil && b.activationBuilder == nil {
+		logger.Tracef("no kernel provided, using %s", operation.GaussianActivation)
+		b.activation = operation.NewGaussianActivation()
+	}
+	kernel, err := b.getActivation()
+	if err != nil {
+		return nil, err
+	}
+
+	b.weightBuilder.InputsCount(centers.Rows())
+	b.biasBuilder.InputsCount(centers.Rows())
+	if b.weight, err = b.getWeight(); err != nil {
+		return nil, err
+	}
+	if b.bias, err = b.getBias(); err != nil {
+		return nil, err
+	}
+	weight, ok := b.weight.(operation.IParamOperation)
+	if !ok {
+		return nil, fmt.Errorf("error casting weight to operation.IParamOperation")
+	}
+	bias, ok := b.bias.(operation.IParamOperation)
+	if !ok {
+		return nil, fmt.Errorf("error casting bias to operation.IParamOperation")
+	}
+	biasVec, err := bias.Parameter().GetRow(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRBFLayer(centers, widths, kernel, weight.Parameter(), biasVec)
+}
+
+func (b *Builder) buildAttention() (ILayer, error) {
+	if b.attention == nil {
+		return nil, fmt.Errorf("no attention parameters provided: %v", b.attention)
+	}
+
+	weights := make([]*matrix.Matrix, 4)
+	for i := range weights {
+		wb, err := operation.NewBuilder(operation.WeightMultiply)
+		if err != nil {
+			return nil, err
+		}
+		inputs := b.inputsCount
+		if i == len(weights)-1 {
+			inputs = b.neuronsCount
+		}
+		w, err := wb.InputsCount(inputs).NeuronsCount(b.neuronsCount).ParamInitType(b.paramInitType).Build()
+		if err != nil {
+			return nil, fmt.Errorf("error building %d'th projection: %w", i, err)
+		}
+		casted, ok := w.(operation.IParamOperation)
+		if !ok {
+			return nil, fmt.Errorf("error casting %d'th projection to operation.IParamOperation", i)
+		}
+		weights[i] = casted.Parameter()
+	}
+
+	return NewMultiHeadAttentionLayer(weights[0], weights[1], weights[2], weights[3], b.attention)
+}
+
+func (b *Builder) buildEmbedding() (ILayer, error) {
+	if b.embedding == nil {
+		return nil, fmt.Errorf("no embedding parameters provided: %v", b.embedding)
+	}
+	columns, categories, sizes := b.embedding.Columns, b.embedding.Categories, b.embedding.Sizes
+	if len(categories) != len(columns) {
+		return nil, fmt.Errorf("categorical columns and categories counts mismatches: %d != %d",
+			len(columns), len(categories))
+	} else if len(sizes) != 1 && len(sizes) != len(columns) {
+		return nil, fmt.Errorf("embedding sizes count must be 1 or %d: %d", len(columns), len(sizes))
+	}
+
+	tables := make([]*matrix.Matrix, len(columns))
+	for i := range tables {
+		size := sizes[0]
+		if len(sizes) > 1 {
+			size = sizes[i]
+		}
+		tb, err := operation.NewBuilder(operation.WeightMultiply)
+		if err != nil {
+			return nil, err
+		}
+		table, err := tb.InputsCount(categories[i]).NeuronsCount(size).ParamInitType(b.paramInitType).Build()
+		if err != nil {
+			return nil, fmt.Errorf("error building %d'th table: %w", i, err)
+		}
+		casted, ok := table.(operation.IParamOperation)
+		if !ok {
+			return nil, fmt.Errorf("error casting %d'th table to operation.IParamOperation", i)
+		}
+		tables[i] = casted.Parameter()
+	}
+
+	return NewEmbeddingLayer(b.inputsCount, columns, tables)
+}
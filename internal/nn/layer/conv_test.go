@@ -0,0 +1,269 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+func TestNewConv1DLayer(t *testing.T) {
+	window := &operation.WindowParameters{Length: 6, Channels: 2, Kernel: 3, Stride: 2, Padding: 1}
+	testcases := []struct {
+		testutils.Base
+		weight *matrix.Matrix
+		bias   *vector.Vector
+		window *operation.WindowParameters
+		size   int
+	}{
+		{
+			Base:   testutils.Base{Name: "valid"},
+			weight: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 6, Cols: 4}),
+			bias:   testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+			window: window,
+			size:   3 * 4,
+		},
+		{
+			Base:   testutils.Base{Name: "weight rows mismatch", Err: ErrCreate},
+			weight: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 4}),
+			bias:   testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+			window: window,
+		},
+		{
+			Base:   testutils.Base{Name: "bias size mismatch", Err: ErrCreate},
+			weight: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 6, Cols: 4}),
+			bias:   testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+			window: window,
+		},
+		{
+			Base:   testutils.Base{Name: "no window", Err: ErrCreate},
+			weight: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 6, Cols: 4}),
+			bias:   testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := NewConv1DLayer(tc.weight, tc.bias, operation.NewLinearActivation(), tc.window)
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, l.Is(Conv1DLayer))
+			require.Equal(t, 12, l.InputsCount())
+			require.Equal(t, tc.size, l.Size())
+		})
+	}
+}
+
+func TestWindowLayers_Forward(t *testing.T) {
+	window := &operation.WindowParameters{Length: 4, Channels: 1, Kernel: 2, Stride: 1}
+	poolWindow := &operation.WindowParameters{Length: 4, Channels: 2, Kernel: 2, Stride: 2}
+	testcases := []struct {
+		testutils.Base
+		layer        ILayer
+		in           *matrix.Matrix
+		expected     *matrix.Matrix
+		outGrad      *matrix.Matrix
+		expectedGrad *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "conv1d"},
+			layer: newLayer(t, Conv1DLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{
+					1, -1,
+					2, 1,
+				}}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2, Values: []float64{1, 0}}),
+				operation.NewLinearActivation(),
+				window,
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{1, 2, 3, 4}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 6, Values: []float64{
+				6, 1, 9, 1, 12, 1,
+			}}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 6, Values: []float64{
+				1, 0, 0, 1, 1, 1,
+			}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{
+				1, 1, 1, 3,
+			}}),
+		},
+		{
+			Base:         testutils.Base{Name: "max pool 1d"},
+			layer:        newLayer(t, MaxPool1DLayer, poolWindow),
+			in:           testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 8, Values: []float64{1, 8, 3, 4, 7, 2, 5, 6}}),
+			expected:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{3, 8, 7, 6}}),
+			outGrad:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{1, 2, 3, 4}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 8, Values: []float64{0, 2, 1, 0, 3, 0, 0, 4}}),
+		},
+		{
+			Base:         testutils.Base{Name: "avg pool 1d"},
+			layer:        newLayer(t, AvgPool1DLayer, poolWindow),
+			in:           testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 8, Values: []float64{1, 8, 3, 4, 7, 2, 5, 6}}),
+			expected:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{2, 6, 6, 4}}),
+			outGrad:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{2, 4, 6, 8}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 8, Values: []float64{1, 2, 1, 2, 3, 4, 3, 4}}),
+		},
+		{
+			Base:         testutils.Base{Name: "flatten"},
+			layer:        newLayer(t, FlattenLayer, 3),
+			in:           testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{1, 2, 3}}),
+			expected:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{1, 2, 3}}),
+			outGrad:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{4, 5, 6}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{4, 5, 6}}),
+		},
+		{
+			Base:  testutils.Base{Name: "wrong input", Err: ErrExec},
+			layer: newLayer(t, MaxPool1DLayer, poolWindow),
+			in:    testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 6}),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			out, err := tc.layer.Forward(tc.in)
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, out.Equal(tc.expected), "wrong output: %s", out.String())
+
+			grad, err := tc.layer.Backward(tc.outGrad)
+			require.NoError(t, err)
+			require.True(t, grad.Equal(tc.expectedGrad), "wrong input gradient: %s", grad.String())
+		})
+	}
+}
+
+func TestConv1DLayer_Backward(t *testing.T) {
+	const (
+		eps       = 1e-6
+		tolerance = 1e-5
+	)
+	window := &operation.WindowParameters{Length: 5, Channels: 2, Kernel: 3, Stride: 2, Padding: 1}
+	weight := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 6, Cols: 3})
+	bias := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3})
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 10})
+	r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 9})
+
+	newConv := func(params []*matrix.Matrix) ILayer {
+		b, err := params[1].GetRow(0)
+		require.NoError(t, err)
+		return newLayer(t, Conv1DLayer, params[0], b, operation.NewTanhActivation(), window)
+	}
+	params := []*matrix.Matrix{weight, bias}
+	l := newConv(params)
+
+	testutils.LinearLoss(t, l.Forward, x, r)
+	dx, err := l.Backward(r)
+	require.NoError(t, err)
+	grads := collectGradients(t, l)
+	require.Len(t, grads, len(params))
+
+	for i := range x.RawFlat() {
+		numeric := testutils.NumericGradient(t, func(x *matrix.Matrix) float64 {
+			return testutils.LinearLoss(t, l.Forward, x, r)
+		}, x, i, eps)
+		require.InDelta(t, numeric, dx.RawFlat()[i], tolerance, "input gradient %d", i)
+	}
+
+	for pi, p := range params {
+		for i := range p.RawFlat() {
+			numeric := testutils.NumericGradient(t, func(p *matrix.Matrix) float64 {
+				changed := append([]*matrix.Matrix(nil), params...)
+				changed[pi] = p
+				return testutils.LinearLoss(t, newConv(changed).Forward, x, r)
+			}, p, i, eps)
+			require.InDelta(t, numeric, grads[pi].RawFlat()[i], tolerance, "parameter %d, value %d", pi, i)
+		}
+	}
+}
+
+func TestBuilder_BuildWindowed(t *testing.T) {
+	window := &operation.WindowParameters{Length: 8, Channels: 2, Kernel: 3, Stride: 1}
+	testcases := []struct {
+		testutils.Base
+		builder func(t *testing.T) *Builder
+		kind    nn.Kind
+		inputs  int
+		size    int
+	}{
+		{
+			Base: testutils.Base{Name: "conv1d"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(Conv1DLayer)
+				require.NoError(t, err)
+				return b.Window(window).NeuronsCount(4).ParamInitType(operation.GlorotInit).
+					ActivationKind(operation.TanhActivation)
+			},
+			kind:   Conv1DLayer,
+			inputs: 16,
+			size:   6 * 4,
+		},
+		{
+			Base: testutils.Base{Name: "max pool 1d"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(MaxPool1DLayer)
+				require.NoError(t, err)
+				return b.Window(window)
+			},
+			kind:   MaxPool1DLayer,
+			inputs: 16,
+			size:   6 * 2,
+		},
+		{
+			Base: testutils.Base{Name: "avg pool 1d"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(AvgPool1DLayer)
+				require.NoError(t, err)
+				return b.Window(window)
+			},
+			kind:   AvgPool1DLayer,
+			inputs: 16,
+			size:   6 * 2,
+		},
+		{
+			Base: testutils.Base{Name: "flatten"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(FlattenLayer)
+				require.NoError(t, err)
+				return b.InputsCount(12)
+			},
+			kind:   FlattenLayer,
+			inputs: 12,
+			size:   12,
+		},
+		{
+			Base: testutils.Base{Name: "conv1d without window", Err: ErrBuilder},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(Conv1DLayer)
+				require.NoError(t, err)
+				return b.NeuronsCount(4)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := tc.builder(t).Build()
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, l.Is(tc.kind))
+			require.Equal(t, tc.inputs, l.InputsCount())
+			require.Equal(t, tc.size, l.Size())
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+// embeddingTables return tables of embedding layer with given categories counts and embedding size.
+func embeddingTables(t *testing.T, size int, categories ...int) []*matrix.Matrix {
+	tables := make([]*matrix.Matrix, len(categories))
+	for i, count := range categories {
+		tables[i] = testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: count, Cols: size})
+	}
+	return tables
+}
+
+func TestNewEmbeddingLayer(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		inputs  int
+		columns []int
+		tables  []*matrix.Matrix
+	}{
+		{Base: testutils.Base{Name: "valid"}, inputs: 4, columns: []int{2, 0}, tables: embeddingTables(t, 3, 5, 2)},
+		{
+			Base:   testutils.Base{Name: "no categorical columns", Err: ErrCreate},
+			inputs: 4,
+		},
+		{
+			Base:    testutils.Base{Name: "tables count mismatch", Err: ErrCreate},
+			inputs:  4,
+			columns: []int{2, 0},
+			tables:  embeddingTables(t, 3, 5),
+		},
+		{
+			Base:    testutils.Base{Name: "column out of inputs", Err: ErrCreate},
+			inputs:  4,
+			columns: []int{4, 0},
+			tables:  embeddingTables(t, 3, 5, 2),
+		},
+		{
+			Base:    testutils.Base{Name: "duplicated column", Err: ErrCreate},
+			inputs:  4,
+			columns: []int{0, 0},
+			tables:  embeddingTables(t, 3, 5, 2),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := NewEmbeddingLayer(tc.inputs, tc.columns, tc.tables)
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, l.Is(CategoricalEmbeddingLayer))
+			require.Equal(t, 4, l.InputsCount())
+			require.Equal(t, 8, l.Size())
+		})
+	}
+}
+
+func TestEmbeddingLayer_Forward(t *testing.T) {
+	tables := []*matrix.Matrix{
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2, Values: []float64{1, 2, 3, 4, 5, 6}}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{7, 8}}),
+	}
+	l := newLayer(t, CategoricalEmbeddingLayer, 4, []int{1, 3}, tables)
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 4, Values: []float64{
+		0.5, 2, -1, 1,
+		1.5, 0, -2, 0,
+	}})
+
+	y, err := l.Forward(x)
+	require.NoError(t, err)
+	require.Equal(t, []float64{
+		0.5, -1, 5, 6, 8,
+		1.5, -2, 1, 2, 7,
+	}, y.RawFlat())
+
+	for _, code := range []float64{3, -1, 0.5} {
+		wrong := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{0, code, 0, 0}})
+		_, err = l.Forward(wrong)
+		require.ErrorIs(t, err, ErrExec)
+	}
+}
+
+func TestEmbeddingLayer_Backward(t *testing.T) {
+	const (
+		eps       = 1e-6
+		tolerance = 1e-5
+	)
+
+	columns := []int{2, 0}
+	tables := embeddingTables(t, 3, 4, 3)
+	l := newLayer(t, CategoricalEmbeddingLayer, 4, columns, tables)
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4, Values: []float64{
+		2, 0.1, 3, 0.2,
+		0, 0.3, 3, 0.4,
+		2, 0.5, 1, 0.6,
+	}})
+	r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: l.Size()})
+
+	testutils.LinearLoss(t, l.Forward, x, r)
+	dx, err := l.Backward(r)
+	require.NoError(t, err)
+	grads := collectGradients(t, l)
+	require.Len(t, grads, len(tables))
+
+	for _, col := range []int{1, 3} {
+		for row := 0; row < x.Rows(); row++ {
+			i := row*x.Cols() + col
+			numeric := testutils.NumericGradient(t, func(x *matrix.Matrix) float64 {
+				return testutils.LinearLoss(t, l.Forward, x, r)
+			}, x, i, eps)
+			require.InDelta(t, numeric, dx.RawFlat()[i], tolerance, "input gradient %d", i)
+		}
+	}
+	for _, col := range columns {
+		gradient, err := dx.GetCol(col)
+		require.NoError(t, err)
+		require.Equal(t, []float64{0, 0, 0}, gradient.Raw())
+	}
+
+	// only used codes have gradients: 1 and 3 in first table, 0 and 2 in second one
+	used := [][]int{{1, 3}, {0, 2}}
+	for ti, table := range tables {
+		require.Equal(t, len(used[ti]), grads[ti].Rows())
+		for p, code := range used[ti] {
+			for k := 0; k < table.Cols(); k++ {
+				i := code*table.Cols() + k
+				numeric := testutils.NumericGradient(t, func(table *matrix.Matrix) float64 {
+					shifted := append([]*matrix.Matrix(nil), tables...)
+					shifted[ti] = table
+					return testutils.LinearLoss(t, newLayer(t, CategoricalEmbeddingLayer, 4, columns, shifted).Forward, x, r)
+				}, table, i, eps)
+				actual, err := grads[ti].Get(p, k)
+				require.NoError(t, err)
+				require.InDelta(t, numeric, actual, tolerance, "table %d, code %d, value %d", ti, code, k)
+			}
+		}
+	}
+}
+
+func TestEmbeddingLayer_ApplyOptim(t *testing.T) {
+	tables := []*matrix.Matrix{
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 2, Values: []float64{1, 2, 3, 4, 5, 6, 7, 8}}),
+	}
+	l := newLayer(t, CategoricalEmbeddingLayer, 2, []int{0}, tables)
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2, Values: []float64{
+		2, 0,
+		0, 0,
+		2, 0,
+	}})
+	_, err := l.Forward(x)
+	require.NoError(t, err)
+	_, err = l.Backward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 3, Values: []float64{
+		0, 1, 1,
+		0, 1, 2,
+		0, 1, 3,
+	}}))
+	require.NoError(t, err)
+
+	var optimized []*matrix.Matrix
+	require.NoError(t, l.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		optimized = append(optimized, param)
+		return param.Sub(grad)
+	}))
+	require.Len(t, optimized, 1)
+	require.Equal(t, []float64{1, 2, 5, 6}, optimized[0].RawFlat())
+
+	casted, ok := l.(*EmbeddingLayer)
+	require.True(t, ok)
+	require.Equal(t, []float64{0, 0, 3, 4, 3, 2, 7, 8}, casted.Tables()[0].RawFlat())
+}
+
+func TestEmbeddingLayer_Copy(t *testing.T) {
+	l := newLayer(t, CategoricalEmbeddingLayer, 3, []int{1}, embeddingTables(t, 2, 3))
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{0, 1, 0, 0, 2, 0}})
+	_, err := l.Forward(x)
+	require.NoError(t, err)
+
+	c := l.Copy()
+	require.True(t, l.Equal(c))
+	require.True(t, c.EqualApprox(l))
+
+	_, err = l.Backward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 4}))
+	require.NoError(t, err)
+	require.NoError(t, l.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad)
+	}))
+	require.False(t, l.Equal(c))
+}
+
+func TestBuilder_BuildEmbedding(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		embedding *EmbeddingParameters
+		size      int
+	}{
+		{
+			Base:      testutils.Base{Name: "single size"},
+			embedding: &EmbeddingParameters{Columns: []int{0, 3}, Categories: []int{5, 2}, Sizes: []int{3}},
+			size:      8,
+		},
+		{
+			Base:      testutils.Base{Name: "size per column"},
+			embedding: &EmbeddingParameters{Columns: []int{0, 3}, Categories: []int{5, 2}, Sizes: []int{3, 1}},
+			size:      6,
+		},
+		{
+			Base: testutils.Base{Name: "no embedding parameters", Err: ErrBuilder},
+		},
+		{
+			Base:      testutils.Base{Name: "categories count mismatch", Err: ErrBuilder},
+			embedding: &EmbeddingParameters{Columns: []int{0, 3}, Categories: []int{5}, Sizes: []int{3}},
+		},
+		{
+			Base:      testutils.Base{Name: "sizes count mismatch", Err: ErrBuilder},
+			embedding: &EmbeddingParameters{Columns: []int{0, 3}, Categories: []int{5, 2}, Sizes: []int{3, 1, 2}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			b, err := NewBuilder(CategoricalEmbeddingLayer)
+			require.NoError(t, err)
+			l, err := b.InputsCount(4).Embedding(tc.embedding).Build()
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, l.Is(CategoricalEmbeddingLayer))
+			require.Equal(t, 4, l.InputsCount())
+			require.Equal(t, tc.size, l.Size())
+		})
+	}
+}
@@ -41,7 +41,149 @@ func Create(kind nn.Kind, args ...interface{}) (layer ILayer, err error) {
 		} else {
 			return NewDenseDropLayer(w, b, a, d)
 		}
+	case ResidualDenseLayer:
+		if len(args) < 3 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required at least %d, provided %d", ResidualDenseLayer, 3, len(args))
+		} else if w, ok := args[0].(*matrix.Matrix); !ok {
+			return nil, fmt.Errorf("first argument is not *matrix.Matrix: %T", args[0])
+		} else if b, ok := args[1].(*vector.Vector); !ok {
+			return nil, fmt.Errorf("second argument is not *vector.Vector: %T", args[1])
+		} else if a, ok := args[2].(operation.IOperation); !ok {
+			return nil, fmt.Errorf("third argument is not operation.IOperation: %T", args[2])
+		} else if len(args) < 4 || args[3] == nil {
+			return NewResidualDenseLayer(w, b, a, nil)
+		} else if p, ok := args[3].(*matrix.Matrix); !ok {
+			return nil, fmt.Errorf("fourth argument is not *matrix.Matrix: %T", args[3])
+		} else {
+			return NewResidualDenseLayer(w, b, a, p)
+		}
+	case RNNLayer:
+		if len(args) < 3 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", RNNLayer, 3, len(args))
+		} else if g, ok := args[0].(*GateParameters); !ok {
+			return nil, fmt.Errorf("first argument is not *layer.GateParameters: %T", args[0])
+		} else if a, ok := args[1].(operation.IOperation); !ok {
+			return nil, fmt.Errorf("second argument is not operation.IOperation: %T", args[1])
+		} else if s, ok := args[2].(*SequenceParameters); !ok {
+			return nil, fmt.Errorf("third argument is not *layer.SequenceParameters: %T", args[2])
+		} else {
+			return NewRNNLayer(g, a, s)
+		}
+	case LSTMLayer:
+		if len(args) < 5 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", LSTMLayer, 5, len(args))
+		}
+		gates, err := castGateParameters(args[:4])
+		if err != nil {
+			return nil, err
+		} else if s, ok := args[4].(*SequenceParameters); !ok {
+			return nil, fmt.Errorf("fifth argument is not *layer.SequenceParameters: %T", args[4])
+		} else {
+			return NewLSTMLayer(gates[0], gates[1], gates[2], gates[3], s)
+		}
+	case GRULayer:
+		if len(args) < 4 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", GRULayer, 4, len(args))
+		}
+		gates, err := castGateParameters(args[:3])
+		if err != nil {
+			return nil, err
+		} else if s, ok := args[3].(*SequenceParameters); !ok {
+			return nil, fmt.Errorf("fourth argument is not *layer.SequenceParameters: %T", args[3])
+		} else {
+			return NewGRULayer(gates[0], gates[1], gates[2], s)
+		}
+	case Conv1DLayer:
+		if len(args) < 4 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", Conv1DLayer, 4, len(args))
+		} else if w, ok := args[0].(*matrix.Matrix); !ok {
+			return nil, fmt.Errorf("first argument is not *matrix.Matrix: %T", args[0])
+		} else if b, ok := args[1].(*vector.Vector); !ok {
+			return nil, fmt.Errorf("second argument is not *vector.Vector: %T", args[1])
+		} else if a, ok := args[2].(operation.IOperation); !ok {
+			return nil, fmt.Errorf("third argument is not operation.IOperation: %T", args[2])
+		} else if wp, ok := args[3].(*operation.WindowParameters); !ok {
+			return nil, fmt.Errorf("fourth argument is not *operation.WindowParameters: %T", args[3])
+		} else {
+			return NewConv1DLayer(w, b, a, wp)
+		}
+	case MaxPool1DLayer, AvgPool1DLayer:
+		if len(args) < 1 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", kind, 1, len(args))
+		} else if wp, ok := args[0].(*operation.WindowParameters); !ok {
+			return nil, fmt.Errorf("first argument is not *operation.WindowParameters: %T", args[0])
+		} else if kind == MaxPool1DLayer {
+			return NewMaxPool1DLayer(wp)
+		} else {
+			return NewAvgPool1DLayer(wp)
+		}
+	case RBFLayer:
+		if len(args) < 5 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", RBFLayer, 5, len(args))
+		} else if c, ok := args[0].(*matrix.Matrix); !ok {
+			return nil, fmt.Errorf("first argument is not *matrix.Matrix: %T", args[0])
+		} else if wd, ok := args[1].(*vector.Vector); !ok {
+			return nil, fmt.Errorf("second argument is not *vector.Vector: %T", args[1])
+		} else if k, ok := args[2].(operation.IOperation); !ok {
+			return nil, fmt.Errorf("third argument is not operation.IOperation: %T", args[2])
+		} else if w, ok := args[3].(*matrix.Matrix); !ok {
+			return nil, fmt.Errorf("fourth argument is not *matrix.Matrix: %T", args[3])
+		} else if b, ok := args[4].(*vector.Vector); !ok {
+			return nil, fmt.Errorf("fifth argument is not *vector.Vector: %T", args[4])
+		} else {
+			return NewRBFLayer(c, wd, k, w, b)
+		}
+	case MultiHeadAttentionLayer:
+		if len(args) < 5 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d",
+				MultiHeadAttentionLayer, 5, len(args))
+		}
+		weights := make([]*matrix.Matrix, 4)
+		for i := range weights {
+			w, ok := args[i].(*matrix.Matrix)
+			if !ok {
+				return nil, fmt.Errorf("%d'th argument is not *matrix.Matrix: %T", i, args[i])
+			}
+			weights[i] = w
+		}
+		if a, ok := args[4].(*AttentionParameters); !ok {
+			return nil, fmt.Errorf("fifth argument is not *AttentionParameters: %T", args[4])
+		} else {
+			return NewMultiHeadAttentionLayer(weights[0], weights[1], weights[2], weights[3], a)
+		}
+	case CategoricalEmbeddingLayer:
+		if len(args) < 3 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", CategoricalEmbeddingLayer, 3, len(args))
+		} else if n, ok := args[0].(int); !ok {
+			return nil, fmt.Errorf("first argument is not int: %T", args[0])
+		} else if c, ok := args[1].([]int); !ok {
+			return nil, fmt.Errorf("second argument is not []int: %T", args[1])
+		} else if t, ok := args[2].([]*matrix.Matrix); !ok {
+			return nil, fmt.Errorf("third argument is not []*matrix.Matrix: %T", args[2])
+		} else {
+			return NewEmbeddingLayer(n, c, t)
+		}
+	case FlattenLayer:
+		if len(args) < 1 {
+			return nil, fmt.Errorf("not enough arguments to create %q, required %d, provided %d", FlattenLayer, 1, len(args))
+		} else if n, ok := args[0].(int); !ok {
+			return nil, fmt.Errorf("first argument is not int: %T", args[0])
+		} else {
+			return NewFlattenLayer(n)
+		}
 	}
 
 	return nil, fmt.Errorf("unknown layer: %s", kind)
 }
+
+func castGateParameters(args []interface{}) ([]*GateParameters, error) {
+	res := make([]*GateParameters, len(args))
+	for i, arg := range args {
+		g, ok := arg.(*GateParameters)
+		if !ok {
+			return nil, fmt.Errorf("%d'th argument is not *layer.GateParameters: %T", i+1, arg)
+		}
+		res[i] = g
+	}
+	return res, nil
+}
@@ -11,8 +11,9 @@ import (
 )
 
 const (
-	DenseLayer     nn.Kind = "dense layer"
-	DenseDropLayer nn.Kind = "densedrop layer"
+	DenseLayer         nn.Kind = "dense layer"
+	DenseDropLayer     nn.Kind = "densedrop layer"
+	ResidualDenseLayer nn.Kind = "residual dense layer"
 )
 
 func NewDenseLayer(weight *matrix.Matrix, bias *vector.Vector, activation operation.IOperation) (l ILayer, err error) {
@@ -94,3 +95,50 @@ func NewDenseDropLayer(
 
 	return casted, nil
 }
+
+// NewResidualDenseLayer creates dense layer with skip-connection (see ResidualLayer). Projection is required only if
+// weight rows count (inputs count) mismatch weight cols count (layer's size), in that case projection must have the
+// same shape as weight. Projection may be nil for identity skip-connection.
+func NewResidualDenseLayer(
+	weight *matrix.Matrix,
+	bias *vector.Vector,
+	activation operation.IOperation,
+	projection *matrix.Matrix,
+) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create residual dense layer")
+
+	l, err = NewDenseLayer(weight, bias, activation)
+	if err != nil {
+		return nil, err
+	}
+
+	casted, ok := l.(*Layer)
+	if !ok {
+		panic("could not cast layer.ILayer to *layer.Layer")
+	}
+	casted.kind = ResidualDenseLayer
+
+	if projection == nil {
+		if weight.Rows() != weight.Cols() {
+			return nil, fmt.Errorf("no projection provided for layer with inputs count mismatching size: %d != %d",
+				weight.Rows(), weight.Cols())
+		}
+		logger.Debug("use identity skip-connection")
+		return &ResidualLayer{Layer: casted}, nil
+	}
+
+	if err = weight.CheckEqualShape(projection); err != nil {
+		return nil, fmt.Errorf("projection shape must match weight shape: %w", err)
+	}
+
+	logger.Debug("use projection skip-connection")
+	p, err := operation.NewWeightOperation(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResidualLayer{Layer: casted, projection: p}, nil
+}
@@ -0,0 +1,78 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+const MultiHeadAttentionLayer nn.Kind = "multi-head attention layer"
+
+// AttentionParameters describes sequence processed by MultiHeadAttentionLayer.
+type AttentionParameters struct {
+	Steps  int  // count of sequence's steps
+	Heads  int  // count of attention heads, model size must be divisible by it
+	Causal bool // if true, step attends only to itself and previous steps
+}
+
+// NewMultiHeadAttentionLayer creates self-attention layer over packed sequence (see PackSequence):
+//     Q = x * Wq, K = x * Wk, V = x * Wv;
+//     head = softmax(Qh * Kh^T / sqrt(dk) + mask) * Vh, for each head h;
+//     y = concat(heads) * Wo,
+//     where x is sequence of single sample (steps x features), Qh, Kh, Vh are h'th heads' cols of Q, K, V, dk is head
+//     size (model size / heads count), mask is -Inf above diagonal for causal attention and 0 otherwise.
+//
+// Weights <query>, <key>, <value> are features x model size, <output> is model size x output features. Input is
+// batch x steps*features, output is batch x steps*output features.
+func NewMultiHeadAttentionLayer(
+	query, key, value, output *matrix.Matrix,
+	attention *AttentionParameters,
+) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create multi-head attention layer")
+	if attention == nil {
+		return nil, fmt.Errorf("no attention parameters provided: %v", attention)
+	} else if attention.Steps < 1 {
+		return nil, fmt.Errorf("negative or zero steps count: %d", attention.Steps)
+	} else if attention.Heads < 1 {
+		return nil, fmt.Errorf("negative or zero heads count: %d", attention.Heads)
+	}
+
+	weights := []*matrix.Matrix{query, key, value, output}
+	operations := make([]operation.IOperation, len(weights))
+	for i, w := range weights {
+		if operations[i], err = operation.NewWeightOperation(w); err != nil {
+			return nil, fmt.Errorf("error creating %d'th projection: %w", i, err)
+		}
+	}
+
+	for i, w := range weights[1:3] {
+		if err = query.CheckEqualShape(w); err != nil {
+			return nil, fmt.Errorf("%d'th projection's shape must match query's shape: %w", i+1, err)
+		}
+	}
+	if query.Cols()%attention.Heads != 0 {
+		return nil, fmt.Errorf("model size must be divisible by heads count: %d %% %d != 0",
+			query.Cols(), attention.Heads)
+	} else if output.Rows() != query.Cols() {
+		return nil, fmt.Errorf("output projection's rows count must match model size: %d != %d",
+			output.Rows(), query.Cols())
+	}
+
+	return &AttentionLayer{
+		kind:        MultiHeadAttentionLayer,
+		query:       operations[0],
+		key:         operations[1],
+		value:       operations[2],
+		output:      operations[3],
+		softmax:     operation.NewSoftmaxActivation(),
+		inputsCount: query.Rows(),
+		modelSize:   query.Cols(),
+		size:        output.Cols(),
+		attention:   *attention,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+)
+
+const (
+	Conv1DLayer    nn.Kind = "conv1d layer"
+	MaxPool1DLayer nn.Kind = "max pool 1d layer"
+	AvgPool1DLayer nn.Kind = "avg pool 1d layer"
+	FlattenLayer   nn.Kind = "flatten layer"
+)
+
+// NewConv1DLayer creates 1D convolution layer over packed signal (see operation.WindowParameters). Each window is
+// unfolded to separate row (see operation.NewIm2Col), so convolution is computed as dense layer with shared weight:
+//     - weight is window.Kernel*window.Channels x filters count;
+//     - bias size is filters count;
+//     - input is batch x window.Length*window.Channels;
+//     - output is batch x OutputLength*filters, packed the same way as input (filters become output channels).
+func NewConv1DLayer(
+	weight *matrix.Matrix,
+	bias *vector.Vector,
+	activation operation.IOperation,
+	window *operation.WindowParameters,
+) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create conv1d layer")
+	if err = window.Check(); err != nil {
+		return nil, err
+	}
+
+	l, err = NewDenseLayer(weight, bias, activation)
+	if err != nil {
+		return nil, err
+	}
+	dense, ok := l.(*Layer)
+	if !ok {
+		panic("could not cast layer.ILayer to *layer.Layer")
+	}
+
+	if weight.Rows() != window.Kernel*window.Channels {
+		return nil, fmt.Errorf("weight rows count must match kernel size * channels count: %d != %d*%d",
+			weight.Rows(), window.Kernel, window.Channels)
+	}
+
+	filters := weight.Cols()
+	outputsCount := window.OutputLength() * filters
+	im2col, err := operation.NewIm2Col(window)
+	if err != nil {
+		return nil, err
+	}
+	reshape, err := operation.NewReshape(filters, outputsCount)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("combine dense layer with im2col and reshape operations")
+	dense.operations = append([]operation.IOperation{im2col}, append(dense.operations, reshape)...)
+	dense.kind = Conv1DLayer
+	dense.inputsCount = window.Length * window.Channels
+	dense.size = outputsCount
+
+	return dense, nil
+}
+
+// NewMaxPool1DLayer creates layer taking maximum of each channel over each window of packed signal
+// (see operation.WindowParameters). Output is batch x OutputLength*window.Channels.
+func NewMaxPool1DLayer(window *operation.WindowParameters) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create max pool 1d layer")
+	if err = window.Check(); err != nil {
+		return nil, err
+	}
+	pool, err := operation.NewMaxPool(window)
+	if err != nil {
+		return nil, err
+	}
+	return newPool1DLayer(MaxPool1DLayer, pool, window)
+}
+
+// NewAvgPool1DLayer creates layer taking average of each channel over each window of packed signal
+// (see operation.WindowParameters). Output is batch x OutputLength*window.Channels.
+func NewAvgPool1DLayer(window *operation.WindowParameters) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create avg pool 1d layer")
+	if err = window.Check(); err != nil {
+		return nil, err
+	}
+	pool, err := operation.NewAvgPool(window)
+	if err != nil {
+		return nil, err
+	}
+	return newPool1DLayer(AvgPool1DLayer, pool, window)
+}
+
+func newPool1DLayer(kind nn.Kind, pool operation.IOperation, window *operation.WindowParameters) (*Layer, error) {
+	return &Layer{
+		kind:        kind,
+		inputsCount: window.Length * window.Channels,
+		size:        window.OutputLength() * window.Channels,
+		operations:  []operation.IOperation{pool},
+	}, nil
+}
+
+// NewFlattenLayer creates layer passing packed signal to dense layers. Packed signal is already flat
+// (batch x length*channels), so layer only checks inputs count and marks boundary of convolution part of network:
+//     y = x;
+//     dx = dy.
+func NewFlattenLayer(inputsCount int) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create flatten layer")
+	reshape, err := operation.NewReshape(inputsCount, inputsCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Layer{
+		kind:        FlattenLayer,
+		inputsCount: inputsCount,
+		size:        inputsCount,
+		operations:  []operation.IOperation{reshape},
+	}, nil
+}
@@ -0,0 +1,81 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+)
+
+const CategoricalEmbeddingLayer nn.Kind = "embedding layer"
+
+// EmbeddingParameters describes categorical inputs of EmbeddingLayer built by Builder.
+type EmbeddingParameters struct {
+	Columns    []int // indices of categorical input columns, e.g. dataset.Data.Categorical
+	Categories []int // categories count of each categorical column, e.g. dataset.Data.Categories()
+	Sizes      []int // embedding size of each categorical column, single size is used for all the columns
+}
+
+// NewEmbeddingLayer creates layer mapping integer-coded categorical input columns to trainable dense vectors:
+//     y[i] = concat(n[i], e[0][x[i][c[0]]], ..., e[k][x[i][c[k]]]),
+//     where n[i] is numeric (not categorical) columns of x[i] in their order, c[j] is j'th of <columns>, e[j] is
+//     j'th of <tables> (categories count x embedding size).
+//
+// Only rows of tables used in the last batch are updated by ApplyOptim. Input gradient of categorical columns is
+// zero. Input is batch x <inputsCount>, output is batch x (numeric columns count + sum of embedding sizes).
+//
+// Throws ErrCreate error.
+func NewEmbeddingLayer(inputsCount int, columns []int, tables []*matrix.Matrix) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create embedding layer")
+	if inputsCount < 1 {
+		return nil, fmt.Errorf("negative or zero inputs count: %d", inputsCount)
+	} else if len(columns) == 0 {
+		return nil, fmt.Errorf("no categorical columns provided: %v", columns)
+	} else if len(columns) != len(tables) {
+		return nil, fmt.Errorf("categorical columns and tables count mismatches: %d != %d", len(columns), len(tables))
+	}
+
+	categorical := make(map[int]struct{}, len(columns))
+	for _, col := range columns {
+		if col < 0 || col >= inputsCount {
+			return nil, fmt.Errorf("categorical column must be in [0; %d): %d", inputsCount, col)
+		} else if _, ok := categorical[col]; ok {
+			return nil, fmt.Errorf("categorical column is duplicated: %d", col)
+		}
+		categorical[col] = struct{}{}
+	}
+	var numeric []int
+	for col := 0; col < inputsCount; col++ {
+		if _, ok := categorical[col]; !ok {
+			numeric = append(numeric, col)
+		}
+	}
+
+	size := len(numeric)
+	rows := make([][]*vector.Vector, len(tables))
+	for i, table := range tables {
+		if table == nil {
+			return nil, fmt.Errorf("no %d'th table provided: %v", i, table)
+		}
+		rows[i] = make([]*vector.Vector, table.Rows())
+		for code := range rows[i] {
+			if rows[i][code], err = table.GetRow(code); err != nil {
+				return nil, fmt.Errorf("error getting %d'th row of %d'th table: %w", code, i, err)
+			}
+		}
+		size += table.Cols()
+	}
+
+	return &EmbeddingLayer{
+		kind:        CategoricalEmbeddingLayer,
+		tables:      rows,
+		columns:     append([]int(nil), columns...),
+		numeric:     numeric,
+		inputsCount: inputsCount,
+		size:        size,
+	}, nil
+}
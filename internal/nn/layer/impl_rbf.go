@@ -0,0 +1,93 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+)
+
+const RBFLayer nn.Kind = "rbf layer"
+
+// NewRBFLayer creates radial basis function layer:
+//     y = kernel(|x - c|^2 / w^2) * W + b,
+//     where c is <centers> (centers count x inputs count), w is <widths> (centers count), kernel is one of radial basis
+//     activations (see operation.IsRadialBasis), W is <weight> (centers count x layer's size), b is <bias>.
+//
+// Centers, widths, weight and bias are trainable.
+func NewRBFLayer(
+	centers *matrix.Matrix,
+	widths *vector.Vector,
+	kernel operation.IOperation,
+	weight *matrix.Matrix,
+	bias *vector.Vector,
+) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create rbf layer")
+	if kernel == nil || !operation.IsRadialBasis(kernel.Kind()) {
+		return nil, fmt.Errorf("provided operation is not a radial basis activation: %v", kernel)
+	}
+
+	distance, err := operation.NewSquaredDistance(centers)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := operation.NewWidthScale(widths)
+	if err != nil {
+		return nil, err
+	}
+	w, err := operation.NewWeightOperation(weight)
+	if err != nil {
+		return nil, err
+	}
+	b, err := operation.NewBiasOperation(bias)
+	if err != nil {
+		return nil, err
+	}
+
+	if centers.Rows() != widths.Size() {
+		return nil, fmt.Errorf("widths count must match centers count: %d != %d", widths.Size(), centers.Rows())
+	} else if centers.Rows() != weight.Rows() {
+		return nil, fmt.Errorf("weight rows count must match centers count: %d != %d", weight.Rows(), centers.Rows())
+	} else if weight.Cols() != bias.Size() {
+		return nil, fmt.Errorf("weight cols count must match bias size (as it is layer's size): %d != %d",
+			weight.Cols(), bias.Size())
+	}
+
+	return &Layer{
+		kind:        RBFLayer,
+		inputsCount: centers.Cols(),
+		size:        weight.Cols(),
+		operations:  []operation.IOperation{distance, scale, kernel.Copy().(operation.IOperation), w, b},
+	}, nil
+}
+
+// RBFWidths return widths of radial basis function layer by the common heuristic, all widths are equal:
+//     w = dmax / sqrt(2 * k),
+//     where dmax is maximal distance between <centers>, k is centers count.
+// Return ones if there are less than two distinct centers.
+func RBFWidths(centers *matrix.Matrix) (*vector.Vector, error) {
+	if centers == nil {
+		return nil, fmt.Errorf("no centers provided: %v", centers)
+	}
+	raw := centers.Raw()
+	maxDist := 0.0
+	for i := range raw {
+		for j := i + 1; j < len(raw); j++ {
+			dist := 0.0
+			for c := range raw[i] {
+				dist += (raw[i][c] - raw[j][c]) * (raw[i][c] - raw[j][c])
+			}
+			maxDist = math.Max(maxDist, dist)
+		}
+	}
+	if maxDist == 0 {
+		return vector.NewVectorOf(1, len(raw))
+	}
+	return vector.NewVectorOf(math.Sqrt(maxDist)/math.Sqrt(2*float64(len(raw))), len(raw))
+}
@@ -0,0 +1,352 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+)
+
+const (
+	RNNLayer  nn.Kind = "rnn layer"
+	LSTMLayer nn.Kind = "lstm layer"
+	GRULayer  nn.Kind = "gru layer"
+)
+
+// GateParameters holds parameters of recurrent cell's gate:
+//     a = activation(x * Weight + h * Recurrent + Bias).
+type GateParameters struct {
+	Weight    *matrix.Matrix // features count x size
+	Recurrent *matrix.Matrix // size x size
+	Bias      *vector.Vector // size
+}
+
+// SequenceParameters describes packed sequence processed by recurrent layer.
+type SequenceParameters struct {
+	Steps           int  // count of time steps in sequence
+	Truncation      int  // count of last steps to backpropagate through, 0 for full backpropagation through time
+	ReturnSequences bool // return hidden states of all steps instead of the last one
+}
+
+var cells = map[nn.Kind]*cell{
+	RNNLayer:  {gatesCount: 1, forward: rnnForward, backward: rnnBackward},
+	LSTMLayer: {gatesCount: 4, forward: lstmForward, backward: lstmBackward},
+	GRULayer:  {gatesCount: 3, forward: gruForward, backward: gruBackward},
+}
+
+// NewRNNLayer creates simple recurrent layer:
+//     h(t) = activation(x(t) * W + h(t-1) * U + b).
+func NewRNNLayer(
+	parameters *GateParameters,
+	activation operation.IOperation,
+	sequence *SequenceParameters,
+) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create rnn layer")
+
+	if activation == nil {
+		return nil, fmt.Errorf("no activation provided: %v", activation)
+	} else if !activation.IsActivation() {
+		return nil, fmt.Errorf("provided operation it is not an activation: %s", activation.ShortString())
+	} else if activation.Is(operation.SigmoidParamActivation) {
+		return nil, fmt.Errorf("%s is not supported by %s", operation.SigmoidParamActivation, RNNLayer)
+	}
+
+	return newRecurrentLayer(RNNLayer, []*GateParameters{parameters}, []operation.IOperation{activation}, sequence)
+}
+
+// NewLSTMLayer creates long short-term memory layer:
+//     i(t) = sigmoid(x(t) * Wi + h(t-1) * Ui + bi) - input gate;
+//     f(t) = sigmoid(x(t) * Wf + h(t-1) * Uf + bf) - forget gate;
+//     o(t) = sigmoid(x(t) * Wo + h(t-1) * Uo + bo) - output gate;
+//     g(t) = tanh(x(t) * Wg + h(t-1) * Ug + bg) - cell candidate;
+//     c(t) = f(t) * c(t-1) + i(t) * g(t);
+//     h(t) = o(t) * tanh(c(t)).
+func NewLSTMLayer(input, forget, output, candidate *GateParameters, sequence *SequenceParameters) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create lstm layer")
+	return newRecurrentLayer(
+		LSTMLayer,
+		[]*GateParameters{input, forget, output, candidate},
+		[]operation.IOperation{
+			operation.NewSigmoidActivation(),
+			operation.NewSigmoidActivation(),
+			operation.NewSigmoidActivation(),
+			operation.NewTanhActivation(),
+		},
+		sequence,
+	)
+}
+
+// NewGRULayer creates gated recurrent unit layer:
+//     z(t) = sigmoid(x(t) * Wz + h(t-1) * Uz + bz) - update gate;
+//     r(t) = sigmoid(x(t) * Wr + h(t-1) * Ur + br) - reset gate;
+//     n(t) = tanh(x(t) * Wn + (r(t) * h(t-1)) * Un + bn) - candidate;
+//     h(t) = (1 - z(t)) * n(t) + z(t) * h(t-1).
+func NewGRULayer(update, reset, candidate *GateParameters, sequence *SequenceParameters) (l ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create gru layer")
+	return newRecurrentLayer(
+		GRULayer,
+		[]*GateParameters{update, reset, candidate},
+		[]operation.IOperation{
+			operation.NewSigmoidActivation(),
+			operation.NewSigmoidActivation(),
+			operation.NewTanhActivation(),
+		},
+		sequence,
+	)
+}
+
+func newRecurrentLayer(
+	kind nn.Kind,
+	parameters []*GateParameters,
+	activations []operation.IOperation,
+	sequence *SequenceParameters,
+) (*RecurrentLayer, error) {
+	if sequence == nil {
+		return nil, fmt.Errorf("no sequence parameters provided: %v", sequence)
+	} else if sequence.Steps < 1 {
+		return nil, fmt.Errorf("negative or zero steps count: %d", sequence.Steps)
+	} else if sequence.Truncation < 0 {
+		return nil, fmt.Errorf("negative truncation: %d", sequence.Truncation)
+	}
+
+	var inputs, size int
+	gates := make([]*gate, len(parameters))
+	for i, p := range parameters {
+		if p == nil || p.Weight == nil || p.Recurrent == nil || p.Bias == nil {
+			return nil, fmt.Errorf("no parameters provided for %d'th gate: %v", i, p)
+		}
+		if i == 0 {
+			inputs, size = p.Weight.Size()
+		}
+		if rows, cols := p.Weight.Size(); rows != inputs || cols != size {
+			return nil, fmt.Errorf("%d'th gate weight shape mismatch first gate weight shape: %dx%d != %dx%d",
+				i, rows, cols, inputs, size)
+		} else if rows, cols := p.Recurrent.Size(); rows != size || cols != size {
+			return nil, fmt.Errorf("%d'th gate recurrent weight must be %dx%d, got %dx%d", i, size, size, rows, cols)
+		} else if p.Bias.Size() != size {
+			return nil, fmt.Errorf("%d'th gate bias size must match weight cols count (as it is layer's size): %d != %d",
+				i, p.Bias.Size(), size)
+		}
+
+		w, err := operation.NewWeightOperation(p.Weight)
+		if err != nil {
+			return nil, err
+		}
+		u, err := operation.NewWeightOperation(p.Recurrent)
+		if err != nil {
+			return nil, err
+		}
+		b, err := operation.NewBiasOperation(p.Bias)
+		if err != nil {
+			return nil, err
+		}
+		gates[i] = &gate{
+			weight:     w,
+			recurrent:  u,
+			bias:       b,
+			activation: activations[i].Copy().(operation.IOperation),
+		}
+	}
+
+	return &RecurrentLayer{
+		kind:        kind,
+		gates:       gates,
+		inputsCount: inputs,
+		size:        size,
+		sequence:    *sequence,
+	}, nil
+}
+
+func rnnForward(s *step, x *matrix.Matrix) (err error) {
+	s.h, err = s.gates[0].forward(x, s.hPrev)
+	s.c = s.cPrev
+	return err
+}
+
+func rnnBackward(s *step, dh, dc *matrix.Matrix) (dx, dhPrev, dcPrev *matrix.Matrix, err error) {
+	dx, dhPrev, err = s.gates[0].backward(dh)
+	return dx, dhPrev, dc, err
+}
+
+func lstmForward(s *step, x *matrix.Matrix) error {
+	a := make([]*matrix.Matrix, len(s.gates))
+	for i, g := range s.gates {
+		var err error
+		if a[i], err = g.forward(x, s.hPrev); err != nil {
+			return fmt.Errorf("error computing %d'th gate: %w", i, err)
+		}
+	}
+	in, forget, out, candidate := a[0], a[1], a[2], a[3]
+
+	kept, err := forget.Mul(s.cPrev)
+	if err != nil {
+		return err
+	}
+	added, err := in.Mul(candidate)
+	if err != nil {
+		return err
+	}
+	if s.c, err = kept.Add(added); err != nil {
+		return err
+	}
+	tc, err := s.cell.Forward(s.c)
+	if err != nil {
+		return err
+	}
+	s.h, err = out.Mul(tc)
+	return err
+}
+
+func lstmBackward(s *step, dh, dc *matrix.Matrix) (dx, dhPrev, dcPrev *matrix.Matrix, err error) {
+	in, forget, out, candidate := s.gates[0].activation.Output(), s.gates[1].activation.Output(),
+		s.gates[2].activation.Output(), s.gates[3].activation.Output()
+	tc := s.cell.Output()
+
+	dOut, err := dh.Mul(tc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dtc, err := dh.Mul(out)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dcCell, err := s.cell.Backward(dtc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if dc, err = dc.Add(dcCell); err != nil {
+		return nil, nil, nil, err
+	}
+
+	dIn, err := dc.Mul(candidate)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dForget, err := dc.Mul(s.cPrev)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dCandidate, err := dc.Mul(in)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if dcPrev, err = dc.Mul(forget); err != nil {
+		return nil, nil, nil, err
+	}
+
+	dx, dhPrev, err = gatesBackward(s.gates, []*matrix.Matrix{dIn, dForget, dOut, dCandidate})
+	return dx, dhPrev, dcPrev, err
+}
+
+func gruForward(s *step, x *matrix.Matrix) error {
+	update, err := s.gates[0].forward(x, s.hPrev)
+	if err != nil {
+		return fmt.Errorf("error computing update gate: %w", err)
+	}
+	reset, err := s.gates[1].forward(x, s.hPrev)
+	if err != nil {
+		return fmt.Errorf("error computing reset gate: %w", err)
+	}
+	resetH, err := reset.Mul(s.hPrev)
+	if err != nil {
+		return err
+	}
+	candidate, err := s.gates[2].forward(x, resetH)
+	if err != nil {
+		return fmt.Errorf("error computing candidate: %w", err)
+	}
+
+	// h = (1 - z) * n + z * h(t-1) = n + z * (h(t-1) - n)
+	diff, err := s.hPrev.Sub(candidate)
+	if err != nil {
+		return err
+	}
+	kept, err := update.Mul(diff)
+	if err != nil {
+		return err
+	}
+	s.h, err = candidate.Add(kept)
+	s.c = s.cPrev
+	return err
+}
+
+func gruBackward(s *step, dh, dc *matrix.Matrix) (dx, dhPrev, dcPrev *matrix.Matrix, err error) {
+	update, reset, candidate := s.gates[0].activation.Output(), s.gates[1].activation.Output(),
+		s.gates[2].activation.Output()
+
+	dCandidate, err := dh.Mul(update.MulNum(-1).AddNum(1))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	diff, err := s.hPrev.Sub(candidate)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dUpdate, err := dh.Mul(diff)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if dhPrev, err = dh.Mul(update); err != nil {
+		return nil, nil, nil, err
+	}
+
+	dxCandidate, dResetH, err := s.gates[2].backward(dCandidate)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error computing candidate gradient: %w", err)
+	}
+	dReset, err := dResetH.Mul(s.hPrev)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dhReset, err := dResetH.Mul(reset)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if dhPrev, err = dhPrev.Add(dhReset); err != nil {
+		return nil, nil, nil, err
+	}
+
+	dxGates, dhGates, err := gatesBackward(s.gates[:2], []*matrix.Matrix{dUpdate, dReset})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if dx, err = dxGates.Add(dxCandidate); err != nil {
+		return nil, nil, nil, err
+	}
+	if dhPrev, err = dhPrev.Add(dhGates); err != nil {
+		return nil, nil, nil, err
+	}
+	return dx, dhPrev, dc, nil
+}
+
+// gatesBackward propagates given gradients through gates, return sums of gates' input and hidden state gradients.
+func gatesBackward(gates []*gateStep, das []*matrix.Matrix) (dx, dh *matrix.Matrix, err error) {
+	for i, g := range gates {
+		gdx, gdh, err := g.backward(das[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("error computing %d'th gate gradient: %w", i, err)
+		}
+		if i == 0 {
+			dx, dh = gdx, gdh
+			continue
+		}
+		if dx, err = dx.Add(gdx); err != nil {
+			return nil, nil, err
+		}
+		if dh, err = dh.Add(gdh); err != nil {
+			return nil, nil, err
+		}
+	}
+	return dx, dh, nil
+}
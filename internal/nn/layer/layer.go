@@ -29,7 +29,7 @@ func (l *Layer) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
 		return nil, fmt.Errorf("no input provided: %v", x)
 	}
 
-	y = x.Copy()
+	y = x
 	for i, op := range l.operations {
 		y, err = op.Forward(y)
 		if err != nil {
@@ -51,7 +51,7 @@ func (l *Layer) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
 		return nil, fmt.Errorf("no output gradient provided: %v", dy)
 	}
 
-	dx = dy.Copy()
+	dx = dy
 	length := len(l.operations)
 	for i := length - 1; i >= 0; i-- {
 		dx, err = l.operations[i].Backward(dx)
@@ -73,7 +73,7 @@ func (l *Layer) ApplyOptim(optimizer operation.Optimizer) (err error) {
 	}
 
 	for i, op := range l.operations {
-		if paramOp, ok := op.(*operation.ParamOperation); ok {
+		if paramOp, ok := op.(operation.IParamOperation); ok {
 			err = paramOp.ApplyOptim(optimizer)
 			if err != nil {
 				return fmt.Errorf("error optimizing %d'th operation's parameter: %w", i, err)
@@ -106,6 +106,15 @@ func (l *Layer) Size() int {
 	return l.size
 }
 
+func (l *Layer) mapOperations(f func(op operation.IOperation) (operation.IOperation, error)) (err error) {
+	for i, op := range l.operations {
+		if l.operations[i], err = f(op); err != nil {
+			return fmt.Errorf("error mapping %d'th operation: %w", i, err)
+		}
+	}
+	return nil
+}
+
 func (l *Layer) Copy() nn.IModule {
 	if l == nil {
 		return nil
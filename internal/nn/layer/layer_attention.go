@@ -0,0 +1,468 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+var _ ILayer = (*AttentionLayer)(nil)
+
+// AttentionLayer represents multi-head self-attention over packed sequence (see NewMultiHeadAttentionLayer).
+// Projections are applied to all steps of all samples at once: packed input is reshaped to batch*steps x features.
+// Attention scores of all samples and heads are stacked to single batch*heads*steps x steps Matrix, so softmax is
+// computed by single operation.
+type AttentionLayer struct {
+	kind        nn.Kind
+	query       operation.IOperation
+	key         operation.IOperation
+	value       operation.IOperation
+	output      operation.IOperation
+	softmax     operation.IOperation
+	inputsCount int // features count of single step
+	modelSize   int // size of query, key and value of single step
+	size        int // output features count of single step
+	attention   AttentionParameters
+
+	q, k, v *matrix.Matrix // projections of stacked input
+	y       *matrix.Matrix
+}
+
+// headSize return count of query, key and value cols of single head.
+func (l *AttentionLayer) headSize() int {
+	return l.modelSize / l.attention.Heads
+}
+
+// block return rows of b'th sample and cols of h'th head of stacked Matrix (batch*steps x model size).
+func (l *AttentionLayer) block(m *matrix.Matrix, b, h int) (*matrix.Matrix, error) {
+	steps, dk := l.attention.Steps, l.headSize()
+	return m.SubMatrix(b*steps, (b+1)*steps, 1, h*dk, (h+1)*dk, 1)
+}
+
+// scores return rows of b'th sample and h'th head of stacked scores (batch*heads*steps x steps).
+func (l *AttentionLayer) scores(m *matrix.Matrix, b, h int) (*matrix.Matrix, error) {
+	steps := l.attention.Steps
+	first := (b*l.attention.Heads + h) * steps
+	return m.SubMatrix(first, first+steps, 1, 0, steps, 1)
+}
+
+// unblock return stacked Matrix (batch*steps x model size) built from blocks indexed by sample and head.
+func unblock(blocks [][]*matrix.Matrix) (*matrix.Matrix, error) {
+	rows := make([]*matrix.Matrix, len(blocks))
+	for b, heads := range blocks {
+		row, err := hstack(heads)
+		if err != nil {
+			return nil, err
+		}
+		rows[b] = row
+	}
+	return vstack(rows)
+}
+
+func hstack(matrices []*matrix.Matrix) (*matrix.Matrix, error) {
+	if len(matrices) == 1 {
+		return matrices[0].Copy(), nil
+	}
+	return matrices[0].HStack(matrices[1:])
+}
+
+// mask return steps x steps Matrix with -Inf above diagonal for causal attention, zeros otherwise.
+func (l *AttentionLayer) mask() (*matrix.Matrix, error) {
+	steps := l.attention.Steps
+	values := make([]float64, steps*steps)
+	if l.attention.Causal {
+		for i := 0; i < steps; i++ {
+			for j := i + 1; j < steps; j++ {
+				values[i*steps+j] = math.Inf(-1)
+			}
+		}
+	}
+	return matrix.NewMatrixRawFlat(steps, steps, values)
+}
+
+func (l *AttentionLayer) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Forward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if x == nil {
+		return nil, fmt.Errorf("no input provided: %v", x)
+	} else if x.Cols() != l.InputsCount() {
+		return nil, fmt.Errorf("input cols count must match layer's inputs count: %d != %d",
+			x.Cols(), l.InputsCount())
+	}
+
+	batch, steps := x.Rows(), l.attention.Steps
+	stacked, err := x.Reshape(batch*steps, l.inputsCount)
+	if err != nil {
+		return nil, err
+	}
+	q, err := l.query.Forward(stacked)
+	if err != nil {
+		return nil, fmt.Errorf("error computing query: %w", err)
+	}
+	k, err := l.key.Forward(stacked)
+	if err != nil {
+		return nil, fmt.Errorf("error computing key: %w", err)
+	}
+	v, err := l.value.Forward(stacked)
+	if err != nil {
+		return nil, fmt.Errorf("error computing value: %w", err)
+	}
+
+	mask, err := l.mask()
+	if err != nil {
+		return nil, err
+	}
+	scale := 1 / math.Sqrt(float64(l.headSize()))
+	scores := make([]*matrix.Matrix, 0, batch*l.attention.Heads)
+	for b := 0; b < batch; b++ {
+		for h := 0; h < l.attention.Heads; h++ {
+			qh, err := l.block(q, b, h)
+			if err != nil {
+				return nil, err
+			}
+			kh, err := l.block(k, b, h)
+			if err != nil {
+				return nil, err
+			}
+			s, err := qh.MatMulT(kh)
+			if err != nil {
+				return nil, err
+			}
+			if s, err = s.MulNum(scale).Add(mask); err != nil {
+				return nil, err
+			}
+			scores = append(scores, s)
+		}
+	}
+	stackedScores, err := vstack(scores)
+	if err != nil {
+		return nil, err
+	}
+	weights, err := l.softmax.Forward(stackedScores)
+	if err != nil {
+		return nil, fmt.Errorf("error computing attention weights: %w", err)
+	}
+
+	heads := make([][]*matrix.Matrix, batch)
+	for b := range heads {
+		heads[b] = make([]*matrix.Matrix, l.attention.Heads)
+		for h := range heads[b] {
+			a, err := l.scores(weights, b, h)
+			if err != nil {
+				return nil, err
+			}
+			vh, err := l.block(v, b, h)
+			if err != nil {
+				return nil, err
+			}
+			if heads[b][h], err = a.MatMul(vh); err != nil {
+				return nil, err
+			}
+		}
+	}
+	concat, err := unblock(heads)
+	if err != nil {
+		return nil, err
+	}
+	out, err := l.output.Forward(concat)
+	if err != nil {
+		return nil, fmt.Errorf("error computing output projection: %w", err)
+	}
+	if y, err = out.Reshape(batch, steps*l.size); err != nil {
+		return nil, err
+	}
+
+	l.q, l.k, l.v = q, k, v
+	l.y = y.Copy()
+	return y, nil
+}
+
+func (l *AttentionLayer) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Backward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if dy == nil {
+		return nil, fmt.Errorf("no output gradient provided: %v", dy)
+	} else if l.y == nil {
+		return nil, fmt.Errorf("call Backward() before Forward()")
+	} else if err = l.y.CheckEqualShape(dy); err != nil {
+		return nil, fmt.Errorf("error checking output and output gradient shapes: %w", err)
+	}
+
+	batch, steps := dy.Rows(), l.attention.Steps
+	stackedDy, err := dy.Reshape(batch*steps, l.size)
+	if err != nil {
+		return nil, err
+	}
+	dConcat, err := l.output.Backward(stackedDy)
+	if err != nil {
+		return nil, fmt.Errorf("error computing output projection gradient: %w", err)
+	}
+
+	weights := l.softmax.Output()
+	dWeights := make([]*matrix.Matrix, 0, batch*l.attention.Heads)
+	dv := make([][]*matrix.Matrix, batch)
+	for b := range dv {
+		dv[b] = make([]*matrix.Matrix, l.attention.Heads)
+		for h := range dv[b] {
+			dHead, err := l.block(dConcat, b, h)
+			if err != nil {
+				return nil, err
+			}
+			a, err := l.scores(weights, b, h)
+			if err != nil {
+				return nil, err
+			}
+			vh, err := l.block(l.v, b, h)
+			if err != nil {
+				return nil, err
+			}
+			da, err := dHead.MatMulT(vh)
+			if err != nil {
+				return nil, err
+			}
+			dWeights = append(dWeights, da)
+			if dv[b][h], err = a.TMatMul(dHead); err != nil {
+				return nil, err
+			}
+		}
+	}
+	stackedDWeights, err := vstack(dWeights)
+	if err != nil {
+		return nil, err
+	}
+	dScores, err := l.softmax.Backward(stackedDWeights)
+	if err != nil {
+		return nil, fmt.Errorf("error computing attention weights gradient: %w", err)
+	}
+
+	scale := 1 / math.Sqrt(float64(l.headSize()))
+	dq := make([][]*matrix.Matrix, batch)
+	dk := make([][]*matrix.Matrix, batch)
+	for b := range dq {
+		dq[b] = make([]*matrix.Matrix, l.attention.Heads)
+		dk[b] = make([]*matrix.Matrix, l.attention.Heads)
+		for h := range dq[b] {
+			ds, err := l.scores(dScores, b, h)
+			if err != nil {
+				return nil, err
+			}
+			ds = ds.MulNum(scale)
+			qh, err := l.block(l.q, b, h)
+			if err != nil {
+				return nil, err
+			}
+			kh, err := l.block(l.k, b, h)
+			if err != nil {
+				return nil, err
+			}
+			if dq[b][h], err = ds.MatMul(kh); err != nil {
+				return nil, err
+			}
+			if dk[b][h], err = ds.TMatMul(qh); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	projections := []operation.IOperation{l.query, l.key, l.value}
+	for i, blocks := range [][][]*matrix.Matrix{dq, dk, dv} {
+		d, err := unblock(blocks)
+		if err != nil {
+			return nil, err
+		}
+		dxi, err := projections[i].Backward(d)
+		if err != nil {
+			return nil, fmt.Errorf("error computing %d'th projection gradient: %w", i, err)
+		}
+		if dx == nil {
+			dx = dxi
+		} else if dx, err = dx.Add(dxi); err != nil {
+			return nil, err
+		}
+	}
+
+	return dx.Reshape(batch, steps*l.inputsCount)
+}
+
+func (l *AttentionLayer) projections() []operation.IOperation {
+	return []operation.IOperation{l.query, l.key, l.value, l.output}
+}
+
+func (l *AttentionLayer) ApplyOptim(optimizer operation.Optimizer) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during apply Optimizer on %s", l.kind), &err)
+
+	if l == nil {
+		return ErrNil
+	}
+
+	for i, op := range l.projections() {
+		paramOp, ok := op.(operation.IParamOperation)
+		if !ok {
+			return fmt.Errorf("error casting %d'th projection to operation.IParamOperation", i)
+		}
+		if err = paramOp.ApplyOptim(optimizer); err != nil {
+			return fmt.Errorf("error optimizing %d'th projection: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (l *AttentionLayer) Is(kind nn.Kind) bool {
+	if l == nil {
+		return false
+	}
+	return l.kind == kind
+}
+
+func (l *AttentionLayer) Kind() nn.Kind {
+	return l.kind
+}
+
+func (l *AttentionLayer) Output() *matrix.Matrix {
+	if l.y == nil {
+		return nil
+	}
+	return l.y.Copy()
+}
+
+// InputsCount return count of packed sequence's cols: steps * features.
+func (l *AttentionLayer) InputsCount() int {
+	return l.attention.Steps * l.inputsCount
+}
+
+// Size return count of packed output sequence's cols: steps * output features.
+func (l *AttentionLayer) Size() int {
+	return l.attention.Steps * l.size
+}
+
+// Attention return copy of layer's attention parameters.
+func (l *AttentionLayer) Attention() *AttentionParameters {
+	res := l.attention
+	return &res
+}
+
+func (l *AttentionLayer) mapOperations(f func(op operation.IOperation) (operation.IOperation, error)) (err error) {
+	for i, op := range []*operation.IOperation{&l.query, &l.key, &l.value, &l.output, &l.softmax} {
+		if *op, err = f(*op); err != nil {
+			return fmt.Errorf("error mapping %d'th operation: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (l *AttentionLayer) Copy() nn.IModule {
+	if l == nil {
+		return nil
+	}
+	res := &AttentionLayer{
+		kind:        l.kind,
+		query:       l.query.Copy().(operation.IOperation),
+		key:         l.key.Copy().(operation.IOperation),
+		value:       l.value.Copy().(operation.IOperation),
+		output:      l.output.Copy().(operation.IOperation),
+		softmax:     l.softmax.Copy().(operation.IOperation),
+		inputsCount: l.inputsCount,
+		modelSize:   l.modelSize,
+		size:        l.size,
+		attention:   l.attention,
+	}
+	if l.y != nil {
+		res.q, res.k, res.v = l.q.Copy(), l.k.Copy(), l.v.Copy()
+		res.y = l.y.Copy()
+	}
+	return res
+}
+
+func (l *AttentionLayer) Equal(layer nn.IModule) bool {
+	return l.equal(layer, func(a, b nn.IModule) bool { return a.Equal(b) },
+		func(a, b *matrix.Matrix) bool { return a.Equal(b) })
+}
+
+func (l *AttentionLayer) EqualApprox(layer nn.IModule) bool {
+	return l.equal(layer, func(a, b nn.IModule) bool { return a.EqualApprox(b) },
+		func(a, b *matrix.Matrix) bool { return a.EqualApprox(b) })
+}
+
+func (l *AttentionLayer) equal(
+	layer nn.IModule,
+	modulesEqual func(a, b nn.IModule) bool,
+	matricesEqual func(a, b *matrix.Matrix) bool,
+) bool {
+	if l == nil || layer == nil {
+		if (l != nil && layer == nil) || (l == nil && layer != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	la, ok := layer.(*AttentionLayer)
+	if !ok {
+		return false
+	} else if l.kind != la.kind {
+		return false
+	} else if l.inputsCount != la.inputsCount || l.modelSize != la.modelSize || l.size != la.size {
+		return false
+	} else if l.attention != la.attention {
+		return false
+	} else if l.y != nil && !matricesEqual(l.y, la.y) {
+		return false
+	}
+	projections, another := l.projections(), la.projections()
+	for i := range projections {
+		if !modulesEqual(projections[i], another[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *AttentionLayer) toMap(stringers func(s []utils.SPStringer) string) map[string]string {
+	projections := l.projections()
+	operations := make([]utils.SPStringer, len(projections))
+	for i, op := range projections {
+		operations[i] = op
+	}
+	return map[string]string{
+		"kind":       string(l.kind),
+		"operations": stringers(operations),
+		"steps":      fmt.Sprintf("%d", l.attention.Steps),
+		"heads":      fmt.Sprintf("%d", l.attention.Heads),
+		"causal":     fmt.Sprintf("%t", l.attention.Causal),
+	}
+}
+
+func (l *AttentionLayer) String() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.Strings), utils.BaseFormat)
+}
+
+func (l *AttentionLayer) PrettyString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.PrettyStrings), utils.PrettyFormat)
+}
+
+func (l *AttentionLayer) ShortString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.ShortStrings), utils.ShortFormat)
+}
@@ -0,0 +1,329 @@
+package layer
+
+import (
+	"fmt"
+	"math"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+	"sort"
+)
+
+var _ ILayer = (*EmbeddingLayer)(nil)
+
+// EmbeddingLayer represents embedding layer (see NewEmbeddingLayer). Tables are stored by rows, so ApplyOptim passes to
+// Optimizer and replaces only rows used in the last batch.
+type EmbeddingLayer struct {
+	kind        nn.Kind
+	tables      [][]*vector.Vector // rows of embedding tables indexed by categorical column and category code
+	columns     []int              // categorical columns
+	numeric     []int              // numeric columns in ascending order
+	inputsCount int
+	size        int
+
+	codes [][]int          // category codes of the last batch indexed by sample and categorical column
+	used  [][]int          // ascending category codes used in the last batch indexed by categorical column
+	grads []*matrix.Matrix // gradients of used rows indexed by categorical column
+	y     *matrix.Matrix
+}
+
+func (l *EmbeddingLayer) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during forward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if x == nil {
+		return nil, fmt.Errorf("no input provided: %v", x)
+	} else if x.Cols() != l.inputsCount {
+		return nil, fmt.Errorf("input cols count mismatches layer's inputs count: %d != %d", x.Cols(), l.inputsCount)
+	}
+
+	raw := x.Raw()
+	codes := make([][]int, len(raw))
+	values := make([][]float64, len(raw))
+	for i, row := range raw {
+		values[i] = make([]float64, 0, l.size)
+		for _, col := range l.numeric {
+			values[i] = append(values[i], row[col])
+		}
+		codes[i] = make([]int, len(l.columns))
+		for j, col := range l.columns {
+			code := row[col]
+			if code < 0 || code >= float64(len(l.tables[j])) || code != math.Trunc(code) {
+				return nil, fmt.Errorf("value of categorical column %d must be category code in [0; %d): %v",
+					col, len(l.tables[j]), code)
+			}
+			codes[i][j] = int(code)
+			values[i] = append(values[i], l.tables[j][codes[i][j]].Raw()...)
+		}
+	}
+
+	if y, err = matrix.NewMatrixRaw(values); err != nil {
+		return nil, err
+	}
+	l.codes, l.y = codes, y
+	return y.Copy(), nil
+}
+
+func (l *EmbeddingLayer) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during backward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if dy == nil {
+		return nil, fmt.Errorf("no output gradient provided: %v", dy)
+	} else if l.y == nil {
+		return nil, fmt.Errorf("can not compute gradient before forward propagation")
+	} else if err = l.y.CheckEqualShape(dy); err != nil {
+		return nil, fmt.Errorf("output gradient shape mismatches output shape: %w", err)
+	}
+
+	// positions of used rows in gradients, indexed by categorical column and category code
+	positions := make([]map[int]int, len(l.columns))
+	used := make([][]int, len(l.columns))
+	for j := range l.columns {
+		positions[j] = make(map[int]int)
+		for _, codes := range l.codes {
+			if _, ok := positions[j][codes[j]]; !ok {
+				positions[j][codes[j]] = 0
+				used[j] = append(used[j], codes[j])
+			}
+		}
+		sort.Ints(used[j])
+		for p, code := range used[j] {
+			positions[j][code] = p
+		}
+	}
+
+	raw := dy.Raw()
+	grads := make([][][]float64, len(l.columns))
+	for j := range l.columns {
+		grads[j] = make([][]float64, len(used[j]))
+		for p := range grads[j] {
+			grads[j][p] = make([]float64, len(l.tables[j][0].Raw()))
+		}
+	}
+	dxValues := make([][]float64, len(raw))
+	for i, row := range raw {
+		dxValues[i] = make([]float64, l.inputsCount)
+		for k, col := range l.numeric {
+			dxValues[i][col] = row[k]
+		}
+		offset := len(l.numeric)
+		for j := range l.columns {
+			grad := grads[j][positions[j][l.codes[i][j]]]
+			for k := range grad {
+				grad[k] += row[offset+k]
+			}
+			offset += len(grad)
+		}
+	}
+
+	l.grads = make([]*matrix.Matrix, len(l.columns))
+	for j := range grads {
+		if l.grads[j], err = matrix.NewMatrixRaw(grads[j]); err != nil {
+			return nil, fmt.Errorf("error getting gradient of %d'th table: %w", j, err)
+		}
+	}
+	l.used = used
+	return matrix.NewMatrixRaw(dxValues)
+}
+
+// ApplyOptim passes to Optimizer only rows of tables used in the last batch and their gradients.
+func (l *EmbeddingLayer) ApplyOptim(optimizer operation.Optimizer) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during apply Optimizer on %s", l.kind), &err)
+
+	if l == nil {
+		return ErrNil
+	} else if optimizer == nil {
+		return fmt.Errorf("no optimizer provided")
+	} else if l.grads == nil {
+		return fmt.Errorf("can not apply optimizer before gradient computation: %v", l.grads)
+	}
+
+	for j, table := range l.tables {
+		rows := make([]*vector.Vector, len(l.used[j]))
+		for p, code := range l.used[j] {
+			rows[p] = table[code]
+		}
+		param, err := matrix.NewMatrix(rows)
+		if err != nil {
+			return err
+		}
+		newParam, err := optimizer(param, l.grads[j].Copy())
+		if err != nil {
+			return fmt.Errorf("error computing new rows of %d'th table: %w", j, err)
+		} else if newParam == nil {
+			return fmt.Errorf("nil rows of %d'th table after optimization: %v", j, newParam)
+		} else if err = param.CheckEqualShape(newParam); err != nil {
+			return err
+		}
+		for p, code := range l.used[j] {
+			if table[code], err = newParam.GetRow(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Tables return copies of embedding tables in order of categorical columns.
+func (l *EmbeddingLayer) Tables() []*matrix.Matrix {
+	res := make([]*matrix.Matrix, len(l.tables))
+	for j, table := range l.tables {
+		m, err := matrix.NewMatrix(table)
+		if err != nil {
+			panic(err)
+		}
+		res[j] = m
+	}
+	return res
+}
+
+// Columns return indices of categorical columns.
+func (l *EmbeddingLayer) Columns() []int {
+	return append([]int(nil), l.columns...)
+}
+
+func (l *EmbeddingLayer) Is(kind nn.Kind) bool {
+	if l == nil {
+		return false
+	}
+	return l.kind == kind
+}
+
+func (l *EmbeddingLayer) Kind() nn.Kind {
+	return l.kind
+}
+
+func (l *EmbeddingLayer) Output() *matrix.Matrix {
+	if l.y == nil {
+		return nil
+	}
+	return l.y.Copy()
+}
+
+func (l *EmbeddingLayer) InputsCount() int {
+	return l.inputsCount
+}
+
+func (l *EmbeddingLayer) Size() int {
+	return l.size
+}
+
+func (l *EmbeddingLayer) Copy() nn.IModule {
+	if l == nil {
+		return nil
+	}
+	res := &EmbeddingLayer{
+		kind:        l.kind,
+		tables:      make([][]*vector.Vector, len(l.tables)),
+		columns:     append([]int(nil), l.columns...),
+		numeric:     append([]int(nil), l.numeric...),
+		inputsCount: l.inputsCount,
+		size:        l.size,
+	}
+	for j, table := range l.tables {
+		res.tables[j] = make([]*vector.Vector, len(table))
+		for code, row := range table {
+			res.tables[j][code] = row.Copy()
+		}
+	}
+	if l.y != nil {
+		res.codes = make([][]int, len(l.codes))
+		for i, codes := range l.codes {
+			res.codes[i] = append([]int(nil), codes...)
+		}
+		res.y = l.y.Copy()
+	}
+	return res
+}
+
+func (l *EmbeddingLayer) Equal(layer nn.IModule) bool {
+	return l.equal(layer, func(a, b *vector.Vector) bool { return a.Equal(b) },
+		func(a, b *matrix.Matrix) bool { return a.Equal(b) })
+}
+
+func (l *EmbeddingLayer) EqualApprox(layer nn.IModule) bool {
+	return l.equal(layer, func(a, b *vector.Vector) bool { return a.EqualApprox(b) },
+		func(a, b *matrix.Matrix) bool { return a.EqualApprox(b) })
+}
+
+func (l *EmbeddingLayer) equal(
+	layer nn.IModule,
+	vectorsEqual func(a, b *vector.Vector) bool,
+	matricesEqual func(a, b *matrix.Matrix) bool,
+) bool {
+	if l == nil || layer == nil {
+		if (l != nil && layer == nil) || (l == nil && layer != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	ll, ok := layer.(*EmbeddingLayer)
+	if !ok {
+		return false
+	} else if l.kind != ll.kind {
+		return false
+	} else if l.inputsCount != ll.inputsCount || l.size != ll.size || len(l.columns) != len(ll.columns) {
+		return false
+	} else if l.y != nil && !matricesEqual(l.y, ll.y) {
+		return false
+	}
+	for j, col := range l.columns {
+		if col != ll.columns[j] || len(l.tables[j]) != len(ll.tables[j]) {
+			return false
+		}
+		for code, row := range l.tables[j] {
+			if !vectorsEqual(row, ll.tables[j][code]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (l *EmbeddingLayer) toMap(stringers func(s []utils.SPStringer) string) map[string]string {
+	tables := l.Tables()
+	strs := make([]utils.SPStringer, len(tables))
+	for j, table := range tables {
+		strs[j] = table
+	}
+	return map[string]string{
+		"kind":    string(l.kind),
+		"tables":  stringers(strs),
+		"columns": fmt.Sprintf("%v", l.columns),
+	}
+}
+
+func (l *EmbeddingLayer) String() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.Strings), utils.BaseFormat)
+}
+
+func (l *EmbeddingLayer) PrettyString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.PrettyStrings), utils.PrettyFormat)
+}
+
+func (l *EmbeddingLayer) ShortString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.ShortStrings), utils.ShortFormat)
+}
@@ -0,0 +1,561 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+var _ ILayer = (*RecurrentLayer)(nil)
+
+// RecurrentLayer represents layer, that processes packed sequence (see PackSequence) step by step, passing hidden
+// state from previous step to the next one. Layer's cell is built from gates:
+//     a = activation(x * W + h * U + b),
+// where x is step's input and h is previous step's hidden state.
+//
+// Gradients are computed by backpropagation through time, truncated to the last Truncation steps when it is set.
+type RecurrentLayer struct {
+	kind        nn.Kind
+	gates       []*gate
+	inputsCount int // features count of single step
+	size        int // hidden state size
+	sequence    SequenceParameters
+
+	steps []*step
+	y     *matrix.Matrix
+}
+
+// gate holds operations of cell's gate. Parameter operations are shared by all the time steps, activation is a
+// template copied for each step, as operations store input and output of the last call only.
+type gate struct {
+	weight     operation.IOperation
+	recurrent  operation.IOperation
+	bias       operation.IOperation
+	activation operation.IOperation
+
+	w, u *matrix.Matrix // parameters of weight and recurrent operations, taken at the beginning of Backward
+}
+
+// parameters takes current parameters of weight and recurrent operations to compute steps' input gradients.
+func (g *gate) parameters() error {
+	for _, p := range []struct {
+		op  operation.IOperation
+		dst **matrix.Matrix
+	}{{g.weight, &g.w}, {g.recurrent, &g.u}} {
+		paramOp, ok := p.op.(operation.IParamOperation)
+		if !ok {
+			return fmt.Errorf("operation has no parameter: %s", p.op.ShortString())
+		}
+		*p.dst = paramOp.Parameter()
+	}
+	return nil
+}
+
+// accumulate computes gate's parameters gradients as sum of gradients of given steps by propagating stacked steps'
+// inputs and gradients through gate's operations once.
+func (g *gate) accumulate(steps []*gateStep) error {
+	xs := make([]*matrix.Matrix, 0, len(steps))
+	hs := make([]*matrix.Matrix, 0, len(steps))
+	ds := make([]*matrix.Matrix, 0, len(steps))
+	for i, s := range steps {
+		if s.d == nil {
+			return fmt.Errorf("no gradient computed for %d'th step", i)
+		}
+		xs, hs, ds = append(xs, s.x), append(hs, s.h), append(ds, s.d)
+	}
+
+	x, err := vstack(xs)
+	if err != nil {
+		return err
+	}
+	h, err := vstack(hs)
+	if err != nil {
+		return err
+	}
+	d, err := vstack(ds)
+	if err != nil {
+		return err
+	}
+
+	for _, replay := range []struct {
+		op operation.IOperation
+		x  *matrix.Matrix
+	}{{g.weight, x}, {g.recurrent, h}, {g.bias, d}} {
+		if _, err = replay.op.Forward(replay.x); err != nil {
+			return err
+		}
+		if _, err = replay.op.Backward(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func vstack(matrices []*matrix.Matrix) (*matrix.Matrix, error) {
+	if len(matrices) == 1 {
+		return matrices[0].Copy(), nil
+	}
+	return matrices[0].VStack(matrices[1:])
+}
+
+func (g *gate) applyOptim(optimizer operation.Optimizer) error {
+	for _, op := range g.operations() {
+		if paramOp, ok := op.(operation.IParamOperation); ok {
+			if err := paramOp.ApplyOptim(optimizer); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *gate) operations() []operation.IOperation {
+	return []operation.IOperation{g.weight, g.recurrent, g.bias, g.activation}
+}
+
+func (g *gate) copy() *gate {
+	return &gate{
+		weight:     g.weight.Copy().(operation.IOperation),
+		recurrent:  g.recurrent.Copy().(operation.IOperation),
+		bias:       g.bias.Copy().(operation.IOperation),
+		activation: g.activation.Copy().(operation.IOperation),
+	}
+}
+
+func (g *gate) equal(another *gate, equal func(a, b nn.IModule) bool) bool {
+	ops, anotherOps := g.operations(), another.operations()
+	for i := range ops {
+		if !equal(ops[i], anotherOps[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// gateStep holds state of gate on single time step: step's activation, input, previous hidden state and
+// pre-activation gradient.
+type gateStep struct {
+	gate       *gate
+	activation operation.IOperation
+
+	x, h, d *matrix.Matrix
+}
+
+func (g *gate) newStep() *gateStep {
+	return &gateStep{gate: g, activation: g.activation.Copy().(operation.IOperation)}
+}
+
+func (s *gateStep) forward(x, h *matrix.Matrix) (*matrix.Matrix, error) {
+	xw, err := s.gate.weight.Forward(x)
+	if err != nil {
+		return nil, fmt.Errorf("error computing input weight: %w", err)
+	}
+	hu, err := s.gate.recurrent.Forward(h)
+	if err != nil {
+		return nil, fmt.Errorf("error computing recurrent weight: %w", err)
+	}
+	sum, err := xw.Add(hu)
+	if err != nil {
+		return nil, err
+	}
+	z, err := s.gate.bias.Forward(sum)
+	if err != nil {
+		return nil, fmt.Errorf("error computing bias: %w", err)
+	}
+	s.x, s.h = x, h
+	return s.activation.Forward(z)
+}
+
+// backward return gradients of step's input and previous hidden state, parameters gradients are computed later by
+// gate.accumulate.
+func (s *gateStep) backward(da *matrix.Matrix) (dx, dh *matrix.Matrix, err error) {
+	d, err := s.activation.Backward(da)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error computing activation gradient: %w", err)
+	}
+	if dx, err = d.MatMulT(s.gate.w); err != nil {
+		return nil, nil, fmt.Errorf("error computing input weight gradient: %w", err)
+	}
+	if dh, err = d.MatMulT(s.gate.u); err != nil {
+		return nil, nil, fmt.Errorf("error computing recurrent weight gradient: %w", err)
+	}
+	s.d = d
+	return dx, dh, nil
+}
+
+func (s *gateStep) copy(g *gate) *gateStep {
+	res := &gateStep{gate: g, activation: s.activation.Copy().(operation.IOperation)}
+	if s.x != nil {
+		res.x = s.x.Copy()
+	}
+	if s.h != nil {
+		res.h = s.h.Copy()
+	}
+	if s.d != nil {
+		res.d = s.d.Copy()
+	}
+	return res
+}
+
+// step holds state of single time step: states of layer's gates, hidden and cell states and cell's intermediate
+// operations.
+type step struct {
+	gates []*gateStep
+	cell  operation.IOperation // activation of cell state, used by LSTM only
+
+	hPrev, cPrev *matrix.Matrix
+	h, c         *matrix.Matrix
+}
+
+// copy return copy of step, which gates states refer to given gates.
+func (s *step) copy(gates []*gate) *step {
+	res := &step{gates: make([]*gateStep, len(s.gates))}
+	for i, g := range s.gates {
+		res.gates[i] = g.copy(gates[i])
+	}
+	if s.cell != nil {
+		res.cell = s.cell.Copy().(operation.IOperation)
+	}
+	if s.hPrev != nil {
+		res.hPrev = s.hPrev.Copy()
+	}
+	if s.cPrev != nil {
+		res.cPrev = s.cPrev.Copy()
+	}
+	if s.h != nil {
+		res.h = s.h.Copy()
+	}
+	if s.c != nil {
+		res.c = s.c.Copy()
+	}
+	return res
+}
+
+// cell describes computation of single time step for specific recurrent layer kind.
+type cell struct {
+	gatesCount int
+	// forward computes s.h and s.c using s.hPrev and s.cPrev
+	forward func(s *step, x *matrix.Matrix) error
+	// backward return gradients of step's input, previous hidden state and previous cell state
+	backward func(s *step, dh, dc *matrix.Matrix) (dx, dhPrev, dcPrev *matrix.Matrix, err error)
+}
+
+func (l *RecurrentLayer) newStep() *step {
+	s := &step{gates: make([]*gateStep, len(l.gates))}
+	for i, g := range l.gates {
+		s.gates[i] = g.newStep()
+	}
+	if l.kind == LSTMLayer {
+		s.cell = operation.NewTanhActivation()
+	}
+	return s
+}
+
+func (l *RecurrentLayer) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Forward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if x == nil {
+		return nil, fmt.Errorf("no input provided: %v", x)
+	} else if x.Cols() != l.InputsCount() {
+		return nil, fmt.Errorf("input cols count must match layer's inputs count: %d != %d",
+			x.Cols(), l.InputsCount())
+	}
+
+	xs, err := UnpackSequence(x, l.sequence.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := matrix.Zeros(x.Rows(), l.size)
+	if err != nil {
+		return nil, err
+	}
+	c := h.Copy()
+
+	rc := cells[l.kind]
+	steps := make([]*step, len(xs))
+	hs := make([]*matrix.Matrix, len(xs))
+	for t := range xs {
+		s := l.newStep()
+		s.hPrev, s.cPrev = h, c
+		if err = rc.forward(s, xs[t]); err != nil {
+			return nil, fmt.Errorf("error computing %d'th step: %w", t, err)
+		}
+		steps[t], hs[t] = s, s.h
+		h, c = s.h, s.c
+	}
+
+	if l.sequence.ReturnSequences {
+		y, err = PackSequence(hs)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		y = h.Copy()
+	}
+
+	l.steps = steps
+	l.y = y.Copy()
+	return y, nil
+}
+
+func (l *RecurrentLayer) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Backward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if dy == nil {
+		return nil, fmt.Errorf("no output gradient provided: %v", dy)
+	} else if l.y == nil || l.steps == nil {
+		return nil, fmt.Errorf("call Backward() before Forward()")
+	} else if err = l.y.CheckEqualShape(dy); err != nil {
+		return nil, fmt.Errorf("error checking output and output gradient shapes: %w", err)
+	}
+
+	count := len(l.steps)
+	dys := make([]*matrix.Matrix, count)
+	if l.sequence.ReturnSequences {
+		dys, err = UnpackSequence(dy, count)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dys[count-1] = dy
+	}
+
+	dh, err := matrix.Zeros(dy.Rows(), l.size)
+	if err != nil {
+		return nil, err
+	}
+	dc := dh.Copy()
+
+	first := 0
+	if l.sequence.Truncation > 0 && l.sequence.Truncation < count {
+		first = count - l.sequence.Truncation
+	}
+
+	for i, g := range l.gates {
+		if err = g.parameters(); err != nil {
+			return nil, fmt.Errorf("error taking %d'th gate's parameters: %w", i, err)
+		}
+	}
+
+	rc := cells[l.kind]
+	dxs := make([]*matrix.Matrix, count)
+	for t := count - 1; t >= first; t-- {
+		if dys[t] != nil {
+			if dh, err = dh.Add(dys[t]); err != nil {
+				return nil, err
+			}
+		}
+		dxs[t], dh, dc, err = rc.backward(l.steps[t], dh, dc)
+		if err != nil {
+			return nil, fmt.Errorf("error computing %d'th step: %w", t, err)
+		}
+	}
+	for t := 0; t < first; t++ {
+		if dxs[t], err = matrix.Zeros(dy.Rows(), l.inputsCount); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, g := range l.gates {
+		stepGates := make([]*gateStep, 0, count-first)
+		for _, s := range l.steps[first:] {
+			stepGates = append(stepGates, s.gates[i])
+		}
+		if err = g.accumulate(stepGates); err != nil {
+			return nil, fmt.Errorf("error accumulating %d'th gate's gradients: %w", i, err)
+		}
+	}
+
+	return PackSequence(dxs)
+}
+
+func (l *RecurrentLayer) ApplyOptim(optimizer operation.Optimizer) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during apply Optimizer on %s", l.kind), &err)
+
+	if l == nil {
+		return ErrNil
+	}
+
+	for i, g := range l.gates {
+		if err = g.applyOptim(optimizer); err != nil {
+			return fmt.Errorf("error optimizing %d'th gate's parameters: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (l *RecurrentLayer) Is(kind nn.Kind) bool {
+	if l == nil {
+		return false
+	}
+	return l.kind == kind
+}
+
+func (l *RecurrentLayer) Kind() nn.Kind {
+	return l.kind
+}
+
+func (l *RecurrentLayer) Output() *matrix.Matrix {
+	if l.y == nil {
+		return nil
+	}
+	return l.y.Copy()
+}
+
+// InputsCount return count of packed sequence's cols: steps * features.
+func (l *RecurrentLayer) InputsCount() int {
+	return l.sequence.Steps * l.inputsCount
+}
+
+// Size return hidden state size, or steps * hidden state size if layer returns sequences.
+func (l *RecurrentLayer) Size() int {
+	if l.sequence.ReturnSequences {
+		return l.sequence.Steps * l.size
+	}
+	return l.size
+}
+
+// Sequence return copy of layer's sequence parameters.
+func (l *RecurrentLayer) Sequence() *SequenceParameters {
+	res := l.sequence
+	return &res
+}
+
+// mapOperations maps operations of gates, steps of the last Forward call refer to replaced operations, so they are
+// dropped.
+func (l *RecurrentLayer) mapOperations(f func(op operation.IOperation) (operation.IOperation, error)) (err error) {
+	for i, g := range l.gates {
+		for _, op := range []*operation.IOperation{&g.weight, &g.recurrent, &g.bias, &g.activation} {
+			if *op, err = f(*op); err != nil {
+				return fmt.Errorf("error mapping %d'th gate: %w", i, err)
+			}
+		}
+	}
+	l.steps = nil
+	return nil
+}
+
+func (l *RecurrentLayer) Copy() nn.IModule {
+	if l == nil {
+		return nil
+	}
+	res := &RecurrentLayer{
+		kind:        l.kind,
+		gates:       make([]*gate, len(l.gates)),
+		inputsCount: l.inputsCount,
+		size:        l.size,
+		sequence:    l.sequence,
+	}
+	for i, g := range l.gates {
+		res.gates[i] = g.copy()
+	}
+	if l.steps != nil {
+		res.steps = make([]*step, len(l.steps))
+		for i, s := range l.steps {
+			res.steps[i] = s.copy(res.gates)
+		}
+	}
+	if l.y != nil {
+		res.y = l.y.Copy()
+	}
+	return res
+}
+
+func (l *RecurrentLayer) Equal(layer nn.IModule) bool {
+	return l.equal(layer, func(a, b nn.IModule) bool { return a.Equal(b) },
+		func(a, b *matrix.Matrix) bool { return a.Equal(b) })
+}
+
+func (l *RecurrentLayer) EqualApprox(layer nn.IModule) bool {
+	return l.equal(layer, func(a, b nn.IModule) bool { return a.EqualApprox(b) },
+		func(a, b *matrix.Matrix) bool { return a.EqualApprox(b) })
+}
+
+func (l *RecurrentLayer) equal(
+	layer nn.IModule,
+	modulesEqual func(a, b nn.IModule) bool,
+	matricesEqual func(a, b *matrix.Matrix) bool,
+) bool {
+	if l == nil || layer == nil {
+		if (l != nil && layer == nil) || (l == nil && layer != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	la, ok := layer.(*RecurrentLayer)
+	if !ok {
+		return false
+	} else if l.kind != la.kind {
+		return false
+	} else if l.inputsCount != la.inputsCount || l.size != la.size {
+		return false
+	} else if l.sequence != la.sequence {
+		return false
+	} else if len(l.gates) != len(la.gates) {
+		return false
+	} else if l.y != nil && !matricesEqual(l.y, la.y) {
+		return false
+	}
+	for i := range l.gates {
+		if !l.gates[i].equal(la.gates[i], modulesEqual) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *RecurrentLayer) operationsAsSPStringers() []utils.SPStringer {
+	res := make([]utils.SPStringer, 0, 4*len(l.gates))
+	for _, g := range l.gates {
+		for _, op := range g.operations() {
+			res = append(res, op)
+		}
+	}
+	return res
+}
+
+func (l *RecurrentLayer) toMap(stringers func(s []utils.SPStringer) string) map[string]string {
+	return map[string]string{
+		"kind":       string(l.kind),
+		"operations": stringers(l.operationsAsSPStringers()),
+		"steps":      fmt.Sprintf("%d", l.sequence.Steps),
+		"truncation": fmt.Sprintf("%d", l.sequence.Truncation),
+	}
+}
+
+func (l *RecurrentLayer) String() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.Strings), utils.BaseFormat)
+}
+
+func (l *RecurrentLayer) PrettyString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.PrettyStrings), utils.PrettyFormat)
+}
+
+func (l *RecurrentLayer) ShortString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.ShortStrings), utils.ShortFormat)
+}
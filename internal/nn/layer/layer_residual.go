@@ -0,0 +1,232 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+var _ ILayer = (*ResidualLayer)(nil)
+
+// ResidualLayer represents Layer with skip-connection: output of Layer's operations is summed with layer's input.
+// When inputs count mismatch layer's size, input is projected by weight multiply operation before summation:
+//     y = f(x) + x, if inputs count == size;
+//     y = f(x) + x * P, otherwise.
+type ResidualLayer struct {
+	*Layer
+
+	projection operation.IOperation // nil for identity skip-connection
+
+	y *matrix.Matrix
+}
+
+func (l *ResidualLayer) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Forward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if x == nil {
+		return nil, fmt.Errorf("no input provided: %v", x)
+	}
+
+	main, err := l.Layer.Forward(x)
+	if err != nil {
+		return nil, fmt.Errorf("error computing main path: %w", err)
+	}
+
+	skip, err := l.skipForward(x)
+	if err != nil {
+		return nil, fmt.Errorf("error computing skip path: %w", err)
+	}
+
+	y, err = main.Add(skip)
+	if err != nil {
+		return nil, fmt.Errorf("error summing main and skip paths: %w", err)
+	}
+
+	l.y = y.Copy()
+	return y, nil
+}
+
+func (l *ResidualLayer) skipForward(x *matrix.Matrix) (*matrix.Matrix, error) {
+	if l.projection == nil {
+		return x.Copy(), nil
+	}
+	return l.projection.Forward(x)
+}
+
+// Backward return input gradient as sum of gradients of main path (Layer's operations) and skip path (identity or
+// projection).
+func (l *ResidualLayer) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Backward propagation on %s", l.kind), &err)
+
+	if l == nil {
+		return nil, ErrNil
+	} else if dy == nil {
+		return nil, fmt.Errorf("no output gradient provided: %v", dy)
+	} else if l.y == nil {
+		return nil, fmt.Errorf("call Backward() before Forward()")
+	} else if err = l.y.CheckEqualShape(dy); err != nil {
+		return nil, fmt.Errorf("error checking output and output gradient shapes: %w", err)
+	}
+
+	main, err := l.Layer.Backward(dy)
+	if err != nil {
+		return nil, fmt.Errorf("error computing main path gradient: %w", err)
+	}
+
+	skip, err := l.skipBackward(dy)
+	if err != nil {
+		return nil, fmt.Errorf("error computing skip path gradient: %w", err)
+	}
+
+	return main.Add(skip)
+}
+
+func (l *ResidualLayer) skipBackward(dy *matrix.Matrix) (*matrix.Matrix, error) {
+	if l.projection == nil {
+		return dy.Copy(), nil
+	}
+	return l.projection.Backward(dy)
+}
+
+func (l *ResidualLayer) ApplyOptim(optimizer operation.Optimizer) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during apply Optimizer on %s", l.kind), &err)
+
+	if l == nil {
+		return ErrNil
+	}
+
+	if err = l.Layer.ApplyOptim(optimizer); err != nil {
+		return err
+	}
+
+	if projection, ok := l.projection.(operation.IParamOperation); ok {
+		if err = projection.ApplyOptim(optimizer); err != nil {
+			return fmt.Errorf("error optimizing projection: %w", err)
+		}
+	}
+	return nil
+}
+
+func (l *ResidualLayer) Output() *matrix.Matrix {
+	if l.y == nil {
+		return nil
+	}
+	return l.y.Copy()
+}
+
+func (l *ResidualLayer) mapOperations(f func(op operation.IOperation) (operation.IOperation, error)) (err error) {
+	if err = l.Layer.mapOperations(f); err != nil {
+		return err
+	}
+	if l.projection != nil {
+		if l.projection, err = f(l.projection); err != nil {
+			return fmt.Errorf("error mapping projection: %w", err)
+		}
+	}
+	return nil
+}
+
+func (l *ResidualLayer) Copy() nn.IModule {
+	if l == nil {
+		return nil
+	}
+	res := &ResidualLayer{
+		Layer: l.Layer.Copy().(*Layer),
+	}
+	if l.projection != nil {
+		res.projection = l.projection.Copy().(operation.IOperation)
+	}
+	if l.y != nil {
+		res.y = l.y.Copy()
+	}
+	return res
+}
+
+func (l *ResidualLayer) Equal(layer nn.IModule) bool {
+	if l == nil || layer == nil {
+		if (l != nil && layer == nil) || (l == nil && layer != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if la, ok := layer.(*ResidualLayer); !ok {
+		return false
+	} else if !l.Layer.Equal(la.Layer) {
+		return false
+	} else if (l.projection == nil) != (la.projection == nil) {
+		return false
+	} else if l.projection != nil && !l.projection.Equal(la.projection) {
+		return false
+	} else if l.y != nil && !l.y.Equal(la.y) {
+		return false
+	}
+
+	return true
+}
+
+func (l *ResidualLayer) EqualApprox(layer nn.IModule) bool {
+	if l == nil || layer == nil {
+		if (l != nil && layer == nil) || (l == nil && layer != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if la, ok := layer.(*ResidualLayer); !ok {
+		return false
+	} else if !l.Layer.EqualApprox(la.Layer) {
+		return false
+	} else if (l.projection == nil) != (la.projection == nil) {
+		return false
+	} else if l.projection != nil && !l.projection.EqualApprox(la.projection) {
+		return false
+	} else if l.y != nil && !l.y.EqualApprox(la.y) {
+		return false
+	}
+
+	return true
+}
+
+func (l *ResidualLayer) toMap(
+	stringer func(s utils.SPStringer) string,
+	stringers func(s []utils.SPStringer) string,
+) map[string]string {
+	res := l.Layer.toMap(stringers)
+	if l.projection != nil {
+		res["projection"] = stringer(l.projection)
+	}
+	return res
+}
+
+func (l *ResidualLayer) String() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.String, utils.Strings), utils.BaseFormat)
+}
+
+func (l *ResidualLayer) PrettyString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.PrettyString, utils.PrettyStrings), utils.PrettyFormat)
+}
+
+func (l *ResidualLayer) ShortString() string {
+	if l == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(l.toMap(utils.ShortString, utils.ShortStrings), utils.ShortFormat)
+}
@@ -0,0 +1,39 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/pkg/wraperr"
+)
+
+// operationsMapper is implemented by layers built from operations: mapOperations replaces each of layer's operations
+// by result of given function.
+type operationsMapper interface {
+	mapOperations(f func(op operation.IOperation) (operation.IOperation, error)) error
+}
+
+// ConvertPrecision return copy of given layer with operations converted to given precision by
+// operation.ConvertPrecision. Layers not built from operations (EmbeddingLayer) always store parameters in
+// nn.Float64 precision, so they are just copied.
+//
+// Throws ErrExec error.
+func ConvertPrecision(l ILayer, precision nn.Precision) (res ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if l == nil {
+		return nil, ErrNil
+	}
+
+	res = l.Copy().(ILayer)
+	if mapper, ok := res.(operationsMapper); ok {
+		err = mapper.mapOperations(func(op operation.IOperation) (operation.IOperation, error) {
+			return operation.ConvertPrecision(op, precision)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error converting %s to %s precision: %w", l.Kind(), precision, err)
+		}
+	}
+	return res, nil
+}
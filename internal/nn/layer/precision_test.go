@@ -0,0 +1,113 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/nn/operation/operationtestutils"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+// requireClose asserts matrices have the same shape and values differing by float32 rounding errors only.
+func requireClose(t *testing.T, expected, actual *matrix.Matrix) {
+	require.Equal(t, expected.Rows(), actual.Rows())
+	require.Equal(t, expected.Cols(), actual.Cols())
+	actualValues := actual.RawFlat()
+	for i, value := range expected.RawFlat() {
+		require.InDelta(t, value, actualValues[i], 1e-4*math.Max(1, math.Abs(value)))
+	}
+}
+
+func TestConvertPrecision(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		l  ILayer
+		in *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "dense"},
+			l: newLayer(t, DenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+				operationtestutils.NewOperation(t, operation.SigmoidActivation),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 3}),
+		},
+		{
+			Base: testutils.Base{Name: "residual dense with projection"},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 3}),
+		},
+		{
+			Base: testutils.Base{Name: "lstm"},
+			l:    newTestRecurrentLayer(t, LSTMLayer, newGates(t, 4, 2, 3), &SequenceParameters{Steps: 3}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 6}),
+		},
+		{
+			Base: testutils.Base{Name: "attention"},
+			l:    newTestAttentionLayer(t, attentionParams(t, 2, 4, 3), &AttentionParameters{Steps: 3, Heads: 2}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 6}),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l32, err := ConvertPrecision(tc.l, nn.Float32Params)
+			require.NoError(t, err)
+			require.False(t, tc.l.Equal(l32))
+
+			y, err := tc.l.Forward(tc.in)
+			require.NoError(t, err)
+			y32, err := l32.Forward(tc.in)
+			require.NoError(t, err)
+			requireClose(t, y, y32)
+
+			dx, err := tc.l.Backward(y)
+			require.NoError(t, err)
+			dx32, err := l32.Backward(y)
+			require.NoError(t, err)
+			requireClose(t, dx, dx32)
+			require.NoError(t, l32.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+				return param.SubInPlace(grad.MulNumInPlace(0.1))
+			}))
+
+			back, err := ConvertPrecision(l32, nn.Float64)
+			require.NoError(t, err)
+			y64, err := back.Forward(tc.in)
+			require.NoError(t, err)
+			y32, err = l32.Forward(tc.in)
+			require.NoError(t, err)
+			requireClose(t, y32, y64)
+		})
+	}
+
+	embedding := newLayer(t, CategoricalEmbeddingLayer, 2, []int{0}, embeddingTables(t, 2, 3))
+	converted, err := ConvertPrecision(embedding, nn.Float32Params)
+	require.NoError(t, err)
+	require.True(t, embedding.Equal(converted))
+
+	_, err = ConvertPrecision(nil, nn.Float32Params)
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestBuilder_Precision(t *testing.T) {
+	builder, err := NewBuilder(DenseLayer)
+	require.NoError(t, err)
+	l, err := builder.InputsCount(3).NeuronsCount(4).ActivationKind(operation.SigmoidActivation).
+		Precision(nn.Float32Params).Build()
+	require.NoError(t, err)
+
+	casted, ok := l.(*Layer)
+	require.True(t, ok)
+	require.IsType(t, &operation.Param32Operation{}, casted.operations[0])
+	require.IsType(t, &operation.Param32Operation{}, casted.operations[1])
+}
@@ -0,0 +1,31 @@
+package layer
+
+import (
+	"fmt"
+	"nn/internal/nn/operation"
+	"nn/pkg/wraperr"
+)
+
+// Prune return copy of given layer with weights pruned by operation.Prune. Layers not built from operations
+// (EmbeddingLayer) are just copied.
+//
+// Throws ErrExec error.
+func Prune(l ILayer, threshold float64) (res ILayer, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if l == nil {
+		return nil, ErrNil
+	}
+
+	res = l.Copy().(ILayer)
+	if mapper, ok := res.(operationsMapper); ok {
+		err = mapper.mapOperations(func(op operation.IOperation) (operation.IOperation, error) {
+			return operation.Prune(op, threshold)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error pruning %s: %w", l.Kind(), err)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,105 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn/operation"
+	"nn/internal/nn/operation/operationtestutils"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		l  ILayer
+		in *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "dense"},
+			l: newLayer(t, DenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+				operationtestutils.NewOperation(t, operation.SigmoidActivation),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 3}),
+		},
+		{
+			Base: testutils.Base{Name: "residual dense with projection"},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 3}),
+		},
+		{
+			Base: testutils.Base{Name: "lstm"},
+			l:    newTestRecurrentLayer(t, LSTMLayer, newGates(t, 4, 2, 3), &SequenceParameters{Steps: 3}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 6}),
+		},
+		{
+			Base: testutils.Base{Name: "attention"},
+			l:    newTestAttentionLayer(t, attentionParams(t, 2, 4, 3), &AttentionParameters{Steps: 3, Heads: 2}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 6}),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// zero threshold keeps all weights, so pruned layer computes the same
+			pruned, err := Prune(tc.l, 0)
+			require.NoError(t, err)
+			require.False(t, tc.l.Equal(pruned))
+			sparseCount := 0
+			require.NoError(t, pruned.(operationsMapper).mapOperations(
+				func(op operation.IOperation) (operation.IOperation, error) {
+					if op.Is(operation.WeightMultiply) {
+						require.IsType(t, &operation.SparseOperation{}, op)
+						sparseCount++
+					}
+					return op, nil
+				}))
+			require.NotZero(t, sparseCount)
+
+			y, err := tc.l.Forward(tc.in)
+			require.NoError(t, err)
+			yPruned, err := pruned.Forward(tc.in)
+			require.NoError(t, err)
+			require.True(t, y.EqualApprox(yPruned), "%s != %s", y, yPruned)
+
+			dx, err := tc.l.Backward(y)
+			require.NoError(t, err)
+			dxPruned, err := pruned.Backward(y)
+			require.NoError(t, err)
+			require.True(t, dx.EqualApprox(dxPruned), "%s != %s", dx, dxPruned)
+			require.NoError(t, pruned.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+				return param.SubInPlace(grad.MulNumInPlace(0.1))
+			}))
+		})
+	}
+
+	// all weights of dense layer are pruned, so its output depends on bias only
+	dense := newLayer(t, DenseLayer,
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{0.1, -0.2, 0.3, 0.4}}),
+		testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{1, 2}}),
+		operationtestutils.NewOperation(t, operation.LinearActivation),
+	)
+	pruned, err := Prune(dense, 0.5)
+	require.NoError(t, err)
+	y, err := pruned.Forward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2}))
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 2}, y.RawFlat())
+
+	embedding := newLayer(t, CategoricalEmbeddingLayer, 2, []int{0}, embeddingTables(t, 2, 3))
+	prunedEmbedding, err := Prune(embedding, 0.5)
+	require.NoError(t, err)
+	require.True(t, embedding.Equal(prunedEmbedding))
+
+	_, err = Prune(nil, 0)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = Prune(dense, -1)
+	require.ErrorIs(t, err, ErrExec)
+}
@@ -0,0 +1,227 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+// rbfParams return parameters of radial basis function layer in order of its operations: centers, widths (1 x k),
+// weight, bias (1 x size).
+func rbfParams(t *testing.T, inputs, centers, size int) []*matrix.Matrix {
+	return []*matrix.Matrix{
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: centers, Cols: inputs}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: centers}).AddNum(0.5),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: centers, Cols: size}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: size}),
+	}
+}
+
+func newTestRBFLayer(t *testing.T, kernel nn.Kind, params []*matrix.Matrix) ILayer {
+	widths, err := params[1].GetRow(0)
+	require.NoError(t, err)
+	bias, err := params[3].GetRow(0)
+	require.NoError(t, err)
+	k, err := operation.Create(kernel)
+	require.NoError(t, err)
+	return newLayer(t, RBFLayer, params[0], widths, k, params[2], bias)
+}
+
+func TestNewRBFLayer(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		kernel  operation.IOperation
+		centers int
+		widths  int
+		weight  int
+	}{
+		{Base: testutils.Base{Name: "valid"}, kernel: operation.NewGaussianActivation(), centers: 4, widths: 4, weight: 4},
+		{
+			Base:    testutils.Base{Name: "not a radial basis kernel", Err: ErrCreate},
+			kernel:  operation.NewTanhActivation(),
+			centers: 4, widths: 4, weight: 4,
+		},
+		{
+			Base:    testutils.Base{Name: "widths count mismatch", Err: ErrCreate},
+			kernel:  operation.NewGaussianActivation(),
+			centers: 4, widths: 3, weight: 4,
+		},
+		{
+			Base:    testutils.Base{Name: "weight rows mismatch", Err: ErrCreate},
+			kernel:  operation.NewGaussianActivation(),
+			centers: 4, widths: 4, weight: 5,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := NewRBFLayer(
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: tc.centers, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: tc.widths}),
+				tc.kernel,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: tc.weight, Cols: 3}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+			)
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, l.Is(RBFLayer))
+			require.Equal(t, 2, l.InputsCount())
+			require.Equal(t, 3, l.Size())
+		})
+	}
+}
+
+func TestRBFLayer_Forward(t *testing.T) {
+	params := []*matrix.Matrix{
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{0, 2}}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{1, 2}}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{1, 2}}),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{0.5}}),
+	}
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{2}})
+
+	// distances are 4 and 0, scaled by widths: 4 and 0
+	y, err := newTestRBFLayer(t, operation.GaussianActivation, params).Forward(x)
+	require.NoError(t, err)
+	require.InDelta(t, math.Exp(-4)+2+0.5, y.RawFlat()[0], 1e-12)
+
+	y, err = newTestRBFLayer(t, operation.MultiquadricActivation, params).Forward(x)
+	require.NoError(t, err)
+	require.InDelta(t, math.Sqrt(5)+2+0.5, y.RawFlat()[0], 1e-12)
+
+	y, err = newTestRBFLayer(t, operation.InverseMultiquadricActivation, params).Forward(x)
+	require.NoError(t, err)
+	require.InDelta(t, 1/math.Sqrt(5)+2+0.5, y.RawFlat()[0], 1e-12)
+}
+
+func TestRBFLayer_Backward(t *testing.T) {
+	const (
+		inputs, centers, size, batch = 2, 4, 3, 5
+		eps                          = 1e-6
+		tolerance                    = 1e-5
+	)
+
+	for _, kernel := range []nn.Kind{
+		operation.GaussianActivation,
+		operation.MultiquadricActivation,
+		operation.InverseMultiquadricActivation,
+	} {
+		t.Run(string(kernel), func(t *testing.T) {
+			params := rbfParams(t, inputs, centers, size)
+			l := newTestRBFLayer(t, kernel, params)
+			x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: inputs})
+			r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: size})
+
+			testutils.LinearLoss(t, l.Forward, x, r)
+			dx, err := l.Backward(r)
+			require.NoError(t, err)
+			grads := collectGradients(t, l)
+			require.Len(t, grads, len(params))
+
+			for i := range x.RawFlat() {
+				numeric := testutils.NumericGradient(t, func(x *matrix.Matrix) float64 {
+					return testutils.LinearLoss(t, l.Forward, x, r)
+				}, x, i, eps)
+				require.InDelta(t, numeric, dx.RawFlat()[i], tolerance, "input gradient %d", i)
+			}
+
+			for pi, p := range params {
+				for i := range p.RawFlat() {
+					numeric := testutils.NumericGradient(t, func(p *matrix.Matrix) float64 {
+						shifted := append([]*matrix.Matrix(nil), params...)
+						shifted[pi] = p
+						return testutils.LinearLoss(t, newTestRBFLayer(t, kernel, shifted).Forward, x, r)
+					}, p, i, eps)
+					require.InDelta(t, numeric, grads[pi].RawFlat()[i], tolerance, "parameter %d, value %d", pi, i)
+				}
+			}
+		})
+	}
+}
+
+func TestRBFWidths(t *testing.T) {
+	centers := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{0, 0, 3, 4}})
+	widths, err := RBFWidths(centers)
+	require.NoError(t, err)
+	require.Equal(t, 2, widths.Size())
+	require.InDeltaSlice(t, []float64{2.5, 2.5}, widths.Raw(), 1e-12)
+
+	single := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{1, 1}})
+	widths, err = RBFWidths(single)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1}, widths.Raw())
+}
+
+func TestBuilder_BuildRBF(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		builder func(t *testing.T) *Builder
+		kernel  nn.Kind
+	}{
+		{
+			Base: testutils.Base{Name: "random centers and default kernel"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(RBFLayer)
+				require.NoError(t, err)
+				return b.InputsCount(2).CentersCount(4).NeuronsCount(3)
+			},
+			kernel: operation.GaussianActivation,
+		},
+		{
+			Base: testutils.Base{Name: "provided centers and kernel"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(RBFLayer)
+				require.NoError(t, err)
+				return b.Centers(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 2})).
+					NeuronsCount(3).
+					ActivationKind(operation.MultiquadricActivation)
+			},
+			kernel: operation.MultiquadricActivation,
+		},
+		{
+			Base: testutils.Base{Name: "no centers count", Err: ErrBuilder},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(RBFLayer)
+				require.NoError(t, err)
+				return b.InputsCount(2).NeuronsCount(3)
+			},
+		},
+		{
+			Base: testutils.Base{Name: "not a radial basis kernel", Err: ErrBuilder},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(RBFLayer)
+				require.NoError(t, err)
+				return b.InputsCount(2).CentersCount(4).NeuronsCount(3).ActivationKind(operation.TanhActivation)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := tc.builder(t).Build()
+			if tc.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, l.Is(RBFLayer))
+			require.Equal(t, 2, l.InputsCount())
+			require.Equal(t, 3, l.Size())
+
+			casted, ok := l.(*Layer)
+			require.True(t, ok)
+			require.True(t, casted.operations[2].Is(tc.kernel))
+			require.Equal(t, 4, casted.operations[3].(*operation.ParamOperation).Parameter().Rows())
+		})
+	}
+}
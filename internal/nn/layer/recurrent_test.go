@@ -0,0 +1,452 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"nn/internal/nn"
+	"nn/internal/nn/operation"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+func newGate(t *testing.T, inputs, size int) *GateParameters {
+	return &GateParameters{
+		Weight:    testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: inputs, Cols: size}),
+		Recurrent: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: size, Cols: size}),
+		Bias:      testfactories.NewVector(t, testfactories.VectorParameters{Size: size}),
+	}
+}
+
+func newGates(t *testing.T, count, inputs, size int) []*GateParameters {
+	gates := make([]*GateParameters, count)
+	for i := range gates {
+		gates[i] = newGate(t, inputs, size)
+	}
+	return gates
+}
+
+func newTestRecurrentLayer(t *testing.T, kind nn.Kind, gates []*GateParameters, sequence *SequenceParameters) ILayer {
+	var args []interface{}
+	switch kind {
+	case RNNLayer:
+		args = []interface{}{gates[0], operation.NewTanhActivation(), sequence}
+	default:
+		for _, g := range gates {
+			args = append(args, g)
+		}
+		args = append(args, sequence)
+	}
+	return newLayer(t, kind, args...)
+}
+
+func TestSequence_PackUnpack(t *testing.T) {
+	packed := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 6, Values: []float64{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	}})
+
+	steps, err := UnpackSequence(packed, 3)
+	require.NoError(t, err)
+	require.Len(t, steps, 3)
+	require.True(t, steps[1].Equal(testfactories.NewMatrix(t, testfactories.MatrixParameters{
+		Rows: 2, Cols: 2, Values: []float64{3, 4, 9, 10},
+	})))
+
+	repacked, err := PackSequence(steps)
+	require.NoError(t, err)
+	require.True(t, packed.Equal(repacked))
+
+	_, err = UnpackSequence(packed, 4)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = PackSequence(nil)
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestNewRecurrentLayer(t *testing.T) {
+	sequence := &SequenceParameters{Steps: 3}
+	testcases := []struct {
+		testutils.Base
+		kind     nn.Kind
+		gates    []*GateParameters
+		sequence *SequenceParameters
+	}{
+		{
+			Base:     testutils.Base{Name: "rnn"},
+			kind:     RNNLayer,
+			gates:    newGates(t, 1, 2, 3),
+			sequence: sequence,
+		},
+		{
+			Base:     testutils.Base{Name: "lstm"},
+			kind:     LSTMLayer,
+			gates:    newGates(t, 4, 2, 3),
+			sequence: sequence,
+		},
+		{
+			Base:     testutils.Base{Name: "gru"},
+			kind:     GRULayer,
+			gates:    newGates(t, 3, 2, 3),
+			sequence: sequence,
+		},
+		{
+			Base:  testutils.Base{Name: "no sequence", Err: ErrCreate},
+			kind:  RNNLayer,
+			gates: newGates(t, 1, 2, 3),
+		},
+		{
+			Base:     testutils.Base{Name: "zero steps", Err: ErrCreate},
+			kind:     RNNLayer,
+			gates:    newGates(t, 1, 2, 3),
+			sequence: &SequenceParameters{},
+		},
+		{
+			Base:     testutils.Base{Name: "negative truncation", Err: ErrCreate},
+			kind:     RNNLayer,
+			gates:    newGates(t, 1, 2, 3),
+			sequence: &SequenceParameters{Steps: 3, Truncation: -1},
+		},
+		{
+			Base:     testutils.Base{Name: "gates shapes mismatch", Err: ErrCreate},
+			kind:     GRULayer,
+			gates:    append(newGates(t, 2, 2, 3), newGate(t, 3, 3)),
+			sequence: sequence,
+		},
+		{
+			Base: testutils.Base{Name: "wrong recurrent shape", Err: ErrCreate},
+			kind: RNNLayer,
+			gates: []*GateParameters{{
+				Weight:    testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+				Recurrent: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+				Bias:      testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+			}},
+			sequence: sequence,
+		},
+		{
+			Base: testutils.Base{Name: "wrong bias size", Err: ErrCreate},
+			kind: RNNLayer,
+			gates: []*GateParameters{{
+				Weight:    testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+				Recurrent: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 3}),
+				Bias:      testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+			}},
+			sequence: sequence,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var l ILayer
+			var err error
+			switch tc.kind {
+			case RNNLayer:
+				l, err = NewRNNLayer(tc.gates[0], operation.NewTanhActivation(), tc.sequence)
+			case LSTMLayer:
+				l, err = NewLSTMLayer(tc.gates[0], tc.gates[1], tc.gates[2], tc.gates[3], tc.sequence)
+			case GRULayer:
+				l, err = NewGRULayer(tc.gates[0], tc.gates[1], tc.gates[2], tc.sequence)
+			}
+			if tc.Err == nil {
+				require.NoError(t, err)
+				require.True(t, l.Is(tc.kind))
+				require.Equal(t, 6, l.InputsCount())
+				require.Equal(t, 3, l.Size())
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
+
+func TestRecurrentLayer_Forward(t *testing.T) {
+	gate := &GateParameters{
+		Weight:    testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{2}}),
+		Recurrent: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{0.5}}),
+		Bias:      testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{1}}),
+	}
+	in := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{
+		1, 2,
+		0, -1,
+	}})
+	testcases := []struct {
+		testutils.Base
+		l        ILayer
+		in       *matrix.Matrix
+		expected *matrix.Matrix
+	}{
+		{
+			// h1 = 2 * 1 + 1 = 3; h2 = 2 * 2 + 0.5 * 3 + 1 = 6.5
+			// h1 = 2 * 0 + 1 = 1; h2 = 2 * -1 + 0.5 * 1 + 1 = -0.5
+			Base:     testutils.Base{Name: "last step"},
+			l:        newLayer(t, RNNLayer, gate, operation.NewLinearActivation(), &SequenceParameters{Steps: 2}),
+			in:       in,
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 1, Values: []float64{6.5, -0.5}}),
+		},
+		{
+			Base: testutils.Base{Name: "return sequences"},
+			l: newLayer(t, RNNLayer, gate, operation.NewLinearActivation(),
+				&SequenceParameters{Steps: 2, ReturnSequences: true}),
+			in: in,
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{
+				3, 6.5,
+				1, -0.5,
+			}}),
+		},
+		{
+			Base: testutils.Base{Name: "wrong input cols count", Err: ErrExec},
+			l:    newLayer(t, RNNLayer, gate, operation.NewLinearActivation(), &SequenceParameters{Steps: 3}),
+			in:   in,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual, err := tc.l.Forward(tc.in)
+			if tc.Err == nil {
+				require.NoError(t, err)
+				require.True(t, tc.expected.EqualApprox(actual))
+				require.True(t, tc.expected.EqualApprox(tc.l.Output()))
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
+
+// collectGradients return parameters gradients of layer in order of gates and their operations.
+func collectGradients(t *testing.T, l ILayer) []*matrix.Matrix {
+	var grads []*matrix.Matrix
+	require.NoError(t, l.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		grads = append(grads, grad)
+		return param, nil
+	}))
+	return grads
+}
+
+func gateParams(g *GateParameters) []*matrix.Matrix {
+	bias, _ := matrix.NewMatrix([]*vector.Vector{g.Bias})
+	return []*matrix.Matrix{g.Weight, g.Recurrent, bias}
+}
+
+func withParam(t *testing.T, gates []*GateParameters, gateIdx, paramIdx int, value *matrix.Matrix) []*GateParameters {
+	res := make([]*GateParameters, len(gates))
+	copy(res, gates)
+	g := *gates[gateIdx]
+	switch paramIdx {
+	case 0:
+		g.Weight = value
+	case 1:
+		g.Recurrent = value
+	case 2:
+		bias, err := value.GetRow(0)
+		require.NoError(t, err)
+		g.Bias = bias
+	}
+	res[gateIdx] = &g
+	return res
+}
+
+func TestRecurrentLayer_Backward(t *testing.T) {
+	const (
+		inputs, size, steps, batch = 2, 3, 4, 2
+		eps                        = 1e-6
+		tolerance                  = 1e-5
+	)
+	testcases := []struct {
+		testutils.Base
+		kind     nn.Kind
+		sequence *SequenceParameters
+	}{
+		{Base: testutils.Base{Name: "rnn"}, kind: RNNLayer, sequence: &SequenceParameters{Steps: steps}},
+		{Base: testutils.Base{Name: "rnn sequences"}, kind: RNNLayer,
+			sequence: &SequenceParameters{Steps: steps, ReturnSequences: true}},
+		{Base: testutils.Base{Name: "lstm"}, kind: LSTMLayer, sequence: &SequenceParameters{Steps: steps}},
+		{Base: testutils.Base{Name: "lstm sequences"}, kind: LSTMLayer,
+			sequence: &SequenceParameters{Steps: steps, ReturnSequences: true}},
+		{Base: testutils.Base{Name: "gru"}, kind: GRULayer, sequence: &SequenceParameters{Steps: steps}},
+		{Base: testutils.Base{Name: "gru sequences"}, kind: GRULayer,
+			sequence: &SequenceParameters{Steps: steps, ReturnSequences: true}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			gates := newGates(t, cells[tc.kind].gatesCount, inputs, size)
+			l := newTestRecurrentLayer(t, tc.kind, gates, tc.sequence)
+			x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: l.InputsCount()})
+			r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: l.Size()})
+
+			testutils.LinearLoss(t, l.Forward, x, r)
+			dx, err := l.Backward(r)
+			require.NoError(t, err)
+			grads := collectGradients(t, l)
+			require.Len(t, grads, 3*len(gates))
+
+			for i := range x.RawFlat() {
+				numeric := testutils.NumericGradient(t, func(x *matrix.Matrix) float64 {
+					return testutils.LinearLoss(t, l.Forward, x, r)
+				}, x, i, eps)
+				require.InDelta(t, numeric, dx.RawFlat()[i], tolerance, "input gradient %d", i)
+			}
+
+			for gi, g := range gates {
+				for pi, p := range gateParams(g) {
+					for i := range p.RawFlat() {
+						numeric := testutils.NumericGradient(t, func(p *matrix.Matrix) float64 {
+							shifted := newTestRecurrentLayer(t, tc.kind, withParam(t, gates, gi, pi, p), tc.sequence)
+							return testutils.LinearLoss(t, shifted.Forward, x, r)
+						}, p, i, eps)
+						require.InDelta(t, numeric, grads[3*gi+pi].RawFlat()[i], tolerance,
+							"gate %d, parameter %d, value %d", gi, pi, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrentLayer_BackwardTruncated(t *testing.T) {
+	gate := &GateParameters{
+		Weight:    testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{2}}),
+		Recurrent: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{0.5}}),
+		Bias:      testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{1}}),
+	}
+	l := newLayer(t, RNNLayer, gate, operation.NewLinearActivation(), &SequenceParameters{Steps: 3, Truncation: 1})
+	in := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{1, 2, 3}})
+
+	// h1 = 3; h2 = 4 + 1.5 + 1 = 6.5; h3 = 6 + 3.25 + 1 = 10.25
+	_, err := l.Forward(in)
+	require.NoError(t, err)
+	dx, err := l.Backward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 1, Values: []float64{1}}))
+	require.NoError(t, err)
+	require.True(t, dx.EqualApprox(testfactories.NewMatrix(t, testfactories.MatrixParameters{
+		Rows: 1, Cols: 3, Values: []float64{0, 0, 2},
+	})))
+
+	// only last step contributes: dW = x3, dU = h2, db = 1
+	grads := collectGradients(t, l)
+	require.Len(t, grads, 3)
+	require.InDelta(t, 3, grads[0].Sum(), 1e-9)
+	require.InDelta(t, 6.5, grads[1].Sum(), 1e-9)
+	require.InDelta(t, 1, grads[2].Sum(), 1e-9)
+}
+
+func TestRecurrentLayer_ApplyOptim(t *testing.T) {
+	l := newTestRecurrentLayer(t, LSTMLayer, newGates(t, 4, 2, 3), &SequenceParameters{Steps: 2})
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: l.InputsCount()})
+	r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: l.Size()})
+	sgd := func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad.MulNum(0.01))
+	}
+
+	require.ErrorIs(t, l.ApplyOptim(sgd), ErrExec)
+
+	before := testutils.LinearLoss(t, l.Forward, x, r)
+	_, err := l.Backward(r)
+	require.NoError(t, err)
+	require.NoError(t, l.ApplyOptim(sgd))
+	require.Less(t, testutils.LinearLoss(t, l.Forward, x, r), before)
+}
+
+func TestRecurrentLayer_Copy(t *testing.T) {
+	for _, kind := range []nn.Kind{RNNLayer, LSTMLayer, GRULayer} {
+		t.Run(string(kind), func(t *testing.T) {
+			l := newTestRecurrentLayer(t, kind, newGates(t, cells[kind].gatesCount, 2, 3), &SequenceParameters{Steps: 2})
+			x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: l.InputsCount()})
+			_, err := l.Forward(x)
+			require.NoError(t, err)
+
+			cp := l.Copy().(ILayer)
+			require.True(t, l.Equal(cp))
+			require.True(t, l.EqualApprox(cp))
+
+			dy := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: l.Size()})
+			expected, err := l.Backward(dy)
+			require.NoError(t, err)
+			actual, err := cp.Backward(dy)
+			require.NoError(t, err)
+			require.True(t, expected.Equal(actual))
+
+			another := newTestRecurrentLayer(t, kind, newGates(t, cells[kind].gatesCount, 2, 3), &SequenceParameters{Steps: 2})
+			require.False(t, l.Equal(another))
+		})
+	}
+}
+
+func TestBuilder_BuildRecurrent(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		builder func(t *testing.T) *Builder
+		kind    nn.Kind
+	}{
+		{
+			Base: testutils.Base{Name: "rnn with default activation"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(RNNLayer)
+				require.NoError(t, err)
+				return b.InputsCount(2).NeuronsCount(3).Sequence(&SequenceParameters{Steps: 4, Truncation: 2})
+			},
+			kind: RNNLayer,
+		},
+		{
+			Base: testutils.Base{Name: "lstm"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(LSTMLayer)
+				require.NoError(t, err)
+				return b.InputsCount(2).NeuronsCount(3).ParamInitType(operation.GlorotInit).
+					Sequence(&SequenceParameters{Steps: 4})
+			},
+			kind: LSTMLayer,
+		},
+		{
+			Base: testutils.Base{Name: "gru with provided gates"},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(GRULayer)
+				require.NoError(t, err)
+				return b.Gates(newGates(t, 3, 2, 3)...).Sequence(&SequenceParameters{Steps: 4})
+			},
+			kind: GRULayer,
+		},
+		{
+			Base: testutils.Base{Name: "no sequence", Err: ErrBuilder},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(GRULayer)
+				require.NoError(t, err)
+				return b.InputsCount(2).NeuronsCount(3)
+			},
+		},
+		{
+			Base: testutils.Base{Name: "wrong gates count", Err: ErrBuilder},
+			builder: func(t *testing.T) *Builder {
+				b, err := NewBuilder(LSTMLayer)
+				require.NoError(t, err)
+				return b.Gates(newGates(t, 3, 2, 3)...).Sequence(&SequenceParameters{Steps: 4})
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := tc.builder(t).Build()
+			if tc.Err == nil {
+				require.NoError(t, err)
+				require.True(t, l.Is(tc.kind))
+				require.Equal(t, 8, l.InputsCount())
+				require.Equal(t, 3, l.Size())
+
+				casted, ok := l.(*RecurrentLayer)
+				require.True(t, ok)
+				for i := 1; i < len(casted.gates); i++ {
+					require.False(t, casted.gates[0].weight.Equal(casted.gates[i].weight), "gates must differ")
+				}
+				require.False(t, math.IsNaN(casted.gates[0].weight.(*operation.ParamOperation).Parameter().Sum()))
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package layer
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn/operation"
+	"nn/internal/nn/operation/operationtestutils"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+func TestNewResidualDenseLayer(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		weight     *matrix.Matrix
+		bias       *vector.Vector
+		activation operation.IOperation
+		projection *matrix.Matrix
+	}{
+		{
+			Base:       testutils.Base{Name: "identity skip-connection"},
+			weight:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+			bias:       testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+			activation: operationtestutils.NewOperation(t, operation.TanhActivation),
+		},
+		{
+			Base:       testutils.Base{Name: "projection skip-connection"},
+			weight:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+			bias:       testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+			activation: operationtestutils.NewOperation(t, operation.TanhActivation),
+			projection: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+		},
+		{
+			Base:       testutils.Base{Name: "no projection for sizes mismatch", Err: ErrCreate},
+			weight:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+			bias:       testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+			activation: operationtestutils.NewOperation(t, operation.TanhActivation),
+		},
+		{
+			Base:       testutils.Base{Name: "wrong projection shape", Err: ErrCreate},
+			weight:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+			bias:       testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+			activation: operationtestutils.NewOperation(t, operation.TanhActivation),
+			projection: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2}),
+		},
+		{
+			Base:       testutils.Base{Name: "not activation", Err: ErrCreate},
+			weight:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+			bias:       testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+			activation: operationtestutils.NewOperation(t, operation.WeightMultiply, testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2})),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := NewResidualDenseLayer(tc.weight, tc.bias, tc.activation, tc.projection)
+			if tc.Err == nil {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
+
+func TestResidualDenseLayer_Forward(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		l        ILayer
+		in       *matrix.Matrix
+		expected *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "identity skip-connection"},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{1, 2, 3, 4}}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{5, 6}}),
+				operationtestutils.NewOperation(t, operation.LinearActivation),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{10, 11}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2,
+				Values: []float64{10*1 + 11*3 + 5 + 10, 10*2 + 11*4 + 6 + 11},
+			}),
+		},
+		{
+			Base: testutils.Base{Name: "projection skip-connection"},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1, 2, 3, 4, 5, 6}}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{7, 8, 9}}),
+				operationtestutils.NewOperation(t, operation.LinearActivation),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1, 0, 0, 0, 1, 0}}),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{10, 11}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3,
+				Values: []float64{10*1 + 11*4 + 7 + 10, 10*2 + 11*5 + 8 + 11, 10*3 + 11*6 + 9},
+			}),
+		},
+		{
+			Base: testutils.Base{Name: "nil input", Err: ErrExec},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+			),
+		},
+		{
+			Base: testutils.Base{Name: "incorrect input shape", Err: ErrExec},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3}),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			y, err := tc.l.Forward(tc.in)
+			if tc.Err == nil {
+				require.NoError(t, err)
+				require.True(t, y.EqualApprox(tc.expected))
+				require.True(t, tc.l.Output().EqualApprox(tc.expected))
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
+
+func TestResidualDenseLayer_Backward(t *testing.T) {
+	testcases := []struct {
+		testutils.Base
+		l        ILayer
+		in       *matrix.Matrix
+		outGrad  *matrix.Matrix
+		expected *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "identity skip-connection"},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{1, 2, 3, 4}}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{5, 6}}),
+				operationtestutils.NewOperation(t, operation.LinearActivation),
+			),
+			in:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{10, 11}}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{1, 2}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2,
+				Values: []float64{1*1 + 2*2 + 1, 1*3 + 2*4 + 2},
+			}),
+		},
+		{
+			Base: testutils.Base{Name: "projection skip-connection"},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1, 2, 3, 4, 5, 6}}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{7, 8, 9}}),
+				operationtestutils.NewOperation(t, operation.LinearActivation),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1, 0, 0, 0, 1, 0}}),
+			),
+			in:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{10, 11}}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{1, 2, 3}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2,
+				Values: []float64{1*1 + 2*2 + 3*3 + 1, 1*4 + 2*5 + 3*6 + 2},
+			}),
+		},
+		{
+			Base: testutils.Base{Name: "nil out grad", Err: ErrExec},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+			),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2}),
+		},
+		{
+			Base: testutils.Base{Name: "no Forward() call", Err: ErrExec},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+			),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2}),
+		},
+		{
+			Base: testutils.Base{Name: "wrong shape of out grad", Err: ErrExec},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+			),
+			in:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3}),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if tc.in != nil {
+				_, err := tc.l.Forward(tc.in)
+				require.NoError(t, err)
+			}
+			inGrad, err := tc.l.Backward(tc.outGrad)
+			if tc.Err == nil {
+				require.NoError(t, err)
+				require.True(t, inGrad.EqualApprox(tc.expected))
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
+
+func TestResidualDenseLayer_ApplyOptim(t *testing.T) {
+	optimizer := func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad)
+	}
+	testcases := []struct {
+		testutils.Base
+		l         ILayer
+		in        *matrix.Matrix
+		outGrad   *matrix.Matrix
+		optimizer operation.Optimizer
+		expected  ILayer
+	}{
+		{
+			Base: testutils.Base{Name: "projection skip-connection"},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1, 2, 3, 4, 5, 6}}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{7, 8, 9}}),
+				operationtestutils.NewOperation(t, operation.LinearActivation),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1, 0, 0, 0, 1, 0}}),
+			),
+			in:        testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{10, 11}}),
+			outGrad:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{1, 2, 3}}),
+			optimizer: optimizer,
+			expected: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1 - 10, 2 - 20, 3 - 30, 4 - 11, 5 - 22, 6 - 33}}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Values: []float64{7 - 1, 8 - 2, 9 - 3}}),
+				operationtestutils.NewOperation(t, operation.LinearActivation),
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{1 - 10, 0 - 20, 0 - 30, 0 - 11, 1 - 22, 0 - 33}}),
+			),
+		},
+		{
+			Base: testutils.Base{Name: "no optimizer", Err: ErrExec},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+			),
+			in:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2}),
+		},
+		{
+			Base: testutils.Base{Name: "no Backward() call", Err: ErrExec},
+			l: newLayer(t, ResidualDenseLayer,
+				testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2}),
+				testfactories.NewVector(t, testfactories.VectorParameters{Size: 2}),
+				operationtestutils.NewOperation(t, operation.TanhActivation),
+			),
+			in:        testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2}),
+			optimizer: optimizer,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := tc.l.Forward(tc.in)
+			require.NoError(t, err)
+
+			if tc.outGrad != nil {
+				_, err := tc.l.Backward(tc.outGrad)
+				require.NoError(t, err)
+			}
+
+			err = tc.l.ApplyOptim(tc.optimizer)
+			if tc.Err == nil {
+				require.NoError(t, err)
+				require.True(t, tc.expected.EqualApprox(tc.l))
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
+
+func TestResidualDenseLayer_Copy(t *testing.T) {
+	l := newLayer(t, ResidualDenseLayer,
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+		testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+		operationtestutils.NewOperation(t, operation.TanhActivation),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+	)
+	_, err := l.Forward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 2}))
+	require.NoError(t, err)
+
+	c := l.Copy()
+	require.True(t, l.Equal(c))
+	require.True(t, c.Equal(l))
+	require.True(t, c.Is(ResidualDenseLayer))
+
+	another := newLayer(t, ResidualDenseLayer,
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+		testfactories.NewVector(t, testfactories.VectorParameters{Size: 3}),
+		operationtestutils.NewOperation(t, operation.TanhActivation),
+		testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+	)
+	require.False(t, l.Equal(another))
+	require.NotEmpty(t, l.String())
+	require.NotEmpty(t, l.PrettyString())
+	require.NotEmpty(t, l.ShortString())
+}
+
+func TestBuilder_BuildResidual(t *testing.T) {
+	newBuilder := func() *Builder {
+		res, err := NewBuilder(ResidualDenseLayer)
+		require.NoError(t, err)
+		return res.ActivationKind(operation.TanhActivation)
+	}
+	testcases := []struct {
+		testutils.Base
+		builder    *Builder
+		projection bool
+	}{
+		{
+			Base:    testutils.Base{Name: "same sizes, identity"},
+			builder: newBuilder().InputsCount(3).NeuronsCount(3),
+		},
+		{
+			Base:       testutils.Base{Name: "sizes mismatch, build projection"},
+			builder:    newBuilder().InputsCount(2).NeuronsCount(3),
+			projection: true,
+		},
+		{
+			Base: testutils.Base{Name: "provided projection"},
+			builder: newBuilder().InputsCount(3).NeuronsCount(3).
+				Projection(operationtestutils.NewOperation(t, operation.WeightMultiply, testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 3}))),
+			projection: true,
+		},
+		{
+			Base: testutils.Base{Name: "wrong projection shape", Err: ErrBuilder},
+			builder: newBuilder().InputsCount(2).NeuronsCount(3).
+				Projection(operationtestutils.NewOperation(t, operation.WeightMultiply, testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2}))),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			l, err := tc.builder.Build()
+			if tc.Err == nil {
+				require.NoError(t, err)
+				require.True(t, l.Is(ResidualDenseLayer))
+				residual, ok := l.(*ResidualLayer)
+				require.True(t, ok)
+				require.Equal(t, tc.projection, residual.projection != nil)
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, tc.Err)
+			}
+		})
+	}
+}
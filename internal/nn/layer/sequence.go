@@ -0,0 +1,65 @@
+package layer
+
+import (
+	"fmt"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+// Sequences are passed to layers packed in Matrix: each row holds one sample, time steps are written sequentially,
+// each step occupies the same count of cols (features).
+//
+// Example (batch of 2 samples, 3 steps, 2 features):
+//     | x(0,0,0) x(0,0,1) x(0,1,0) x(0,1,1) x(0,2,0) x(0,2,1) |
+//     | x(1,0,0) x(1,0,1) x(1,1,0) x(1,1,1) x(1,2,0) x(1,2,1) |
+//     where x(sample,step,feature).
+
+// PackSequence packs given steps (batch x features each) to one Matrix (batch x steps*features).
+//
+// Throws ErrExec error.
+func PackSequence(steps []*matrix.Matrix) (packed *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if len(steps) < 1 {
+		return nil, fmt.Errorf("no steps provided for packing sequence")
+	}
+	for i, step := range steps {
+		if step == nil {
+			return nil, fmt.Errorf("%d'th step is nil", i)
+		} else if step.Cols() != steps[0].Cols() {
+			return nil, fmt.Errorf("%d'th step features count mismatch first step features count: %d != %d",
+				i, step.Cols(), steps[0].Cols())
+		}
+	}
+	if len(steps) == 1 {
+		return steps[0].Copy(), nil
+	}
+
+	return steps[0].HStack(steps[1:])
+}
+
+// UnpackSequence splits packed sequence (batch x steps*features) to given count of steps (batch x features each).
+//
+// Throws ErrExec error.
+func UnpackSequence(packed *matrix.Matrix, stepsCount int) (steps []*matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if packed == nil {
+		return nil, fmt.Errorf("no sequence provided for unpacking: %v", packed)
+	} else if stepsCount < 1 {
+		return nil, fmt.Errorf("negative or zero steps count: %d", stepsCount)
+	} else if packed.Cols()%stepsCount != 0 {
+		return nil, fmt.Errorf("can not unpack sequence with %d cols into %d steps", packed.Cols(), stepsCount)
+	}
+
+	features := packed.Cols() / stepsCount
+	steps = make([]*matrix.Matrix, stepsCount)
+	for t := 0; t < stepsCount; t++ {
+		steps[t], err = packed.SubMatrix(0, packed.Rows(), 1, t*features, (t+1)*features, 1)
+		if err != nil {
+			return nil, fmt.Errorf("error getting %d'th step: %w", t, err)
+		}
+	}
+
+	return steps, nil
+}
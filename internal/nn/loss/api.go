@@ -5,15 +5,19 @@ package loss
 import (
 	"nn/internal/nn"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
 )
 
 // ILoss represents loss operation
 type ILoss interface {
 	nn.IModule
-	// Forward calculates loss for given targets and outputs
-	Forward(t *matrix.Matrix, y *matrix.Matrix) (float64, error)
+	// Forward calculates loss for given targets and outputs as mean of samples' (rows') losses scaled by their weights:
+	// 1 / N * sum[w * loss], it is not normalized by sum of weights. Weights must be non-negative, nil weights are all
+	// ones.
+	Forward(t *matrix.Matrix, y *matrix.Matrix, w *vector.Vector) (float64, error)
 
-	// Backward calculates input gradient for targets and outputs given during previous Forward() call
+	// Backward calculates input gradient for targets, outputs and weights given during previous Forward() call, so
+	// gradient of each sample is scaled by its weight too
 	Backward() (*matrix.Matrix, error)
 
 	Output() float64
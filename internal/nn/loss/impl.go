@@ -3,6 +3,7 @@ package loss
 import (
 	"nn/internal/nn"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
 )
 
 const (
@@ -14,22 +15,21 @@ func NewMSELoss() ILoss {
 	logger.Debug("create new MSE loss")
 	return &Loss{
 		kind: MSELoss,
-		output: func(t, y *matrix.Matrix) (float64, error) {
-			// 1 / (2 * N) * sum[(y - t) ** 2]
+		samples: func(t, y *matrix.Matrix) (*vector.Vector, error) {
+			// 1 / 2 * sum[(y - t) ** 2] of each row
 			delta, err := y.Sub(t)
 			if err != nil {
-				return 0, err
+				return nil, err
 			}
-			return delta.Sqr().Sum() / (2 * float64(delta.Rows())), nil
-		},
-		gradient: func(t, y *matrix.Matrix) (*matrix.Matrix, error) {
-			// (y - t) / N
-			delta, err := y.Sub(t)
+			sums, err := delta.Sqr().SumAxed(matrix.Horizontal)
 			if err != nil {
 				return nil, err
 			}
-
-			return delta.DivNum(float64(delta.Rows())), nil
+			return sums.DivNum(2), nil
+		},
+		gradient: func(t, y *matrix.Matrix) (*matrix.Matrix, error) {
+			// y - t
+			return y.Sub(t)
 		},
 	}
 }
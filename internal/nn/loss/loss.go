@@ -5,26 +5,45 @@ import (
 	"nn/internal/nn"
 	"nn/internal/utils"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
 	"nn/pkg/wraperr"
 )
 
 var _ ILoss = (*Loss)(nil)
 
-// Loss represent loss module that holds inputs and computed outputs
+// Loss represent loss module that holds inputs and computed outputs. Concrete loss provides losses of samples and
+// their gradients, Loss averages them with samples' weights.
 type Loss struct {
 	kind nn.Kind
 
 	t *matrix.Matrix
 	y *matrix.Matrix
+	w *vector.Vector
 
 	l float64
 	d *matrix.Matrix
 
-	output   func(t, y *matrix.Matrix) (float64, error)
-	gradient func(t, y *matrix.Matrix) (*matrix.Matrix, error)
+	samples  func(t, y *matrix.Matrix) (*vector.Vector, error) // loss of each sample (row)
+	gradient func(t, y *matrix.Matrix) (*matrix.Matrix, error) // gradient of each sample's loss by its outputs
 }
 
-func (l *Loss) Forward(t *matrix.Matrix, y *matrix.Matrix) (loss float64, err error) {
+// checkWeights checks weights are non-negative and there is a weight for each of <rows> samples
+func checkWeights(w *vector.Vector, rows int) error {
+	if w == nil {
+		return nil
+	} else if w.Size() != rows {
+		return fmt.Errorf("weights count mismatches samples count: %d != %d", w.Size(), rows)
+	} else if w.Min() < 0 {
+		return fmt.Errorf("negative weight: %f", w.Min())
+	}
+	return nil
+}
+
+// Forward computes mean of weighted samples' losses: 1 / N * sum[w * loss], where N is samples count, so weights
+// scale losses rather than normalize them. Weights of ones give plain mean loss.
+//
+// Throws ErrExec error.
+func (l *Loss) Forward(t *matrix.Matrix, y *matrix.Matrix, w *vector.Vector) (loss float64, err error) {
 	defer logger.CatchErr(&err)
 	defer wraperr.WrapError(ErrExec, &err)
 	defer wraperr.WrapError(fmt.Errorf("error during Forward propagation on %s", l.kind), &err)
@@ -38,16 +57,28 @@ func (l *Loss) Forward(t *matrix.Matrix, y *matrix.Matrix) (loss float64, err er
 	} else if t.Rows() != y.Rows() || t.Cols() != y.Cols() {
 		return 0, fmt.Errorf("targets and outputs sizes mismatch: %dx%d != %dx%d",
 			t.Rows(), y.Rows(), t.Cols(), y.Cols())
+	} else if err = checkWeights(w, t.Rows()); err != nil {
+		return 0, err
 	}
 
-	l.t = t.Copy()
-	l.y = y.Copy()
+	l.t = t.CopyInto(l.t)
+	l.y = y.CopyInto(l.y)
+	l.w = nil
+	if w != nil {
+		l.w = w.Copy()
+	}
 
-	loss, err = l.output(t, y)
+	samples, err := l.samples(t, y)
 	if err != nil {
 		return 0, fmt.Errorf("error computing output: %w", err)
 	}
+	if w != nil {
+		if samples, err = samples.Mul(w); err != nil {
+			return 0, fmt.Errorf("error weighting output: %w", err)
+		}
+	}
 
+	loss = samples.Sum() / float64(t.Rows())
 	l.l = loss
 	return loss, nil
 }
@@ -67,8 +98,14 @@ func (l *Loss) Backward() (grad *matrix.Matrix, err error) {
 	if err != nil {
 		return nil, fmt.Errorf("error computing input gradient: %w", err)
 	}
+	if l.w != nil {
+		if grad, err = grad.MulCol(l.w); err != nil {
+			return nil, fmt.Errorf("error weighting input gradient: %w", err)
+		}
+	}
+	grad.DivNumInPlace(float64(grad.Rows()))
 
-	l.d = grad.Copy()
+	l.d = grad.CopyInto(l.d)
 	return grad, nil
 }
 
@@ -94,7 +131,7 @@ func (l *Loss) Copy() nn.IModule {
 	res := &Loss{
 		kind:     l.kind,
 		l:        l.l,
-		output:   l.output,
+		samples:  l.samples,
 		gradient: l.gradient,
 	}
 	if l.t != nil {
@@ -103,6 +140,9 @@ func (l *Loss) Copy() nn.IModule {
 	if l.y != nil {
 		res.y = l.y.Copy()
 	}
+	if l.w != nil {
+		res.w = l.w.Copy()
+	}
 	if l.d != nil {
 		res.d = l.d.Copy()
 	}
@@ -125,6 +165,8 @@ func (l *Loss) Equal(loss nn.IModule) bool {
 		return false
 	} else if l.y != nil && !l.y.Equal(lo.y) {
 		return false
+	} else if l.w != nil && !l.w.Equal(lo.w) {
+		return false
 	} else if l.d != nil && !l.d.Equal(lo.d) {
 		return false
 	} else if l.l != lo.l {
@@ -150,6 +192,8 @@ func (l *Loss) EqualApprox(loss nn.IModule) bool {
 		return false
 	} else if l.y != nil && !l.y.EqualApprox(lo.y) {
 		return false
+	} else if l.w != nil && !l.w.EqualApprox(lo.w) {
+		return false
 	} else if l.d != nil && !l.d.EqualApprox(lo.d) {
 		return false
 	} else if l.l != lo.l {
@@ -164,6 +208,7 @@ func (l *Loss) toMap(stringer func(spStringer utils.SPStringer) string) map[stri
 		"kind": string(l.kind),
 		"t":    stringer(l.t),
 		"y":    stringer(l.y),
+		"w":    stringer(l.w),
 		"d":    stringer(l.d),
 		"l":    fmt.Sprintf("%f", l.l),
 	}
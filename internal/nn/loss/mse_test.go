@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"nn/internal/testutils"
 	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/vector"
 	"testing"
 )
 
@@ -43,7 +44,7 @@ func TestMSELoss_Forward(t *testing.T) {
 			out := testfactories.NewMatrix(t, test.out)
 			targets := testfactories.NewMatrix(t, test.targets)
 
-			actual, err := mse.Forward(targets, out)
+			actual, err := mse.Forward(targets, out, nil)
 			if test.Err == nil {
 				require.NoError(t, err)
 				require.Equal(t, test.expected, actual)
@@ -101,7 +102,7 @@ func TestMSELoss_Backward(t *testing.T) {
 			targets := testfactories.NewMatrix(t, test.targets)
 
 			if test.forward {
-				_, err := mse.Forward(targets, out)
+				_, err := mse.Forward(targets, out, nil)
 				require.NoError(t, err)
 			}
 			backward, err := mse.Backward()
@@ -116,3 +117,42 @@ func TestMSELoss_Backward(t *testing.T) {
 		})
 	}
 }
+
+func TestMSELoss_Weighted(t *testing.T) {
+	out := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2, Values: []float64{1, 2, 3, 4, 5, 6}})
+	targets := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2, Values: []float64{2, 2, 3, 6, 8, 6}})
+	weights, err := vector.NewVector([]float64{2, 0, 0.5})
+	require.NoError(t, err)
+
+	mse := NewMSELoss()
+	actual, err := mse.Forward(targets, out, weights)
+	require.NoError(t, err)
+	require.InDelta(t, (2*(1.0/2)+0*(4.0/2)+0.5*(9.0/2))/3, actual, 1e-12)
+
+	backward, err := mse.Backward()
+	require.NoError(t, err)
+	expected := testfactories.NewMatrix(t, testfactories.MatrixParameters{
+		Rows:   3,
+		Cols:   2,
+		Values: []float64{2 * -1 / 3.0, 0, 0, 0, 0.5 * -3 / 3.0, 0},
+	})
+	require.True(t, expected.EqualApprox(backward), backward.String())
+
+	// weights of ones give unweighted loss
+	ones, err := vector.NewVectorOf(1, 3)
+	require.NoError(t, err)
+	weighted, err := NewMSELoss().Forward(targets, out, ones)
+	require.NoError(t, err)
+	unweighted, err := NewMSELoss().Forward(targets, out, nil)
+	require.NoError(t, err)
+	require.InDelta(t, unweighted, weighted, 1e-12)
+
+	short, err := vector.NewVector([]float64{1, 1})
+	require.NoError(t, err)
+	_, err = NewMSELoss().Forward(targets, out, short)
+	require.ErrorIs(t, err, ErrExec)
+	negative, err := vector.NewVector([]float64{1, -1, 1})
+	require.NoError(t, err)
+	_, err = NewMSELoss().Forward(targets, out, negative)
+	require.ErrorIs(t, err, ErrExec)
+}
@@ -4,14 +4,23 @@ import (
 	"nn/internal/nn"
 	"nn/internal/nn/operation"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
 )
 
 type INetwork interface {
 	nn.IModule
 	Forward(x *matrix.Matrix) (*matrix.Matrix, error)
-	Loss(t *matrix.Matrix) (float64, error)
+	// Loss calculates loss for given targets and outputs of the last Forward call, each sample (row) is weighted by
+	// given weights if not nil (see loss.ILoss)
+	Loss(t *matrix.Matrix, w *vector.Vector) (float64, error)
+	// Backward result is owned by the first layer (see layer.ILayer), so it is valid until the next call and must not
+	// be modified.
 	Backward() (*matrix.Matrix, error)
 	ApplyOptim(optimizer operation.Optimizer) error
+	// SetScaler attaches nn.Transform to network, nil detaches it
+	SetScaler(s nn.Transform)
+	// Scaler return nn.Transform attached to network or nil
+	Scaler() nn.Transform
 }
 
 var networks = map[nn.Kind]struct{}{
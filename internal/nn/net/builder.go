@@ -6,6 +6,7 @@ import (
 	"nn/internal/nn/layer"
 	"nn/internal/nn/loss"
 	"nn/internal/nn/operation"
+	"nn/pkg/mmath/matrix"
 	"nn/pkg/mmath/vector"
 	"nn/pkg/percent"
 	"nn/pkg/wraperr"
@@ -126,6 +127,69 @@ func (b *Builder) AddDropout(dropout operation.IOperation) *Builder {
 	return b
 }
 
+func (b *Builder) AddProjection(projection operation.IOperation) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Projection(projection)
+	}
+	return b
+}
+
+func (b *Builder) AddGates(gates ...*layer.GateParameters) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Gates(gates...)
+	}
+	return b
+}
+
+func (b *Builder) AddSequence(sequence *layer.SequenceParameters) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Sequence(sequence)
+	}
+	return b
+}
+
+func (b *Builder) AddWindow(window *operation.WindowParameters) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Window(window)
+	}
+	return b
+}
+
+func (b *Builder) AddCenters(centers *matrix.Matrix) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Centers(centers)
+	}
+	return b
+}
+
+func (b *Builder) AddCentersCount(centersCount int) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].CentersCount(centersCount)
+	}
+	return b
+}
+
+func (b *Builder) AddWidths(widths *vector.Vector) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Widths(widths)
+	}
+	return b
+}
+
+func (b *Builder) AddAttention(attention *layer.AttentionParameters) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Attention(attention)
+	}
+	return b
+}
+
+func (b *Builder) AddEmbedding(embedding *layer.EmbeddingParameters) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Embedding(embedding)
+	}
+	return b
+}
+
 func (b *Builder) AddInputsCount(inputsCount int) *Builder {
 	if len(b.layerBuilders) > 0 {
 		b.layerBuilders[len(b.layerBuilders)-1].InputsCount(inputsCount)
@@ -168,6 +232,13 @@ func (b *Builder) AddParamInitType(paramInitType operation.ParamInitType) *Build
 	return b
 }
 
+func (b *Builder) AddPrecision(precision nn.Precision) *Builder {
+	if len(b.layerBuilders) > 0 {
+		b.layerBuilders[len(b.layerBuilders)-1].Precision(precision)
+	}
+	return b
+}
+
 func (b *Builder) AddKeepProbability(probability percent.Percent) *Builder {
 	if len(b.layerBuilders) > 0 {
 		b.layerBuilders[len(b.layerBuilders)-1].KeepProbability(probability)
@@ -247,6 +318,105 @@ func (b *Builder) Dropout(index int, dropout operation.IOperation) *Builder {
 	return b
 }
 
+func (b *Builder) Projection(index int, projection operation.IOperation) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Projection(projection)
+	return b
+}
+
+func (b *Builder) Gates(index int, gates ...*layer.GateParameters) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Gates(gates...)
+	return b
+}
+
+func (b *Builder) Sequence(index int, sequence *layer.SequenceParameters) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Sequence(sequence)
+	return b
+}
+
+func (b *Builder) Window(index int, window *operation.WindowParameters) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Window(window)
+	return b
+}
+
+func (b *Builder) Centers(index int, centers *matrix.Matrix) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Centers(centers)
+	return b
+}
+
+func (b *Builder) CentersCount(index int, centersCount int) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].CentersCount(centersCount)
+	return b
+}
+
+func (b *Builder) Widths(index int, widths *vector.Vector) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Widths(widths)
+	return b
+}
+
+func (b *Builder) Attention(index int, attention *layer.AttentionParameters) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Attention(attention)
+	return b
+}
+
+func (b *Builder) Embedding(index int, embedding *layer.EmbeddingParameters) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Embedding(embedding)
+	return b
+}
+
 func (b *Builder) InputsCount(index int, inputsCount int) *Builder {
 	if index < 0 {
 		return b
@@ -313,6 +483,17 @@ func (b *Builder) ParamInitType(index int, paramInitType operation.ParamInitType
 	return b
 }
 
+func (b *Builder) Precision(index int, precision nn.Precision) *Builder {
+	if index < 0 {
+		return b
+	}
+	for len(b.layerBuilders) <= index {
+		b.layerBuilders = append(b.layerBuilders, nil)
+	}
+	b.layerBuilders[index].Precision(precision)
+	return b
+}
+
 func (b *Builder) KeepProbability(index int, probability percent.Percent) *Builder {
 	if index < 0 {
 		return b
@@ -11,7 +11,6 @@ import (
 	"nn/internal/nn/operation/operationtestutils"
 	"nn/internal/testutils"
 	"nn/internal/testutils/testfactories"
-	"nn/internal/utils"
 	"nn/pkg/percent"
 	"testing"
 )
@@ -49,12 +48,12 @@ func TestBuilder_Build(t *testing.T) {
 		require.NoError(t, err)
 		return res
 	}
-	rawW_6 := utils.RandNormArray(6, 0, 1)
-	rawW_3 := utils.RandNormArray(3, 0, 1)
-	//rawW_1 := utils.RandNormArray(3, 0, 1)
-	//rawB_6 := utils.RandNormArray(6, 0, 1)
-	rawB_3 := utils.RandNormArray(3, 0, 1)
-	rawB_1 := utils.RandNormArray(1, 0, 1)
+	rawW_6 := testutils.RandomArray(6)
+	rawW_3 := testutils.RandomArray(3)
+	//rawW_1 := testutils.RandomArray(3)
+	//rawB_6 := testutils.RandomArray(6)
+	rawB_3 := testutils.RandomArray(3)
+	rawB_1 := testutils.RandomArray(1)
 	testcases := []struct {
 		testutils.Base
 		builder   *Builder
@@ -123,3 +122,41 @@ func TestBuilder_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_Precision(t *testing.T) {
+	builder, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	// layers are built again with new parameters on each Build call
+	builder.SetResetAfterBuild(true).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(2).
+		AddNeuronsCount(4).
+		AddActivationKind(operation.TanhActivation).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(4).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		LossKind(loss.MSELoss)
+	network32, err := builder.Precision(0, nn.Float32Params).Precision(1, nn.Float32Params).Build()
+	require.NoError(t, err)
+
+	// conversion to the same precision is copying
+	same, err := ConvertPrecision(network32, nn.Float32Params)
+	require.NoError(t, err)
+	require.True(t, network32.Equal(same))
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2})
+	y32, err := network32.Forward(x)
+	require.NoError(t, err)
+
+	// converted network computes the same outputs as built in float32
+	converted, err := ConvertPrecision(network32, nn.Float64)
+	require.NoError(t, err)
+	y64, err := converted.Forward(x)
+	require.NoError(t, err)
+	require.False(t, network32.Equal(converted))
+	require.True(t, y64.EqualApprox(y32), "%s != %s", y64, y32)
+
+	_, err = ConvertPrecision(nil, nn.Float32Params)
+	require.ErrorIs(t, err, ErrExec)
+}
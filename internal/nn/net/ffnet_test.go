@@ -1,7 +1,10 @@
 package net
 
 import (
+	"bytes"
 	"github.com/stretchr/testify/require"
+	"nn/internal/data/dataset"
+	"nn/internal/data/scaler"
 	"nn/internal/nn/layer"
 	"nn/internal/nn/layer/layertestutils"
 	"nn/internal/nn/loss"
@@ -10,7 +13,9 @@ import (
 	"nn/internal/nn/operation/operationtestutils"
 	"nn/internal/testutils"
 	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
 	"nn/pkg/percent"
+	"runtime"
 	"testing"
 )
 
@@ -99,3 +104,299 @@ func TestNewFFNetwork(t *testing.T) {
 		})
 	}
 }
+
+func TestFFNetwork_Residual(t *testing.T) {
+	depth := 12
+	nb, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	nb = nb.
+		LossKind(loss.MSELoss).
+		AddLayerKind(layer.ResidualDenseLayer).
+		AddInputsCount(2).
+		AddNeuronsCount(8).
+		AddParamInitType(operation.GlorotInit).
+		AddActivationKind(operation.TanhActivation)
+	for i := 1; i < depth; i++ {
+		nb = nb.
+			AddLayerKind(layer.ResidualDenseLayer).
+			AddInputsCount(8).
+			AddNeuronsCount(8).
+			AddParamInitType(operation.GlorotInit).
+			AddActivationKind(operation.TanhActivation)
+	}
+	nb = nb.
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(8).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation)
+
+	network, err := nb.Build()
+	require.NoError(t, err)
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 2})
+	y := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 1})
+
+	_, err = network.Forward(x)
+	require.NoError(t, err)
+	_, err = network.Loss(y, nil)
+	require.NoError(t, err)
+	dx, err := network.Backward()
+	require.NoError(t, err)
+	require.Equal(t, 5, dx.Rows())
+	require.Equal(t, 2, dx.Cols())
+
+	c := network.Copy()
+	require.True(t, network.Equal(c))
+
+	err = network.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad.MulNum(0.01))
+	})
+	require.NoError(t, err)
+	require.False(t, network.Equal(c))
+}
+
+func TestFFNetwork_Convolution(t *testing.T) {
+	nb, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	network, err := nb.
+		LossKind(loss.MSELoss).
+		AddLayerKind(layer.Conv1DLayer).
+		AddWindow(&operation.WindowParameters{Length: 10, Channels: 2, Kernel: 3, Stride: 1, Padding: 1}).
+		AddNeuronsCount(4).
+		AddParamInitType(operation.GlorotInit).
+		AddActivationKind(operation.TanhActivation).
+		AddLayerKind(layer.MaxPool1DLayer).
+		AddWindow(&operation.WindowParameters{Length: 10, Channels: 4, Kernel: 2, Stride: 2}).
+		AddLayerKind(layer.FlattenLayer).
+		AddInputsCount(20).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(20).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		Build()
+	require.NoError(t, err)
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 20})
+	y := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 1})
+
+	out, err := network.Forward(x)
+	require.NoError(t, err)
+	require.Equal(t, 5, out.Rows())
+	require.Equal(t, 1, out.Cols())
+	_, err = network.Loss(y, nil)
+	require.NoError(t, err)
+	dx, err := network.Backward()
+	require.NoError(t, err)
+	require.Equal(t, 5, dx.Rows())
+	require.Equal(t, 20, dx.Cols())
+
+	c := network.Copy()
+	require.True(t, network.Equal(c))
+
+	err = network.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad.MulNum(0.01))
+	})
+	require.NoError(t, err)
+	require.False(t, network.Equal(c))
+}
+
+func TestFFNetwork_Attention(t *testing.T) {
+	nb, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	network, err := nb.
+		LossKind(loss.MSELoss).
+		AddLayerKind(layer.MultiHeadAttentionLayer).
+		AddInputsCount(2).
+		AddNeuronsCount(4).
+		AddParamInitType(operation.GlorotInit).
+		AddAttention(&layer.AttentionParameters{Steps: 5, Heads: 2, Causal: true}).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(20).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		Build()
+	require.NoError(t, err)
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 10})
+	y := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 1})
+
+	_, err = network.Forward(x)
+	require.NoError(t, err)
+	_, err = network.Loss(y, nil)
+	require.NoError(t, err)
+	dx, err := network.Backward()
+	require.NoError(t, err)
+	require.Equal(t, 3, dx.Rows())
+	require.Equal(t, 10, dx.Cols())
+
+	c := network.Copy()
+	require.True(t, network.Equal(c))
+
+	err = network.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad.MulNum(0.01))
+	})
+	require.NoError(t, err)
+	require.False(t, network.Equal(c))
+}
+
+func TestFFNetwork_Embedding(t *testing.T) {
+	nb, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	network, err := nb.
+		LossKind(loss.MSELoss).
+		AddLayerKind(layer.CategoricalEmbeddingLayer).
+		AddInputsCount(3).
+		AddEmbedding(&layer.EmbeddingParameters{Columns: []int{0, 2}, Categories: []int{4, 3}, Sizes: []int{2}}).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(5).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		Build()
+	require.NoError(t, err)
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 3, Values: []float64{
+		0, 0.5, 2,
+		3, 0.1, 0,
+		1, 0.7, 2,
+	}})
+	y := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 1})
+
+	_, err = network.Forward(x)
+	require.NoError(t, err)
+	_, err = network.Loss(y, nil)
+	require.NoError(t, err)
+	dx, err := network.Backward()
+	require.NoError(t, err)
+	require.Equal(t, 3, dx.Rows())
+	require.Equal(t, 3, dx.Cols())
+
+	c := network.Copy()
+	require.True(t, network.Equal(c))
+
+	err = network.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.Sub(grad.MulNum(0.01))
+	})
+	require.NoError(t, err)
+	require.False(t, network.Equal(c))
+}
+
+func TestFFNetwork_StepAllocations(t *testing.T) {
+	const (
+		batch = 32
+		size  = 256
+		steps = 10
+	)
+	nb, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	network, err := nb.
+		LossKind(loss.MSELoss).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(size).
+		AddNeuronsCount(size).
+		AddActivationKind(operation.SigmoidActivation).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(size).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		Build()
+	require.NoError(t, err)
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: size})
+	y := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: batch, Cols: 1})
+	optimizer := func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.SubInPlace(grad.MulNumInPlace(0.01))
+	}
+	step := func() {
+		_, err := network.Forward(x)
+		require.NoError(t, err)
+		_, err = network.Loss(y, nil)
+		require.NoError(t, err)
+		_, err = network.Backward()
+		require.NoError(t, err)
+		require.NoError(t, network.ApplyOptim(optimizer))
+	}
+	step()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < steps; i++ {
+		step()
+	}
+	runtime.ReadMemStats(&after)
+
+	// operations reuse their buffers, so step allocates less than a single hidden layer output
+	perStep := (after.TotalAlloc - before.TotalAlloc) / steps
+	require.Less(t, perStep, uint64(batch*size*8), "bytes allocated per step")
+}
+
+func TestFFNetwork_Scaler(t *testing.T) {
+	nb, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	network, err := nb.
+		LossKind(loss.MSELoss).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(2).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		Build()
+	require.NoError(t, err)
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2, Values: []float64{1, 100, 2, 300, 3, 200}})
+	y := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 1, Values: []float64{10, 20, 30}})
+	data, err := dataset.NewData(x, y)
+	require.NoError(t, err)
+	inputs, err := scaler.New(scaler.Standard)
+	require.NoError(t, err)
+	outputs, err := scaler.NewMinMax(-1, 1)
+	require.NoError(t, err)
+	s := &scaler.DataScaler{Inputs: inputs, Outputs: outputs}
+	require.NoError(t, s.Fit(data))
+	scaled, err := s.Transform(data)
+	require.NoError(t, err)
+
+	// network without scaler on scaled data
+	scaledForward, err := network.Forward(scaled.X)
+	require.NoError(t, err)
+	scaledLoss, err := network.Loss(scaled.Y, nil)
+	require.NoError(t, err)
+
+	// network with scaler on source data
+	n := network.(*Network)
+	n.SetScaler(s)
+	require.Equal(t, s, n.Scaler())
+	forward, err := n.Forward(x)
+	require.NoError(t, err)
+	restored, err := s.InverseOutputs(scaledForward)
+	require.NoError(t, err)
+	require.True(t, restored.EqualApprox(forward))
+	l, err := n.Loss(y, nil)
+	require.NoError(t, err)
+	require.InDelta(t, scaledLoss, l, 1e-12)
+
+	c := n.Copy().(*Network)
+	require.True(t, n.Equal(c))
+	c.SetScaler(&scaler.DataScaler{Inputs: inputs})
+	require.False(t, n.Equal(c))
+
+	// scaler is stored alongside network and attached back
+	buf := new(bytes.Buffer)
+	require.NoError(t, n.Scaler().(*scaler.DataScaler).WriteJSON(buf))
+	read, err := scaler.ReadJSON(buf)
+	require.NoError(t, err)
+	c.SetScaler(read)
+	require.True(t, n.Equal(c))
+
+	// scalers are compared by their Equal and EqualApprox
+	shifted := s.Copy()
+	shifted.Outputs.Scale = shifted.Outputs.Scale.AddNum(1e-12)
+	shiftedNetwork := n.Copy().(*Network)
+	shiftedNetwork.SetScaler(shifted)
+	require.False(t, n.Equal(shiftedNetwork))
+	require.True(t, n.EqualApprox(shiftedNetwork))
+	copied, err := c.Forward(x)
+	require.NoError(t, err)
+	require.True(t, forward.Equal(copied))
+
+	_, err = n.Forward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3}))
+	require.ErrorIs(t, err, ErrExec)
+}
@@ -8,6 +8,7 @@ import (
 	"nn/internal/nn/operation"
 	"nn/internal/utils"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
 	"nn/pkg/wraperr"
 )
 
@@ -17,6 +18,18 @@ type Network struct {
 	kind   nn.Kind
 	layers []layer.ILayer
 	loss   loss.ILoss
+	scaler nn.Transform
+}
+
+// SetScaler attaches fitted nn.Transform to Network (nil detaches it): Forward scales inputs and restores outputs by
+// the Transform and Loss scales targets, so Network is trained and used on unscaled Data.
+func (n *Network) SetScaler(s nn.Transform) {
+	n.scaler = s
+}
+
+// Scaler return nn.Transform attached to Network (see SetScaler) to be stored alongside the Network.
+func (n *Network) Scaler() nn.Transform {
+	return n.scaler
 }
 
 func (n *Network) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
@@ -30,17 +43,26 @@ func (n *Network) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
 		return nil, fmt.Errorf("no input provided: %v", x)
 	}
 
-	y = x.Copy()
+	y = x
+	if n.scaler != nil {
+		if y, err = n.scaler.TransformInputs(x); err != nil {
+			return nil, fmt.Errorf("error scaling inputs: %w", err)
+		}
+	}
 	for i, l := range n.layers {
 		y, err = l.Forward(y)
 		if err != nil {
 			return nil, fmt.Errorf("error processing %d'th layer: %w", i, err)
 		}
 	}
-	return y, nil
+	if n.scaler != nil {
+		return n.scaler.InverseOutputs(y)
+	}
+	// layers' outputs are reused on the next steps, so caller gets own copy
+	return y.Copy(), nil
 }
 
-func (n *Network) Loss(t *matrix.Matrix) (l float64, err error) {
+func (n *Network) Loss(t *matrix.Matrix, w *vector.Vector) (l float64, err error) {
 	defer logger.CatchErr(&err)
 	defer wraperr.WrapError(ErrExec, &err)
 	defer wraperr.WrapError(fmt.Errorf("error during Loss calculation"), &err)
@@ -50,8 +72,13 @@ func (n *Network) Loss(t *matrix.Matrix) (l float64, err error) {
 	} else if t == nil {
 		return 0, fmt.Errorf("no targets provided: %v", t)
 	}
+	if n.scaler != nil {
+		if t, err = n.scaler.TransformOutputs(t); err != nil {
+			return 0, fmt.Errorf("error scaling targets: %w", err)
+		}
+	}
 
-	return n.loss.Forward(t, n.layers[len(n.layers)-1].Output())
+	return n.loss.Forward(t, n.layers[len(n.layers)-1].Output(), w)
 }
 
 func (n *Network) Backward() (dx *matrix.Matrix, err error) {
@@ -113,7 +140,7 @@ func (n *Network) Copy() nn.IModule {
 	if n == nil {
 		return nil
 	}
-	network := &Network{kind: n.kind}
+	network := &Network{kind: n.kind, scaler: n.scaler}
 	if n.loss != nil {
 		network.loss = n.loss.Copy().(loss.ILoss)
 	}
@@ -140,6 +167,8 @@ func (n *Network) Equal(network nn.IModule) bool {
 		return false
 	} else if ne.kind != n.kind || !n.loss.Equal(ne.loss) {
 		return false
+	} else if !equalScalers(n.scaler, ne.scaler, nn.Transform.Equal) {
+		return false
 	} else if len(ne.layers) != len(n.layers) {
 		return false
 	} else {
@@ -166,6 +195,8 @@ func (n *Network) EqualApprox(network nn.IModule) bool {
 		return false
 	} else if ne.kind != n.kind || !n.loss.EqualApprox(ne.loss) {
 		return false
+	} else if !equalScalers(n.scaler, ne.scaler, nn.Transform.EqualApprox) {
+		return false
 	} else if len(ne.layers) != len(n.layers) {
 		return false
 	} else {
@@ -179,6 +210,14 @@ func (n *Network) EqualApprox(network nn.IModule) bool {
 	return true
 }
 
+// equalScalers compares attached scalers by given comparison, nil scaler is equal to nil one only
+func equalScalers(a, b nn.Transform, equal func(a, b nn.Transform) bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return equal(a, b)
+}
+
 func (n *Network) layersAsSPStringers() []utils.SPStringer {
 	res := make([]utils.SPStringer, len(n.layers))
 	for i, l := range n.layers {
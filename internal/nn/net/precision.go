@@ -0,0 +1,31 @@
+package net
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/nn/layer"
+	"nn/pkg/wraperr"
+)
+
+// ConvertPrecision return copy of given network with all layers converted to given precision by
+// layer.ConvertPrecision. nn.Float32Params covers parameters of weight multiply and bias add operations only, see
+// operation.ConvertPrecision.
+//
+// Throws ErrExec error.
+func ConvertPrecision(n INetwork, precision nn.Precision) (res INetwork, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+
+	network, ok := n.(*Network)
+	if !ok || network == nil {
+		return nil, fmt.Errorf("can not convert network of type %T", n)
+	}
+
+	converted := network.Copy().(*Network)
+	for i, l := range converted.layers {
+		if converted.layers[i], err = layer.ConvertPrecision(l, precision); err != nil {
+			return nil, fmt.Errorf("error converting %d'th layer: %w", i, err)
+		}
+	}
+	return converted, nil
+}
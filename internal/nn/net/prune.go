@@ -0,0 +1,29 @@
+package net
+
+import (
+	"fmt"
+	"nn/internal/nn/layer"
+	"nn/pkg/wraperr"
+)
+
+// Prune return copy of given network with weights of all layers pruned by layer.Prune: weights with absolute value
+// not greater than given threshold are removed, the rest ones are stored sparse, so pruned network runs faster.
+//
+// Throws ErrExec error.
+func Prune(n INetwork, threshold float64) (res INetwork, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+
+	network, ok := n.(*Network)
+	if !ok || network == nil {
+		return nil, fmt.Errorf("can not prune network of type %T", n)
+	}
+
+	pruned := network.Copy().(*Network)
+	for i, l := range pruned.layers {
+		if pruned.layers[i], err = layer.Prune(l, threshold); err != nil {
+			return nil, fmt.Errorf("error pruning %d'th layer: %w", i, err)
+		}
+	}
+	return pruned, nil
+}
@@ -0,0 +1,91 @@
+package net
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn/layer"
+	"nn/internal/nn/loss"
+	"nn/internal/nn/operation"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+// newDenseNetwork return network of two dense layers with given inputs, hidden neurons and outputs count.
+func newDenseNetwork(t testing.TB, inputs, hidden, outputs int) INetwork {
+	builder, err := NewBuilder(FFNetwork)
+	require.NoError(t, err)
+	network, err := builder.
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(inputs).
+		AddNeuronsCount(hidden).
+		AddActivationKind(operation.TanhActivation).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(hidden).
+		AddNeuronsCount(outputs).
+		AddActivationKind(operation.LinearActivation).
+		LossKind(loss.MSELoss).
+		Build()
+	require.NoError(t, err)
+	return network
+}
+
+func TestPrune(t *testing.T) {
+	network := newDenseNetwork(t, 2, 4, 1)
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2})
+	target := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 1})
+
+	// zero threshold keeps all weights, so pruned network computes the same
+	pruned, err := Prune(network, 0)
+	require.NoError(t, err)
+	require.False(t, network.Equal(pruned))
+	y, err := network.Forward(x)
+	require.NoError(t, err)
+	yPruned, err := pruned.Forward(x)
+	require.NoError(t, err)
+	require.True(t, y.EqualApprox(yPruned), "%s != %s", y, yPruned)
+
+	// pruned network is trainable
+	_, err = pruned.Loss(target, nil)
+	require.NoError(t, err)
+	_, err = pruned.Backward()
+	require.NoError(t, err)
+	require.NoError(t, pruned.ApplyOptim(func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.SubInPlace(grad.MulNumInPlace(0.1))
+	}))
+
+	_, err = Prune(nil, 0)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = Prune(network, -1)
+	require.ErrorIs(t, err, ErrExec)
+}
+
+// BenchmarkPrune compares training step of dense network and the same network with most of weights pruned.
+func BenchmarkPrune(b *testing.B) {
+	network := newDenseNetwork(b, 64, 256, 64)
+	x, err := matrix.NewMatrixRawFlat(64, 64, testutils.RandomArray(64*64))
+	require.NoError(b, err)
+	target, err := matrix.NewMatrixRawFlat(64, 64, testutils.RandomArray(64*64))
+	require.NoError(b, err)
+	sgd := func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.SubInPlace(grad.MulNumInPlace(0.01))
+	}
+
+	networks := map[string]INetwork{"dense": network}
+	for _, threshold := range []float64{1, 1.5} {
+		networks[fmt.Sprintf("pruned %v", threshold)], err = Prune(network, threshold)
+		require.NoError(b, err)
+	}
+	for _, name := range []string{"dense", "pruned 1", "pruned 1.5"} {
+		n := networks[name]
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = n.Forward(x)
+				_, _ = n.Loss(target, nil)
+				_, _ = n.Backward()
+				_ = n.ApplyOptim(sgd)
+			}
+		})
+	}
+}
@@ -1,5 +1,6 @@
 // Package operation provides functionality of IOperation and its implementations: Operation, ParamOperation,
-// ConstOperation. Each operation is available by constructors (example: NewWeightOperation).
+// Param32Operation, SparseOperation, ConstOperation, PoolOperation. Each operation is available by constructors
+// (example: NewWeightOperation).
 package operation
 
 import (
@@ -10,20 +11,34 @@ import (
 // IOperation represents operation block of neural network
 type IOperation interface {
 	nn.IModule
-	// Forward makes forward propagation step, consuming input and producing output
+	// Forward makes forward propagation step, consuming input and producing output. Output is owned by operation:
+	// it is valid until the next Forward call and must not be modified.
 	Forward(x *matrix.Matrix) (*matrix.Matrix, error)
 
-	// Backward makes backward propagation step, consuming output gradient and producing input gradient
+	// Backward makes backward propagation step, consuming output gradient and producing input gradient. Input
+	// gradient is owned by operation: it is valid until the next Backward call and must not be modified.
 	Backward(dy *matrix.Matrix) (*matrix.Matrix, error)
 
 	Output() *matrix.Matrix
 	IsActivation() bool
 }
 
+// IParamOperation represents IOperation with parameter modified during training
+type IParamOperation interface {
+	IOperation
+	ApplyOptim(optim Optimizer) error
+	// Parameter return copy of operation's parameter
+	Parameter() *matrix.Matrix
+	Precision() nn.Precision
+}
+
 var operations = map[nn.Kind]struct{}{
-	LinearActivation: {}, TanhActivation: {}, SigmoidActivation: {},
+	LinearActivation: {}, TanhActivation: {}, SigmoidActivation: {}, SoftmaxActivation: {},
 	SigmoidParamActivation: {}, Dropout: {},
 	WeightMultiply: {}, BiasAdd: {},
+	Im2Col: {}, Reshape: {}, MaxPool: {}, AvgPool: {},
+	SquaredDistance: {}, WidthScale: {},
+	GaussianActivation: {}, MultiquadricActivation: {}, InverseMultiquadricActivation: {},
 }
 
 func IsOperation(kind nn.Kind) bool {
@@ -2,6 +2,7 @@ package operation
 
 import (
 	"fmt"
+	"math/rand"
 	"nn/internal/nn"
 	"nn/internal/utils"
 	"nn/pkg/mmath/matrix"
@@ -19,11 +20,13 @@ type ParamInitType uint8
 const (
 	DefaultInit ParamInitType = iota
 	GlorotInit
+	OrthogonalInit
 )
 
 type Builder struct {
 	kind               nn.Kind
 	paramInitType      ParamInitType
+	precision          nn.Precision
 	keepProbability    percent.Percent
 	sigmoidCoeffs      *vector.Vector
 	sigmoidCoeffsRange *SigmoidCoeffsRange
@@ -31,6 +34,10 @@ type Builder struct {
 	bias               *vector.Vector
 	inputsCount        int
 	neuronsCount       int
+	window             *WindowParameters
+	centers            *matrix.Matrix
+	widths             *vector.Vector
+	rng                *rand.Rand
 
 	resetAfterBuild bool
 }
@@ -55,6 +62,8 @@ func (b *Builder) Build() (o IOperation, err error) {
 		if b.resetAfterBuild {
 			b.weight = nil
 			b.bias = nil
+			b.centers = nil
+			b.widths = nil
 		}
 	}()
 
@@ -70,13 +79,27 @@ func (b *Builder) Build() (o IOperation, err error) {
 
 	switch b.kind {
 	case Dropout:
-		return Create(b.kind, b.keepProbability)
+		return Create(b.kind, b.keepProbability, b.rand())
 	case SigmoidParamActivation:
 		return Create(b.kind, b.sigmoidCoeffs)
 	case WeightMultiply:
+		if b.precision == nn.Float32Params {
+			return NewWeight32Operation(b.weight)
+		}
 		return Create(b.kind, b.weight)
 	case BiasAdd:
+		if b.precision == nn.Float32Params {
+			return NewBias32Operation(b.bias)
+		}
 		return Create(b.kind, b.bias)
+	case SquaredDistance:
+		return Create(b.kind, b.centers)
+	case WidthScale:
+		return Create(b.kind, b.widths)
+	case Im2Col, MaxPool, AvgPool:
+		return Create(b.kind, b.window)
+	case Reshape:
+		return Create(b.kind, b.inputsCount, b.neuronsCount)
 	}
 	return Create(b.kind)
 }
@@ -86,11 +109,24 @@ func (b *Builder) ParamInitType(paramInitType ParamInitType) *Builder {
 	return b
 }
 
+// Precision sets precision parameters of WeightMultiply and BiasAdd operations are stored in, other operations are
+// always built in nn.Float64 precision.
+func (b *Builder) Precision(precision nn.Precision) *Builder {
+	b.precision = precision
+	return b
+}
+
 func (b *Builder) KeepProbability(probability percent.Percent) *Builder {
 	b.keepProbability = probability
 	return b
 }
 
+// Rand sets random generator used for parameters initialization and dropout masks, utils.Rand is used by default.
+func (b *Builder) Rand(rng *rand.Rand) *Builder {
+	b.rng = rng
+	return b
+}
+
 func (b *Builder) SigmoidCoeffs(sigmoidCoeffs *vector.Vector) *Builder {
 	b.sigmoidCoeffs = sigmoidCoeffs
 	return b
@@ -121,6 +157,23 @@ func (b *Builder) NeuronsCount(neuronsCount int) *Builder {
 	return b
 }
 
+func (b *Builder) Window(window *WindowParameters) *Builder {
+	b.window = window
+	return b
+}
+
+// Centers sets centers of SquaredDistance operation: centers count (neurons count) x inputs count.
+func (b *Builder) Centers(centers *matrix.Matrix) *Builder {
+	b.centers = centers
+	return b
+}
+
+// Widths sets widths of WidthScale operation, size is centers count (neurons count).
+func (b *Builder) Widths(widths *vector.Vector) *Builder {
+	b.widths = widths
+	return b
+}
+
 func (b *Builder) SetResetAfterBuild(value bool) *Builder {
 	b.resetAfterBuild = value
 	return b
@@ -152,12 +205,40 @@ func (b *Builder) prepare() (err error) {
 			if b.inputsCount < 1 || b.neuronsCount < 1 {
 				return fmt.Errorf("no inputs/neurons count provided: %d, %d", b.inputsCount, b.neuronsCount)
 			}
-			weights := utils.RandNormArray(b.inputsCount*b.neuronsCount, 0, b.scale())
-
-			b.weight, err = matrix.NewMatrixRawFlat(b.inputsCount, b.neuronsCount, weights)
+			b.weight, err = matrix.RandNormal(b.rand(), b.inputsCount, b.neuronsCount, 0, b.scale())
 			if err != nil {
 				return fmt.Errorf("error creating weights: %w", err)
 			}
+			if b.paramInitType == OrthogonalInit {
+				b.weight, err = b.weight.Orthonormalize()
+				if err != nil {
+					return fmt.Errorf("error orthonormalizing weights: %w", err)
+				}
+			}
+		}
+	case SquaredDistance:
+		if b.centers == nil {
+			if b.inputsCount < 1 || b.neuronsCount < 1 {
+				return fmt.Errorf("no inputs/neurons count provided: %d, %d", b.inputsCount, b.neuronsCount)
+			}
+			b.centers, err = matrix.RandNormal(b.rand(), b.neuronsCount, b.inputsCount, 0, 1)
+			if err != nil {
+				return fmt.Errorf("error creating centers: %w", err)
+			}
+		}
+	case WidthScale:
+		if b.widths == nil {
+			if b.neuronsCount < 1 {
+				return fmt.Errorf("no neurons count provided: %d", b.neuronsCount)
+			}
+			b.widths, err = vector.NewVectorOf(1, b.neuronsCount)
+			if err != nil {
+				return fmt.Errorf("error creating widths: %w", err)
+			}
+		}
+	case Im2Col, MaxPool, AvgPool:
+		if b.window == nil {
+			return fmt.Errorf("no window parameters provided: %v", b.window)
 		}
 	case BiasAdd:
 		if b.bias == nil {
@@ -167,9 +248,7 @@ func (b *Builder) prepare() (err error) {
 			if b.paramInitType == GlorotInit && b.inputsCount < 1 {
 				return fmt.Errorf("no inputs count provided for glorot init: %d", b.inputsCount)
 			}
-			biases := utils.RandNormArray(b.neuronsCount, 0, b.scale())
-
-			b.bias, err = vector.NewVector(biases)
+			b.bias, err = vector.RandNormal(b.rand(), b.neuronsCount, 0, b.scale())
 			if err != nil {
 				return fmt.Errorf("error creating biases: %w", err)
 			}
@@ -179,6 +258,13 @@ func (b *Builder) prepare() (err error) {
 	return nil
 }
 
+func (b *Builder) rand() *rand.Rand {
+	if b.rng == nil {
+		return utils.Rand
+	}
+	return b.rng
+}
+
 func (b *Builder) scale() float64 {
 	if b.paramInitType == GlorotInit {
 		return 2.0 / float64(b.inputsCount+b.neuronsCount)
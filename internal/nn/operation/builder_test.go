@@ -2,9 +2,11 @@ package operation
 
 import (
 	"github.com/stretchr/testify/require"
+	"math/rand"
 	"nn/internal/nn"
 	"nn/internal/testutils"
 	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
 	"nn/pkg/percent"
 	"testing"
 )
@@ -147,6 +149,11 @@ func TestBuilder_Build(t *testing.T) {
 			builder: newBuilder(WeightMultiply).InputsCount(2).
 				NeuronsCount(3).ParamInitType(GlorotInit),
 		},
+		{
+			Base: testutils.Base{Name: "build weight, random orthogonal"},
+			builder: newBuilder(WeightMultiply).InputsCount(2).
+				NeuronsCount(3).ParamInitType(OrthogonalInit),
+		},
 		{
 			Base:    testutils.Base{Name: "build weight, random no inputs count", Err: ErrBuilder},
 			builder: newBuilder(WeightMultiply).NeuronsCount(3),
@@ -186,3 +193,38 @@ func TestBuilder_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_OrthogonalInit(t *testing.T) {
+	for _, size := range [][2]int{{5, 3}, {4, 4}, {2, 6}} {
+		builder, err := NewBuilder(WeightMultiply)
+		require.NoError(t, err)
+		op, err := builder.InputsCount(size[0]).NeuronsCount(size[1]).ParamInitType(OrthogonalInit).Build()
+		require.NoError(t, err)
+
+		weight := op.(IParamOperation).Parameter()
+		if weight.Rows() < weight.Cols() {
+			weight = weight.T()
+		}
+		product, err := weight.TMatMul(weight)
+		require.NoError(t, err)
+		identity, err := matrix.Identity(weight.Cols())
+		require.NoError(t, err)
+		require.True(t, identity.EqualApprox(product), "%s", product)
+	}
+}
+
+func TestBuilder_Rand(t *testing.T) {
+	build := func(kind nn.Kind, seed int64) IParamOperation {
+		builder, err := NewBuilder(kind)
+		require.NoError(t, err)
+		op, err := builder.InputsCount(3).NeuronsCount(4).Rand(rand.New(rand.NewSource(seed))).Build()
+		require.NoError(t, err)
+		return op.(IParamOperation)
+	}
+
+	for _, kind := range []nn.Kind{WeightMultiply, BiasAdd, SquaredDistance} {
+		// the same seed gives the same parameters
+		require.True(t, build(kind, 1).Parameter().Equal(build(kind, 1).Parameter()), kind)
+		require.False(t, build(kind, 1).Parameter().Equal(build(kind, 2).Parameter()), kind)
+	}
+}
@@ -3,6 +3,7 @@ package operation
 import (
 	"github.com/stretchr/testify/require"
 	"math"
+	"math/rand"
 	"nn/pkg/mmath/matrix"
 	"nn/pkg/percent"
 	"testing"
@@ -13,6 +14,24 @@ func TestNewDropoutOperation(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNewDropoutRand(t *testing.T) {
+	in, err := matrix.NewMatrixOf(4, 5, 1)
+	require.NoError(t, err)
+	forward := func(seed int64) *matrix.Matrix {
+		dropout, err := Create(Dropout, percent.Percent50, rand.New(rand.NewSource(seed)))
+		require.NoError(t, err)
+		out, err := dropout.Forward(in)
+		require.NoError(t, err)
+		return out
+	}
+	require.True(t, forward(1).Equal(forward(1)))
+
+	_, err = NewDropoutRand(percent.Percent50, nil)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = Create(Dropout, percent.Percent50, 1)
+	require.ErrorIs(t, err, ErrFabric)
+}
+
 func TestDropout_Forward(t *testing.T) {
 	prob := percent.Percent30
 	dropout := newOperation(t, Dropout, prob)
@@ -24,8 +43,9 @@ func TestDropout_Forward(t *testing.T) {
 	tries := 10 // results are random, so it needs to take several tries
 	outs := make([]*matrix.Matrix, tries)
 	for try := 0; try < tries; try++ {
-		outs[try], err = dropout.Forward(in)
+		out, err := dropout.Forward(in)
 		require.NoError(t, err)
+		outs[try] = out.Copy() // output is reused by the next call
 	}
 	result := false
 	for i, out := range outs {
@@ -2,8 +2,10 @@ package operation
 
 import (
 	"fmt"
+	"math/rand"
 	"nn/internal/nn"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/sparse"
 	"nn/pkg/mmath/vector"
 	"nn/pkg/percent"
 	"nn/pkg/wraperr"
@@ -20,6 +22,14 @@ func Create(kind nn.Kind, args ...interface{}) (o IOperation, err error) {
 		return NewTanhActivation(), nil
 	case SigmoidActivation:
 		return NewSigmoidActivation(), nil
+	case SoftmaxActivation:
+		return NewSoftmaxActivation(), nil
+	case GaussianActivation:
+		return NewGaussianActivation(), nil
+	case MultiquadricActivation:
+		return NewMultiquadricActivation(), nil
+	case InverseMultiquadricActivation:
+		return NewInverseMultiquadricActivation(), nil
 	case SigmoidParamActivation:
 		if len(args) < 1 {
 			return nil, fmt.Errorf("no coefficients provided for %s", kind)
@@ -33,14 +43,20 @@ func Create(kind nn.Kind, args ...interface{}) (o IOperation, err error) {
 			return nil, fmt.Errorf("no keep probability provided for %s", kind)
 		} else if p, ok := args[0].(percent.Percent); !ok {
 			return nil, fmt.Errorf("first argument for %s is not a percent.Percent: %T", kind, args[0])
-		} else {
+		} else if len(args) < 2 {
 			return NewDropout(p)
+		} else if rng, ok := args[1].(*rand.Rand); !ok {
+			return nil, fmt.Errorf("second argument for %s is not a *rand.Rand: %T", kind, args[1])
+		} else {
+			return NewDropoutRand(p, rng)
 		}
 	case WeightMultiply:
 		if len(args) < 1 {
 			return nil, fmt.Errorf("no weight provided for %s", kind)
+		} else if w, ok := args[0].(*sparse.Matrix); ok {
+			return NewSparseWeightOperation(w)
 		} else if w, ok := args[0].(*matrix.Matrix); !ok {
-			return nil, fmt.Errorf("first argument for %s is not a *matrix.Matrix: %T", kind, args[0])
+			return nil, fmt.Errorf("first argument for %s is not a *matrix.Matrix or *sparse.Matrix: %T", kind, args[0])
 		} else {
 			return NewWeightOperation(w)
 		}
@@ -52,6 +68,44 @@ func Create(kind nn.Kind, args ...interface{}) (o IOperation, err error) {
 		} else {
 			return NewBiasOperation(b)
 		}
+	case SquaredDistance:
+		if len(args) < 1 {
+			return nil, fmt.Errorf("no centers provided for %s", kind)
+		} else if c, ok := args[0].(*matrix.Matrix); !ok {
+			return nil, fmt.Errorf("first argument for %s is not a *matrix.Matrix: %T", kind, args[0])
+		} else {
+			return NewSquaredDistance(c)
+		}
+	case WidthScale:
+		if len(args) < 1 {
+			return nil, fmt.Errorf("no widths provided for %s", kind)
+		} else if w, ok := args[0].(*vector.Vector); !ok {
+			return nil, fmt.Errorf("first argument for %s is not a *vector.Vector: %T", kind, args[0])
+		} else {
+			return NewWidthScale(w)
+		}
+	case Im2Col, MaxPool, AvgPool:
+		if len(args) < 1 {
+			return nil, fmt.Errorf("no window parameters provided for %s", kind)
+		} else if w, ok := args[0].(*WindowParameters); !ok {
+			return nil, fmt.Errorf("first argument for %s is not a *WindowParameters: %T", kind, args[0])
+		} else if kind == MaxPool {
+			return NewMaxPool(w)
+		} else if kind == AvgPool {
+			return NewAvgPool(w)
+		} else {
+			return NewIm2Col(w)
+		}
+	case Reshape:
+		if len(args) < 2 {
+			return nil, fmt.Errorf("not enough arguments for %s, required %d, provided %d", kind, 2, len(args))
+		} else if a, ok := args[0].(int); !ok {
+			return nil, fmt.Errorf("first argument for %s is not an int: %T", kind, args[0])
+		} else if b, ok := args[1].(int); !ok {
+			return nil, fmt.Errorf("second argument for %s is not an int: %T", kind, args[1])
+		} else {
+			return NewReshape(a, b)
+		}
 	}
 
 	return nil, fmt.Errorf("unknown operation: %s", kind)
@@ -10,6 +10,7 @@ const (
 	LinearActivation  nn.Kind = "linear activation"
 	SigmoidActivation nn.Kind = "sigmoid activation"
 	TanhActivation    nn.Kind = "tanh activation"
+	SoftmaxActivation nn.Kind = "softmax activation"
 )
 
 // NewLinearActivation return operation:
@@ -20,14 +21,14 @@ func NewLinearActivation() IOperation {
 	return &Operation{
 		kind:       LinearActivation,
 		activation: true,
-		output:     func(x *matrix.Matrix) (*matrix.Matrix, error) { return x.Copy(), nil },
-		gradient:   func(y, dy *matrix.Matrix) (*matrix.Matrix, error) { return dy.Copy(), nil },
+		output:     func(x, y *matrix.Matrix) (*matrix.Matrix, error) { return x.CopyInto(y), nil },
+		gradient:   func(y, dy, dx *matrix.Matrix) (*matrix.Matrix, error) { return dy.CopyInto(dx), nil },
 	}
 }
 
 // NewSigmoidActivation return operation:
 //     y = f(x) = 1 / (1 + exp(-x));
-//     dx = f(dy) = dy * (1 - dy).
+//     dx = f(dy) = dy * y * (1 - y).
 func NewSigmoidActivation() IOperation {
 	logger.Debug("create new sigmoid activation")
 	sigmoid := func(value float64) float64 {
@@ -36,32 +37,75 @@ func NewSigmoidActivation() IOperation {
 	return &Operation{
 		kind:       SigmoidActivation,
 		activation: true,
-		output: func(x *matrix.Matrix) (*matrix.Matrix, error) {
-			return x.ApplyFunc(sigmoid), nil
+		output: func(x, y *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.ApplyFuncInto(y, sigmoid), nil
 		},
-		gradient: func(y, dy *matrix.Matrix) (*matrix.Matrix, error) {
-			return y.ApplyFunc(func(value float64) float64 {
-				return sigmoid(value) * (1 - sigmoid(value))
-			}).Mul(dy)
+		gradient: func(y, dy, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			return y.ApplyFuncMatInto(dx, dy, func(value, grad float64) float64 {
+				return value * (1 - value) * grad
+			})
 		},
 	}
 }
 
 // NewTanhActivation return operation:
 //     y = f(x) = tanh(x);
-//     dx = f(dy) = dy * (1 - dy).
+//     dx = f(dy) = dy * (1 - y^2).
 func NewTanhActivation() IOperation {
 	logger.Debug("create new tanh activation")
 	return &Operation{
 		kind:       TanhActivation,
 		activation: true,
-		output: func(x *matrix.Matrix) (*matrix.Matrix, error) {
-			return x.Tanh(), nil
+		output: func(x, y *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.ApplyFuncInto(y, math.Tanh), nil
 		},
-		gradient: func(y, dy *matrix.Matrix) (*matrix.Matrix, error) {
-			return y.ApplyFunc(func(value float64) float64 {
-				return math.Tanh(value) * (1 - math.Tanh(value))
-			}).Mul(dy)
+		gradient: func(y, dy, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			return y.ApplyFuncMatInto(dx, dy, func(value, grad float64) float64 {
+				return (1 - value*value) * grad
+			})
+		},
+	}
+}
+
+// NewSoftmaxActivation return row-wise operation:
+//     y = f(x) = exp(x - max(x)) / sum(exp(x - max(x)));
+//     dx = f(dy) = y * (dy - sum(dy * y)),
+//     where max and sum are taken over each row. Values equal to -Inf (masked) produce zero output.
+func NewSoftmaxActivation() IOperation {
+	logger.Debug("create new softmax activation")
+	return &Operation{
+		kind:       SoftmaxActivation,
+		activation: true,
+		output: func(x, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			maxes, err := x.MaxAxed(matrix.Horizontal)
+			if err != nil {
+				return nil, err
+			}
+			shifted, err := x.SubCol(maxes)
+			if err != nil {
+				return nil, err
+			}
+			exp := shifted.Exp()
+			sums, err := exp.SumAxed(matrix.Horizontal)
+			if err != nil {
+				return nil, err
+			}
+			return exp.DivCol(sums)
+		},
+		gradient: func(y, dy, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			prod, err := dy.Mul(y)
+			if err != nil {
+				return nil, err
+			}
+			sums, err := prod.SumAxedM(matrix.Horizontal)
+			if err != nil {
+				return nil, err
+			}
+			centered, err := dy.SubColM(sums)
+			if err != nil {
+				return nil, err
+			}
+			return centered.MulInPlace(y)
 		},
 	}
 }
@@ -3,7 +3,9 @@ package operation
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"nn/internal/nn"
+	"nn/internal/utils"
 	"nn/pkg/mmath/matrix"
 	"nn/pkg/mmath/vector"
 	"nn/pkg/percent"
@@ -15,50 +17,44 @@ const (
 	SigmoidParamActivation nn.Kind = "parametrized sigmoid activation"
 )
 
-// generateMask return Matrix containing only values 0 and 1 distributed by given probability (count of 1 is defined
-// by <probability>)
-func generateMask(rows, cols int, probability percent.Percent) *matrix.Matrix {
-	values := make([][]float64, rows)
-	for i := 0; i < rows; i++ {
-		values[i] = make([]float64, cols)
-		for j := 0; j < cols; j++ {
-			if probability.Hit() {
-				values[i][j] = 1
-			}
-		}
-	}
-
-	mask, err := matrix.NewMatrixRaw(values)
-	if err != nil {
-		panic(err)
-	}
-
-	return mask
-}
-
 // NewDropout return dropout operation:
 //     - each call will be generated mask of 0 and 1;
 //     - shape of mask match shape of input;
 //     - y = x * mask;
 //     - dx = dy * mask.
 //
+// Masks are drawn from utils.Rand, see NewDropoutRand.
+//
 // Throws ErrCreate error.
 func NewDropout(keepProbability percent.Percent) (o IOperation, err error) {
+	return NewDropoutRand(keepProbability, utils.Rand)
+}
+
+// NewDropoutRand return dropout operation (see NewDropout) drawing masks from given random generator.
+//
+// Throws ErrCreate error.
+func NewDropoutRand(keepProbability percent.Percent, rng *rand.Rand) (o IOperation, err error) {
 	defer logger.CatchErr(&err)
 	defer wraperr.WrapError(ErrCreate, &err)
 
 	logger.Debug("create new dropout operation")
+	if rng == nil {
+		return nil, fmt.Errorf("no random generator provided: %v", rng)
+	}
 	params := []*matrix.Matrix{nil}
 	return &ConstOperation{
 		Operation: &Operation{kind: Dropout},
 		p:         params,
-		output: func(x *matrix.Matrix, p []*matrix.Matrix) (*matrix.Matrix, error) {
-			mask := generateMask(x.Rows(), x.Cols(), keepProbability)
+		output: func(x *matrix.Matrix, p []*matrix.Matrix, y *matrix.Matrix) (*matrix.Matrix, error) {
+			mask, err := matrix.RandBernoulli(rng, x.Rows(), x.Cols(), keepProbability.GetF(1))
+			if err != nil {
+				return nil, err
+			}
 			p[0] = mask
-			return x.Mul(p[0])
+			return x.ApplyFuncMatInto(y, p[0], matrix.Mul)
 		},
-		gradient: func(dy *matrix.Matrix, p []*matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error) {
-			return dy.Mul(p[0])
+		gradient: func(dy *matrix.Matrix, p []*matrix.Matrix, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.ApplyFuncMatInto(dx, p[0], matrix.Mul)
 		},
 	}, nil
 }
@@ -86,7 +82,7 @@ func NewSigmoidParam(coeffs *vector.Vector) (o IOperation, err error) {
 	return &ConstOperation{
 		Operation: &Operation{kind: SigmoidParamActivation, activation: true},
 		p:         params,
-		output: func(x *matrix.Matrix, p []*matrix.Matrix) (*matrix.Matrix, error) {
+		output: func(x *matrix.Matrix, p []*matrix.Matrix, _ *matrix.Matrix) (*matrix.Matrix, error) {
 			multiplied, err := x.MulRowM(p[0])
 			if err != nil {
 				return nil, err
@@ -95,7 +91,7 @@ func NewSigmoidParam(coeffs *vector.Vector) (o IOperation, err error) {
 				return 1 / (1 + math.Exp(-value))
 			}), nil
 		},
-		gradient: func(dy *matrix.Matrix, p []*matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error) {
+		gradient: func(dy *matrix.Matrix, p []*matrix.Matrix, x *matrix.Matrix, _ *matrix.Matrix) (*matrix.Matrix, error) {
 			return dy.ApplyFunc(func(value float64) float64 {
 				return value * (1 - value)
 			}).MulRowM(p[0])
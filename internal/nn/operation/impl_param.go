@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"nn/internal/nn"
 	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/sparse"
 	"nn/pkg/mmath/vector"
 	"nn/pkg/wraperr"
 )
@@ -28,14 +29,14 @@ func NewBiasOperation(bias *vector.Vector) (o IOperation, err error) {
 	return &ParamOperation{
 		Operation: &Operation{kind: BiasAdd},
 		p:         biasAsMatrix,
-		output: func(x *matrix.Matrix, b *matrix.Matrix) (*matrix.Matrix, error) {
-			return x.AddRowM(b)
+		output: func(x *matrix.Matrix, b *matrix.Matrix, y *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.ApplyFuncMatRowInto(y, b, matrix.Add)
 		},
-		gradient: func(dy *matrix.Matrix, b *matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error) {
-			return dy.Copy(), nil
+		gradient: func(dy *matrix.Matrix, b *matrix.Matrix, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.CopyInto(dx), nil
 		},
-		gradParam: func(dy *matrix.Matrix, b *matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error) {
-			return dy.SumAxedM(matrix.Vertical)
+		gradParam: func(dy *matrix.Matrix, b *matrix.Matrix, x *matrix.Matrix, db *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.SumAxedMInto(db, matrix.Vertical)
 		},
 	}, nil
 }
@@ -54,14 +55,84 @@ func NewWeightOperation(weight *matrix.Matrix) (o IOperation, err error) {
 	return &ParamOperation{
 		Operation: &Operation{kind: WeightMultiply},
 		p:         weight.Copy(),
-		output: func(x *matrix.Matrix, w *matrix.Matrix) (*matrix.Matrix, error) {
-			return x.MatMul(w)
+		output: func(x *matrix.Matrix, w *matrix.Matrix, y *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.MatMulInto(y, w)
 		},
-		gradient: func(dy *matrix.Matrix, w *matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error) {
-			return dy.MatMul(w.T())
+		gradient: func(dy *matrix.Matrix, w *matrix.Matrix, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.MatMulTInto(dx, w)
 		},
-		gradParam: func(dy *matrix.Matrix, w *matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error) {
-			return x.T().MatMul(dy)
+		gradParam: func(dy *matrix.Matrix, w *matrix.Matrix, x *matrix.Matrix, dw *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.TMatMulInto(dw, dy)
+		},
+	}, nil
+}
+
+// NewSparseWeightOperation returns operation of multiply weights stored as sparse.Matrix (see SparseOperation)
+//
+// Throws ErrCreate error
+func NewSparseWeightOperation(weight *sparse.Matrix) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create new sparse weight multiply operation")
+	if weight == nil {
+		return nil, fmt.Errorf("no weight provided: %v", weight)
+	}
+	return &SparseOperation{
+		Operation: &Operation{kind: WeightMultiply},
+		p:         weight.Copy(),
+	}, nil
+}
+
+// NewBias32Operation returns operation of adding bias stored in nn.Float32Params precision
+//
+// Throws ErrCreate error
+func NewBias32Operation(bias *vector.Vector) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create new bias add operation with float32 bias")
+	if bias == nil {
+		return nil, fmt.Errorf("no bias provided: %v", bias)
+	}
+	biasAsMatrix, _ := matrix.NewMatrix([]*vector.Vector{bias})
+	return &Param32Operation{
+		Operation: &Operation{kind: BiasAdd},
+		p:         biasAsMatrix.Float32(),
+		output: func(x *matrix.Matrix, b *matrix.Matrix32, y *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.AddRow32Into(y, b)
+		},
+		gradient: func(dy *matrix.Matrix, b *matrix.Matrix32, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.CopyInto(dx), nil
+		},
+		gradParam: func(dy *matrix.Matrix, b *matrix.Matrix32, x *matrix.Matrix, db *matrix.Matrix32) (*matrix.Matrix32, error) {
+			return dy.SumColsTo32(db)
+		},
+	}, nil
+}
+
+// NewWeight32Operation returns operation of multiply weights stored in nn.Float32Params precision
+//
+// Throws ErrCreate error
+func NewWeight32Operation(weight *matrix.Matrix) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create new weight multiply operation with float32 weight")
+	if weight == nil {
+		return nil, fmt.Errorf("no weight provided: %v", weight)
+	}
+	return &Param32Operation{
+		Operation: &Operation{kind: WeightMultiply},
+		p:         weight.Float32(),
+		output: func(x *matrix.Matrix, w *matrix.Matrix32, y *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.MatMul32Into(y, w)
+		},
+		gradient: func(dy *matrix.Matrix, w *matrix.Matrix32, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.MatMulT32Into(dx, w)
+		},
+		gradParam: func(dy *matrix.Matrix, w *matrix.Matrix32, x *matrix.Matrix, dw *matrix.Matrix32) (*matrix.Matrix32, error) {
+			return x.TMatMulTo32(dw, dy)
 		},
 	}, nil
 }
@@ -0,0 +1,214 @@
+package operation
+
+import (
+	"fmt"
+	"math"
+	"nn/internal/nn"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+)
+
+const (
+	SquaredDistance               nn.Kind = "squared distance"
+	WidthScale                    nn.Kind = "width scale"
+	GaussianActivation            nn.Kind = "gaussian activation"
+	MultiquadricActivation        nn.Kind = "multiquadric activation"
+	InverseMultiquadricActivation nn.Kind = "inverse multiquadric activation"
+)
+
+// NewSquaredDistance return operation of computing squared euclidean distances from inputs to trainable centers:
+//     y[i][j] = |x[i] - c[j]|^2;
+//     dx[i] = 2 * sum(dy[i][j] * (x[i] - c[j])) over j;
+//     dc[j] = 2 * sum(dy[i][j] * (c[j] - x[i])) over i,
+//     where c[j] is j'th row of <centers> (centers count x inputs count).
+//
+// Throws ErrCreate error.
+func NewSquaredDistance(centers *matrix.Matrix) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create new squared distance operation")
+	if centers == nil {
+		return nil, fmt.Errorf("no centers provided: %v", centers)
+	}
+	return &ParamOperation{
+		Operation: &Operation{kind: SquaredDistance},
+		p:         centers.Copy(),
+		output: func(x *matrix.Matrix, c *matrix.Matrix, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			xSqr, err := x.Sqr().SumAxedM(matrix.Horizontal)
+			if err != nil {
+				return nil, err
+			}
+			cSqr, err := c.Sqr().SumAxedM(matrix.Horizontal)
+			if err != nil {
+				return nil, err
+			}
+			prod, err := x.MatMulT(c)
+			if err != nil {
+				return nil, err
+			}
+			dist, err := prod.MulNum(-2).AddColM(xSqr)
+			if err != nil {
+				return nil, err
+			}
+			return dist.AddRowM(cSqr.T())
+		},
+		gradient: func(dy *matrix.Matrix, c *matrix.Matrix, x *matrix.Matrix, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			dySum, err := dy.SumAxedM(matrix.Horizontal)
+			if err != nil {
+				return nil, err
+			}
+			scaled, err := x.MulColM(dySum)
+			if err != nil {
+				return nil, err
+			}
+			weighted, err := dy.MatMul(c)
+			if err != nil {
+				return nil, err
+			}
+			dx, err := scaled.SubInPlace(weighted)
+			if err != nil {
+				return nil, err
+			}
+			return dx.MulNumInPlace(2), nil
+		},
+		gradParam: func(dy *matrix.Matrix, c *matrix.Matrix, x *matrix.Matrix, dc *matrix.Matrix) (*matrix.Matrix, error) {
+			dySum, err := dy.SumAxedM(matrix.Vertical)
+			if err != nil {
+				return nil, err
+			}
+			scaled, err := c.MulColM(dySum.T())
+			if err != nil {
+				return nil, err
+			}
+			weighted, err := dy.TMatMulInto(dc, x)
+			if err != nil {
+				return nil, err
+			}
+			if dc, err = scaled.ApplyFuncMatInto(weighted, weighted, matrix.Sub); err != nil {
+				return nil, err
+			}
+			return dc.MulNumInPlace(2), nil
+		},
+	}, nil
+}
+
+// NewWidthScale return operation of dividing squared distances by squared trainable widths:
+//     y = x / w^2;
+//     dx = dy / w^2;
+//     dw = -2 * sum(dy * x) / w^3 over batch,
+//     where w is <widths> vector (centers count).
+//
+// Throws ErrCreate error.
+func NewWidthScale(widths *vector.Vector) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create new width scale operation")
+	if widths == nil {
+		return nil, fmt.Errorf("no widths provided: %v", widths)
+	}
+	for i, value := range widths.Raw() {
+		if value == 0 {
+			return nil, fmt.Errorf("zero width: %d'th", i)
+		}
+	}
+	widthsAsMatrix, _ := matrix.NewMatrix([]*vector.Vector{widths.Copy()})
+	return &ParamOperation{
+		Operation: &Operation{kind: WidthScale},
+		p:         widthsAsMatrix,
+		output: func(x *matrix.Matrix, w *matrix.Matrix, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.DivRowM(w.Sqr())
+		},
+		gradient: func(dy *matrix.Matrix, w *matrix.Matrix, x *matrix.Matrix, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.DivRowM(w.Sqr())
+		},
+		gradParam: func(dy *matrix.Matrix, w *matrix.Matrix, x *matrix.Matrix, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			prod, err := dy.Mul(x)
+			if err != nil {
+				return nil, err
+			}
+			sum, err := prod.SumAxedM(matrix.Vertical)
+			if err != nil {
+				return nil, err
+			}
+			dw, err := sum.DivRowM(w.Pow(3))
+			if err != nil {
+				return nil, err
+			}
+			return dw.MulNumInPlace(-2), nil
+		},
+	}, nil
+}
+
+// NewGaussianActivation return radial basis operation:
+//     y = f(x) = exp(-x);
+//     dx = f(dy) = -dy * y.
+func NewGaussianActivation() IOperation {
+	logger.Debug("create new gaussian activation")
+	return &Operation{
+		kind:       GaussianActivation,
+		activation: true,
+		output: func(x, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.MulNum(-1).Exp(), nil
+		},
+		gradient: func(y, dy, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			grad, err := y.Mul(dy)
+			if err != nil {
+				return nil, err
+			}
+			return grad.MulNumInPlace(-1), nil
+		},
+	}
+}
+
+// NewMultiquadricActivation return radial basis operation:
+//     y = f(x) = sqrt(1 + x);
+//     dx = f(dy) = dy / (2 * y).
+func NewMultiquadricActivation() IOperation {
+	logger.Debug("create new multiquadric activation")
+	return &Operation{
+		kind:       MultiquadricActivation,
+		activation: true,
+		output: func(x, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.AddNum(1).Sqrt(), nil
+		},
+		gradient: func(y, dy, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return dy.Div(y.MulNum(2))
+		},
+	}
+}
+
+// NewInverseMultiquadricActivation return radial basis operation:
+//     y = f(x) = 1 / sqrt(1 + x);
+//     dx = f(dy) = -dy * y^3 / 2.
+func NewInverseMultiquadricActivation() IOperation {
+	logger.Debug("create new inverse multiquadric activation")
+	return &Operation{
+		kind:       InverseMultiquadricActivation,
+		activation: true,
+		output: func(x, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return x.ApplyFunc(func(value float64) float64 {
+				return 1 / math.Sqrt(1+value)
+			}), nil
+		},
+		gradient: func(y, dy, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			grad, err := y.Pow(3).MulInPlace(dy)
+			if err != nil {
+				return nil, err
+			}
+			return grad.MulNumInPlace(-0.5), nil
+		},
+	}
+}
+
+var radialBasis = map[nn.Kind]struct{}{
+	GaussianActivation: {}, MultiquadricActivation: {}, InverseMultiquadricActivation: {},
+}
+
+// IsRadialBasis return true if kind is one of radial basis activations (kernels of radial basis function layer).
+func IsRadialBasis(kind nn.Kind) bool {
+	_, ok := radialBasis[kind]
+	return ok
+}
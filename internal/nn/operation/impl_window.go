@@ -0,0 +1,191 @@
+package operation
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+const (
+	Im2Col  nn.Kind = "im2col"
+	Reshape nn.Kind = "reshape"
+	MaxPool nn.Kind = "max pool"
+	AvgPool nn.Kind = "avg pool"
+)
+
+// WindowParameters describes sliding window over packed signal. Signal is packed row-wise: each row holds one sample,
+// positions are written sequentially, each position occupies Channels cols.
+//
+// Example (signal of 3 positions with 2 channels):
+//     | x(0,0) x(0,1) x(1,0) x(1,1) x(2,0) x(2,1) |
+//     where x(position,channel).
+type WindowParameters struct {
+	Length   int // count of signal positions
+	Channels int // count of channels of each position
+	Kernel   int // window size
+	Stride   int // window step
+	Padding  int // count of zero positions added to both sides of signal
+}
+
+// Check return error if parameters describe no valid window.
+func (p *WindowParameters) Check() error {
+	if p == nil {
+		return fmt.Errorf("no window parameters provided: %v", p)
+	} else if p.Length < 1 {
+		return fmt.Errorf("negative or zero length: %d", p.Length)
+	} else if p.Channels < 1 {
+		return fmt.Errorf("negative or zero channels count: %d", p.Channels)
+	} else if p.Kernel < 1 {
+		return fmt.Errorf("negative or zero kernel size: %d", p.Kernel)
+	} else if p.Stride < 1 {
+		return fmt.Errorf("negative or zero stride: %d", p.Stride)
+	} else if p.Padding < 0 {
+		return fmt.Errorf("negative padding: %d", p.Padding)
+	} else if p.Kernel > p.Length+2*p.Padding {
+		return fmt.Errorf("kernel size exceeds padded length: %d > %d", p.Kernel, p.Length+2*p.Padding)
+	}
+	return nil
+}
+
+// OutputLength return count of window positions:
+//     (Length + 2 * Padding - Kernel) / Stride + 1.
+func (p *WindowParameters) OutputLength() int {
+	return (p.Length+2*p.Padding-p.Kernel)/p.Stride + 1
+}
+
+// NewIm2Col return operation, that unfolds each window of each sample to separate row:
+//     - input is batch x Length*Channels;
+//     - output is batch*OutputLength x Kernel*Channels, rows of the same sample are consecutive;
+//     - positions outside of signal (padding) are filled with zeros;
+//     - dx is sum of dy values of all windows covering the position.
+//
+// Throws ErrCreate error.
+func NewIm2Col(window *WindowParameters) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create new im2col operation")
+	if err = window.Check(); err != nil {
+		return nil, err
+	}
+	w := *window
+	outLength := w.OutputLength()
+	windowCols := w.Kernel * w.Channels
+
+	// position return signal position of k'th value of o'th window, false if it is padding
+	position := func(o, k int) (int, bool) {
+		pos := o*w.Stride - w.Padding + k
+		return pos, pos >= 0 && pos < w.Length
+	}
+
+	return &Operation{
+		kind: Im2Col,
+		output: func(x, y *matrix.Matrix) (*matrix.Matrix, error) {
+			if x.Cols() != w.Length*w.Channels {
+				return nil, fmt.Errorf("input cols count must match length * channels: %d != %d*%d",
+					x.Cols(), w.Length, w.Channels)
+			}
+			// padding values are never written, so they stay zero in reused output
+			y, err := reuseMatrix(y, x.Rows()*outLength, windowCols)
+			if err != nil {
+				return nil, err
+			}
+			in, values := x.Data(), y.Data()
+			for b := 0; b < x.Rows(); b++ {
+				row := in[b*x.Cols() : (b+1)*x.Cols()]
+				for o := 0; o < outLength; o++ {
+					offset := (b*outLength + o) * windowCols
+					for k := 0; k < w.Kernel; k++ {
+						if pos, ok := position(o, k); ok {
+							copy(values[offset+k*w.Channels:offset+(k+1)*w.Channels],
+								row[pos*w.Channels:(pos+1)*w.Channels])
+						}
+					}
+				}
+			}
+			return y, nil
+		},
+		gradient: func(y, dy, dx *matrix.Matrix) (*matrix.Matrix, error) {
+			batch := dy.Rows() / outLength
+			dx, err := reuseMatrix(dx, batch, w.Length*w.Channels)
+			if err != nil {
+				return nil, err
+			}
+			flat, values := dy.Data(), dx.Data()
+			for i := range values {
+				values[i] = 0
+			}
+			for b := 0; b < batch; b++ {
+				for o := 0; o < outLength; o++ {
+					offset := (b*outLength + o) * windowCols
+					for k := 0; k < w.Kernel; k++ {
+						if pos, ok := position(o, k); ok {
+							for c := 0; c < w.Channels; c++ {
+								values[(b*w.Length+pos)*w.Channels+c] += flat[offset+k*w.Channels+c]
+							}
+						}
+					}
+				}
+			}
+			return dx, nil
+		},
+	}, nil
+}
+
+// NewReshape return operation, that changes cols count keeping values order:
+//     - input is rows x inputCols;
+//     - output is rows*inputCols/outputCols x outputCols;
+//     - dx is dy reshaped back to input shape.
+//
+// Throws ErrCreate error.
+func NewReshape(inputCols, outputCols int) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debug("create new reshape operation")
+	if inputCols < 1 || outputCols < 1 {
+		return nil, fmt.Errorf("negative or zero cols count: %d, %d", inputCols, outputCols)
+	}
+
+	reshape := func(m *matrix.Matrix, from, to int) (*matrix.Matrix, error) {
+		if m.Cols() != from {
+			return nil, fmt.Errorf("cols count mismatch: %d != %d", m.Cols(), from)
+		}
+		return m.Reshape(m.Rows()*m.Cols()/to, to)
+	}
+
+	return &Operation{
+		kind: Reshape,
+		output: func(x, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return reshape(x, inputCols, outputCols)
+		},
+		gradient: func(y, dy, _ *matrix.Matrix) (*matrix.Matrix, error) {
+			return reshape(dy, outputCols, inputCols)
+		},
+	}, nil
+}
+
+// NewMaxPool return operation, that takes maximum of each channel over each window of packed signal
+// (see PoolOperation):
+//     - input is batch x Length*Channels;
+//     - output is batch x OutputLength*Channels, each window position occupies Channels cols;
+//     - padding positions are never picked, window of padding only gives 0;
+//     - dx is dy for first maximal value of each window and 0 for others.
+//
+// Throws ErrCreate error.
+func NewMaxPool(window *WindowParameters) (IOperation, error) {
+	return newPoolOperation(MaxPool, window)
+}
+
+// NewAvgPool return operation, that takes average of each channel over each window of packed signal
+// (see PoolOperation):
+//     - input is batch x Length*Channels;
+//     - output is batch x OutputLength*Channels, each window position occupies Channels cols;
+//     - padding positions count as zeros, so sum of window is divided by Kernel;
+//     - dx is dy / Kernel for each value of window.
+//
+// Throws ErrCreate error.
+func NewAvgPool(window *WindowParameters) (IOperation, error) {
+	return newPoolOperation(AvgPool, window)
+}
@@ -12,6 +12,9 @@ var _ IOperation = (*Operation)(nil)
 
 // Operation represents main part of all operations. It stores all given and computed data. It defines operation
 // behavior.
+//
+// Computed output and gradients are stored in buffers reused on the next calls while shapes do not change, so steady
+// training steps do not allocate them again. Given matrices are copied, so they may be modified after the call.
 type Operation struct {
 	kind       nn.Kind
 	activation bool
@@ -22,8 +25,9 @@ type Operation struct {
 	dx *matrix.Matrix
 	dy *matrix.Matrix
 
-	output   func(x *matrix.Matrix) (*matrix.Matrix, error)
-	gradient func(y, dy *matrix.Matrix) (*matrix.Matrix, error)
+	// output and gradient may write result to y or dx (result of the previous call, nil on the first one) and return it
+	output   func(x, y *matrix.Matrix) (*matrix.Matrix, error)
+	gradient func(y, dy, dx *matrix.Matrix) (*matrix.Matrix, error)
 }
 
 func (o *Operation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
@@ -37,12 +41,12 @@ func (o *Operation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
 		return nil, fmt.Errorf("no input provided: %v", x)
 	}
 
-	o.x = x.Copy()
-	y, err = o.output(x)
+	o.x = x.CopyInto(o.x)
+	y, err = o.output(o.x, o.y)
 	if err != nil {
 		return nil, fmt.Errorf("error computing output: %w", err)
 	}
-	o.y = y.Copy()
+	o.y = y
 	return y, nil
 }
 
@@ -59,17 +63,17 @@ func (o *Operation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
 		return nil, fmt.Errorf("call Backward() before Forward()")
 	}
 
-	o.dy = dy.Copy()
+	o.dy = dy.CopyInto(o.dy)
 	if err := o.y.CheckEqualShape(dy); err != nil {
 		return nil, fmt.Errorf("error checking output and output gradient shapes: %w", err)
 	}
-	dx, err = o.gradient(o.y.Copy(), dy)
+	dx, err = o.gradient(o.y, o.dy, o.dx)
 	if err != nil {
 		return nil, fmt.Errorf("error computing input gradient: %w", err)
 	} else if err = o.x.CheckEqualShape(dx); err != nil {
 		return nil, fmt.Errorf("error checking input and input gradient shapes: %w", err)
 	}
-	o.dx = dx.Copy()
+	o.dx = dx
 	return dx, nil
 }
 
@@ -89,8 +93,9 @@ func (o *Operation) IsActivation() bool {
 }
 
 var activations = map[nn.Kind]struct{}{
-	LinearActivation: {}, TanhActivation: {}, SigmoidActivation: {},
-	SigmoidParamActivation: {},
+	LinearActivation: {}, TanhActivation: {}, SigmoidActivation: {}, SoftmaxActivation: {},
+	SigmoidParamActivation: {}, GaussianActivation: {}, MultiquadricActivation: {},
+	InverseMultiquadricActivation: {},
 }
 
 func IsActivation(kind nn.Kind) bool {
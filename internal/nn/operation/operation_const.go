@@ -16,8 +16,9 @@ type ConstOperation struct {
 
 	p []*matrix.Matrix
 
-	output   func(x *matrix.Matrix, p []*matrix.Matrix) (*matrix.Matrix, error)
-	gradient func(dy *matrix.Matrix, p []*matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error)
+	// output and gradient may write result to y or dx the same way as Operation's ones
+	output   func(x *matrix.Matrix, p []*matrix.Matrix, y *matrix.Matrix) (*matrix.Matrix, error)
+	gradient func(dy *matrix.Matrix, p []*matrix.Matrix, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error)
 }
 
 func (o *ConstOperation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
@@ -31,12 +32,12 @@ func (o *ConstOperation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error)
 		return nil, fmt.Errorf("no input provided: %v", x)
 	}
 
-	o.x = x.Copy()
-	y, err = o.output(x, o.p)
+	o.x = x.CopyInto(o.x)
+	y, err = o.output(o.x, o.p, o.y)
 	if err != nil {
 		return nil, fmt.Errorf("error computing output: %w", err)
 	}
-	o.y = y.Copy()
+	o.y = y
 	return y, nil
 }
 
@@ -53,11 +54,11 @@ func (o *ConstOperation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err err
 		return nil, fmt.Errorf("call Backward() before Forward()")
 	}
 
-	o.dy = dy.Copy()
+	o.dy = dy.CopyInto(o.dy)
 	if err := o.y.CheckEqualShape(dy); err != nil {
 		return nil, err
 	}
-	dx, err = o.gradient(dy, o.p, o.x)
+	dx, err = o.gradient(o.dy, o.p, o.x, o.dx)
 	if err != nil {
 		return nil, fmt.Errorf("error computing input gradient: %w", err)
 	} else if o.x != nil {
@@ -65,7 +66,7 @@ func (o *ConstOperation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err err
 			return nil, err
 		}
 	}
-	o.dx = dx.Copy()
+	o.dx = dx
 	return dx, nil
 }
 
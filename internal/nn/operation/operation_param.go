@@ -8,7 +8,7 @@ import (
 	"nn/pkg/wraperr"
 )
 
-var _ IOperation = (*ParamOperation)(nil)
+var _ IParamOperation = (*ParamOperation)(nil)
 
 // ParamOperation represent Operation with parameters, that will be modified during training
 type ParamOperation struct {
@@ -17,9 +17,14 @@ type ParamOperation struct {
 	p  *matrix.Matrix
 	dp *matrix.Matrix
 
-	output    func(x *matrix.Matrix, p *matrix.Matrix) (*matrix.Matrix, error)
-	gradient  func(dy *matrix.Matrix, p *matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error)
-	gradParam func(dy *matrix.Matrix, p *matrix.Matrix, x *matrix.Matrix) (*matrix.Matrix, error)
+	// buffers of parameter and its gradient copies passed to Optimizer
+	optimP  *matrix.Matrix
+	optimDp *matrix.Matrix
+
+	// output, gradient and gradParam may write result to y, dx or dp the same way as Operation's ones
+	output    func(x *matrix.Matrix, p *matrix.Matrix, y *matrix.Matrix) (*matrix.Matrix, error)
+	gradient  func(dy *matrix.Matrix, p *matrix.Matrix, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error)
+	gradParam func(dy *matrix.Matrix, p *matrix.Matrix, x *matrix.Matrix, dp *matrix.Matrix) (*matrix.Matrix, error)
 }
 
 func (o *ParamOperation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
@@ -33,12 +38,12 @@ func (o *ParamOperation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error)
 		return nil, fmt.Errorf("no input provided: %v", x)
 	}
 
-	o.x = x.Copy()
-	y, err = o.output(x, o.p)
+	o.x = x.CopyInto(o.x)
+	y, err = o.output(o.x, o.p, o.y)
 	if err != nil {
 		return nil, wraperr.NewWrapErr(fmt.Errorf("error computing output"), err)
 	}
-	o.y = y.Copy()
+	o.y = y
 	return y, nil
 }
 
@@ -57,19 +62,19 @@ func (o *ParamOperation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err err
 		return nil, fmt.Errorf("call Backward() before Forward()")
 	}
 
-	dp, err := o.gradParam(dy, o.p, o.x)
+	dp, err := o.gradParam(dy, o.p, o.x, o.dp)
 	if err != nil {
 		return nil, fmt.Errorf("error computing paramter gradient: %w", err)
 	} else if err = o.p.CheckEqualShape(dp); err != nil {
 		return nil, err
 	}
-	o.dp = dp.Copy()
+	o.dp = dp
 
-	o.dy = dy.Copy()
+	o.dy = dy.CopyInto(o.dy)
 	if err := o.y.CheckEqualShape(dy); err != nil {
 		return nil, err
 	}
-	dx, err = o.gradient(dy, o.p, o.x)
+	dx, err = o.gradient(o.dy, o.p, o.x, o.dx)
 	if err != nil {
 		return nil, fmt.Errorf("error computing input gradient: %w", err)
 	} else if o.x != nil {
@@ -77,11 +82,12 @@ func (o *ParamOperation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err err
 			return nil, err
 		}
 	}
-	o.dx = dx.Copy()
+	o.dx = dx
 	return dx, nil
 }
 
-// Optimizer represents rule to modify parameters by pre-computed gradients
+// Optimizer represents rule to modify parameters by pre-computed gradients. Given parameter and gradient are copies
+// owned by Optimizer, so it may modify them and return modified parameter, e.g. using in-place matrix operations.
 type Optimizer func(param, grad *matrix.Matrix) (*matrix.Matrix, error)
 
 // ApplyOptim applies provided Optimizer to ParamOperation's parameter
@@ -97,7 +103,8 @@ func (o *ParamOperation) ApplyOptim(optim Optimizer) (err error) {
 	} else if o.dp == nil {
 		return fmt.Errorf("can not apply optimizer before gradient computation: %v", o.dp)
 	}
-	newP, err := optim(o.p.Copy(), o.dp.Copy())
+	o.optimP, o.optimDp = o.p.CopyInto(o.optimP), o.dp.CopyInto(o.optimDp)
+	newP, err := optim(o.optimP, o.optimDp)
 	if err != nil {
 		return fmt.Errorf("error computing new parameter: %w", err)
 	} else if err := o.p.CheckEqualShape(newP); err != nil {
@@ -106,7 +113,12 @@ func (o *ParamOperation) ApplyOptim(optim Optimizer) (err error) {
 		return fmt.Errorf("nil parameter after optimization: %v", newP)
 	}
 
-	o.p = newP
+	if newP == o.optimP {
+		// parameter is modified in place, so the previous one is reused as buffer on the next step
+		o.p, o.optimP = newP, o.p
+	} else {
+		o.p = newP
+	}
 	return nil
 }
 
@@ -115,6 +127,10 @@ func (o *ParamOperation) Parameter() *matrix.Matrix {
 	return o.p.Copy()
 }
 
+func (o *ParamOperation) Precision() nn.Precision {
+	return nn.Float64
+}
+
 func (o *ParamOperation) Copy() nn.IModule {
 	if o == nil {
 		return nil
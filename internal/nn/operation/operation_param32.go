@@ -0,0 +1,242 @@
+package operation
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+	"sync"
+)
+
+var _ IParamOperation = (*Param32Operation)(nil)
+
+// Param32Operation represents ParamOperation with parameter stored in nn.Float32Params precision: parameter and its
+// gradient are stored as matrix.Matrix32, so they take half of ParamOperation's ones memory. Input, output and their
+// gradients are matrix.Matrix stored the same way as ParamOperation's ones and are computed in double precision from
+// float32 parameter, so Param32Operation may be used with any other operations.
+type Param32Operation struct {
+	*Operation
+
+	p  *matrix.Matrix32
+	dp *matrix.Matrix32
+
+	// output, gradient and gradParam may write result to y, dx or dp the same way as ParamOperation's ones
+	output    func(x *matrix.Matrix, p *matrix.Matrix32, y *matrix.Matrix) (*matrix.Matrix, error)
+	gradient  func(dy *matrix.Matrix, p *matrix.Matrix32, x *matrix.Matrix, dx *matrix.Matrix) (*matrix.Matrix, error)
+	gradParam func(dy *matrix.Matrix, p *matrix.Matrix32, x *matrix.Matrix, dp *matrix.Matrix32) (*matrix.Matrix32, error)
+}
+
+// optimBuffers holds float64 buffers parameters and gradients of Param32Operations are converted to for Optimizer.
+// Buffers are shared by all the operations, so float64 copies of parameters are not kept between ApplyOptim calls.
+var optimBuffers = sync.Pool{New: func() interface{} {
+	return &optimBuffer{}
+}}
+
+type optimBuffer struct {
+	p  []float64
+	dp []float64
+}
+
+// float64View return given Matrix32 converted to Matrix backed by <buf>, <buf> grows if it is smaller than Matrix32.
+func float64View(buf *[]float64, m *matrix.Matrix32) (*matrix.Matrix, error) {
+	size := m.Rows() * m.Cols()
+	if cap(*buf) < size {
+		*buf = make([]float64, size)
+	}
+	view, err := matrix.NewMatrixShared(m.Rows(), m.Cols(), (*buf)[:size])
+	if err != nil {
+		return nil, err
+	}
+	return m.Float64Into(view), nil
+}
+
+func (o *Param32Operation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Forward propagation on %s", o.kind), &err)
+
+	if o == nil {
+		return nil, ErrNil
+	} else if x == nil {
+		return nil, fmt.Errorf("no input provided: %v", x)
+	}
+
+	o.x = x.CopyInto(o.x)
+	y, err = o.output(o.x, o.p, o.y)
+	if err != nil {
+		return nil, fmt.Errorf("error computing output: %w", err)
+	}
+	o.y = y
+	return y, nil
+}
+
+// Backward return input gradient just as ParamOperation.Backward() does.
+func (o *Param32Operation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Backward propagation on %s", o.kind), &err)
+
+	if o == nil {
+		return nil, ErrNil
+	} else if dy == nil {
+		return nil, fmt.Errorf("no output gradient provided: %v", dy)
+	} else if o.x == nil || o.y == nil {
+		return nil, fmt.Errorf("call Backward() before Forward()")
+	} else if err = o.y.CheckEqualShape(dy); err != nil {
+		return nil, err
+	}
+
+	o.dy = dy.CopyInto(o.dy)
+	dp, err := o.gradParam(o.dy, o.p, o.x, o.dp)
+	if err != nil {
+		return nil, fmt.Errorf("error computing paramter gradient: %w", err)
+	} else if err = o.p.CheckEqualShape(dp); err != nil {
+		return nil, err
+	}
+	o.dp = dp
+
+	dx, err = o.gradient(o.dy, o.p, o.x, o.dx)
+	if err != nil {
+		return nil, fmt.Errorf("error computing input gradient: %w", err)
+	} else if err = o.x.CheckEqualShape(dx); err != nil {
+		return nil, err
+	}
+	o.dx = dx
+	return dx, nil
+}
+
+// ApplyOptim applies provided Optimizer to Param32Operation's parameter. Optimizer gets float64 copies of parameter
+// and its gradient written to buffers shared by all the Param32Operations, new parameter is rounded to float32.
+func (o *Param32Operation) ApplyOptim(optim Optimizer) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during apply Optimizer on %s", o.kind), &err)
+
+	if o == nil {
+		return ErrNil
+	} else if optim == nil {
+		return fmt.Errorf("no optimizer provided")
+	} else if o.dp == nil {
+		return fmt.Errorf("can not apply optimizer before gradient computation: %v", o.dp)
+	}
+
+	buf := optimBuffers.Get().(*optimBuffer)
+	defer optimBuffers.Put(buf)
+	p, err := float64View(&buf.p, o.p)
+	if err != nil {
+		return err
+	}
+	dp, err := float64View(&buf.dp, o.dp)
+	if err != nil {
+		return err
+	}
+
+	newP, err := optim(p, dp)
+	if err != nil {
+		return fmt.Errorf("error computing new parameter: %w", err)
+	} else if newP == nil {
+		return fmt.Errorf("nil parameter after optimization: %v", newP)
+	} else if newP.Rows() != o.p.Rows() || newP.Cols() != o.p.Cols() {
+		return fmt.Errorf("parameter shape changed after optimization: %dx%d != %dx%d",
+			newP.Rows(), newP.Cols(), o.p.Rows(), o.p.Cols())
+	}
+
+	o.p = newP.Float32Into(o.p)
+	return nil
+}
+
+// Parameter return copy of Param32Operation's parameter converted to matrix.Matrix
+func (o *Param32Operation) Parameter() *matrix.Matrix {
+	return o.p.Float64()
+}
+
+func (o *Param32Operation) Precision() nn.Precision {
+	return nn.Float32Params
+}
+
+func (o *Param32Operation) Copy() nn.IModule {
+	if o == nil {
+		return nil
+	}
+	res := &Param32Operation{
+		Operation: o.Operation.Copy().(*Operation),
+		output:    o.output,
+		gradient:  o.gradient,
+		gradParam: o.gradParam,
+	}
+	res.p, res.dp = o.p.Copy(), o.dp.Copy()
+	return res
+}
+
+func (o *Param32Operation) Equal(operation nn.IModule) bool {
+	if o == nil || operation == nil {
+		if (o != nil && operation == nil) || (o == nil && operation != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if op, ok := operation.(*Param32Operation); !ok {
+		return false
+	} else if !o.Operation.Equal(op.Operation) {
+		return false
+	} else if o.p != nil && !o.p.Equal(op.p) {
+		return false
+	} else if o.dp != nil && !o.dp.Equal(op.dp) {
+		return false
+	}
+
+	return true
+}
+
+func (o *Param32Operation) EqualApprox(operation nn.IModule) bool {
+	if o == nil || operation == nil {
+		if (o != nil && operation == nil) || (o == nil && operation != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if op, ok := operation.(*Param32Operation); !ok {
+		return false
+	} else if !o.Operation.EqualApprox(op.Operation) {
+		return false
+	} else if o.p != nil && !o.p.EqualApprox(op.p) {
+		return false
+	} else if o.dp != nil && !o.dp.EqualApprox(op.dp) {
+		return false
+	}
+
+	return true
+}
+
+func (o *Param32Operation) toMap(stringer func(spStringer utils.SPStringer) string) map[string]string {
+	return map[string]string{
+		"operation": stringer(o.Operation),
+		"precision": nn.Float32Params.String(),
+		"p":         stringer(o.p),
+		"dp":        stringer(o.dp),
+	}
+}
+
+func (o *Param32Operation) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(o.toMap(utils.String), utils.BaseFormat)
+}
+
+func (o *Param32Operation) PrettyString() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(o.toMap(utils.PrettyString), utils.PrettyFormat)
+}
+
+func (o *Param32Operation) ShortString() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(o.toMap(utils.ShortString), utils.ShortFormat)
+}
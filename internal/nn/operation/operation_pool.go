@@ -0,0 +1,204 @@
+package operation
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+var _ IOperation = (*PoolOperation)(nil)
+
+// PoolOperation represents pooling of each channel over each window of packed signal (see WindowParameters). Windows
+// are read from signal directly, so positions of padding are known: they are zeros for average pooling and are never
+// picked by max pooling.
+//
+// Output and input gradient are stored in buffers reused the same way as Operation's ones.
+type PoolOperation struct {
+	*Operation
+
+	window WindowParameters
+
+	// argmax holds index of input value picked by max pooling for each output value of the last Forward call, -1 for
+	// window of padding only
+	argmax []int
+}
+
+// newPoolOperation return PoolOperation of given kind (MaxPool or AvgPool) over given window.
+func newPoolOperation(kind nn.Kind, window *WindowParameters) (o IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	logger.Debugf("create new %s operation", kind)
+	if err = window.Check(); err != nil {
+		return nil, err
+	}
+	return &PoolOperation{Operation: &Operation{kind: kind}, window: *window}, nil
+}
+
+func (o *PoolOperation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Forward propagation on %s", o.kind), &err)
+
+	if o == nil {
+		return nil, ErrNil
+	} else if x == nil {
+		return nil, fmt.Errorf("no input provided: %v", x)
+	}
+	w := o.window
+	if x.Cols() != w.Length*w.Channels {
+		return nil, fmt.Errorf("input cols count must match length * channels: %d != %d*%d",
+			x.Cols(), w.Length, w.Channels)
+	}
+
+	o.x = x.CopyInto(o.x)
+	if o.y, err = reuseMatrix(o.y, x.Rows(), w.OutputLength()*w.Channels); err != nil {
+		return nil, err
+	}
+	if o.kind == MaxPool {
+		o.maxPool()
+	} else {
+		o.avgPool()
+	}
+	return o.y, nil
+}
+
+func (o *PoolOperation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Backward propagation on %s", o.kind), &err)
+
+	if o == nil {
+		return nil, ErrNil
+	} else if dy == nil {
+		return nil, fmt.Errorf("no output gradient provided: %v", dy)
+	} else if o.x == nil || o.y == nil {
+		return nil, fmt.Errorf("call Backward() before Forward()")
+	} else if err = o.y.CheckEqualShape(dy); err != nil {
+		return nil, err
+	}
+
+	o.dy = dy.CopyInto(o.dy)
+	if o.dx, err = reuseMatrix(o.dx, o.x.Rows(), o.x.Cols()); err != nil {
+		return nil, err
+	}
+	values := o.dx.Data()
+	for i := range values {
+		values[i] = 0
+	}
+	if o.kind == MaxPool {
+		for i, grad := range o.dy.Data() {
+			if o.argmax[i] >= 0 {
+				values[o.argmax[i]] += grad
+			}
+		}
+	} else {
+		kernel := float64(o.window.Kernel)
+		o.eachWindow(func(out int, in []int) {
+			grad := o.dy.Data()[out] / kernel
+			for _, i := range in {
+				values[i] += grad
+			}
+		})
+	}
+	return o.dx, nil
+}
+
+// maxPool writes maximum of each window to output and index of picked input value to argmax.
+func (o *PoolOperation) maxPool() {
+	x, y := o.x.Data(), o.y.Data()
+	if len(o.argmax) != len(y) {
+		o.argmax = make([]int, len(y))
+	}
+	o.eachWindow(func(out int, in []int) {
+		o.argmax[out], y[out] = -1, 0
+		for _, i := range in {
+			if o.argmax[out] < 0 || x[i] > y[out] {
+				o.argmax[out], y[out] = i, x[i]
+			}
+		}
+	})
+}
+
+// avgPool writes average of each window to output, padding positions count as zeros.
+func (o *PoolOperation) avgPool() {
+	x, y := o.x.Data(), o.y.Data()
+	kernel := float64(o.window.Kernel)
+	o.eachWindow(func(out int, in []int) {
+		y[out] = 0
+		for _, i := range in {
+			y[out] += x[i] / kernel
+		}
+	})
+}
+
+// eachWindow calls f for index of each output value with indexes of input values of its window, padding positions
+// are skipped. Input is read row by row with stride of input cols, given indexes slice is reused between calls.
+func (o *PoolOperation) eachWindow(f func(out int, in []int)) {
+	w := o.window
+	cols, outLength := o.x.Cols(), w.OutputLength()
+	in := make([]int, 0, w.Kernel)
+	for b := 0; b < o.x.Rows(); b++ {
+		for p := 0; p < outLength; p++ {
+			for c := 0; c < w.Channels; c++ {
+				in = in[:0]
+				for k := 0; k < w.Kernel; k++ {
+					if pos := p*w.Stride - w.Padding + k; pos >= 0 && pos < w.Length {
+						in = append(in, b*cols+pos*w.Channels+c)
+					}
+				}
+				f((b*outLength+p)*w.Channels+c, in)
+			}
+		}
+	}
+}
+
+func (o *PoolOperation) Copy() nn.IModule {
+	if o == nil {
+		return nil
+	}
+	return &PoolOperation{
+		Operation: o.Operation.Copy().(*Operation),
+		window:    o.window,
+		argmax:    append([]int(nil), o.argmax...),
+	}
+}
+
+func (o *PoolOperation) Equal(operation nn.IModule) bool {
+	if o == nil || operation == nil {
+		if (o != nil && operation == nil) || (o == nil && operation != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if op, ok := operation.(*PoolOperation); !ok {
+		return false
+	} else {
+		return o.window == op.window && o.Operation.Equal(op.Operation)
+	}
+}
+
+func (o *PoolOperation) EqualApprox(operation nn.IModule) bool {
+	if o == nil || operation == nil {
+		if (o != nil && operation == nil) || (o == nil && operation != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if op, ok := operation.(*PoolOperation); !ok {
+		return false
+	} else {
+		return o.window == op.window && o.Operation.EqualApprox(op.Operation)
+	}
+}
+
+// reuseMatrix return given Matrix if it is sized rows x cols, otherwise new zero Matrix.
+func reuseMatrix(m *matrix.Matrix, rows, cols int) (*matrix.Matrix, error) {
+	if m != nil && m.Rows() == rows && m.Cols() == cols {
+		return m, nil
+	}
+	return matrix.Zeros(rows, cols)
+}
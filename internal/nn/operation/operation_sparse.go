@@ -0,0 +1,220 @@
+package operation
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/internal/utils"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/sparse"
+	"nn/pkg/wraperr"
+)
+
+var _ IParamOperation = (*SparseOperation)(nil)
+
+// SparseOperation represents WeightMultiply with pruned weight stored as sparse.Matrix: output, input gradient and
+// weight gradient are computed by sparse kernels, so their cost is proportional to count of kept weights. Pruned
+// weights stay zero during training: weight gradient is computed at positions of kept weights only.
+type SparseOperation struct {
+	*Operation
+
+	p  *sparse.Matrix
+	dp *sparse.Matrix
+
+	// buffers of kept weights and their gradients passed to Optimizer
+	optimP  *matrix.Matrix
+	optimDp *matrix.Matrix
+}
+
+func (o *SparseOperation) Forward(x *matrix.Matrix) (y *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Forward propagation on %s", o.kind), &err)
+
+	if o == nil {
+		return nil, ErrNil
+	} else if x == nil {
+		return nil, fmt.Errorf("no input provided: %v", x)
+	}
+
+	o.x = x.CopyInto(o.x)
+	y, err = sparse.DenseMatMulInto(o.y, o.x, o.p)
+	if err != nil {
+		return nil, fmt.Errorf("error computing output: %w", err)
+	}
+	o.y = y
+	return y, nil
+}
+
+// Backward return input gradient just as ParamOperation.Backward() does.
+func (o *SparseOperation) Backward(dy *matrix.Matrix) (dx *matrix.Matrix, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during Backward propagation on %s", o.kind), &err)
+
+	if o == nil {
+		return nil, ErrNil
+	} else if dy == nil {
+		return nil, fmt.Errorf("no output gradient provided: %v", dy)
+	} else if o.x == nil || o.y == nil {
+		return nil, fmt.Errorf("call Backward() before Forward()")
+	}
+
+	o.dy = dy.CopyInto(o.dy)
+	if err = o.y.CheckEqualShape(o.dy); err != nil {
+		return nil, err
+	}
+
+	dp, err := o.p.SampledTMatMulInto(o.dp, o.x, o.dy)
+	if err != nil {
+		return nil, fmt.Errorf("error computing paramter gradient: %w", err)
+	}
+	o.dp = dp
+
+	dx, err = sparse.DenseMatMulTInto(o.dx, o.dy, o.p)
+	if err != nil {
+		return nil, fmt.Errorf("error computing input gradient: %w", err)
+	}
+	o.dx = dx
+	return dx, nil
+}
+
+// ApplyOptim applies provided Optimizer to SparseOperation's kept weights. Optimizer gets kept weights and their
+// gradients as 1xN matrices, so it must be element-wise (as all optim optimizers are).
+func (o *SparseOperation) ApplyOptim(optim Optimizer) (err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+	defer wraperr.WrapError(fmt.Errorf("error during apply Optimizer on %s", o.kind), &err)
+
+	if o == nil {
+		return ErrNil
+	} else if optim == nil {
+		return fmt.Errorf("no optimizer provided")
+	} else if o.dp == nil {
+		return fmt.Errorf("can not apply optimizer before gradient computation: %v", o.dp)
+	} else if o.p.NNZ() == 0 {
+		return nil // all weights are pruned
+	}
+
+	o.optimP, err = valuesInto(o.optimP, o.p)
+	if err != nil {
+		return err
+	}
+	o.optimDp, err = valuesInto(o.optimDp, o.dp)
+	if err != nil {
+		return err
+	}
+	newP, err := optim(o.optimP, o.optimDp)
+	if err != nil {
+		return fmt.Errorf("error computing new parameter: %w", err)
+	} else if newP == nil {
+		return fmt.Errorf("nil parameter after optimization: %v", newP)
+	} else if err = o.optimP.CheckEqualShape(newP); err != nil {
+		return err
+	}
+	return o.p.SetValues(newP.Data())
+}
+
+// valuesInto return stored values of sparse.Matrix as 1xN matrix.Matrix written to <dst> if it is sized properly.
+func valuesInto(dst *matrix.Matrix, s *sparse.Matrix) (*matrix.Matrix, error) {
+	if dst != nil && dst.Cols() == s.NNZ() {
+		copy(dst.Data(), s.Data())
+		return dst, nil
+	}
+	return matrix.NewMatrixRawFlat(1, s.NNZ(), s.Data())
+}
+
+// Parameter return dense copy of SparseOperation's weight
+func (o *SparseOperation) Parameter() *matrix.Matrix {
+	return o.p.Dense()
+}
+
+// Sparse return copy of SparseOperation's weight
+func (o *SparseOperation) Sparse() *sparse.Matrix {
+	return o.p.Copy()
+}
+
+func (o *SparseOperation) Precision() nn.Precision {
+	return nn.Float64
+}
+
+func (o *SparseOperation) Copy() nn.IModule {
+	if o == nil {
+		return nil
+	}
+	return &SparseOperation{
+		Operation: o.Operation.Copy().(*Operation),
+		p:         o.p.Copy(),
+		dp:        o.dp.Copy(),
+	}
+}
+
+func (o *SparseOperation) Equal(operation nn.IModule) bool {
+	if o == nil || operation == nil {
+		if (o != nil && operation == nil) || (o == nil && operation != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if op, ok := operation.(*SparseOperation); !ok {
+		return false
+	} else if !o.Operation.Equal(op.Operation) {
+		return false
+	} else if o.p != nil && !o.p.Equal(op.p) {
+		return false
+	} else if o.dp != nil && !o.dp.Equal(op.dp) {
+		return false
+	}
+
+	return true
+}
+
+func (o *SparseOperation) EqualApprox(operation nn.IModule) bool {
+	if o == nil || operation == nil {
+		if (o != nil && operation == nil) || (o == nil && operation != nil) {
+			return false // non-nil != nil and nil != non-nil
+		} else {
+			return true // nil == nil
+		}
+	}
+	if op, ok := operation.(*SparseOperation); !ok {
+		return false
+	} else if !o.Operation.EqualApprox(op.Operation) {
+		return false
+	} else if o.p != nil && !o.p.EqualApprox(op.p) {
+		return false
+	} else if o.dp != nil && !o.dp.EqualApprox(op.dp) {
+		return false
+	}
+
+	return true
+}
+
+func (o *SparseOperation) toMap(stringer func(spStringer utils.SPStringer) string) map[string]string {
+	return map[string]string{
+		"operation": stringer(o.Operation),
+		"p":         stringer(o.p),
+		"dp":        stringer(o.dp),
+	}
+}
+
+func (o *SparseOperation) String() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(o.toMap(utils.String), utils.BaseFormat)
+}
+
+func (o *SparseOperation) PrettyString() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(o.toMap(utils.PrettyString), utils.PrettyFormat)
+}
+
+func (o *SparseOperation) ShortString() string {
+	if o == nil {
+		return "<nil>"
+	}
+	return utils.FormatObject(o.toMap(utils.ShortString), utils.ShortFormat)
+}
@@ -0,0 +1,147 @@
+package operation
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"runtime"
+	"testing"
+)
+
+func TestParam32Operation(t *testing.T) {
+	tests := []struct {
+		testutils.Base
+		kind nn.Kind
+		arg  interface{}
+		in   *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "weight"},
+			kind: WeightMultiply,
+			arg:  testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 3}),
+		},
+		{
+			Base: testutils.Base{Name: "bias"},
+			kind: BiasAdd,
+			arg:  testfactories.NewVector(t, testfactories.VectorParameters{Size: 4}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 4}),
+		},
+	}
+
+	sgd := func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.SubInPlace(grad.MulNumInPlace(0.1))
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			op64 := newOperation(t, test.kind, test.arg)
+			converted, err := ConvertPrecision(op64, nn.Float32Params)
+			require.NoError(t, err)
+			op32, ok := converted.(*Param32Operation)
+			require.True(t, ok)
+			require.Equal(t, nn.Float32Params, op32.Precision())
+			require.True(t, op64.(IParamOperation).Parameter().EqualApprox(op32.Parameter()))
+
+			y64, err := op64.Forward(test.in)
+			require.NoError(t, err)
+			y32, err := op32.Forward(test.in)
+			require.NoError(t, err)
+			require.True(t, y64.EqualApprox(y32), "%s != %s", y64, y32)
+			require.True(t, y32.Equal(op32.Output()))
+
+			dx64, err := op64.Backward(y64)
+			require.NoError(t, err)
+			dx32, err := op32.Backward(y64)
+			require.NoError(t, err)
+			require.True(t, dx64.EqualApprox(dx32), "%s != %s", dx64, dx32)
+
+			require.NoError(t, op64.(IParamOperation).ApplyOptim(sgd))
+			require.NoError(t, op32.ApplyOptim(sgd))
+			require.True(t, op64.(IParamOperation).Parameter().EqualApprox(op32.Parameter()))
+
+			copied := op32.Copy()
+			require.True(t, op32.Equal(copied))
+			require.False(t, op32.Equal(op64))
+
+			back, err := ConvertPrecision(op32, nn.Float64)
+			require.NoError(t, err)
+			require.IsType(t, &ParamOperation{}, back)
+			require.True(t, op32.Parameter().Equal(back.(IParamOperation).Parameter()))
+		})
+	}
+
+	// operations with no float32 variant are copied
+	sigmoid := NewSigmoidActivation()
+	converted, err := ConvertPrecision(sigmoid, nn.Float32Params)
+	require.NoError(t, err)
+	require.True(t, sigmoid.Equal(converted))
+
+	_, err = NewWeight32Operation(nil)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = ConvertPrecision(sigmoid, nn.Precision(5))
+	require.ErrorIs(t, err, ErrExec)
+	dy, err := matrix.Zeros(1, 1)
+	require.NoError(t, err)
+	_, err = (&Param32Operation{Operation: &Operation{kind: WeightMultiply}}).Backward(dy)
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestParam32Operation_Buffers(t *testing.T) {
+	sgd := func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.SubInPlace(grad.MulNumInPlace(0.1))
+	}
+	newWeight32 := func(rows, cols int) IOperation {
+		op, err := NewWeight32Operation(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: rows, Cols: cols}))
+		require.NoError(t, err)
+		return op
+	}
+
+	op := newWeight32(3, 4)
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 3})
+	dy := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 4})
+	y, err := op.Forward(x)
+	require.NoError(t, err)
+	dx, err := op.Backward(dy)
+	require.NoError(t, err)
+
+	// output and input gradient are written to the same buffers on the next calls
+	yNext, err := op.Forward(x)
+	require.NoError(t, err)
+	require.Same(t, y, yNext)
+	dxNext, err := op.Backward(dy)
+	require.NoError(t, err)
+	require.Same(t, dx, dxNext)
+
+	// float64 copies passed to Optimizer are written to shared buffers, so steps allocate much less than parameter
+	// takes in float64
+	rows, cols := 300, 400
+	large := newWeight32(rows, cols).(*Param32Operation)
+	_, err = large.Forward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: rows}))
+	require.NoError(t, err)
+	_, err = large.Backward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: cols}))
+	require.NoError(t, err)
+	require.NoError(t, large.ApplyOptim(sgd))
+
+	const runs = 10
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < runs; i++ {
+		require.NoError(t, large.ApplyOptim(sgd))
+	}
+	runtime.ReadMemStats(&after)
+	require.Less(t, (after.TotalAlloc-before.TotalAlloc)/runs, uint64(rows*cols*8/10))
+}
+
+func TestBuilder_Precision(t *testing.T) {
+	for _, kind := range []nn.Kind{WeightMultiply, BiasAdd} {
+		builder, err := NewBuilder(kind)
+		require.NoError(t, err)
+		op, err := builder.InputsCount(3).NeuronsCount(4).Precision(nn.Float32Params).Build()
+		require.NoError(t, err)
+		require.IsType(t, &Param32Operation{}, op)
+		require.True(t, op.Is(kind))
+	}
+}
@@ -0,0 +1,47 @@
+package operation
+
+import (
+	"fmt"
+	"nn/internal/nn"
+	"nn/pkg/wraperr"
+)
+
+// ConvertPrecision return copy of given operation with parameter stored in given precision. Only WeightMultiply and
+// BiasAdd have nn.Float32Params variants (see Param32Operation), other operations (including SparseOperation and all
+// the activations) have no float32 parameters, so they are just copied. Stored inputs and outputs are not converted,
+// so operation must be called Forward before Backward again.
+//
+// Throws ErrExec error.
+func ConvertPrecision(o IOperation, precision nn.Precision) (res IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if o == nil {
+		return nil, ErrNil
+	} else if precision != nn.Float64 && precision != nn.Float32Params {
+		return nil, fmt.Errorf("unknown precision: %d", precision)
+	}
+
+	paramOp, ok := o.(IParamOperation)
+	if _, isSparse := o.(*SparseOperation); isSparse || !ok || paramOp.Precision() == precision ||
+		!(o.Is(WeightMultiply) || o.Is(BiasAdd)) {
+		return o.Copy().(IOperation), nil
+	}
+
+	logger.Debugf("convert %s to %s precision", o.Kind(), precision)
+	param := paramOp.Parameter()
+	if o.Is(BiasAdd) {
+		bias, err := param.GetRow(0)
+		if err != nil {
+			return nil, err
+		}
+		if precision == nn.Float32Params {
+			return NewBias32Operation(bias)
+		}
+		return NewBiasOperation(bias)
+	}
+	if precision == nn.Float32Params {
+		return NewWeight32Operation(param)
+	}
+	return NewWeightOperation(param)
+}
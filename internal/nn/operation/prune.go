@@ -0,0 +1,34 @@
+package operation
+
+import (
+	"fmt"
+	"nn/pkg/mmath/sparse"
+	"nn/pkg/wraperr"
+)
+
+// Prune return copy of given operation with small weights removed: WeightMultiply keeps weights with absolute value
+// greater than given non-negative threshold only and becomes SparseOperation, so pruned weights cost nothing in
+// Forward and Backward. Already pruned WeightMultiply is pruned again. Other operations are just copied. Stored
+// inputs and outputs are not kept, so operation must be called Forward before Backward again.
+//
+// Throws ErrExec error.
+func Prune(o IOperation, threshold float64) (res IOperation, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if o == nil {
+		return nil, ErrNil
+	}
+
+	paramOp, ok := o.(IParamOperation)
+	if !ok || !o.Is(WeightMultiply) {
+		return o.Copy().(IOperation), nil
+	}
+
+	weight, err := sparse.FromDense(paramOp.Parameter(), threshold)
+	if err != nil {
+		return nil, fmt.Errorf("error pruning weight: %w", err)
+	}
+	logger.Debugf("prune %s: %d of %d weights kept", o.Kind(), weight.NNZ(), weight.Rows()*weight.Cols())
+	return NewSparseWeightOperation(weight)
+}
@@ -0,0 +1,171 @@
+package operation
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+func TestRBFOperations_Forward(t *testing.T) {
+	tests := []struct {
+		testutils.Base
+		op       IOperation
+		in       *matrix.Matrix
+		expected *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "squared distance"},
+			op: newOperation(t, SquaredDistance, testfactories.NewMatrix(t, testfactories.MatrixParameters{
+				Rows: 2, Cols: 2, Values: []float64{0, 0, 1, 2},
+			})),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{
+				1, 1,
+				3, 0,
+			}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{
+				2, 1,
+				9, 8,
+			}}),
+		},
+		{
+			Base: testutils.Base{Name: "width scale"},
+			op: newOperation(t, WidthScale, testfactories.NewVector(t, testfactories.VectorParameters{
+				Size: 2, Values: []float64{2, 0.5},
+			})),
+			in:       testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{8, 1}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{2, 4}}),
+		},
+		{
+			Base:     testutils.Base{Name: "multiquadric"},
+			op:       newOperation(t, MultiquadricActivation),
+			in:       testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{0, 3}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{1, 2}}),
+		},
+		{
+			Base:     testutils.Base{Name: "inverse multiquadric"},
+			op:       newOperation(t, InverseMultiquadricActivation),
+			in:       testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{0, 3}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{1, 0.5}}),
+		},
+		{
+			Base: testutils.Base{Name: "squared distance wrong input", Err: ErrExec},
+			op: newOperation(t, SquaredDistance, testfactories.NewMatrix(t, testfactories.MatrixParameters{
+				Rows: 2, Cols: 2,
+			})),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			out, err := test.op.Forward(test.in)
+			if test.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, test.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, out.EqualApprox(test.expected), "wrong output: %s", out.String())
+		})
+	}
+}
+
+func TestRBFOperations_Backward(t *testing.T) {
+	const (
+		eps       = 1e-6
+		tolerance = 1e-5
+	)
+	centers := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 2})
+	widths := testfactories.NewVector(t, testfactories.VectorParameters{Size: 3, Values: []float64{0.5, 1, 2}})
+
+	tests := []struct {
+		testutils.Base
+		kind nn.Kind
+		args []interface{}
+		// withParam return args for operation with given parameter
+		withParam func(p *matrix.Matrix) []interface{}
+	}{
+		{
+			Base: testutils.Base{Name: "squared distance"},
+			kind: SquaredDistance,
+			args: []interface{}{centers},
+			withParam: func(p *matrix.Matrix) []interface{} {
+				return []interface{}{p}
+			},
+		},
+		{
+			Base: testutils.Base{Name: "width scale"},
+			kind: WidthScale,
+			args: []interface{}{widths},
+			withParam: func(p *matrix.Matrix) []interface{} {
+				row, err := p.GetRow(0)
+				require.NoError(t, err)
+				return []interface{}{row}
+			},
+		},
+		{Base: testutils.Base{Name: "gaussian"}, kind: GaussianActivation},
+		{Base: testutils.Base{Name: "multiquadric"}, kind: MultiquadricActivation},
+		{Base: testutils.Base{Name: "inverse multiquadric"}, kind: InverseMultiquadricActivation},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			op := newOperation(t, test.kind, test.args...)
+			cols := 3
+			if test.kind == SquaredDistance {
+				cols = 2
+			}
+			x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: cols})
+			r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 3})
+
+			testutils.LinearLoss(t, op.Forward, x, r)
+			dx, err := op.Backward(r)
+			require.NoError(t, err)
+
+			for i := range x.RawFlat() {
+				numeric := testutils.NumericGradient(t, func(x *matrix.Matrix) float64 {
+					return testutils.LinearLoss(t, op.Forward, x, r)
+				}, x, i, eps)
+				require.InDelta(t, numeric, dx.RawFlat()[i], tolerance, "input gradient %d", i)
+			}
+
+			paramOp, ok := op.(*ParamOperation)
+			if !ok {
+				return
+			}
+			p := paramOp.Parameter()
+			for i := range p.RawFlat() {
+				numeric := testutils.NumericGradient(t, func(p *matrix.Matrix) float64 {
+					return testutils.LinearLoss(t, newOperation(t, test.kind, test.withParam(p)...).Forward, x, r)
+				}, p, i, eps)
+				require.InDelta(t, numeric, paramOp.dp.RawFlat()[i], tolerance, "parameter gradient %d", i)
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildRBF(t *testing.T) {
+	b, err := NewBuilder(SquaredDistance)
+	require.NoError(t, err)
+	op, err := b.InputsCount(2).NeuronsCount(5).Build()
+	require.NoError(t, err)
+	require.Equal(t, 5, op.(*ParamOperation).Parameter().Rows())
+	require.Equal(t, 2, op.(*ParamOperation).Parameter().Cols())
+
+	b, err = NewBuilder(WidthScale)
+	require.NoError(t, err)
+	op, err = b.NeuronsCount(5).Build()
+	require.NoError(t, err)
+	ones, err := vector.NewVectorOf(1, 5)
+	require.NoError(t, err)
+	onesMat, err := matrix.NewMatrix([]*vector.Vector{ones})
+	require.NoError(t, err)
+	require.True(t, op.(*ParamOperation).Parameter().Equal(onesMat))
+
+	_, err = NewWidthScale(testfactories.NewVector(t, testfactories.VectorParameters{Size: 2, Values: []float64{1, 0}}))
+	require.ErrorIs(t, err, ErrCreate)
+}
@@ -0,0 +1,64 @@
+package operation
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+func TestSoftmax_Forward(t *testing.T) {
+	op := newOperation(t, SoftmaxActivation)
+	require.True(t, op.IsActivation())
+
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 3, Values: []float64{
+		1, 2, 3,
+		1000, 1000, math.Inf(-1),
+	}})
+	y, err := op.Forward(x)
+	require.NoError(t, err)
+
+	sum := math.Exp(1) + math.Exp(2) + math.Exp(3)
+	require.InDeltaSlice(t, []float64{
+		math.Exp(1) / sum, math.Exp(2) / sum, math.Exp(3) / sum,
+		0.5, 0.5, 0,
+	}, y.RawFlat(), 1e-12)
+}
+
+func TestSoftmax_Backward(t *testing.T) {
+	const (
+		eps       = 1e-6
+		tolerance = 1e-6
+	)
+	op := newOperation(t, SoftmaxActivation)
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4})
+	r := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 4})
+
+	// loss return sum(y * r), so r is output gradient of such loss
+	loss := func(x *matrix.Matrix) float64 {
+		y, err := op.Forward(x)
+		require.NoError(t, err)
+		prod, err := y.Mul(r)
+		require.NoError(t, err)
+		return prod.Sum()
+	}
+
+	loss(x)
+	dx, err := op.Backward(r)
+	require.NoError(t, err)
+
+	values := x.RawFlat()
+	for i := range values {
+		plus := append([]float64(nil), values...)
+		plus[i] += eps
+		minus := append([]float64(nil), values...)
+		minus[i] -= eps
+		xPlus, err := matrix.NewMatrixRawFlat(x.Rows(), x.Cols(), plus)
+		require.NoError(t, err)
+		xMinus, err := matrix.NewMatrixRawFlat(x.Rows(), x.Cols(), minus)
+		require.NoError(t, err)
+		numeric := (loss(xPlus) - loss(xMinus)) / (2 * eps)
+		require.InDelta(t, numeric, dx.RawFlat()[i], tolerance, "input gradient %d", i)
+	}
+}
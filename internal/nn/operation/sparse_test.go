@@ -0,0 +1,91 @@
+package operation
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/sparse"
+	"testing"
+)
+
+func TestSparseOperation(t *testing.T) {
+	weight := testfactories.NewMatrix(t, testfactories.MatrixParameters{
+		Rows: 3, Cols: 4, Values: []float64{0.1, -2, 0, 1.5, 0.3, 0, 1, -0.2, -1, 0.05, 0.7, 3}})
+	in := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 3})
+	dy := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 5, Cols: 4})
+	sgd := func(param, grad *matrix.Matrix) (*matrix.Matrix, error) {
+		return param.SubInPlace(grad.MulNumInPlace(0.1))
+	}
+
+	op := newOperation(t, WeightMultiply, weight)
+	pruned, err := Prune(op, 0.5)
+	require.NoError(t, err)
+	sparseOp, ok := pruned.(*SparseOperation)
+	require.True(t, ok)
+	require.Equal(t, 6, sparseOp.Sparse().NNZ())
+	require.Equal(t, nn.Float64, sparseOp.Precision())
+
+	// pruned operation computes the same as dense one with zeroed small weights
+	masked := newOperation(t, WeightMultiply, sparseOp.Parameter())
+	y, err := masked.Forward(in)
+	require.NoError(t, err)
+	ySparse, err := sparseOp.Forward(in)
+	require.NoError(t, err)
+	require.True(t, y.EqualApprox(ySparse), "%s != %s", y, ySparse)
+
+	dx, err := masked.Backward(dy)
+	require.NoError(t, err)
+	dxSparse, err := sparseOp.Backward(dy)
+	require.NoError(t, err)
+	require.True(t, dx.EqualApprox(dxSparse), "%s != %s", dx, dxSparse)
+
+	// pruned weights stay zero after optimization, kept ones are changed as dense ones
+	kept := sparseOp.Sparse()
+	require.NoError(t, masked.(IParamOperation).ApplyOptim(sgd))
+	require.NoError(t, sparseOp.ApplyOptim(sgd))
+	require.NoError(t, kept.CheckPattern(sparseOp.Sparse()))
+	require.False(t, kept.Equal(sparseOp.Sparse()))
+	for _, triplet := range sparseOp.Sparse().Triplets() {
+		value, err := masked.(IParamOperation).Parameter().Get(triplet.Row, triplet.Col)
+		require.NoError(t, err)
+		require.InDelta(t, value, triplet.Value, 1e-12)
+	}
+
+	copied := sparseOp.Copy()
+	require.True(t, sparseOp.Equal(copied))
+	require.True(t, sparseOp.EqualApprox(copied))
+	require.False(t, sparseOp.Equal(op))
+
+	// operations without weight are copied, float32 conversion keeps sparse operation
+	sigmoid := NewSigmoidActivation()
+	prunedSigmoid, err := Prune(sigmoid, 0.5)
+	require.NoError(t, err)
+	require.True(t, sigmoid.Equal(prunedSigmoid))
+	converted, err := ConvertPrecision(sparseOp, nn.Float32Params)
+	require.NoError(t, err)
+	require.IsType(t, &SparseOperation{}, converted)
+
+	_, err = Prune(op, -1)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = Prune(nil, 0)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = NewSparseWeightOperation(nil)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = (&SparseOperation{Operation: &Operation{kind: WeightMultiply}}).Backward(dy)
+	require.ErrorIs(t, err, ErrExec)
+	require.ErrorIs(t, (&SparseOperation{Operation: &Operation{kind: WeightMultiply}}).ApplyOptim(sgd), ErrExec)
+}
+
+func TestCreate_SparseWeight(t *testing.T) {
+	weight, err := sparse.NewMatrix(2, 3, []sparse.Triplet{{Row: 0, Col: 2, Value: 1}, {Row: 1, Col: 0, Value: 2}})
+	require.NoError(t, err)
+	op, err := Create(WeightMultiply, weight)
+	require.NoError(t, err)
+	require.IsType(t, &SparseOperation{}, op)
+	require.True(t, op.Is(WeightMultiply))
+
+	y, err := op.Forward(testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{3, 4}}))
+	require.NoError(t, err)
+	require.Equal(t, []float64{8, 0, 3}, y.RawFlat())
+}
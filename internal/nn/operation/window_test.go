@@ -0,0 +1,211 @@
+package operation
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/internal/nn"
+	"nn/internal/testutils"
+	"nn/internal/testutils/testfactories"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+func TestNewIm2Col(t *testing.T) {
+	tests := []struct {
+		testutils.Base
+		window *WindowParameters
+	}{
+		{
+			Base:   testutils.Base{Name: "valid window"},
+			window: &WindowParameters{Length: 8, Channels: 2, Kernel: 3, Stride: 1, Padding: 1},
+		},
+		{
+			Base: testutils.Base{Name: "no window", Err: ErrCreate},
+		},
+		{
+			Base:   testutils.Base{Name: "zero stride", Err: ErrCreate},
+			window: &WindowParameters{Length: 8, Channels: 2, Kernel: 3},
+		},
+		{
+			Base:   testutils.Base{Name: "kernel exceeds padded length", Err: ErrCreate},
+			window: &WindowParameters{Length: 2, Channels: 1, Kernel: 5, Stride: 1, Padding: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewIm2Col(test.window)
+			if test.Err == nil {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.ErrorIs(t, err, test.Err)
+			}
+		})
+	}
+}
+
+func TestWindowOperations(t *testing.T) {
+	tests := []struct {
+		testutils.Base
+		op           IOperation
+		in           *matrix.Matrix
+		expected     *matrix.Matrix
+		outGrad      *matrix.Matrix
+		expectedGrad *matrix.Matrix
+	}{
+		{
+			Base: testutils.Base{Name: "im2col with padding"},
+			op:   newOperation(t, Im2Col, &WindowParameters{Length: 3, Channels: 1, Kernel: 2, Stride: 1, Padding: 1}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{1, 2, 3}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 2, Values: []float64{
+				0, 1,
+				1, 2,
+				2, 3,
+				3, 0,
+			}}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 4, Cols: 2, Values: []float64{
+				1, 2,
+				3, 4,
+				5, 6,
+				7, 8,
+			}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{5, 9, 13}}),
+		},
+		{
+			Base: testutils.Base{Name: "im2col with stride and channels"},
+			op:   newOperation(t, Im2Col, &WindowParameters{Length: 3, Channels: 2, Kernel: 2, Stride: 2}),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 6, Values: []float64{
+				1, 2, 3, 4, 5, 6,
+				7, 8, 9, 10, 11, 12,
+			}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 4, Values: []float64{
+				1, 2, 3, 4,
+				7, 8, 9, 10,
+			}}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 4, Values: []float64{
+				1, 2, 3, 4,
+				5, 6, 7, 8,
+			}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 6, Values: []float64{
+				1, 2, 3, 4, 0, 0,
+				5, 6, 7, 8, 0, 0,
+			}}),
+		},
+		{
+			Base:         testutils.Base{Name: "reshape"},
+			op:           newOperation(t, Reshape, 2, 4),
+			in:           testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{1, 2, 3, 4}}),
+			expected:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{1, 2, 3, 4}}),
+			outGrad:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{5, 6, 7, 8}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 2, Cols: 2, Values: []float64{5, 6, 7, 8}}),
+		},
+		{
+			Base:         testutils.Base{Name: "max pool"},
+			op:           newOperation(t, MaxPool, &WindowParameters{Length: 2, Channels: 2, Kernel: 2, Stride: 1}),
+			in:           testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{1, 5, 3, 4}}),
+			expected:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{3, 5}}),
+			outGrad:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{10, 20}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{0, 20, 10, 0}}),
+		},
+		{
+			Base:         testutils.Base{Name: "avg pool"},
+			op:           newOperation(t, AvgPool, &WindowParameters{Length: 2, Channels: 2, Kernel: 2, Stride: 1}),
+			in:           testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{1, 5, 3, 4}}),
+			expected:     testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{2, 4.5}}),
+			outGrad:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{10, 20}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 4, Values: []float64{5, 10, 5, 10}}),
+		},
+		{
+			// padding is never picked: maximum of negative values is taken from signal only
+			Base: testutils.Base{Name: "max pool with padding"},
+			op:   newOperation(t, MaxPool, &WindowParameters{Length: 3, Channels: 2, Kernel: 2, Stride: 1, Padding: 1}),
+			in: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 6, Values: []float64{
+				-1, -6, -3, -4, -5, -2,
+			}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 8, Values: []float64{
+				-1, -6, -1, -4, -3, -2, -5, -2,
+			}}),
+			outGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 8, Values: []float64{
+				1, 2, 3, 4, 5, 6, 7, 8,
+			}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 6, Values: []float64{
+				4, 2, 5, 4, 7, 14,
+			}}),
+		},
+		{
+			Base: testutils.Base{Name: "avg pool with padding"},
+			op:   newOperation(t, AvgPool, &WindowParameters{Length: 3, Channels: 1, Kernel: 2, Stride: 2, Padding: 1}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{-2, -4, -6}}),
+			expected: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{
+				-1, -5,
+			}}),
+			outGrad:      testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 2, Values: []float64{2, 4}}),
+			expectedGrad: testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3, Values: []float64{1, 2, 2}}),
+		},
+		{
+			Base: testutils.Base{Name: "im2col wrong input", Err: ErrExec},
+			op:   newOperation(t, Im2Col, &WindowParameters{Length: 3, Channels: 2, Kernel: 2, Stride: 2}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3}),
+		},
+		{
+			Base: testutils.Base{Name: "max pool wrong input", Err: ErrExec},
+			op:   newOperation(t, MaxPool, &WindowParameters{Length: 2, Channels: 2, Kernel: 2, Stride: 1}),
+			in:   testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 1, Cols: 3}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			out, err := test.op.Forward(test.in)
+			if test.Err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, test.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, out.Equal(test.expected))
+
+			grad, err := test.op.Backward(test.outGrad)
+			require.NoError(t, err)
+			require.True(t, grad.Equal(test.expectedGrad))
+		})
+	}
+}
+
+func TestPoolOperation_Buffers(t *testing.T) {
+	window := &WindowParameters{Length: 4, Channels: 2, Kernel: 2, Stride: 2, Padding: 1}
+	x := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 8})
+	dy := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 3, Cols: 6})
+	for _, kind := range []nn.Kind{MaxPool, AvgPool} {
+		t.Run(string(kind), func(t *testing.T) {
+			op := newOperation(t, kind, window)
+			y, err := op.Forward(x)
+			require.NoError(t, err)
+			dx, err := op.Backward(dy)
+			require.NoError(t, err)
+			c := op.Copy()
+			require.True(t, op.Equal(c))
+
+			// output and input gradient are written to the same buffers on the next calls
+			yNext, err := op.Forward(x)
+			require.NoError(t, err)
+			require.Same(t, y, yNext)
+			dxNext, err := op.Backward(dy)
+			require.NoError(t, err)
+			require.Same(t, dx, dxNext)
+			require.True(t, op.Equal(c))
+
+			// steps allocate no buffers, so allocations don't depend on batch size
+			allocs := func(x, dy *matrix.Matrix) float64 {
+				op := newOperation(t, kind, window)
+				return testing.AllocsPerRun(10, func() {
+					_, _ = op.Forward(x)
+					_, _ = op.Backward(dy)
+				})
+			}
+			large := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 300, Cols: 8})
+			largeDy := testfactories.NewMatrix(t, testfactories.MatrixParameters{Rows: 300, Cols: 6})
+			require.Equal(t, allocs(x, dy), allocs(large, largeDy))
+		})
+	}
+}
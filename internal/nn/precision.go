@@ -0,0 +1,24 @@
+package nn
+
+// Precision is floating point type parameters of neural network are stored in.
+type Precision uint8
+
+const (
+	// Float64 precision stores parameters as matrix.Matrix, it is used by default
+	Float64 Precision = iota
+	// Float32Params precision stores parameters and their gradients of weight multiply and bias add operations as
+	// matrix.Matrix32, halving their memory. It is not a float32 mode of the whole network: inputs, outputs and
+	// gradients of all operations stay matrix.Matrix and all the computations are done in Float64 precision, other
+	// operations and layers keep their parameters as matrix.Matrix.
+	Float32Params
+)
+
+func (p Precision) String() string {
+	switch p {
+	case Float64:
+		return "float64"
+	case Float32Params:
+		return "float32 params"
+	}
+	return "unknown precision"
+}
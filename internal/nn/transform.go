@@ -0,0 +1,18 @@
+package nn
+
+import "nn/pkg/mmath/matrix"
+
+// Transform maps values between source scale and scale network is trained on, e.g. scaler.DataScaler. Network
+// transforms inputs before the first layer and targets before Loss, outputs of the last layer are restored by
+// InverseOutputs. Transform must not be changed after it is attached, copies of network share it.
+type Transform interface {
+	TransformInputs(x *matrix.Matrix) (*matrix.Matrix, error)
+	TransformOutputs(t *matrix.Matrix) (*matrix.Matrix, error)
+	InverseOutputs(y *matrix.Matrix) (*matrix.Matrix, error)
+
+	// Equal return true if this and argument are deep-equal
+	Equal(transform Transform) bool
+
+	// EqualApprox same as Equal, but it compares floats using some epsilon
+	EqualApprox(transform Transform) bool
+}
@@ -77,7 +77,11 @@ func NewSGD(parameters *SGDParameters) (operation.Optimizer, PostOptimizeFunc) {
 			defer logger.CatchErr(&err)
 			defer wraperr.WrapError(ErrExec, &err)
 
-			return param.Sub(grad.MulNum(learnRate))
+			// gradient must not be broadcast to parameter
+			if err = param.CheckEqualShape(grad); err != nil {
+				return nil, err
+			}
+			return param.SubInPlace(grad.MulNumInPlace(learnRate))
 		}, func() {
 			decrement(&learnRate)
 		}
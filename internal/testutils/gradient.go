@@ -0,0 +1,30 @@
+package testutils
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+// LinearLoss return sum(y * r), where y is output of forward on x, so r is output gradient of such loss. Used to
+// check gradients computed by Backward against NumericGradient.
+func LinearLoss(t *testing.T, forward func(x *matrix.Matrix) (*matrix.Matrix, error), x, r *matrix.Matrix) float64 {
+	y, err := forward(x)
+	require.NoError(t, err)
+	prod, err := y.Mul(r)
+	require.NoError(t, err)
+	return prod.Sum()
+}
+
+// NumericGradient return central difference estimate of derivative of f by i'th value of m (values are counted row
+// by row): (f(m + eps) - f(m - eps)) / 2eps.
+func NumericGradient(t *testing.T, f func(m *matrix.Matrix) float64, m *matrix.Matrix, i int, eps float64) float64 {
+	shift := func(delta float64) *matrix.Matrix {
+		values := m.RawFlat()
+		values[i] += delta
+		shifted, err := matrix.NewMatrixRawFlat(m.Rows(), m.Cols(), values)
+		require.NoError(t, err)
+		return shifted
+	}
+	return (f(shift(eps)) - f(shift(-eps))) / (2 * eps)
+}
@@ -0,0 +1,238 @@
+package train
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"math/rand"
+	"nn/internal/data/approx/estimate"
+	"nn/internal/data/dataset"
+	"nn/internal/nn/net"
+	"nn/internal/nn/operation"
+	"nn/internal/optim"
+	"nn/internal/utils"
+	"nn/pkg/mylog"
+	"nn/pkg/percent"
+	"nn/pkg/wraperr"
+)
+
+// CrossValidationParameters describe K-fold cross-validation: Data is shuffled and split in FoldsCount folds, each
+// fold is used once as valid Data for network trained on the other folds. TestsPercent of rows of the other folds are
+// held out as tests Data, which picks the best network if SaveBest, so valid Data never affects the train. Whole
+// procedure is repeated RepeatsCount times with different shuffles (repeated K-fold). Network, Dataset and Optimizer
+// of SingleParameters are ignored, fresh ones are taken from providers for each fold, sources of SingleParameters
+// must not be provided.
+type CrossValidationParameters struct {
+	SingleParameters
+
+	Data         *dataset.Data
+	FoldsCount   int
+	RepeatsCount int
+	TestsPercent percent.Percent // DefaultCVTestsPercent is used if zero
+	Rand         *rand.Rand      // shuffles Data before each repeat, utils.Rand is used by default
+
+	NetProvider       func() (net.INetwork, error)
+	OptimizerProvider func() (operation.Optimizer, optim.PostOptimizeFunc, error)
+}
+
+// Summary holds mean and population standard deviation of some value over folds
+type Summary struct {
+	Mean float64
+	Std  float64
+}
+
+// DefaultCVTestsPercent is share of training folds rows held out as tests Data by default
+const DefaultCVTestsPercent = percent.Percent20
+
+// FoldResult is result of training on all the folds except Fold'th one in Repeat'th repeat. Evaluated holds network
+// returned by the run (the best one if SaveBest, the last one otherwise) with its loss and outputs on valid Data,
+// Estimate is computed from these outputs.
+type FoldResult struct {
+	*SingleResult
+	Evaluated MainSingleResult
+	Estimate  *estimate.Result
+
+	Repeat int
+	Fold   int
+}
+
+// CrossValidationResults holds results of every fold and summaries of valid loss and estimate.Result metrics over them
+// (see FoldResult.Evaluated)
+type CrossValidationResults struct {
+	TrainId
+
+	Folds []*FoldResult
+
+	Loss             Summary
+	MaxAbsoluteError Summary
+	AvgAbsoluteError Summary
+	MaxRelativeError Summary
+}
+
+func checkCrossValidationParameters(p *CrossValidationParameters) (err error) {
+	defer wraperr.WrapError(ErrParameters, &err)
+
+	if p == nil {
+		return fmt.Errorf("no parameters provided")
+	} else if p.NetProvider == nil {
+		return fmt.Errorf("no network provider")
+	} else if p.OptimizerProvider == nil {
+		return fmt.Errorf("no optimizer provider")
+	} else if p.Data == nil {
+		return fmt.Errorf("no data provided")
+	} else if p.Source != nil || p.TestsSource != nil || p.ValidSource != nil {
+		return fmt.Errorf("sources are not supported, folds are taken from data")
+	} else if p.FoldsCount < 2 || p.FoldsCount > p.Data.X.Rows() {
+		return fmt.Errorf("invalid folds count provided for %d rows: %d", p.Data.X.Rows(), p.FoldsCount)
+	} else if p.RepeatsCount < 1 {
+		return fmt.Errorf("invalid repeats count provided: %d", p.RepeatsCount)
+	} else if p.TestsPercent >= percent.Percent100 {
+		return fmt.Errorf("invalid tests percent provided: %s", p.TestsPercent.ShortString())
+	} else if p.EpochsCount < 1 {
+		return fmt.Errorf("invalid epochs count provided: %d", p.EpochsCount)
+	} else if p.TestEpochPicker == nil {
+		return fmt.Errorf("no test epoch picker provided")
+	}
+
+	return nil
+}
+
+func preFoldTrain(parameters *CrossValidationParameters, ds *dataset.Dataset) (sp *SingleParameters, err error) {
+	defer wraperr.WrapError(ErrPreTrain, &err)
+
+	if n, err := parameters.NetProvider(); err != nil {
+		return nil, err
+	} else if o, f, err := parameters.OptimizerProvider(); err != nil {
+		return nil, err
+	} else {
+		return &SingleParameters{
+			TrainId: TrainId{
+				Id:       uuid.New(),
+				ParentId: parameters.Id,
+			},
+			EpochsCount:      parameters.EpochsCount,
+			Network:          n,
+			Dataset:          ds,
+			Optimizer:        o,
+			PostOptimizeFunc: f,
+			BatchSize:        parameters.BatchSize,
+			TestEpochPicker:  parameters.TestEpochPicker,
+			SaveBest:         parameters.SaveBest,
+			SaveStats:        parameters.SaveStats,
+		}, nil
+	}
+}
+
+// CrossValidate trains network on K-fold splits of Data (see CrossValidationParameters) and return results of every
+// fold with mean and standard deviation of valid loss and estimate.Result metrics over all the folds.
+//
+// Throws ErrExec error.
+func CrossValidate(parameters *CrossValidationParameters) (r *CrossValidationResults, err error) {
+	defer logger.CatchErr(&err)
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if err = checkCrossValidationParameters(parameters); err != nil {
+		return nil, fmt.Errorf("error checking parameters for cross validation: %w", err)
+	}
+
+	rng := parameters.Rand
+	if rng == nil {
+		rng = utils.Rand
+	}
+	logger.Infof("start %d times repeated %d-fold cross validation [%s] on data [%s]", parameters.RepeatsCount,
+		parameters.FoldsCount, parameters.Id.String(), parameters.Data.ShortString())
+
+	r = &CrossValidationResults{
+		TrainId: parameters.TrainId,
+		Folds:   make([]*FoldResult, 0, parameters.RepeatsCount*parameters.FoldsCount),
+	}
+	for repeat := 0; repeat < parameters.RepeatsCount; repeat++ {
+		shuffled, _ := parameters.Data.ShuffleRand(rng)
+		folds, err := shuffled.Folds(parameters.FoldsCount)
+		if err != nil {
+			return nil, fmt.Errorf("error splitting data in folds on [%d] repeat: %w", repeat, err)
+		}
+
+		for fold, valid := range folds {
+			result, err := trainFold(parameters, folds, fold)
+			if err != nil {
+				return nil, fmt.Errorf("error running [%d] fold on [%d] repeat: %w", fold, repeat, err)
+			}
+			result.Repeat = repeat
+			logger.Debugf("fold [%d/%d] of repeat [%d] with valid data [%s], loss: %e",
+				fold, parameters.FoldsCount, repeat, valid.ShortString(), result.Evaluated.Loss)
+			r.Folds = append(r.Folds, result)
+		}
+	}
+
+	r.summarize()
+	logger.Infof("done cross validation [%s], loss: %e ± %e", parameters.Id.String(), r.Loss.Mean, r.Loss.Std)
+	return r, nil
+}
+
+func trainFold(parameters *CrossValidationParameters, folds []*dataset.Data, fold int) (*FoldResult, error) {
+	train, err := dataset.MergeFolds(folds, fold)
+	if err != nil {
+		return nil, err
+	}
+	testsPercent := parameters.TestsPercent
+	if testsPercent == percent.Percent0 {
+		testsPercent = DefaultCVTestsPercent
+	}
+	testsSize := testsPercent.GetI(train.X.Rows())
+	if testsSize < 1 {
+		testsSize = 1
+	}
+	train, tests, err := train.Split(train.X.Rows() - testsSize)
+	if err != nil {
+		return nil, fmt.Errorf("error holding out tests data: %w", err)
+	}
+	ds, err := dataset.NewDataset(train, tests, folds[fold])
+	if err != nil {
+		return nil, err
+	}
+	sp, err := preFoldTrain(parameters, ds)
+	if err != nil {
+		return nil, err
+	}
+	result, err := SingleTrain(sp)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluated := result.MainSingleResult
+	if result.BestSingleResult != nil && result.BestSingleResult.Network != nil {
+		evaluated.Network = result.BestSingleResult.Network
+		if evaluated.Loss, evaluated.Forward, err = calcAndPrintLoss(evaluated.Network, ds.Valid, mylog.Debug,
+			"loss of the best network on valid data"); err != nil {
+			return nil, err
+		}
+	}
+	e, err := estimate.Estimate(evaluated.Forward, ds.Valid.Y)
+	if err != nil {
+		return nil, err
+	}
+	return &FoldResult{SingleResult: result, Evaluated: evaluated, Estimate: e, Fold: fold}, nil
+}
+
+func (r *CrossValidationResults) summarize() {
+	for _, metric := range []struct {
+		summary *Summary
+		value   func(f *FoldResult) float64
+	}{
+		{&r.Loss, func(f *FoldResult) float64 { return f.Evaluated.Loss }},
+		{&r.MaxAbsoluteError, func(f *FoldResult) float64 { return f.Estimate.MaxAbsoluteError }},
+		{&r.AvgAbsoluteError, func(f *FoldResult) float64 { return f.Estimate.AvgAbsoluteError }},
+		{&r.MaxRelativeError, func(f *FoldResult) float64 { return f.Estimate.MaxRelativeError }},
+	} {
+		for _, f := range r.Folds {
+			metric.summary.Mean += metric.value(f)
+		}
+		metric.summary.Mean /= float64(len(r.Folds))
+		for _, f := range r.Folds {
+			delta := metric.value(f) - metric.summary.Mean
+			metric.summary.Std += delta * delta
+		}
+		metric.summary.Std = math.Sqrt(metric.summary.Std / float64(len(r.Folds)))
+	}
+}
@@ -0,0 +1,115 @@
+package train
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"nn/internal/data/approx/estimate"
+	"nn/internal/data/dataset"
+	"nn/internal/nn/layer"
+	"nn/internal/nn/loss"
+	"nn/internal/nn/net"
+	"nn/internal/nn/operation"
+	"nn/internal/optim"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mylog"
+	"nn/pkg/percent"
+	"testing"
+)
+
+func TestCrossValidate(t *testing.T) {
+	nb, err := net.NewBuilder(net.FFNetwork)
+	require.NoError(t, err)
+	nb = nb.
+		SetResetAfterBuild(true).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(1).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		LossKind(loss.MSELoss)
+
+	rows := 20
+	values := make([]float64, rows)
+	for i := range values {
+		values[i] = float64(i) / float64(rows)
+	}
+	x, err := matrix.NewMatrixRawFlat(rows, 1, values)
+	require.NoError(t, err)
+	data, err := dataset.NewData(x, x.MulNum(2).AddNum(1))
+	require.NoError(t, err)
+
+	epochs := 200
+	p := &CrossValidationParameters{
+		SingleParameters: SingleParameters{
+			TrainId:         TrainId{Id: uuid.New()},
+			EpochsCount:     epochs,
+			TestEpochPicker: func(epoch, epochs int) bool { return false },
+		},
+		Data:         data,
+		FoldsCount:   4,
+		RepeatsCount: 2,
+		Rand:         rand.New(rand.NewSource(1)),
+		NetProvider: func() (net.INetwork, error) {
+			return nb.Build()
+		},
+		OptimizerProvider: func() (operation.Optimizer, optim.PostOptimizeFunc, error) {
+			sgd, f := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.5, StopLearnRate: 0.01, EpochsCount: epochs})
+			return sgd, f, nil
+		},
+	}
+	results, err := CrossValidate(p)
+	require.NoError(t, err)
+
+	require.Len(t, results.Folds, 8)
+	sum, validRows := 0.0, 0
+	for i, fold := range results.Folds {
+		require.Equal(t, i/4, fold.Repeat)
+		require.Equal(t, i%4, fold.Fold)
+		require.Equal(t, rows, fold.Dataset.Train.X.Rows()+fold.Dataset.Tests.X.Rows()+fold.Dataset.Valid.X.Rows())
+		require.Equal(t, 3, fold.Dataset.Tests.X.Rows()) // 20% of training folds
+		require.Equal(t, fold.Loss, fold.Evaluated.Loss) // the last network is returned without SaveBest
+		require.Equal(t, p.Id, fold.ParentId)
+		require.NotNil(t, fold.Estimate)
+		sum += fold.Evaluated.Loss
+		validRows += fold.Dataset.Valid.X.Rows()
+	}
+	require.Equal(t, 2*rows, validRows) // each row is validated once per repeat
+	require.InDelta(t, sum/8, results.Loss.Mean, 1e-12)
+	require.GreaterOrEqual(t, results.Loss.Std, 0.0)
+	require.Less(t, results.AvgAbsoluteError.Mean, 0.1) // linear target is learned by linear network
+	require.False(t, math.IsNaN(results.MaxRelativeError.Std))
+
+	// repeats use different shuffles
+	require.False(t, results.Folds[0].Dataset.Valid.Equal(results.Folds[4].Dataset.Valid))
+
+	// metrics are computed from the best network picked by tests data
+	p.SaveBest = true
+	p.TestEpochPicker = func(epoch, epochs int) bool { return epoch%(epochs/10) == 0 }
+	results, err = CrossValidate(p)
+	require.NoError(t, err)
+	for _, fold := range results.Folds {
+		require.True(t, fold.Evaluated.Network.Equal(fold.BestSingleResult.Network))
+		loss, forward, err := calcAndPrintLoss(fold.BestSingleResult.Network, fold.Dataset.Valid, mylog.Debug, "loss")
+		require.NoError(t, err)
+		require.Equal(t, loss, fold.Evaluated.Loss)
+		estimation, err := estimate.Estimate(forward, fold.Dataset.Valid.Y)
+		require.NoError(t, err)
+		require.Equal(t, estimation.MaxAbsoluteError, fold.Estimate.MaxAbsoluteError)
+	}
+
+	p.FoldsCount = 1
+	_, err = CrossValidate(p)
+	require.ErrorIs(t, err, ErrParameters)
+	p.FoldsCount, p.RepeatsCount = 4, 0
+	_, err = CrossValidate(p)
+	require.ErrorIs(t, err, ErrParameters)
+	p.RepeatsCount, p.TestsPercent = 2, percent.Percent100
+	_, err = CrossValidate(p)
+	require.ErrorIs(t, err, ErrParameters)
+	p.TestsPercent = 0
+	p.Source, err = dataset.NewMemorySource(data, nil)
+	require.NoError(t, err)
+	_, err = CrossValidate(p)
+	require.ErrorIs(t, err, ErrParameters)
+}
@@ -13,6 +13,9 @@ import (
 	"time"
 )
 
+// MultiParameters describe RetriesCount train runs with fresh network, dataset and optimizer taken from providers
+// for each run. Sources of SingleParameters are shared by all the runs, so they can't be used by Parallel runs, and
+// DatasetProvider is not required if all the sources are provided.
 type MultiParameters struct {
 	SingleParameters
 
@@ -38,8 +41,10 @@ func checkMultiParameters(p *MultiParameters) (err error) {
 		return fmt.Errorf("no parameters provided")
 	} else if p.NetProvider == nil {
 		return fmt.Errorf("no network provider")
-	} else if p.DatasetProvider == nil {
+	} else if p.DatasetProvider == nil && (p.Source == nil || p.TestsSource == nil || p.ValidSource == nil) {
 		return fmt.Errorf("no dataset provider")
+	} else if p.Parallel && (p.Source != nil || p.TestsSource != nil || p.ValidSource != nil) {
+		return fmt.Errorf("sources can't be shared by parallel train runs")
 	} else if p.OptimizerProvider == nil {
 		return fmt.Errorf("no optimizer provider")
 	} else if p.EpochsCount < 1 {
@@ -58,10 +63,14 @@ func checkMultiParameters(p *MultiParameters) (err error) {
 func preMultiTrain(parameters *MultiParameters) (sp *SingleParameters, err error) {
 	defer wraperr.WrapError(ErrPreTrain, &err)
 
+	var ds *dataset.Dataset
+	if parameters.DatasetProvider != nil {
+		if ds, err = parameters.DatasetProvider(); err != nil {
+			return nil, err
+		}
+	}
 	if n, err := parameters.NetProvider(); err != nil {
 		return nil, err
-	} else if ds, err := parameters.DatasetProvider(); err != nil {
-		return nil, err
 	} else if o, f, err := parameters.OptimizerProvider(); err != nil {
 		return nil, err
 	} else {
@@ -75,6 +84,10 @@ func preMultiTrain(parameters *MultiParameters) (sp *SingleParameters, err error
 			Dataset:          ds,
 			Optimizer:        o,
 			PostOptimizeFunc: f,
+			Source:           parameters.Source,
+			TestsSource:      parameters.TestsSource,
+			ValidSource:      parameters.ValidSource,
+			BatchSize:        parameters.BatchSize,
 			TestEpochPicker:  parameters.TestEpochPicker,
 			SaveBest:         parameters.SaveBest,
 			SaveStats:        parameters.SaveStats,
@@ -102,8 +115,9 @@ func getBestResultFinder() func(result interface{}) interface{} {
 	return func(result interface{}) interface{} {
 		if casted, ok := result.(*SingleResult); !ok {
 			return result
-		} else if first || bestResult.Loss < casted.Loss {
+		} else if first || casted.Loss < bestResult.Loss {
 			bestResult = casted
+			first = false
 		}
 		return bestResult
 	}
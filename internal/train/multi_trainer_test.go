@@ -3,6 +3,7 @@ package train
 import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"math/rand"
 	"nn/internal/data/approx/datagen"
 	"nn/internal/data/approx/estimate"
 	"nn/internal/data/dataset"
@@ -123,3 +124,48 @@ func TestTrain(t *testing.T) {
 	require.NoError(t, err)
 	t.Logf("\n" + table)
 }
+
+func TestMultiTrain_Sources(t *testing.T) {
+	const retries, epochs = 2, 20
+
+	ds := newLinearDataset(t, 200, rand.New(rand.NewSource(42)))
+	sources := make([]dataset.DataSource, 3)
+	for i, data := range []*dataset.Data{ds.Train, ds.Tests, ds.Valid} {
+		var err error
+		sources[i], err = dataset.NewMemorySource(data, nil)
+		require.NoError(t, err)
+	}
+
+	p := &MultiParameters{
+		SingleParameters: SingleParameters{
+			TrainId:     TrainId{Id: uuid.New()},
+			EpochsCount: epochs,
+			Source:      sources[0],
+			TestsSource: sources[1],
+			ValidSource: sources[2],
+			BatchSize:   16,
+			TestEpochPicker: func(epoch, epochs int) bool {
+				return epoch%(epochs/10) == 0
+			},
+		},
+		RetriesCount: retries,
+		NetProvider: func() (net.INetwork, error) {
+			return newLinearNetwork(t), nil
+		},
+		OptimizerProvider: func() (operation.Optimizer, optim.PostOptimizeFunc, error) {
+			sgd, f := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.1})
+			return sgd, f, nil
+		},
+	}
+	results, err := MultiTrain(p)
+	require.NoError(t, err)
+	require.Equal(t, retries, len(results.AllResults))
+
+	loss, _, err := calcAndPrintLoss(results.BestResults.Network, ds.Valid, mylog.Debug, "loss on valid data")
+	require.NoError(t, err)
+	require.InDelta(t, loss, results.BestResults.Loss, 1e-9)
+
+	p.Parallel = true
+	_, err = MultiTrain(p)
+	require.ErrorIs(t, err, ErrExec)
+}
@@ -3,11 +3,13 @@ package train
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"io"
 	"math"
 	"nn/internal/data/dataset"
 	"nn/internal/nn/net"
 	"nn/internal/nn/operation"
 	"nn/internal/optim"
+	"nn/internal/utils"
 	"nn/pkg/mmath/matrix"
 	"nn/pkg/mylog"
 	"nn/pkg/wraperr"
@@ -22,6 +24,17 @@ type SingleParameters struct {
 	Optimizer        operation.Optimizer
 	PostOptimizeFunc optim.PostOptimizeFunc
 
+	// Source yields train data instead of Dataset.Train, which is used (reshuffled every epoch) if no Source provided.
+	// TestsSource and ValidSource yield tests and valid data instead of Dataset.Tests and Dataset.Valid, loss on them
+	// is computed batch by batch. Dataset is not required if all the sources are provided. SingleTrain closes sources
+	// implementing io.Closer when train is done; CSVSource reopens its file on the next Reset, so it can be reused.
+	Source      dataset.DataSource
+	TestsSource dataset.DataSource
+	ValidSource dataset.DataSource
+	// BatchSize is rows count of train batch, network is optimized once per batch. Whole epoch is one batch if it is
+	// not positive.
+	BatchSize int
+
 	TestEpochPicker func(epoch, epochs int) bool
 
 	SaveBest  bool
@@ -64,8 +77,12 @@ func checkSingleParameters(p *SingleParameters) (err error) {
 		return fmt.Errorf("no parameters provided")
 	} else if p.Network == nil {
 		return fmt.Errorf("no network provided")
-	} else if p.Dataset == nil {
-		return fmt.Errorf("no dataset provided")
+	} else if p.Source == nil && (p.Dataset == nil || p.Dataset.Train == nil) {
+		return fmt.Errorf("no train data or source provided")
+	} else if p.TestsSource == nil && (p.Dataset == nil || p.Dataset.Tests == nil) {
+		return fmt.Errorf("no tests data or source provided")
+	} else if p.ValidSource == nil && (p.Dataset == nil || p.Dataset.Valid == nil) {
+		return fmt.Errorf("no valid data or source provided")
 	} else if p.Optimizer == nil {
 		return fmt.Errorf("no optimizer provided")
 	} else if p.PostOptimizeFunc == nil {
@@ -94,7 +111,15 @@ func SingleTrain(parameters *SingleParameters) (r *SingleResult, err error) {
 		"[%d], network [%s], dataset [%s]",
 		parameters.ParentId.String(), id, parameters.EpochsCount, parameters.Network.ShortString(), parameters.Dataset.ShortString())
 
-	trainData := parameters.Dataset.Train.Copy()
+	sources, err := newSingleSources(parameters)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := sources.close(); closeErr != nil && err == nil {
+			r, err = nil, closeErr
+		}
+	}()
 
 	result := &SingleResult{
 		TrainId: parameters.TrainId,
@@ -117,7 +142,7 @@ func SingleTrain(parameters *SingleParameters) (r *SingleResult, err error) {
 	for i := 0; i < parameters.EpochsCount; i++ {
 		if parameters.TestEpochPicker(i, parameters.EpochsCount) {
 			logger.Tracef("evaluating current results on epoch: %d", i)
-			loss, forward, err := calcAndPrintLoss(parameters.Network, parameters.Dataset.Tests, mylog.Debug,
+			loss, forward, err := sources.tests.calcAndPrintLoss(parameters.Network, mylog.Debug,
 				fmt.Sprintf("loss on tests data on [%d/%d] epoch", i, parameters.EpochsCount))
 			if err != nil {
 				return nil, fmt.Errorf("error calculating loss on epoch [%d]: %w", i, err)
@@ -150,23 +175,13 @@ func SingleTrain(parameters *SingleParameters) (r *SingleResult, err error) {
 			}
 		}
 
-		trainData, _ = trainData.Shuffle()
-		if _, err = parameters.Network.Forward(trainData.X); err != nil {
-			return nil, err
-		}
-		if _, err = parameters.Network.Loss(trainData.Y); err != nil {
-			return nil, err
-		}
-		if _, err = parameters.Network.Backward(); err != nil {
-			return nil, err
-		}
-		if err = parameters.Network.ApplyOptim(parameters.Optimizer); err != nil {
-			return nil, err
+		if err = trainEpoch(parameters, sources.train); err != nil {
+			return nil, fmt.Errorf("error training on epoch [%d]: %w", i, err)
 		}
 		parameters.PostOptimizeFunc()
 	}
 
-	loss, forward, err := calcAndPrintLoss(parameters.Network, parameters.Dataset.Valid, mylog.Info, "loss on valid data after train")
+	loss, forward, err := sources.valid.calcAndPrintLoss(parameters.Network, mylog.Info, "loss on valid data after train")
 	if err != nil {
 		return nil, err
 	}
@@ -180,11 +195,126 @@ func SingleTrain(parameters *SingleParameters) (r *SingleResult, err error) {
 	return result, nil
 }
 
+// trainEpoch optimizes network once per batch of source rewound to the first batch
+func trainEpoch(parameters *SingleParameters, source dataset.DataSource) error {
+	if err := source.Reset(); err != nil {
+		return err
+	}
+	for {
+		batch, err := source.Next(parameters.BatchSize)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if _, err = parameters.Network.Forward(batch.X); err != nil {
+			return err
+		}
+		if _, err = parameters.Network.Loss(batch.Y, batch.W); err != nil {
+			return err
+		}
+		if _, err = parameters.Network.Backward(); err != nil {
+			return err
+		}
+		if err = parameters.Network.ApplyOptim(parameters.Optimizer); err != nil {
+			return err
+		}
+	}
+}
+
+// singleSources holds train source and tests and valid data of single train run
+type singleSources struct {
+	train        dataset.DataSource
+	tests, valid *evaluationData
+}
+
+// evaluationData is Data held in memory or source read by batches of given size if provided
+type evaluationData struct {
+	data   *dataset.Data
+	source dataset.DataSource
+	size   int
+}
+
+// newSingleSources return sources of parameters, Dataset's Data is used where no source is provided
+func newSingleSources(p *SingleParameters) (s *singleSources, err error) {
+	s = &singleSources{
+		train: p.Source,
+		tests: &evaluationData{source: p.TestsSource, size: p.BatchSize},
+		valid: &evaluationData{source: p.ValidSource, size: p.BatchSize},
+	}
+	if p.Dataset != nil {
+		s.tests.data, s.valid.data = p.Dataset.Tests, p.Dataset.Valid
+	}
+	if s.train == nil {
+		if s.train, err = dataset.NewMemorySource(p.Dataset.Train, utils.Rand); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// close closes sources implementing io.Closer
+func (s *singleSources) close() error {
+	for _, source := range []dataset.DataSource{s.train, s.tests.source, s.valid.source} {
+		if closer, ok := source.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// calcAndPrintLoss return loss of network and its outputs on source if provided or on Data otherwise. Losses of
+// source's batches are averaged by rows count, so it is the loss on whole data for losses averaged over rows (see
+// loss.ILoss).
+func (e *evaluationData) calcAndPrintLoss(network net.INetwork, level mylog.Level, msg string) (l float64, m *matrix.Matrix, err error) {
+	if e.source == nil {
+		return calcAndPrintLoss(network, e.data, level, msg)
+	} else if err = e.source.Reset(); err != nil {
+		return 0, nil, err
+	}
+
+	var forwards []*matrix.Matrix
+	var rows int
+	for {
+		batch, err := e.source.Next(e.size)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, nil, err
+		}
+		forward, err := network.Forward(batch.X)
+		if err != nil {
+			return 0, nil, err
+		}
+		loss, err := network.Loss(batch.Y, batch.W)
+		if err != nil {
+			return 0, nil, err
+		}
+		l += loss * float64(batch.X.Rows())
+		rows += batch.X.Rows()
+		forwards = append(forwards, forward)
+	}
+	if rows == 0 {
+		return 0, nil, fmt.Errorf("source has no data")
+	}
+	l /= float64(rows)
+	if m = forwards[0]; len(forwards) > 1 {
+		if m, err = m.VStack(forwards[1:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	logger.Logf(level, "%s: %e", msg, l)
+	return l, m, nil
+}
+
 func calcAndPrintLoss(network net.INetwork, data *dataset.Data, level mylog.Level, msg string) (l float64, m *matrix.Matrix, err error) {
 	if m, err = network.Forward(data.X); err != nil {
 		return 0, nil, err
 	}
-	l, err = network.Loss(data.Y)
+	l, err = network.Loss(data.Y, data.W)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -0,0 +1,486 @@
+package train
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"nn/internal/data/approx/estimate"
+	"nn/internal/data/dataset"
+	"nn/internal/nn"
+	"nn/internal/nn/layer"
+	"nn/internal/nn/loss"
+	"nn/internal/nn/net"
+	"nn/internal/nn/operation"
+	"nn/internal/optim"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/mylog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newSequenceDataset generates sequences of given steps count, target is weighted sum of sequence's values, so the
+// latest values matter the most.
+func newSequenceDataset(t *testing.T, count, steps int) *dataset.Dataset {
+	rng := rand.New(rand.NewSource(42))
+	x := make([]float64, count*steps)
+	y := make([]float64, count)
+	for i := 0; i < count; i++ {
+		for s := 0; s < steps; s++ {
+			value := rng.Float64()*2 - 1
+			x[i*steps+s] = value
+			y[i] += value * float64(s+1) / float64(steps)
+		}
+	}
+	xm, err := matrix.NewMatrixRawFlat(count, steps, x)
+	require.NoError(t, err)
+	ym, err := matrix.NewMatrixRawFlat(count, 1, y)
+	require.NoError(t, err)
+	data, err := dataset.NewData(xm, ym)
+	require.NoError(t, err)
+	ds, err := dataset.NewDatasetSplit(data, dataset.DefaultDataSplitParameters)
+	require.NoError(t, err)
+	return ds
+}
+
+func TestSingleTrain_Recurrent(t *testing.T) {
+	const steps, epochs = 3, 100
+
+	testcases := []struct {
+		kind      nn.Kind
+		learnRate float64
+	}{
+		{kind: layer.RNNLayer, learnRate: 0.1},
+		{kind: layer.LSTMLayer, learnRate: 0.5},
+		{kind: layer.GRULayer, learnRate: 0.2},
+	}
+
+	for _, tc := range testcases {
+		t.Run(string(tc.kind), func(t *testing.T) {
+			ds := newSequenceDataset(t, 100, steps)
+
+			nb, err := net.NewBuilder(net.FFNetwork)
+			require.NoError(t, err)
+			network, err := nb.
+				AddLayerKind(tc.kind).
+				AddInputsCount(1).
+				AddNeuronsCount(8).
+				AddParamInitType(operation.GlorotInit).
+				AddSequence(&layer.SequenceParameters{Steps: steps, Truncation: steps}).
+				AddLayerKind(layer.DenseLayer).
+				AddInputsCount(8).
+				AddNeuronsCount(1).
+				AddParamInitType(operation.GlorotInit).
+				AddActivationKind(operation.LinearActivation).
+				LossKind(loss.MSELoss).
+				Build()
+			require.NoError(t, err)
+
+			initialLoss, _, err := calcAndPrintLoss(network, ds.Valid, mylog.Debug, "loss on valid data before train")
+			require.NoError(t, err)
+
+			sgd, post := optim.NewSGD(&optim.SGDParameters{LearnRate: tc.learnRate})
+			result, err := SingleTrain(&SingleParameters{
+				TrainId:          TrainId{Id: uuid.New()},
+				EpochsCount:      epochs,
+				Network:          network,
+				Dataset:          ds,
+				Optimizer:        sgd,
+				PostOptimizeFunc: post,
+				TestEpochPicker: func(epoch, epochs int) bool {
+					return epoch%(epochs/10) == 0
+				},
+				SaveBest: true,
+			})
+			require.NoError(t, err)
+			require.Less(t, result.Loss, initialLoss/2)
+		})
+	}
+}
+
+func TestSingleTrain_RBF(t *testing.T) {
+	const count, centers, epochs = 200, 10, 100
+
+	rng := rand.New(rand.NewSource(42))
+	x := make([]float64, count)
+	y := make([]float64, count)
+	for i := range x {
+		x[i] = rng.Float64()*6 - 3
+		y[i] = math.Sin(x[i])
+	}
+	xm, err := matrix.NewMatrixRawFlat(count, 1, x)
+	require.NoError(t, err)
+	ym, err := matrix.NewMatrixRawFlat(count, 1, y)
+	require.NoError(t, err)
+	data, err := dataset.NewData(xm, ym)
+	require.NoError(t, err)
+	ds, err := dataset.NewDatasetSplit(data, dataset.DefaultDataSplitParameters)
+	require.NoError(t, err)
+
+	kernels := []nn.Kind{
+		operation.GaussianActivation,
+		operation.MultiquadricActivation,
+		operation.InverseMultiquadricActivation,
+	}
+	for _, kernel := range kernels {
+		t.Run(string(kernel), func(t *testing.T) {
+			c, err := ds.Train.KMeans(centers, 100)
+			require.NoError(t, err)
+
+			nb, err := net.NewBuilder(net.FFNetwork)
+			require.NoError(t, err)
+			network, err := nb.
+				AddLayerKind(layer.RBFLayer).
+				AddCenters(c).
+				AddNeuronsCount(1).
+				AddActivationKind(kernel).
+				LossKind(loss.MSELoss).
+				Build()
+			require.NoError(t, err)
+
+			initialLoss, _, err := calcAndPrintLoss(network, ds.Valid, mylog.Debug, "loss on valid data before train")
+			require.NoError(t, err)
+
+			sgd, post := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.05})
+			result, err := SingleTrain(&SingleParameters{
+				TrainId:          TrainId{Id: uuid.New()},
+				EpochsCount:      epochs,
+				Network:          network,
+				Dataset:          ds,
+				Optimizer:        sgd,
+				PostOptimizeFunc: post,
+				TestEpochPicker: func(epoch, epochs int) bool {
+					return epoch%(epochs/10) == 0
+				},
+				SaveBest: true,
+			})
+			require.NoError(t, err)
+			require.Less(t, result.Loss, initialLoss/4)
+		})
+	}
+}
+
+func TestSingleTrain_Precision(t *testing.T) {
+	const count, epochs = 200, 3000
+
+	rng := rand.New(rand.NewSource(42))
+	x := make([]float64, count)
+	y := make([]float64, count)
+	for i := range x {
+		x[i] = rng.Float64()*4 - 2
+		y[i] = math.Sin(x[i])
+	}
+	xm, err := matrix.NewMatrixRawFlat(count, 1, x)
+	require.NoError(t, err)
+	ym, err := matrix.NewMatrixRawFlat(count, 1, y)
+	require.NoError(t, err)
+	data, err := dataset.NewData(xm, ym)
+	require.NoError(t, err)
+	ds, err := dataset.NewDatasetSplit(data, dataset.DefaultDataSplitParameters)
+	require.NoError(t, err)
+
+	nb, err := net.NewBuilder(net.FFNetwork)
+	require.NoError(t, err)
+	network64, err := nb.
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(1).
+		AddNeuronsCount(16).
+		AddActivationKind(operation.TanhActivation).
+		AddParamInitType(operation.GlorotInit).
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(16).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		AddParamInitType(operation.GlorotInit).
+		LossKind(loss.MSELoss).
+		Build()
+	require.NoError(t, err)
+	// the same initial parameters rounded to float32
+	network32, err := net.ConvertPrecision(network64, nn.Float32Params)
+	require.NoError(t, err)
+
+	results := make(map[nn.Precision]*estimate.Result)
+	for precision, network := range map[nn.Precision]net.INetwork{nn.Float64: network64, nn.Float32Params: network32} {
+		sgd, post := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.2})
+		result, err := SingleTrain(&SingleParameters{
+			TrainId:          TrainId{Id: uuid.New()},
+			EpochsCount:      epochs,
+			Network:          network,
+			Dataset:          ds,
+			Optimizer:        sgd,
+			PostOptimizeFunc: post,
+			TestEpochPicker: func(epoch, epochs int) bool {
+				return epoch%(epochs/10) == 0
+			},
+		})
+		require.NoError(t, err)
+
+		outputs, err := result.Network.Forward(ds.Valid.X)
+		require.NoError(t, err)
+		results[precision], err = estimate.Estimate(outputs, ds.Valid.Y)
+		require.NoError(t, err)
+		t.Logf("%s: max absolute error %.5f, avg absolute error %.5f", precision,
+			results[precision].MaxAbsoluteError, results[precision].AvgAbsoluteError)
+	}
+
+	for _, result := range results {
+		require.Less(t, result.AvgAbsoluteError, 0.05)
+		require.Less(t, result.MaxAbsoluteError, 0.1)
+	}
+	require.InDelta(t, results[nn.Float64].AvgAbsoluteError, results[nn.Float32Params].AvgAbsoluteError, 0.001)
+}
+
+// newLinearDataset generates Dataset of y = 2x + 1 for x in [-1; 1)
+func newLinearDataset(t *testing.T, count int, rng *rand.Rand) *dataset.Dataset {
+	x := make([]float64, count)
+	y := make([]float64, count)
+	for i := range x {
+		x[i] = rng.Float64()*2 - 1
+		y[i] = 2*x[i] + 1
+	}
+	xm, err := matrix.NewMatrixRawFlat(count, 1, x)
+	require.NoError(t, err)
+	ym, err := matrix.NewMatrixRawFlat(count, 1, y)
+	require.NoError(t, err)
+	data, err := dataset.NewData(xm, ym)
+	require.NoError(t, err)
+	ds, err := dataset.NewDatasetSplit(data, dataset.DefaultDataSplitParameters)
+	require.NoError(t, err)
+	return ds
+}
+
+func newLinearNetwork(t *testing.T) net.INetwork {
+	nb, err := net.NewBuilder(net.FFNetwork)
+	require.NoError(t, err)
+	network, err := nb.
+		AddLayerKind(layer.DenseLayer).
+		AddInputsCount(1).
+		AddNeuronsCount(1).
+		AddActivationKind(operation.LinearActivation).
+		AddParamInitType(operation.GlorotInit).
+		LossKind(loss.MSELoss).
+		Build()
+	require.NoError(t, err)
+	return network
+}
+
+func TestSingleTrain_Source(t *testing.T) {
+	const count, epochs = 200, 50
+
+	rng := rand.New(rand.NewSource(42))
+	ds := newLinearDataset(t, count, rng)
+	memory, err := dataset.NewMemorySource(ds.Train, nil)
+	require.NoError(t, err)
+	source, err := dataset.NewShuffleBuffer(memory, 32, rng)
+	require.NoError(t, err)
+	network := newLinearNetwork(t)
+
+	initialLoss, _, err := calcAndPrintLoss(network, ds.Valid, mylog.Debug, "loss on valid data before train")
+	require.NoError(t, err)
+
+	sgd, post := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.1})
+	result, err := SingleTrain(&SingleParameters{
+		TrainId:          TrainId{Id: uuid.New()},
+		EpochsCount:      epochs,
+		Network:          network,
+		Dataset:          ds,
+		Optimizer:        sgd,
+		PostOptimizeFunc: post,
+		Source:           source,
+		BatchSize:        16,
+		TestEpochPicker: func(epoch, epochs int) bool {
+			return epoch%(epochs/10) == 0
+		},
+	})
+	require.NoError(t, err)
+	require.Less(t, result.Loss, initialLoss/100)
+}
+
+func TestSingleTrain_CSVSources(t *testing.T) {
+	const count, epochs = 200, 50
+
+	ds := newLinearDataset(t, count, rand.New(rand.NewSource(42)))
+	parameters := &dataset.CSVParameters{Header: true, Inputs: []string{"x"}, Outputs: []string{"y"}}
+	sources := make([]*dataset.CSVSource, 3)
+	for i, data := range []*dataset.Data{ds.Train, ds.Tests, ds.Valid} {
+		path := filepath.Join(t.TempDir(), fmt.Sprintf("%d.csv", i))
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		require.NoError(t, data.WriteCSV(file, nil, parameters))
+		require.NoError(t, file.Close())
+		sources[i], err = dataset.NewCSVSource(path, parameters)
+		require.NoError(t, err)
+	}
+
+	// no Dataset: train, tests and valid data are read from files by batches
+	sgd, post := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.1})
+	p := &SingleParameters{
+		TrainId:          TrainId{Id: uuid.New()},
+		EpochsCount:      epochs,
+		Network:          newLinearNetwork(t),
+		Optimizer:        sgd,
+		PostOptimizeFunc: post,
+		Source:           sources[0],
+		TestsSource:      sources[1],
+		ValidSource:      sources[2],
+		BatchSize:        16,
+		TestEpochPicker: func(epoch, epochs int) bool {
+			return epoch%(epochs/10) == 0
+		},
+		SaveBest: true,
+	}
+	result, err := SingleTrain(p)
+	require.NoError(t, err)
+	require.Less(t, result.Loss, 1e-3)
+
+	// loss and outputs on valid source are ones on whole valid Data
+	loss, forward, err := calcAndPrintLoss(result.Network, ds.Valid, mylog.Debug, "loss on valid data")
+	require.NoError(t, err)
+	require.InDelta(t, loss, result.Loss, 1e-9)
+	require.True(t, forward.EqualApprox(result.Forward))
+
+	// sources are closed after train and reopened by Reset
+	for _, source := range sources {
+		_, err = source.Next(1)
+		require.ErrorIs(t, err, dataset.ErrCreate)
+	}
+	p.Id = uuid.New()
+	_, err = SingleTrain(p)
+	require.NoError(t, err)
+
+	p.ValidSource = nil
+	_, err = SingleTrain(p)
+	require.ErrorIs(t, err, ErrParameters)
+}
+
+func TestSingleTrain_Forecast(t *testing.T) {
+	const steps, lookback, epochs = 300, 8, 300
+
+	values := make([]float64, steps)
+	for i := range values {
+		values[i] = math.Sin(float64(i)/5) + 0.5*math.Sin(float64(i)/13)
+	}
+	series, err := matrix.NewMatrixRawFlat(steps, 1, values)
+	require.NoError(t, err)
+
+	for _, horizon := range []int{1, 4} {
+		t.Run(fmt.Sprintf("horizon %d", horizon), func(t *testing.T) {
+			ds, err := dataset.NewWindowDataset([]*matrix.Matrix{series},
+				&dataset.WindowParameters{Lookback: lookback, Horizon: horizon}, dataset.DefaultDataSplitParameters)
+			require.NoError(t, err)
+
+			nb, err := net.NewBuilder(net.FFNetwork)
+			require.NoError(t, err)
+			network, err := nb.
+				AddLayerKind(layer.DenseLayer).
+				AddInputsCount(lookback).
+				AddNeuronsCount(16).
+				AddActivationKind(operation.TanhActivation).
+				AddParamInitType(operation.GlorotInit).
+				AddLayerKind(layer.DenseLayer).
+				AddInputsCount(16).
+				AddNeuronsCount(horizon).
+				AddActivationKind(operation.LinearActivation).
+				AddParamInitType(operation.GlorotInit).
+				LossKind(loss.MSELoss).
+				Build()
+			require.NoError(t, err)
+
+			initialLoss, _, err := calcAndPrintLoss(network, ds.Valid, mylog.Debug, "loss on valid data before train")
+			require.NoError(t, err)
+
+			sgd, post := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.1})
+			result, err := SingleTrain(&SingleParameters{
+				TrainId:          TrainId{Id: uuid.New()},
+				EpochsCount:      epochs,
+				Network:          network,
+				Dataset:          ds,
+				Optimizer:        sgd,
+				PostOptimizeFunc: post,
+				BatchSize:        32,
+				TestEpochPicker: func(epoch, epochs int) bool {
+					return epoch%(epochs/10) == 0
+				},
+				SaveBest: true,
+			})
+			require.NoError(t, err)
+			require.Less(t, result.Loss, initialLoss/10)
+		})
+	}
+}
+
+func TestSingleTrain_Weighted(t *testing.T) {
+	const count, epochs = 101, 300
+
+	x := make([]float64, count)
+	y := make([]float64, count)
+	w := make([]float64, count)
+	for i := range x {
+		x[i] = 2*float64(i)/(count-1) - 1
+		y[i] = x[i] * x[i]
+		w[i] = 1
+		if math.Abs(x[i]) > 0.8 {
+			w[i] = 20 // emphasize boundaries of inputs' range
+		}
+	}
+	xm, err := matrix.NewMatrixRawFlat(count, 1, x)
+	require.NoError(t, err)
+	ym, err := matrix.NewMatrixRawFlat(count, 1, y)
+	require.NoError(t, err)
+	weights, err := vector.NewVector(w)
+	require.NoError(t, err)
+
+	// boundaryError trains linear network and return its average absolute error on boundaries of inputs' range
+	boundaryError := func(weighted bool) float64 {
+		data, err := dataset.NewData(xm, ym)
+		require.NoError(t, err)
+		if weighted {
+			require.NoError(t, data.SetWeights(weights))
+		}
+		ds, err := dataset.NewDataset(data, data, data)
+		require.NoError(t, err)
+
+		nb, err := net.NewBuilder(net.FFNetwork)
+		require.NoError(t, err)
+		network, err := nb.
+			AddLayerKind(layer.DenseLayer).
+			AddInputsCount(1).
+			AddNeuronsCount(1).
+			AddActivationKind(operation.LinearActivation).
+			AddParamInitType(operation.GlorotInit).
+			LossKind(loss.MSELoss).
+			Build()
+		require.NoError(t, err)
+
+		sgd, post := optim.NewSGD(&optim.SGDParameters{LearnRate: 0.1})
+		result, err := SingleTrain(&SingleParameters{
+			TrainId:          TrainId{Id: uuid.New()},
+			EpochsCount:      epochs,
+			Network:          network,
+			Dataset:          ds,
+			Optimizer:        sgd,
+			PostOptimizeFunc: post,
+			TestEpochPicker: func(epoch, epochs int) bool {
+				return epoch%(epochs/10) == 0
+			},
+		})
+		require.NoError(t, err)
+
+		outputs, err := result.Network.Forward(xm)
+		require.NoError(t, err)
+		var sum, n float64
+		for i, output := range outputs.RawFlat() {
+			if w[i] > 1 {
+				sum, n = sum+math.Abs(output-y[i]), n+1
+			}
+		}
+		return sum / n
+	}
+
+	unweighted, weighted := boundaryError(false), boundaryError(true)
+	t.Logf("average absolute error on boundaries: unweighted %.4f, weighted %.4f", unweighted, weighted)
+	require.Less(t, weighted, unweighted/2)
+}
@@ -0,0 +1,10 @@
+package utils
+
+// ApplySequentially calls f for each of values in order, return result of the last call (nil for no values).
+func ApplySequentially(values []interface{}, f func(value interface{}) interface{}) interface{} {
+	var res interface{}
+	for _, value := range values {
+		res = f(value)
+	}
+	return res
+}
@@ -2,16 +2,34 @@ package utils
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 )
 
-var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+// Rand is random generator seeded by current time, it is safe for concurrent use. It is default generator of random
+// initialization, dropout masks and data shuffling when no generator is provided explicitly.
+var Rand = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano()).(rand.Source64)})
 
-func RandNormArray(size int, loc float64, scale float64) []float64 {
-	arr := make([]float64, size)
-	for i := 0; i < size; i++ {
-		arr[i] = loc + rng.NormFloat64()*scale
-	}
+// lockedSource is rand.Source guarded by mutex as source of math/rand top-level functions is.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
 
-	return arr
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
 }
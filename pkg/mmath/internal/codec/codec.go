@@ -0,0 +1,300 @@
+// Package codec provides encoding of shaped float arrays shared by matrix and vector packages: binary format with
+// little-endian header and NumPy .npy format.
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DType is type of encoded values
+type DType uint8
+
+const (
+	Float64 DType = iota + 1
+	Float32
+)
+
+func (d DType) size() int {
+	if d == Float32 {
+		return 4
+	}
+	return 8
+}
+
+// magic starts binary encoded arrays
+var magic = [4]byte{'N', 'N', 'M', 'M'}
+
+// version of binary format
+const version uint8 = 1
+
+// header of binary encoded array, it is followed by uint64 sizes of dimensions and values in little-endian order:
+//
+//     | magic [4]byte | version uint8 | dtype uint8 | dims uint16 | shape [dims]uint64 | values |
+type header struct {
+	Magic   [4]byte
+	Version uint8
+	DType   DType
+	Dims    uint16
+}
+
+// EncodeBinary return binary encoding of values of array with given shape as given DType.
+func EncodeBinary(shape []int, values []float64, dtype DType) ([]byte, error) {
+	if dtype != Float64 && dtype != Float32 {
+		return nil, fmt.Errorf("unknown dtype: %d", dtype)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 8+8*len(shape)+dtype.size()*len(values)))
+	_ = binary.Write(buf, binary.LittleEndian, header{Magic: magic, Version: version, DType: dtype, Dims: uint16(len(shape))})
+	for _, dim := range shape {
+		_ = binary.Write(buf, binary.LittleEndian, uint64(dim))
+	}
+
+	data := make([]byte, dtype.size()*len(values))
+	for i, value := range values {
+		if dtype == Float32 {
+			binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(float32(value)))
+		} else {
+			binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(value))
+		}
+	}
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+// DecodeBinary return shape and values of binary encoded array.
+func DecodeBinary(data []byte) (shape []int, values []float64, err error) {
+	r := bytes.NewReader(data)
+	var h header
+	if err = binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, nil, fmt.Errorf("error reading header: %w", err)
+	} else if h.Magic != magic {
+		return nil, nil, fmt.Errorf("wrong magic: %q", h.Magic[:])
+	} else if h.Version != version {
+		return nil, nil, fmt.Errorf("unsupported version: %d", h.Version)
+	} else if h.DType != Float64 && h.DType != Float32 {
+		return nil, nil, fmt.Errorf("unknown dtype: %d", h.DType)
+	}
+
+	dims := make([]uint64, h.Dims)
+	if err = binary.Read(r, binary.LittleEndian, dims); err != nil {
+		return nil, nil, fmt.Errorf("error reading shape: %w", err)
+	}
+	shape, size, err := checkShape(dims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data = data[len(data)-r.Len():]
+	if len(data) != size*h.DType.size() {
+		return nil, nil, fmt.Errorf("wrong data size for shape %v: %d != %d", shape, len(data), size*h.DType.size())
+	}
+	values = make([]float64, size)
+	for i := range values {
+		if h.DType == Float32 {
+			values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:])))
+		} else {
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+		}
+	}
+	return shape, values, nil
+}
+
+// checkShape return shape of given sizes and count of values, sizes must be positive.
+func checkShape(dims []uint64) (shape []int, size int, err error) {
+	shape = make([]int, len(dims))
+	size = 1
+	for i, dim := range dims {
+		if dim < 1 || dim > math.MaxInt32 {
+			return nil, 0, fmt.Errorf("wrong size of %d'th dimension: %d", i, dim)
+		}
+		shape[i] = int(dim)
+		size *= shape[i]
+		if size > math.MaxInt32 {
+			return nil, 0, fmt.Errorf("too many values for shape %v", dims)
+		}
+	}
+	return shape, size, nil
+}
+
+// npyMagic starts .npy files
+const npyMagic = "\x93NUMPY"
+
+// WriteNpy writes array with given shape and values in row-major order to w in NumPy .npy format (version 1.0,
+// little-endian float64).
+func WriteNpy(w io.Writer, shape []int, values []float64) error {
+	dims := make([]string, len(shape))
+	for i, dim := range shape {
+		dims[i] = strconv.Itoa(dim)
+	}
+	shapeStr := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shapeStr += ","
+	}
+	dict := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%s), }", shapeStr)
+
+	// magic, version and header length take 10 bytes, header is padded by spaces to align data by 64 bytes
+	pad := 64 - (10+len(dict)+1)%64
+	if pad == 64 {
+		pad = 0
+	}
+	head := dict + strings.Repeat(" ", pad) + "\n"
+
+	buf := bytes.NewBuffer(make([]byte, 0, 10+len(head)+8*len(values)))
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(head)))
+	buf.WriteString(head)
+	data := make([]byte, 8*len(values))
+	for i, value := range values {
+		binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(value))
+	}
+	buf.Write(data)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+var (
+	npyDescr   = regexp.MustCompile(`'descr'\s*:\s*'([<>|=])([fiub])(\d+)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+)
+
+// ReadNpy return shape and values in row-major order of array read from r in NumPy .npy format. Float, signed and
+// unsigned integer and bool dtypes of any byte order are converted to float64.
+func ReadNpy(r io.Reader) (shape []int, values []float64, err error) {
+	prefix := make([]byte, 8)
+	if _, err = io.ReadFull(r, prefix); err != nil {
+		return nil, nil, fmt.Errorf("error reading npy magic: %w", err)
+	} else if string(prefix[:6]) != npyMagic {
+		return nil, nil, fmt.Errorf("wrong npy magic: %q", prefix[:6])
+	}
+
+	var headerLen int
+	switch prefix[6] {
+	case 1:
+		var l uint16
+		err = binary.Read(r, binary.LittleEndian, &l)
+		headerLen = int(l)
+	case 2, 3:
+		var l uint32
+		err = binary.Read(r, binary.LittleEndian, &l)
+		headerLen = int(l)
+	default:
+		return nil, nil, fmt.Errorf("unsupported npy version: %d.%d", prefix[6], prefix[7])
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading npy header length: %w", err)
+	}
+	head := make([]byte, headerLen)
+	if _, err = io.ReadFull(r, head); err != nil {
+		return nil, nil, fmt.Errorf("error reading npy header: %w", err)
+	}
+
+	descr := npyDescr.FindStringSubmatch(string(head))
+	fortran := npyFortran.FindStringSubmatch(string(head))
+	shapeMatch := npyShape.FindStringSubmatch(string(head))
+	if descr == nil || fortran == nil || shapeMatch == nil {
+		return nil, nil, fmt.Errorf("wrong npy header: %s", strings.TrimSpace(string(head)))
+	}
+
+	var dims []uint64
+	for _, dim := range strings.Split(shapeMatch[1], ",") {
+		if dim = strings.TrimSpace(dim); dim == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(dim, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("wrong npy shape: (%s)", shapeMatch[1])
+		}
+		dims = append(dims, value)
+	}
+	if len(dims) == 0 {
+		dims = []uint64{1} // scalar
+	}
+	shape, size, err := checkShape(dims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order := binary.ByteOrder(binary.LittleEndian)
+	if descr[1] == ">" {
+		order = binary.BigEndian
+	}
+	itemSize, _ := strconv.Atoi(descr[3])
+	decode, err := npyDecoder(descr[2], itemSize, order)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := make([]byte, size*itemSize)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return nil, nil, fmt.Errorf("error reading npy data: %w", err)
+	}
+	values = make([]float64, size)
+	for i := range values {
+		values[i] = decode(data[i*itemSize : (i+1)*itemSize])
+	}
+
+	if fortran[1] == "True" && len(shape) > 1 {
+		values = fromFortranOrder(shape, values)
+	}
+	return shape, values, nil
+}
+
+// npyDecoder return function decoding single value of given npy kind and item size.
+func npyDecoder(kind string, size int, order binary.ByteOrder) (func(b []byte) float64, error) {
+	switch {
+	case kind == "f" && size == 8:
+		return func(b []byte) float64 { return math.Float64frombits(order.Uint64(b)) }, nil
+	case kind == "f" && size == 4:
+		return func(b []byte) float64 { return float64(math.Float32frombits(order.Uint32(b))) }, nil
+	case kind == "i" && size == 8:
+		return func(b []byte) float64 { return float64(int64(order.Uint64(b))) }, nil
+	case kind == "i" && size == 4:
+		return func(b []byte) float64 { return float64(int32(order.Uint32(b))) }, nil
+	case kind == "i" && size == 2:
+		return func(b []byte) float64 { return float64(int16(order.Uint16(b))) }, nil
+	case kind == "i" && size == 1:
+		return func(b []byte) float64 { return float64(int8(b[0])) }, nil
+	case kind == "u" && size == 8:
+		return func(b []byte) float64 { return float64(order.Uint64(b)) }, nil
+	case kind == "u" && size == 4:
+		return func(b []byte) float64 { return float64(order.Uint32(b)) }, nil
+	case kind == "u" && size == 2:
+		return func(b []byte) float64 { return float64(order.Uint16(b)) }, nil
+	case (kind == "u" || kind == "b") && size == 1:
+		return func(b []byte) float64 { return float64(b[0]) }, nil
+	default:
+		return nil, fmt.Errorf("unsupported npy dtype: %s%d", kind, size)
+	}
+}
+
+// fromFortranOrder return values of array with given shape stored in column-major order reordered to row-major one.
+func fromFortranOrder(shape []int, values []float64) []float64 {
+	res := make([]float64, len(values))
+	index := make([]int, len(shape))
+	for i := range values {
+		// i is offset in column-major order, index is its multi-index, first dimension changes fastest
+		offset, stride := 0, 1
+		for d := len(shape) - 1; d >= 0; d-- {
+			offset += index[d] * stride
+			stride *= shape[d]
+		}
+		res[offset] = values[i]
+		for d := 0; d < len(shape); d++ {
+			if index[d]++; index[d] < shape[d] {
+				break
+			}
+			index[d] = 0
+		}
+	}
+	return res
+}
@@ -0,0 +1,176 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+// npy return .npy file of given version with given header dict and data as numpy writes it.
+func npy(major byte, dict string, data []byte) []byte {
+	buf := bytes.NewBufferString(npyMagic)
+	buf.Write([]byte{major, 0})
+	head := dict + "\n"
+	if major == 1 {
+		_ = binary.Write(buf, binary.LittleEndian, uint16(len(head)))
+	} else {
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(head)))
+	}
+	buf.WriteString(head)
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func encode(order binary.ByteOrder, values ...interface{}) []byte {
+	buf := new(bytes.Buffer)
+	for _, value := range values {
+		_ = binary.Write(buf, order, value)
+	}
+	return buf.Bytes()
+}
+
+func TestBinary(t *testing.T) {
+	tests := []struct {
+		name     string
+		shape    []int
+		values   []float64
+		dtype    DType
+		expected []float64
+	}{
+		{name: "matrix", shape: []int{2, 3}, values: []float64{1, 2, 3, 4, 5, 6.5}, dtype: Float64},
+		{name: "vector", shape: []int{3}, values: []float64{-1, 0, 1e-300}, dtype: Float64},
+		{name: "float32", shape: []int{1, 2}, values: []float64{0.1, 2}, dtype: Float32, expected: []float64{float64(float32(0.1)), 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := EncodeBinary(test.shape, test.values, test.dtype)
+			require.NoError(t, err)
+			require.Equal(t, []byte("NNMM"), data[:4])
+			require.Equal(t, 8+8*len(test.shape)+test.dtype.size()*len(test.values), len(data))
+
+			shape, values, err := DecodeBinary(data)
+			require.NoError(t, err)
+			require.Equal(t, test.shape, shape)
+			if test.expected == nil {
+				test.expected = test.values
+			}
+			require.Equal(t, test.expected, values)
+		})
+	}
+
+	data, err := EncodeBinary([]int{2}, []float64{1, 2}, Float64)
+	require.NoError(t, err)
+	for name, corrupted := range map[string][]byte{
+		"truncated":    data[:len(data)-1],
+		"no header":    data[:3],
+		"wrong magic":  append([]byte("XXXX"), data[4:]...),
+		"wrong dtype":  append(append(append([]byte{}, data[:5]...), 9), data[6:]...),
+		"zero size":    append(append(append([]byte{}, data[:8]...), make([]byte, 8)...), data[16:]...),
+		"extra values": append(append([]byte{}, data...), data[16:]...),
+	} {
+		_, _, err = DecodeBinary(corrupted)
+		require.Error(t, err, name)
+	}
+	_, err = EncodeBinary([]int{1}, []float64{1}, DType(9))
+	require.Error(t, err)
+}
+
+func TestWriteNpy(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, WriteNpy(buf, []int{2, 3}, []float64{1, 2, 3, 4, 5, 6}))
+	data := buf.Bytes()
+
+	// the same bytes as numpy.save writes: header is padded to 64 bytes
+	head := "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }"
+	require.Equal(t, npy(1, head+strings.Repeat(" ", 117-len(head)), encode(binary.LittleEndian, []float64{1, 2, 3, 4, 5, 6})), data)
+	require.Zero(t, (len(data)-6*8)%64)
+
+	buf.Reset()
+	require.NoError(t, WriteNpy(buf, []int{3}, []float64{1, 2, 3}))
+	require.Contains(t, buf.String(), "'shape': (3,)")
+	shape, values, err := ReadNpy(buf)
+	require.NoError(t, err)
+	require.Equal(t, []int{3}, shape)
+	require.Equal(t, []float64{1, 2, 3}, values)
+}
+
+func TestReadNpy(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		shape    []int
+		expected []float64
+		err      bool
+	}{
+		{
+			name:     "float64",
+			data:     npy(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 2), }", encode(binary.LittleEndian, []float64{1, 2, 3, 4})),
+			shape:    []int{2, 2},
+			expected: []float64{1, 2, 3, 4},
+		},
+		{
+			name:     "float32",
+			data:     npy(1, "{'descr': '<f4', 'fortran_order': False, 'shape': (3,), }", encode(binary.LittleEndian, []float32{1, 0.5, -2})),
+			shape:    []int{3},
+			expected: []float64{1, 0.5, -2},
+		},
+		{
+			name:     "big-endian int32",
+			data:     npy(1, "{'descr': '>i4', 'fortran_order': False, 'shape': (1, 3), }", encode(binary.BigEndian, []int32{-1, 2, 300})),
+			shape:    []int{1, 3},
+			expected: []float64{-1, 2, 300},
+		},
+		{
+			name:     "int64 fortran order",
+			data:     npy(1, "{'descr': '<i8', 'fortran_order': True, 'shape': (2, 3), }", encode(binary.LittleEndian, []int64{1, 4, 2, 5, 3, 6})),
+			shape:    []int{2, 3},
+			expected: []float64{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:     "bool version 2",
+			data:     npy(2, "{'descr': '|b1', 'fortran_order': False, 'shape': (4,), }", []byte{1, 0, 0, 1}),
+			shape:    []int{4},
+			expected: []float64{1, 0, 0, 1},
+		},
+		{
+			name:     "uint8",
+			data:     npy(1, "{'descr': '|u1', 'fortran_order': False, 'shape': (2,), }", []byte{255, 7}),
+			shape:    []int{2},
+			expected: []float64{255, 7},
+		},
+		{
+			name: "unsupported dtype",
+			data: npy(1, "{'descr': '<c16', 'fortran_order': False, 'shape': (1,), }", make([]byte, 16)),
+			err:  true,
+		},
+		{
+			name: "truncated data",
+			data: npy(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (2,), }", make([]byte, 8)),
+			err:  true,
+		},
+		{
+			name: "zero size",
+			data: npy(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (0, 2), }", nil),
+			err:  true,
+		},
+		{name: "wrong magic", data: []byte("NUMPY\x01\x00\x00\x00"), err: true},
+		{name: "unsupported version", data: npy(4, "{}", nil), err: true},
+		{name: "no shape", data: npy(1, "{'descr': '<f8', 'fortran_order': False}", nil), err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shape, values, err := ReadNpy(bytes.NewReader(test.data))
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.shape, shape)
+			require.Equal(t, test.expected, values)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Package random provides sampling of float arrays from common distributions shared by matrix and vector packages.
+// All functions draw values from explicitly given generator, so results are reproducible with seeded generator.
+package random
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// TruncBound is count of standard deviations from the mean beyond which values of truncated normal distribution are
+// redrawn (as tf.random.truncated_normal does).
+const TruncBound = 2.0
+
+// Check return error if generator is not provided or values count is not positive.
+func Check(rng *rand.Rand, size int) error {
+	if rng == nil {
+		return fmt.Errorf("no random generator provided: %v", rng)
+	} else if size < 1 {
+		return fmt.Errorf("negative or zero values count: %d", size)
+	}
+	return nil
+}
+
+// Normal return <size> values of normal distribution with given mean and standard deviation.
+func Normal(rng *rand.Rand, size int, mean, std float64) ([]float64, error) {
+	if err := Check(rng, size); err != nil {
+		return nil, err
+	} else if std < 0 {
+		return nil, fmt.Errorf("negative standard deviation: %v", std)
+	}
+
+	values := make([]float64, size)
+	for i := range values {
+		values[i] = mean + rng.NormFloat64()*std
+	}
+	return values, nil
+}
+
+// TruncNormal return <size> values of normal distribution with given mean and standard deviation, values farther than
+// TruncBound standard deviations from the mean are redrawn.
+func TruncNormal(rng *rand.Rand, size int, mean, std float64) ([]float64, error) {
+	if err := Check(rng, size); err != nil {
+		return nil, err
+	} else if std < 0 {
+		return nil, fmt.Errorf("negative standard deviation: %v", std)
+	}
+
+	values := make([]float64, size)
+	for i := range values {
+		value := rng.NormFloat64()
+		for value < -TruncBound || value > TruncBound {
+			value = rng.NormFloat64()
+		}
+		values[i] = mean + value*std
+	}
+	return values, nil
+}
+
+// Uniform return <size> values of uniform distribution on [low; high).
+func Uniform(rng *rand.Rand, size int, low, high float64) ([]float64, error) {
+	if err := Check(rng, size); err != nil {
+		return nil, err
+	} else if low > high {
+		return nil, fmt.Errorf("low bound is greater than high bound: %v > %v", low, high)
+	}
+
+	values := make([]float64, size)
+	for i := range values {
+		values[i] = low + rng.Float64()*(high-low)
+	}
+	return values, nil
+}
+
+// Bernoulli return <size> values equal to 1 with probability <p> and to 0 otherwise.
+func Bernoulli(rng *rand.Rand, size int, p float64) ([]float64, error) {
+	if err := Check(rng, size); err != nil {
+		return nil, err
+	} else if p < 0 || p > 1 {
+		return nil, fmt.Errorf("probability must be in [0; 1]: %v", p)
+	}
+
+	values := make([]float64, size)
+	for i := range values {
+		if rng.Float64() < p {
+			values[i] = 1
+		}
+	}
+	return values, nil
+}
+
+// Permutation return random permutation of [0; n).
+func Permutation(rng *rand.Rand, n int) ([]int, error) {
+	if err := Check(rng, n); err != nil {
+		return nil, err
+	}
+	return rng.Perm(n), nil
+}
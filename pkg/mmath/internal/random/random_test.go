@@ -0,0 +1,92 @@
+package random
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func moments(values []float64) (mean, std float64) {
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	for _, value := range values {
+		std += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(values)))
+}
+
+func TestDistributions(t *testing.T) {
+	const size = 20000
+	tests := []struct {
+		name     string
+		sample   func(rng *rand.Rand) ([]float64, error)
+		mean     float64
+		std      float64
+		min, max float64
+	}{
+		{
+			name:   "normal",
+			sample: func(rng *rand.Rand) ([]float64, error) { return Normal(rng, size, 3, 2) },
+			mean:   3, std: 2, min: math.Inf(-1), max: math.Inf(1),
+		},
+		{
+			// variance of standard normal truncated at ±2 is 1 - 4φ(2)/(2Φ(2)-1) ≈ 0.774
+			name:   "truncated normal",
+			sample: func(rng *rand.Rand) ([]float64, error) { return TruncNormal(rng, size, -1, 0.5) },
+			mean:   -1, std: 0.5 * math.Sqrt(0.774), min: -2, max: 0,
+		},
+		{
+			name:   "uniform",
+			sample: func(rng *rand.Rand) ([]float64, error) { return Uniform(rng, size, -1, 3) },
+			mean:   1, std: 4 / math.Sqrt(12), min: -1, max: 3,
+		},
+		{
+			name:   "bernoulli",
+			sample: func(rng *rand.Rand) ([]float64, error) { return Bernoulli(rng, size, 0.2) },
+			mean:   0.2, std: 0.4, min: 0, max: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := test.sample(rand.New(rand.NewSource(1)))
+			require.NoError(t, err)
+			require.Len(t, values, size)
+			mean, std := moments(values)
+			require.InDelta(t, test.mean, mean, 0.05)
+			require.InDelta(t, test.std, std, 0.05)
+			sort.Float64s(values)
+			require.GreaterOrEqual(t, values[0], test.min)
+			require.LessOrEqual(t, values[size-1], test.max)
+
+			// the same seed gives the same values
+			again, err := test.sample(rand.New(rand.NewSource(1)))
+			require.NoError(t, err)
+			sort.Float64s(again)
+			require.Equal(t, values, again)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for name, err := range map[string]error{
+		"no generator":       func() error { _, err := Normal(nil, 1, 0, 1); return err }(),
+		"zero size":          func() error { _, err := Uniform(rng, 0, 0, 1); return err }(),
+		"negative std":       func() error { _, err := TruncNormal(rng, 1, 0, -1); return err }(),
+		"wrong bounds":       func() error { _, err := Uniform(rng, 1, 1, 0); return err }(),
+		"wrong probability":  func() error { _, err := Bernoulli(rng, 1, 1.5); return err }(),
+		"negative perm size": func() error { _, err := Permutation(rng, -1); return err }(),
+	} {
+		require.Error(t, err, name)
+	}
+
+	perm, err := Permutation(rng, 5)
+	require.NoError(t, err)
+	sort.Ints(perm)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, perm)
+}
@@ -0,0 +1,138 @@
+package matrix
+
+import (
+	"fmt"
+	"nn/pkg/wraperr"
+)
+
+// Greater return 1 if a > b, otherwise 0
+func Greater(a, b float64) float64 {
+	return mask(a > b)
+}
+
+// Less return 1 if a < b, otherwise 0
+func Less(a, b float64) float64 {
+	return mask(a < b)
+}
+
+// Equal return 1 if a == b, otherwise 0
+func Equal(a, b float64) float64 {
+	return mask(a == b)
+}
+
+func mask(condition bool) float64 {
+	if condition {
+		return 1
+	}
+	return 0
+}
+
+// Greater return mask of this Matrix's values greater than values of given Matrix: 1 where condition holds, 0
+// elsewhere. Matrices are broadcast (see ApplyFuncMat).
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 5 |.Greater(| 2 2 |) = | 0 1 |
+//     | 3 2 |                    | 1 0 |
+func (m *Matrix) Greater(matrix *Matrix) (*Matrix, error) {
+	return m.ApplyFuncMat(matrix, Greater)
+}
+
+// Less return mask of this Matrix's values less than values of given Matrix (see Greater).
+//
+// Throws ErrExec error.
+func (m *Matrix) Less(matrix *Matrix) (*Matrix, error) {
+	return m.ApplyFuncMat(matrix, Less)
+}
+
+// EqualMask return mask of this Matrix's values equal to values of given Matrix (see Greater). It is not named Equal
+// as Equal compares whole matrices.
+//
+// Throws ErrExec error.
+func (m *Matrix) EqualMask(matrix *Matrix) (*Matrix, error) {
+	return m.ApplyFuncMat(matrix, Equal)
+}
+
+// See ApplyFuncNum and Greater
+func (m *Matrix) GreaterNum(number float64) *Matrix {
+	return m.ApplyFuncNum(number, Greater)
+}
+
+// See ApplyFuncNum and Less
+func (m *Matrix) LessNum(number float64) *Matrix {
+	return m.ApplyFuncNum(number, Less)
+}
+
+// See ApplyFuncNum and Equal
+func (m *Matrix) EqualNum(number float64) *Matrix {
+	return m.ApplyFuncNum(number, Equal)
+}
+
+// Where return Matrix of values of <a> where <mask> is non-zero and values of <b> elsewhere. All three matrices are
+// broadcast to common shape (see ApplyFuncMat).
+//
+// Throws ErrExec error.
+//
+// Example:
+//     Where(| 1 0 |, | 1 2 |, | 0 |) = | 1 0 |
+//           | 0 1 |  | 3 4 |  | 9 |    | 9 4 |
+func Where(mask, a, b *Matrix) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if mask == nil {
+		return nil, fmt.Errorf("no mask provided: %v", mask)
+	} else if a == nil || b == nil {
+		return nil, fmt.Errorf("no values provided: %v, %v", a, b)
+	}
+
+	rows, cols, err := mask.broadcastShape(a)
+	if err == nil {
+		rows, cols, err = wrap(rows, cols, nil).broadcastShape(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("matrix shapes are not broadcastable: %dx%d, %dx%d and %dx%d",
+			mask.rows, mask.cols, a.rows, a.cols, b.rows, b.cols)
+	}
+
+	maskRow, maskCol := mask.broadcastStrides()
+	aRow, aCol := a.broadcastStrides()
+	bRow, bCol := b.broadcastStrides()
+	values := make([]float64, rows*cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if mask.values[i*maskRow+j*maskCol] != 0 {
+				values[i*cols+j] = a.values[i*aRow+j*aCol]
+			} else {
+				values[i*cols+j] = b.values[i*bRow+j*bCol]
+			}
+		}
+	}
+	return wrap(rows, cols, values), nil
+}
+
+// Clip return Matrix of this Matrix's values limited to [min; max].
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | -3 0.5 |.Clip(-1, 1) = | -1 0.5 |
+//     |  2  -1 |               |  1  -1 |
+func (m *Matrix) Clip(min, max float64) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if min > max {
+		return nil, fmt.Errorf("min is greater than max for clipping: %v > %v", min, max)
+	}
+
+	return m.ApplyFunc(func(a float64) float64 {
+		if a < min {
+			return min
+		} else if a > max {
+			return max
+		}
+		return a
+	}), nil
+}
@@ -0,0 +1,132 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMatrix_Compare(t *testing.T) {
+	a := matrixInput{in: []float64{1, 5, 3, 2}, rows: 2, cols: 2}
+	tests := []struct {
+		testBase
+		a, b    matrixInput
+		compare func(a, b *Matrix) (*Matrix, error)
+	}{
+		{
+			testBase: testBase{name: "greater", expected: []float64{0, 1, 1, 0}},
+			a:        a,
+			b:        matrixInput{in: []float64{2, 2, 2, 2}, rows: 2, cols: 2},
+			compare:  (*Matrix).Greater,
+		},
+		{
+			testBase: testBase{name: "less row", expected: []float64{1, 0, 0, 1}},
+			a:        a,
+			b:        matrixInput{in: []float64{3, 4}, rows: 1, cols: 2},
+			compare:  (*Matrix).Less,
+		},
+		{
+			testBase: testBase{name: "equal col", expected: []float64{1, 0, 0, 1}},
+			a:        a,
+			b:        matrixInput{in: []float64{1, 2}, rows: 2, cols: 1},
+			compare:  (*Matrix).EqualMask,
+		},
+		{
+			testBase: testBase{name: "shapes mismatch", err: ErrExec},
+			a:        a,
+			b:        matrixInput{in: []float64{1, 2, 3}, rows: 1, cols: 3},
+			compare:  (*Matrix).Greater,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := test.compare(newMatrix(t, test.a), newMatrix(t, test.b))
+			makeAssertions(t, matrixInput{in: test.expected, rows: test.a.rows, cols: test.a.cols}, test.err, err, res)
+		})
+	}
+}
+
+func TestMatrix_CompareNum(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{-1, 0, 2, 0}, rows: 2, cols: 2})
+	tests := []struct {
+		testBase
+		res *Matrix
+	}{
+		{testBase: testBase{name: "greater", expected: []float64{0, 0, 1, 0}}, res: m.GreaterNum(0)},
+		{testBase: testBase{name: "less", expected: []float64{1, 0, 0, 0}}, res: m.LessNum(0)},
+		{testBase: testBase{name: "equal", expected: []float64{0, 1, 0, 1}}, res: m.EqualNum(0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.res.RawFlat())
+		})
+	}
+}
+
+func TestWhere(t *testing.T) {
+	mask := matrixInput{in: []float64{1, 0, 0, 1}, rows: 2, cols: 2}
+	tests := []struct {
+		testBase
+		mask, a, b matrixInput
+		rows, cols int
+	}{
+		{
+			testBase: testBase{name: "same shapes", expected: []float64{1, 6, 7, 4}},
+			mask:     mask,
+			a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{5, 6, 7, 8}, rows: 2, cols: 2},
+			rows:     2, cols: 2,
+		},
+		{
+			testBase: testBase{name: "broadcast values", expected: []float64{1, 0, 0, 4}},
+			mask:     mask,
+			a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{0}, rows: 1, cols: 1},
+			rows:     2, cols: 2,
+		},
+		{
+			testBase: testBase{name: "broadcast mask", expected: []float64{1, 6, 3, 8}},
+			mask:     matrixInput{in: []float64{1, 0}, rows: 1, cols: 2},
+			a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{5, 6, 7, 8}, rows: 2, cols: 2},
+			rows:     2, cols: 2,
+		},
+		{
+			testBase: testBase{name: "shapes mismatch", err: ErrExec},
+			mask:     mask,
+			a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{1, 2, 3}, rows: 3, cols: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := Where(newMatrix(t, test.mask), newMatrix(t, test.a), newMatrix(t, test.b))
+			makeAssertions(t, matrixInput{in: test.expected, rows: test.rows, cols: test.cols}, test.err, err, res)
+		})
+	}
+
+	_, err := Where(nil, newMatrix(t, mask), newMatrix(t, mask))
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_Clip(t *testing.T) {
+	m := matrixInput{in: []float64{-3, 0.5, 2, -1}, rows: 2, cols: 2}
+	tests := []struct {
+		testBase
+		min, max float64
+	}{
+		{testBase: testBase{name: "both bounds", expected: []float64{-1, 0.5, 1, -1}}, min: -1, max: 1},
+		{testBase: testBase{name: "no changes", expected: []float64{-3, 0.5, 2, -1}}, min: -5, max: 5},
+		{testBase: testBase{name: "single value", expected: []float64{0, 0, 0, 0}}, min: 0, max: 0},
+		{testBase: testBase{name: "min greater than max", err: ErrExec}, min: 1, max: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := newMatrix(t, m).Clip(test.min, test.max)
+			makeAssertions(t, matrixInput{in: test.expected, rows: m.rows, cols: m.cols}, test.err, err, res)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package matrix
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"nn/pkg/mmath/internal/codec"
+	"nn/pkg/wraperr"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Matrix)(nil)
+	_ encoding.BinaryUnmarshaler = (*Matrix)(nil)
+	_ json.Marshaler             = (*Matrix)(nil)
+	_ json.Unmarshaler           = (*Matrix)(nil)
+)
+
+// MarshalBinary return Matrix encoded as little-endian header with shape and dtype followed by float64 values.
+//
+// Throws ErrExec error.
+func (m *Matrix) MarshalBinary() (data []byte, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+	return codec.EncodeBinary([]int{m.rows, m.cols}, m.values, codec.Float64)
+}
+
+// UnmarshalBinary replaces Matrix with one decoded from MarshalBinary result, float32 values are accepted too.
+//
+// Throws ErrCreate error.
+func (m *Matrix) UnmarshalBinary(data []byte) (err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if m == nil {
+		return ErrNil
+	}
+	shape, values, err := codec.DecodeBinary(data)
+	if err != nil {
+		return err
+	} else if len(shape) != 2 {
+		return fmt.Errorf("encoded array is not matrix: shape %v", shape)
+	}
+	*m = *wrap(shape[0], shape[1], values)
+	return nil
+}
+
+// MarshalJSON return Matrix encoded as JSON array of rows.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 3 |.MarshalJSON() = `[[1,2,3],[4,5,6]]`
+//     | 4 5 6 |
+func (m *Matrix) MarshalJSON() (data []byte, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return []byte("null"), nil
+	}
+	rows := make([][]float64, m.rows)
+	for i := range rows {
+		rows[i] = m.row(i)
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON replaces Matrix with one decoded from JSON array of rows (see MarshalJSON).
+//
+// Throws ErrCreate error.
+func (m *Matrix) UnmarshalJSON(data []byte) (err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if m == nil {
+		return ErrNil
+	}
+	var rows [][]float64
+	if err = json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+	matrix, err := NewMatrixRaw(rows)
+	if err != nil {
+		return err
+	}
+	*m = *matrix
+	return nil
+}
+
+// WriteNpy writes Matrix to w in NumPy .npy format as 2-dimensional float64 array.
+//
+// Throws ErrExec error.
+func (m *Matrix) WriteNpy(w io.Writer) (err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return ErrNil
+	} else if w == nil {
+		return fmt.Errorf("no writer provided: %v", w)
+	}
+	return codec.WriteNpy(w, []int{m.rows, m.cols}, m.values)
+}
+
+// ReadNpy creates Matrix from 2-dimensional array read from r in NumPy .npy format. Float, integer and bool arrays
+// in any byte order and in C or Fortran order are supported.
+//
+// Throws ErrCreate error.
+func ReadNpy(r io.Reader) (m *Matrix, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if r == nil {
+		return nil, fmt.Errorf("no reader provided: %v", r)
+	}
+	shape, values, err := codec.ReadNpy(r)
+	if err != nil {
+		return nil, err
+	} else if len(shape) != 2 {
+		return nil, fmt.Errorf("npy array is not matrix: shape %v", shape)
+	}
+	return wrap(shape[0], shape[1], values), nil
+}
@@ -0,0 +1,88 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"math"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+func TestMatrix_Binary(t *testing.T) {
+	tests := []struct {
+		name string
+		in   matrixInput
+	}{
+		{name: "2x3", in: matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3}},
+		{name: "1x1", in: matrixInput{in: []float64{-0.5}, rows: 1, cols: 1}},
+		{name: "special values", in: matrixInput{in: []float64{math.Inf(1), math.MaxFloat64, math.SmallestNonzeroFloat64}, rows: 3, cols: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMatrix(t, test.in)
+			data, err := m.MarshalBinary()
+			require.NoError(t, err)
+
+			decoded := &Matrix{}
+			require.NoError(t, decoded.UnmarshalBinary(data))
+			require.True(t, m.Equal(decoded))
+		})
+	}
+
+	vec, err := vector.NewVector([]float64{1, 2})
+	require.NoError(t, err)
+	data, err := vec.MarshalBinary()
+	require.NoError(t, err)
+	require.ErrorIs(t, (&Matrix{}).UnmarshalBinary(data), ErrCreate)
+	require.ErrorIs(t, (&Matrix{}).UnmarshalBinary(data[:5]), ErrCreate)
+	_, err = (*Matrix)(nil).MarshalBinary()
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_JSON(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{1, 2.5, 3, 4, 5, 6}, rows: 2, cols: 3})
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, `[[1,2.5,3],[4,5,6]]`, string(data))
+
+	decoded := &Matrix{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.True(t, m.Equal(decoded))
+
+	// matrix is serialized as field of struct
+	checkpoint := struct {
+		Weight *Matrix `json:"weight"`
+		Bias   *Matrix `json:"bias"`
+	}{Weight: m}
+	data, err = json.Marshal(checkpoint)
+	require.NoError(t, err)
+	require.Equal(t, `{"weight":[[1,2.5,3],[4,5,6]],"bias":null}`, string(data))
+
+	for _, in := range []string{`[[1,2],[3]]`, `[]`, `[[]]`, `{"rows":2}`, `[[1,"a"]]`} {
+		require.ErrorIs(t, (&Matrix{}).UnmarshalJSON([]byte(in)), ErrCreate, in)
+	}
+	_, err = newMatrix(t, matrixInput{in: []float64{math.NaN()}, rows: 1, cols: 1}).MarshalJSON()
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_Npy(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 3, cols: 2})
+	buf := new(bytes.Buffer)
+	require.NoError(t, m.WriteNpy(buf))
+	require.Contains(t, buf.String(), "'shape': (3, 2)")
+
+	decoded, err := ReadNpy(buf)
+	require.NoError(t, err)
+	require.True(t, m.Equal(decoded))
+
+	vec, err := vector.NewVector([]float64{1, 2})
+	require.NoError(t, err)
+	require.NoError(t, vec.WriteNpy(buf))
+	_, err = ReadNpy(buf)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = ReadNpy(bytes.NewReader([]byte("not npy")))
+	require.ErrorIs(t, err, ErrCreate)
+	require.ErrorIs(t, (*Matrix)(nil).WriteNpy(buf), ErrExec)
+}
@@ -7,4 +7,6 @@ var (
 	ErrNotFound = errors.New("can not find value in matrix")
 	ErrExec     = errors.New("can not perform operation to matrix")
 	ErrNil      = errors.New("calling nil matrix")
+	// ErrSingular is cause of ErrExec returned by linear algebra operations on singular or ill-conditioned matrix
+	ErrSingular = errors.New("matrix is singular or ill-conditioned")
 )
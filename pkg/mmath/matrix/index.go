@@ -0,0 +1,216 @@
+package matrix
+
+import (
+	"fmt"
+	"nn/pkg/wraperr"
+	"sort"
+)
+
+// lines return count of lines of given axis (rows for Horizontal, cols for Vertical), their size and function
+// returning index of j'th value of i'th line in Matrix's values.
+func (m *Matrix) lines(axis Axis) (count, size int, at func(i, j int) int, err error) {
+	switch axis {
+	case Horizontal:
+		return m.rows, m.cols, func(i, j int) int { return i*m.cols + j }, nil
+	case Vertical:
+		return m.cols, m.rows, func(i, j int) int { return j*m.cols + i }, nil
+	default:
+		return 0, 0, nil, fmt.Errorf("unknown axis: %d", axis)
+	}
+}
+
+// ArgMax return indices of max values in given axis: col index for each row (Horizontal) or row index for each col
+// (Vertical). The first index is returned for equal values.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 5 3 |.ArgMax(Horizontal) = [1 0]
+//     | 6 2 6 |
+//
+//     | 1 5 3 |.ArgMax(Vertical) = [1 0 1]
+//     | 6 2 6 |
+func (m *Matrix) ArgMax(axis Axis) ([]int, error) {
+	return m.argBest(axis, Greater)
+}
+
+// ArgMin return indices of min values in given axis (see ArgMax).
+//
+// Throws ErrExec error.
+func (m *Matrix) ArgMin(axis Axis) ([]int, error) {
+	return m.argBest(axis, Less)
+}
+
+// argBest return indices of values of each line in given axis which are better than all previous ones by <better>.
+func (m *Matrix) argBest(axis Axis, better BinaryOperation) (indices []int, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+	count, size, at, err := m.lines(axis)
+	if err != nil {
+		return nil, err
+	}
+
+	indices = make([]int, count)
+	for i := range indices {
+		for j := 1; j < size; j++ {
+			if better(m.values[at(i, j)], m.values[at(i, indices[i])]) != 0 {
+				indices[i] = j
+			}
+		}
+	}
+	return indices, nil
+}
+
+// TopK return k greatest values of each line in given axis in descending order and their indices: for Horizontal
+// axis values are rows x k Matrix and indices are indexed by row, for Vertical axis values are k x cols Matrix and
+// indices are indexed by col. Equal values keep their order.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 5 3 |.TopK(2, Horizontal) = | 5 3 |, [[1 2] [0 2]]
+//     | 6 2 6 |                       | 6 6 |
+func (m *Matrix) TopK(k int, axis Axis) (mat *Matrix, indices [][]int, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, nil, ErrNil
+	}
+	count, size, at, err := m.lines(axis)
+	if err != nil {
+		return nil, nil, err
+	} else if k < 1 || k > size {
+		return nil, nil, fmt.Errorf("k must be in [1; %d]: %d", size, k)
+	}
+
+	if axis == Horizontal {
+		mat = wrap(count, k, make([]float64, count*k))
+	} else {
+		mat = wrap(k, count, make([]float64, k*count))
+	}
+	_, _, atRes, _ := mat.lines(axis)
+
+	indices = make([][]int, count)
+	for i := range indices {
+		order := make([]int, size)
+		for j := range order {
+			order[j] = j
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return m.values[at(i, order[a])] > m.values[at(i, order[b])]
+		})
+		indices[i] = order[:k:k]
+		for j, index := range indices[i] {
+			mat.values[atRes(i, j)] = m.values[at(i, index)]
+		}
+	}
+	return mat, indices, nil
+}
+
+// Gather return Matrix of lines of given axis with given indices: cols (Horizontal) or rows (Vertical). Indices may
+// repeat, e.g. to look up rows of embedding table by category codes.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 |.Gather(Vertical, [2 0 2]) = | 5 6 |
+//     | 3 4 |                             | 1 2 |
+//     | 5 6 |                             | 5 6 |
+func (m *Matrix) Gather(axis Axis, indices []int) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if len(indices) < 1 {
+		return nil, fmt.Errorf("no indices provided for gathering: %v", indices)
+	}
+	if err = m.checkIndices(axis, indices); err != nil {
+		return nil, err
+	}
+
+	if axis == Vertical {
+		values := make([]float64, 0, len(indices)*m.cols)
+		for _, index := range indices {
+			values = append(values, m.row(index)...)
+		}
+		return wrap(len(indices), m.cols, values), nil
+	}
+
+	values := make([]float64, 0, m.rows*len(indices))
+	for i := 0; i < m.rows; i++ {
+		row := m.row(i)
+		for _, index := range indices {
+			values = append(values, row[index])
+		}
+	}
+	return wrap(m.rows, len(indices), values), nil
+}
+
+// Scatter is reverse of Gather: it return Matrix with <size> lines of given axis, where each line of this Matrix is
+// added to line with corresponding index, so lines with repeated indices are summed (as gradients of Gather are).
+// Lines with no index are zero.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 |.Scatter(Vertical, [2 0 2], 3) = |  3  4 |
+//     | 3 4 |                                 |  0  0 |
+//     | 5 6 |                                 |  6  8 |
+func (m *Matrix) Scatter(axis Axis, indices []int, size int) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if size < 1 {
+		return nil, fmt.Errorf("negative or zero size for scattering: %d", size)
+	}
+
+	if axis == Vertical {
+		mat = wrap(size, m.cols, make([]float64, size*m.cols))
+	} else {
+		mat = wrap(m.rows, size, make([]float64, m.rows*size))
+	}
+	if err = mat.checkIndices(axis, indices); err != nil {
+		return nil, err
+	}
+	if _, size, _, _ := m.lines(axis); len(indices) != size {
+		return nil, fmt.Errorf("wrong indices count for scattering matrix %dx%d: %d != %d",
+			m.rows, m.cols, len(indices), size)
+	}
+
+	if axis == Vertical {
+		for i, index := range indices {
+			row := mat.row(index)
+			for j, value := range m.row(i) {
+				row[j] += value
+			}
+		}
+		return mat, nil
+	}
+
+	for i := 0; i < m.rows; i++ {
+		row := mat.row(i)
+		for j, value := range m.row(i) {
+			row[indices[j]] += value
+		}
+	}
+	return mat, nil
+}
+
+// checkIndices return error if axis is unknown or indices are out of range of given axis: [0; cols) for Horizontal
+// and [0; rows) for Vertical.
+func (m *Matrix) checkIndices(axis Axis, indices []int) error {
+	_, size, _, err := m.lines(axis)
+	if err != nil {
+		return err
+	}
+	for _, index := range indices {
+		if index < 0 || index >= size {
+			return fmt.Errorf("index out of range [0; %d) of matrix %dx%d: %d", size, m.rows, m.cols, index)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMatrix_ArgMax(t *testing.T) {
+	m := matrixInput{in: []float64{1, 5, 3, 6, 2, 6}, rows: 2, cols: 3}
+	tests := []struct {
+		name     string
+		arg      func(m *Matrix) ([]int, error)
+		expected []int
+		err      error
+	}{
+		{name: "max horizontal", arg: func(m *Matrix) ([]int, error) { return m.ArgMax(Horizontal) }, expected: []int{1, 0}},
+		{name: "max vertical", arg: func(m *Matrix) ([]int, error) { return m.ArgMax(Vertical) }, expected: []int{1, 0, 1}},
+		{name: "min horizontal", arg: func(m *Matrix) ([]int, error) { return m.ArgMin(Horizontal) }, expected: []int{0, 1}},
+		{name: "min vertical", arg: func(m *Matrix) ([]int, error) { return m.ArgMin(Vertical) }, expected: []int{0, 1, 0}},
+		{name: "unknown axis", arg: func(m *Matrix) ([]int, error) { return m.ArgMax(Axis(5)) }, err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indices, err := test.arg(newMatrix(t, m))
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, indices)
+		})
+	}
+}
+
+func TestMatrix_TopK(t *testing.T) {
+	m := matrixInput{in: []float64{1, 5, 3, 6, 2, 6}, rows: 2, cols: 3}
+	tests := []struct {
+		testBase
+		k          int
+		axis       Axis
+		rows, cols int
+		indices    [][]int
+	}{
+		{
+			testBase: testBase{name: "horizontal", expected: []float64{5, 3, 6, 6}},
+			k:        2, axis: Horizontal,
+			rows: 2, cols: 2,
+			indices: [][]int{{1, 2}, {0, 2}},
+		},
+		{
+			testBase: testBase{name: "vertical", expected: []float64{6, 5, 6}},
+			k:        1, axis: Vertical,
+			rows: 1, cols: 3,
+			indices: [][]int{{1}, {0}, {1}},
+		},
+		{
+			testBase: testBase{name: "whole rows", expected: []float64{5, 3, 1, 6, 6, 2}},
+			k:        3, axis: Horizontal,
+			rows: 2, cols: 3,
+			indices: [][]int{{1, 2, 0}, {0, 2, 1}},
+		},
+		{testBase: testBase{name: "too big k", err: ErrExec}, k: 3, axis: Vertical},
+		{testBase: testBase{name: "zero k", err: ErrExec}, k: 0, axis: Horizontal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, indices, err := newMatrix(t, m).TopK(test.k, test.axis)
+			makeAssertions(t, matrixInput{in: test.expected, rows: test.rows, cols: test.cols}, test.err, err, res)
+			require.Equal(t, test.indices, indices)
+		})
+	}
+}
+
+func TestMatrix_Gather(t *testing.T) {
+	m := matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 3, cols: 2}
+	tests := []struct {
+		testBase
+		axis       Axis
+		indices    []int
+		rows, cols int
+	}{
+		{
+			testBase: testBase{name: "rows", expected: []float64{5, 6, 1, 2, 5, 6}},
+			axis:     Vertical, indices: []int{2, 0, 2},
+			rows: 3, cols: 2,
+		},
+		{
+			testBase: testBase{name: "cols", expected: []float64{2, 2, 4, 4, 6, 6}},
+			axis:     Horizontal, indices: []int{1, 1},
+			rows: 3, cols: 2,
+		},
+		{
+			testBase: testBase{name: "single row", expected: []float64{3, 4}},
+			axis:     Vertical, indices: []int{1},
+			rows: 1, cols: 2,
+		},
+		{testBase: testBase{name: "out of range", err: ErrExec}, axis: Horizontal, indices: []int{2}},
+		{testBase: testBase{name: "negative", err: ErrExec}, axis: Vertical, indices: []int{-1}},
+		{testBase: testBase{name: "no indices", err: ErrExec}, axis: Vertical},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := newMatrix(t, m).Gather(test.axis, test.indices)
+			makeAssertions(t, matrixInput{in: test.expected, rows: test.rows, cols: test.cols}, test.err, err, res)
+		})
+	}
+}
+
+func TestMatrix_Scatter(t *testing.T) {
+	m := matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 3, cols: 2}
+	tests := []struct {
+		testBase
+		axis       Axis
+		indices    []int
+		size       int
+		rows, cols int
+	}{
+		{
+			testBase: testBase{name: "rows", expected: []float64{3, 4, 0, 0, 6, 8}},
+			axis:     Vertical, indices: []int{2, 0, 2}, size: 3,
+			rows: 3, cols: 2,
+		},
+		{
+			testBase: testBase{name: "cols", expected: []float64{0, 2, 1, 0, 4, 3, 0, 6, 5}},
+			axis:     Horizontal, indices: []int{2, 1}, size: 3,
+			rows: 3, cols: 3,
+		},
+		{testBase: testBase{name: "wrong indices count", err: ErrExec}, axis: Vertical, indices: []int{0}, size: 3},
+		{testBase: testBase{name: "out of range", err: ErrExec}, axis: Vertical, indices: []int{0, 1, 2}, size: 2},
+		{testBase: testBase{name: "zero size", err: ErrExec}, axis: Horizontal, indices: []int{0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := newMatrix(t, m).Scatter(test.axis, test.indices, test.size)
+			makeAssertions(t, matrixInput{in: test.expected, rows: test.rows, cols: test.cols}, test.err, err, res)
+		})
+	}
+
+	// scatter of gathered rows sums their values
+	gathered, err := newMatrix(t, m).Gather(Vertical, []int{1, 1})
+	require.NoError(t, err)
+	scattered, err := gathered.Scatter(Vertical, []int{1, 1}, 3)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0, 0, 6, 8, 0, 0}, scattered.RawFlat())
+}
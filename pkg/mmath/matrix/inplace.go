@@ -0,0 +1,278 @@
+package matrix
+
+import (
+	"fmt"
+	"nn/pkg/wraperr"
+)
+
+// In-place operations modify and return this Matrix, "into" operations write result to given destination Matrix
+// and return it. Destination is reused only if it is shaped as the result and does not share values with operands
+// it can not be computed over, otherwise new Matrix is allocated and returned, so result must always be taken from
+// the returned value (the same way as with append):
+//
+//     buf = x.CopyInto(buf)
+//
+// Both are used to avoid allocations on repeated operations with matrices of the same shape, e.g. on training steps.
+
+// ApplyFuncInPlace applies given UnaryOperation to each value of this Matrix and return this Matrix.
+//
+// Example:
+//     | 1 2 |.ApplyFuncInPlace(func(a float64) float64 { return a * a }) = | 1  4 |
+//     | 3 4 |                                                              | 9 16 |
+func (m *Matrix) ApplyFuncInPlace(operation UnaryOperation) *Matrix {
+	if m == nil {
+		return nil
+	}
+	for i, value := range m.values {
+		m.values[i] = operation(value)
+	}
+	return m
+}
+
+// ApplyFuncNumInPlace is in-place variant of ApplyFuncNum.
+func (m *Matrix) ApplyFuncNumInPlace(number float64, operation BinaryOperation) *Matrix {
+	if m == nil {
+		return nil
+	}
+	m.applyInto(m.values, operation, nil, nil, number)
+	return m
+}
+
+// ApplyFuncMatInPlace is in-place variant of ApplyFuncMat, given Matrix may be broadcast to this Matrix's shape only.
+//
+// Throws ErrExec error.
+func (m *Matrix) ApplyFuncMatInPlace(matrix *Matrix, operation BinaryOperation) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+	rows, cols, err := m.broadcastShape(matrix)
+	if err != nil {
+		return nil, err
+	} else if rows != m.rows || cols != m.cols {
+		return nil, fmt.Errorf("can not write result of shape %dx%d to matrix %dx%d", rows, cols, m.rows, m.cols)
+	}
+
+	if shares(m, matrix) && !aliases(m, matrix) {
+		matrix = matrix.Copy()
+	}
+	m.applyBroadcastInto(m.values, matrix, rows, cols, operation)
+	return m, nil
+}
+
+// See ApplyFuncMatInPlace and Add
+func (m *Matrix) AddInPlace(matrix *Matrix) (*Matrix, error) {
+	return m.ApplyFuncMatInPlace(matrix, Add)
+}
+
+// See ApplyFuncMatInPlace and Sub
+func (m *Matrix) SubInPlace(matrix *Matrix) (*Matrix, error) {
+	return m.ApplyFuncMatInPlace(matrix, Sub)
+}
+
+// See ApplyFuncMatInPlace and Mul
+func (m *Matrix) MulInPlace(matrix *Matrix) (*Matrix, error) {
+	return m.ApplyFuncMatInPlace(matrix, Mul)
+}
+
+// See ApplyFuncMatInPlace and Div
+func (m *Matrix) DivInPlace(matrix *Matrix) (*Matrix, error) {
+	return m.ApplyFuncMatInPlace(matrix, Div)
+}
+
+// See ApplyFuncNumInPlace and Add
+func (m *Matrix) AddNumInPlace(number float64) *Matrix {
+	return m.ApplyFuncNumInPlace(number, Add)
+}
+
+// See ApplyFuncNumInPlace and Sub
+func (m *Matrix) SubNumInPlace(number float64) *Matrix {
+	return m.ApplyFuncNumInPlace(number, Sub)
+}
+
+// See ApplyFuncNumInPlace and Mul
+func (m *Matrix) MulNumInPlace(number float64) *Matrix {
+	return m.ApplyFuncNumInPlace(number, Mul)
+}
+
+// See ApplyFuncNumInPlace and Div
+func (m *Matrix) DivNumInPlace(number float64) *Matrix {
+	return m.ApplyFuncNumInPlace(number, Div)
+}
+
+// CopyInto return copy of this Matrix written to <dst> if it is shaped as this Matrix.
+func (m *Matrix) CopyInto(dst *Matrix) *Matrix {
+	if m == nil {
+		return nil
+	}
+	dst = reuse(dst, m.rows, m.cols)
+	copy(dst.values, m.values)
+	return dst
+}
+
+// ApplyFuncInto is "into" variant of ApplyFunc, <dst> may be this Matrix.
+func (m *Matrix) ApplyFuncInto(dst *Matrix, operation UnaryOperation) *Matrix {
+	if m == nil {
+		return nil
+	}
+	if dst = reuse(dst, m.rows, m.cols); shares(dst, m) && !aliases(dst, m) {
+		dst = wrap(m.rows, m.cols, make([]float64, len(m.values)))
+	}
+	for i, value := range m.values {
+		dst.values[i] = operation(value)
+	}
+	return dst
+}
+
+// ApplyFuncNumInto is "into" variant of ApplyFuncNum, <dst> may be this Matrix.
+func (m *Matrix) ApplyFuncNumInto(dst *Matrix, number float64, operation BinaryOperation) *Matrix {
+	if m == nil {
+		return nil
+	}
+	if dst = reuse(dst, m.rows, m.cols); shares(dst, m) && !aliases(dst, m) {
+		dst = wrap(m.rows, m.cols, make([]float64, len(m.values)))
+	}
+	m.applyInto(dst.values, operation, nil, nil, number)
+	return dst
+}
+
+// ApplyFuncMatInto is "into" variant of ApplyFuncMat, <dst> may be one of operands shaped as the result.
+//
+// Throws ErrExec error.
+func (m *Matrix) ApplyFuncMatInto(dst, matrix *Matrix, operation BinaryOperation) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+	return m.applyMatInto(dst, matrix, operation)
+}
+
+// ApplyFuncMatRowInto is "into" variant of ApplyFuncMatRow, <dst> may be this Matrix.
+//
+// Throws ErrExec error.
+func (m *Matrix) ApplyFuncMatRowInto(dst, row *Matrix, operation BinaryOperation) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if err = m.checkRow(row); err != nil {
+		return nil, err
+	}
+	return m.applyMatInto(dst, row, operation)
+}
+
+// SumAxedMInto is "into" variant of SumAxedM.
+//
+// Throws ErrExec error.
+func (m *Matrix) SumAxedMInto(dst *Matrix, axis Axis) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+
+	switch axis {
+	case Horizontal:
+		dst = reuse(dst, m.rows, 1)
+	case Vertical:
+		dst = reuse(dst, 1, m.cols)
+	default:
+		return nil, fmt.Errorf("unknown axis: %d", axis)
+	}
+	if shares(dst, m) {
+		dst = wrap(dst.rows, dst.cols, make([]float64, len(dst.values)))
+	}
+	m.reduceAxedInto(dst.values, axis, Add)
+	return dst, nil
+}
+
+// MatMulInto is "into" variant of MatMul, <dst> is not reused if it shares values with one of operands.
+//
+// Throws ErrExec error.
+func (m *Matrix) MatMulInto(dst, matrix *Matrix) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.cols != matrix.rows {
+		return nil, fmt.Errorf("can't Mul matrices sized %dx%d and %dx%d", m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+
+	dst = m.reuseProduct(dst, matrix, m.rows, matrix.cols)
+	m.matMulInto(matrix, dst.values)
+	return dst, nil
+}
+
+// MatMulTInto is "into" variant of MatMulT, <dst> is not reused if it shares values with one of operands.
+//
+// Throws ErrExec error.
+func (m *Matrix) MatMulTInto(dst, matrix *Matrix) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.cols != matrix.cols {
+		return nil, fmt.Errorf("can't Mul matrix sized %dx%d and transposed %dx%d",
+			m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+
+	dst = m.reuseProduct(dst, matrix, m.rows, matrix.rows)
+	m.matMulTInto(matrix, dst.values)
+	return dst, nil
+}
+
+// TMatMulInto is "into" variant of TMatMul, <dst> is not reused if it shares values with one of operands.
+//
+// Throws ErrExec error.
+func (m *Matrix) TMatMulInto(dst, matrix *Matrix) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.rows != matrix.rows {
+		return nil, fmt.Errorf("can't Mul transposed matrix sized %dx%d and %dx%d",
+			m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+
+	dst = m.reuseProduct(dst, matrix, m.cols, matrix.cols)
+	m.tMatMulInto(matrix, dst.values)
+	return dst, nil
+}
+
+// reuseProduct return zeroed <dst> if it may hold product of this and given Matrix, otherwise new Matrix.
+func (m *Matrix) reuseProduct(dst, matrix *Matrix, rows, cols int) *Matrix {
+	if dst = reuse(dst, rows, cols); shares(dst, m) || shares(dst, matrix) {
+		return wrap(rows, cols, make([]float64, rows*cols))
+	}
+	for i := range dst.values {
+		dst.values[i] = 0
+	}
+	return dst
+}
+
+// reuse return <dst> if it is sized rows x cols, otherwise new zero Matrix.
+func reuse(dst *Matrix, rows, cols int) *Matrix {
+	if dst != nil && dst.rows == rows && dst.cols == cols {
+		return dst
+	}
+	return wrap(rows, cols, make([]float64, rows*cols))
+}
+
+// shares return true if matrices have common values. Matrices made by NewMatrixShared may view overlapping parts of
+// one slice (e.g. over Data), so address ranges of values are compared.
+func shares(a, b *Matrix) bool {
+	return overlaps(a.values, b.values)
+}
+
+// aliases return true if matrices hold the very same values, so element-wise result may be written over operand.
+func aliases(a, b *Matrix) bool {
+	return len(a.values) > 0 && len(a.values) == len(b.values) && &a.values[0] == &b.values[0]
+}
@@ -0,0 +1,318 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestMatrix_ApplyFuncMatInPlace(t *testing.T) {
+	tests := []struct {
+		twoMatrixTest
+		f func(a, b *Matrix) (*Matrix, error)
+	}{
+		{
+			twoMatrixTest: twoMatrixTest{
+				testBase: testBase{name: "add", expected: []float64{6, 8, 10, 12}},
+				a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+				b:        matrixInput{in: []float64{5, 6, 7, 8}, rows: 2, cols: 2},
+			},
+			f: (*Matrix).AddInPlace,
+		},
+		{
+			twoMatrixTest: twoMatrixTest{
+				testBase: testBase{name: "sub", expected: []float64{-4, -4, -4, -4}},
+				a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+				b:        matrixInput{in: []float64{5, 6, 7, 8}, rows: 2, cols: 2},
+			},
+			f: (*Matrix).SubInPlace,
+		},
+		{
+			twoMatrixTest: twoMatrixTest{
+				testBase: testBase{name: "mul", expected: []float64{5, 12, 21, 32}},
+				a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+				b:        matrixInput{in: []float64{5, 6, 7, 8}, rows: 2, cols: 2},
+			},
+			f: (*Matrix).MulInPlace,
+		},
+		{
+			twoMatrixTest: twoMatrixTest{
+				testBase: testBase{name: "div", expected: []float64{0.5, 1, 1.5, 2}},
+				a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+				b:        matrixInput{in: []float64{2, 2, 2, 2}, rows: 2, cols: 2},
+			},
+			f: (*Matrix).DivInPlace,
+		},
+		{
+			twoMatrixTest: twoMatrixTest{
+				testBase: testBase{name: "shape mismatch", err: ErrExec},
+				a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+				b:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 1, cols: 4},
+			},
+			f: (*Matrix).AddInPlace,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := newMatrix(t, test.a)
+			matrix, err := test.f(a, newMatrix(t, test.b))
+			if test.err == nil {
+				require.Same(t, a, matrix)
+				makeAssertions(t, matrixInput{in: test.expected, rows: test.a.rows, cols: test.a.cols}, nil, err, a)
+			} else {
+				require.ErrorIs(t, err, test.err)
+				// matrix is not modified on error
+				makeAssertions(t, test.a, nil, nil, a)
+			}
+		})
+	}
+
+	_, err := newMatrix(t, tests[0].a).AddInPlace(nil)
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_NumInPlace(t *testing.T) {
+	in := matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2}
+	tests := []struct {
+		testBase
+		f func(m *Matrix) *Matrix
+	}{
+		{testBase: testBase{name: "add", expected: []float64{3, 4, 5, 6}}, f: func(m *Matrix) *Matrix { return m.AddNumInPlace(2) }},
+		{testBase: testBase{name: "sub", expected: []float64{-1, 0, 1, 2}}, f: func(m *Matrix) *Matrix { return m.SubNumInPlace(2) }},
+		{testBase: testBase{name: "mul", expected: []float64{2, 4, 6, 8}}, f: func(m *Matrix) *Matrix { return m.MulNumInPlace(2) }},
+		{testBase: testBase{name: "div", expected: []float64{0.5, 1, 1.5, 2}}, f: func(m *Matrix) *Matrix { return m.DivNumInPlace(2) }},
+		{
+			testBase: testBase{name: "func", expected: []float64{1, 4, 9, 16}},
+			f: func(m *Matrix) *Matrix {
+				return m.ApplyFuncInPlace(func(a float64) float64 { return a * a })
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMatrix(t, in)
+			require.Same(t, m, test.f(m))
+			makeAssertions(t, matrixInput{in: test.expected, rows: in.rows, cols: in.cols}, nil, nil, m)
+		})
+	}
+
+	var m *Matrix
+	require.Nil(t, m.MulNumInPlace(2))
+	require.Nil(t, m.ApplyFuncInPlace(func(a float64) float64 { return a }))
+}
+
+func TestMatrix_CopyInto(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3})
+
+	res := m.CopyInto(nil)
+	require.True(t, m.Equal(res))
+	require.NotSame(t, m, res)
+
+	dst := newMatrix(t, matrixInput{in: make([]float64, 6), rows: 2, cols: 3})
+	require.Same(t, dst, m.CopyInto(dst))
+	require.True(t, m.Equal(dst))
+
+	// destination of other shape is not reused
+	other := newMatrix(t, matrixInput{in: make([]float64, 6), rows: 3, cols: 2})
+	res = m.CopyInto(other)
+	require.NotSame(t, other, res)
+	require.True(t, m.Equal(res))
+	require.Equal(t, make([]float64, 6), other.RawFlat())
+}
+
+func TestMatrix_Into(t *testing.T) {
+	a := newMatrix(t, matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3})
+	b := newMatrix(t, matrixInput{in: []float64{6, 5, 4, 3, 2, 1}, rows: 2, cols: 3})
+	row := newMatrix(t, matrixInput{in: []float64{1, 2, 3}, rows: 1, cols: 3})
+	square := newMatrix(t, matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2})
+
+	tests := []struct {
+		name     string
+		dst      *Matrix
+		f        func(dst *Matrix) (*Matrix, error)
+		expected func() (*Matrix, error)
+		reused   bool
+	}{
+		{
+			name:     "apply func",
+			dst:      newMatrix(t, matrixInput{in: make([]float64, 6), rows: 2, cols: 3}),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.ApplyFuncInto(dst, math.Abs), nil },
+			expected: func() (*Matrix, error) { return a.Abs(), nil },
+			reused:   true,
+		},
+		{
+			name:     "apply func num",
+			dst:      newMatrix(t, matrixInput{in: make([]float64, 6), rows: 2, cols: 3}),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.ApplyFuncNumInto(dst, 2, Mul), nil },
+			expected: func() (*Matrix, error) { return a.MulNum(2), nil },
+			reused:   true,
+		},
+		{
+			name:     "apply func mat to operand",
+			dst:      b.Copy(),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.ApplyFuncMatInto(dst, dst, Sub) },
+			expected: func() (*Matrix, error) { return a.Sub(b) },
+			reused:   true,
+		},
+		{
+			name:     "apply func mat row",
+			dst:      newMatrix(t, matrixInput{in: make([]float64, 6), rows: 2, cols: 3}),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.ApplyFuncMatRowInto(dst, row, Add) },
+			expected: func() (*Matrix, error) { return a.AddRowM(row) },
+			reused:   true,
+		},
+		{
+			name:     "sum axed",
+			dst:      newMatrix(t, matrixInput{in: make([]float64, 3), rows: 1, cols: 3}),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.SumAxedMInto(dst, Vertical) },
+			expected: func() (*Matrix, error) { return a.SumAxedM(Vertical) },
+			reused:   true,
+		},
+		{
+			name:     "matmul",
+			dst:      newMatrix(t, matrixInput{in: []float64{1, 1, 1, 1, 1, 1}, rows: 2, cols: 3}),
+			f:        func(dst *Matrix) (*Matrix, error) { return square.MatMulInto(dst, a) },
+			expected: func() (*Matrix, error) { return square.MatMul(a) },
+			reused:   true,
+		},
+		{
+			name:     "matmul transposed",
+			dst:      newMatrix(t, matrixInput{in: []float64{1, 1, 1, 1}, rows: 2, cols: 2}),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.MatMulTInto(dst, b) },
+			expected: func() (*Matrix, error) { return a.MatMulT(b) },
+			reused:   true,
+		},
+		{
+			name:     "transposed matmul",
+			dst:      newMatrix(t, matrixInput{in: make([]float64, 9), rows: 3, cols: 3}).AddNumInPlace(1),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.TMatMulInto(dst, b) },
+			expected: func() (*Matrix, error) { return a.TMatMul(b) },
+			reused:   true,
+		},
+		{
+			name:     "matmul to operand",
+			dst:      square,
+			f:        func(dst *Matrix) (*Matrix, error) { return square.MatMulInto(dst, square) },
+			expected: func() (*Matrix, error) { return square.MatMul(square) },
+		},
+		{
+			name:     "matmul to other shape",
+			dst:      a.Copy(),
+			f:        func(dst *Matrix) (*Matrix, error) { return a.MatMulTInto(dst, b) },
+			expected: func() (*Matrix, error) { return a.MatMulT(b) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected, err := test.expected()
+			require.NoError(t, err)
+			before := test.dst.Copy()
+
+			res, err := test.f(test.dst)
+			require.NoError(t, err)
+			require.True(t, expected.Equal(res))
+			if test.reused {
+				require.Same(t, test.dst, res)
+			} else {
+				require.NotSame(t, test.dst, res)
+				require.True(t, before.Equal(test.dst))
+			}
+		})
+	}
+
+	_, err := a.MatMulInto(nil, b)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = a.ApplyFuncMatInto(nil, square, Add)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = a.SumAxedMInto(nil, Axis(5))
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_IntoOverlapping(t *testing.T) {
+	newViews := func() (a, b, c *Matrix) {
+		values := []float64{1, -2, 3, -4, 5, -6, 7, -8}
+		var err error
+		a, err = NewMatrixShared(2, 3, values[:6])
+		require.NoError(t, err)
+		b, err = NewMatrixShared(2, 3, values[2:])
+		require.NoError(t, err)
+		c, err = NewMatrixShared(2, 2, values[4:])
+		require.NoError(t, err)
+		return a, b, c
+	}
+
+	a, b, c := newViews()
+	require.True(t, shares(a, b))
+	require.True(t, shares(a, c))
+	require.False(t, shares(c, newMatrix(t, matrixInput{in: []float64{1, 2}, rows: 1, cols: 2})))
+	tail, err := NewMatrixShared(1, 2, b.Data()[4:])
+	require.NoError(t, err)
+	require.False(t, shares(a, tail))
+
+	tests := []struct {
+		name     string
+		f        func(a, b, c *Matrix) (*Matrix, error)
+		expected func(a, b, c *Matrix) (*Matrix, error)
+		reused   bool
+	}{
+		{
+			name:     "apply func",
+			f:        func(a, b, c *Matrix) (*Matrix, error) { return a.ApplyFuncInto(b, math.Abs), nil },
+			expected: func(a, b, c *Matrix) (*Matrix, error) { return a.Abs(), nil },
+		},
+		{
+			name:     "apply func num",
+			f:        func(a, b, c *Matrix) (*Matrix, error) { return a.ApplyFuncNumInto(b, 2, Mul), nil },
+			expected: func(a, b, c *Matrix) (*Matrix, error) { return a.MulNum(2), nil },
+		},
+		{
+			name:     "apply func mat",
+			f:        func(a, b, c *Matrix) (*Matrix, error) { return a.ApplyFuncMatInto(b, b, Sub) },
+			expected: func(a, b, c *Matrix) (*Matrix, error) { return a.Sub(b) },
+		},
+		{
+			name:     "in place",
+			f:        func(a, b, c *Matrix) (*Matrix, error) { return b.AddInPlace(a) },
+			expected: func(a, b, c *Matrix) (*Matrix, error) { return b.Add(a) },
+			reused:   true,
+		},
+		{
+			name:     "matmul transposed",
+			f:        func(a, b, c *Matrix) (*Matrix, error) { return a.MatMulTInto(c, b) },
+			expected: func(a, b, c *Matrix) (*Matrix, error) { return a.MatMulT(b) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b, c := newViews()
+			expected, err := test.expected(a.Copy(), b.Copy(), c.Copy())
+			require.NoError(t, err)
+
+			res, err := test.f(a, b, c)
+			require.NoError(t, err)
+			require.True(t, expected.Equal(res), "%v != %v", expected.RawFlat(), res.RawFlat())
+			if !test.reused {
+				require.Equal(t, []float64{1, -2, 3, -4, 5, -6, 7, -8}, append(a.RawFlat(), b.RawFlat()[4:]...))
+			}
+		})
+	}
+}
+
+func TestMatrix_IntoAllocs(t *testing.T) {
+	a, b := randomMatrix(8, 16), randomMatrix(16, 4)
+	dst, err := a.MatMul(b)
+	require.NoError(t, err)
+	buf := a.Copy()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst, _ = a.MatMulInto(dst, b)
+		buf = a.CopyInto(buf)
+		buf.MulNumInPlace(2)
+		_, _ = buf.AddInPlace(a)
+	})
+	require.Zero(t, allocs)
+}
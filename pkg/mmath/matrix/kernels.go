@@ -0,0 +1,96 @@
+package matrix
+
+import "unsafe"
+
+// float is type of values matrix multiplication kernels are computed on, kernels are shared by Matrix and Matrix32.
+// Operands and result of kernel may be of different types: values are converted to result type before multiplication,
+// so product is summed in result precision.
+type float interface {
+	float32 | float64
+}
+
+// matMulRows writes rows [start; stop) of multiplication of a (sized rows x inner) and b (sized inner x cols) to
+// <values> (zeroed, sized rows x cols). Multiplication is split to blocks of inner size and cols. Each value is
+// summed in ascending order of inner index, the same as in scalar multiplication of row and col.
+func matMulRows[A, B, C float](a []A, b []B, values []C, inner, cols, start, stop int) {
+	for kk := 0; kk < inner; kk += blockSize {
+		kStop := minInt(kk+blockSize, inner)
+		for jj := 0; jj < cols; jj += blockSize {
+			jStop := minInt(jj+blockSize, cols)
+			for i := start; i < stop; i++ {
+				res := values[i*cols+jj : i*cols+jStop]
+				for k, x := range a[i*inner+kk : i*inner+kStop] {
+					cx := C(x)
+					for j, y := range b[(kk+k)*cols+jj : (kk+k)*cols+jStop] {
+						res[j] += cx * C(y)
+					}
+				}
+			}
+		}
+	}
+}
+
+// matMulT writes multiplication of a (sized rows x inner) and transposed b (sized cols x inner) to <values> (sized
+// rows x cols).
+func matMulT[A, B, C float](a []A, b []B, values []C, rows, inner, cols int) {
+	f := func(start, stop int) {
+		for jj := 0; jj < cols; jj += blockSize {
+			jStop := minInt(jj+blockSize, cols)
+			for i := start; i < stop; i++ {
+				row := a[i*inner : (i+1)*inner]
+				for j := jj; j < jStop; j++ {
+					var sum C
+					for k, y := range b[j*inner : (j+1)*inner] {
+						sum += C(row[k]) * C(y)
+					}
+					values[i*cols+j] = sum
+				}
+			}
+		}
+	}
+
+	if rows*inner*cols >= ParallelThreshold {
+		inParallel(rows, f)
+	} else {
+		f(0, rows)
+	}
+}
+
+// tMatMul writes multiplication of transposed a (sized inner x rows) and b (sized inner x cols) to <values> (zeroed,
+// sized rows x cols).
+func tMatMul[A, B, C float](a []A, b []B, values []C, rows, inner, cols int) {
+	f := func(start, stop int) {
+		for kk := 0; kk < inner; kk += blockSize {
+			kStop := minInt(kk+blockSize, inner)
+			for jj := 0; jj < cols; jj += blockSize {
+				jStop := minInt(jj+blockSize, cols)
+				for i := start; i < stop; i++ {
+					res := values[i*cols+jj : i*cols+jStop]
+					for k := kk; k < kStop; k++ {
+						x := C(a[k*rows+i])
+						for j, y := range b[k*cols+jj : k*cols+jStop] {
+							res[j] += x * C(y)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if rows*inner*cols >= ParallelThreshold {
+		inParallel(rows, f)
+	} else {
+		f(0, rows)
+	}
+}
+
+// overlaps return true if address ranges of given slices' values intersect, so writing to one of them may change
+// values of the other one.
+func overlaps[T float](a, b []T) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	size := unsafe.Sizeof(a[0])
+	aStart, bStart := uintptr(unsafe.Pointer(&a[0])), uintptr(unsafe.Pointer(&b[0]))
+	return aStart < bStart+uintptr(len(b))*size && bStart < aStart+uintptr(len(a))*size
+}
@@ -0,0 +1,462 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/wraperr"
+)
+
+// machineEpsilon is difference between 1 and the next float64.
+const machineEpsilon = 2.220446049250313e-16
+
+// illConditioned return true if ratio of min and max absolute values of given diagonal is negligible for matrix of
+// given size: such matrix is singular or so ill-conditioned, that result of solving it is meaningless. It is cheap
+// estimation of condition number by triangular factor's diagonal.
+func illConditioned(diagonal []float64, size int) bool {
+	maxAbs, minAbs := 0.0, math.Inf(1)
+	for _, value := range diagonal {
+		maxAbs, minAbs = math.Max(maxAbs, math.Abs(value)), math.Min(minAbs, math.Abs(value))
+	}
+	return maxAbs == 0 || minAbs <= maxAbs*float64(size)*machineEpsilon
+}
+
+// checkSquare return error if Matrix is nil or non-square.
+func (m *Matrix) checkSquare() error {
+	if m == nil {
+		return ErrNil
+	} else if m.rows != m.cols {
+		return fmt.Errorf("matrix is not square: %dx%d", m.rows, m.cols)
+	}
+	return nil
+}
+
+// checkSymmetric return error if Matrix is nil, non-square or non-symmetric up to rounding errors.
+func (m *Matrix) checkSymmetric() error {
+	if err := m.checkSquare(); err != nil {
+		return err
+	}
+	n := m.rows
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			a, b := m.values[i*n+j], m.values[j*n+i]
+			if math.Abs(a-b) > 1e-12*math.Max(1, math.Max(math.Abs(a), math.Abs(b))) {
+				return fmt.Errorf("matrix is not symmetric: (%d, %d) != (%d, %d): %v != %v", i, j, j, i, a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// LU holds LU decomposition with partial pivoting of square Matrix A: P*A = L*U, where P is permutation Matrix, L is
+// lower triangular Matrix with unit diagonal and U is upper triangular Matrix.
+type LU struct {
+	lu    *Matrix // L below diagonal and U on and above diagonal
+	pivot []int   // i'th row of P*A is pivot[i]'th row of A
+	sign  float64 // determinant of P
+}
+
+// LU return LU decomposition with partial pivoting of this square Matrix. Decomposition exists for singular Matrix
+// too, it is reported by LU.Solve.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 |.LU(): P = | 0 1 |, L = |   1 0 |, U = | 3   4 |
+//     | 3 4 |           | 1 0 |      | 1/3 1 |      | 0 2/3 |
+func (m *Matrix) LU() (lu *LU, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if err = m.checkSquare(); err != nil {
+		return nil, err
+	}
+
+	n := m.rows
+	a := m.Copy()
+	pivot := make([]int, n)
+	for i := range pivot {
+		pivot[i] = i
+	}
+	sign := 1.0
+
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a.values[i*n+k]) > math.Abs(a.values[p*n+k]) {
+				p = i
+			}
+		}
+		if p != k {
+			rowP, rowK := a.row(p), a.row(k)
+			for j := range rowK {
+				rowP[j], rowK[j] = rowK[j], rowP[j]
+			}
+			pivot[p], pivot[k] = pivot[k], pivot[p]
+			sign = -sign
+		}
+
+		diagonal := a.values[k*n+k]
+		if diagonal == 0 {
+			continue // the rest of column is zero too
+		}
+		rowK := a.row(k)
+		for i := k + 1; i < n; i++ {
+			rowI := a.row(i)
+			rowI[k] /= diagonal
+			for j := k + 1; j < n; j++ {
+				rowI[j] -= rowI[k] * rowK[j]
+			}
+		}
+	}
+
+	return &LU{lu: a, pivot: pivot, sign: sign}, nil
+}
+
+// L return lower triangular factor with unit diagonal.
+func (d *LU) L() *Matrix {
+	n := d.lu.rows
+	l := wrap(n, n, make([]float64, n*n))
+	for i := 0; i < n; i++ {
+		copy(l.values[i*n:i*n+i], d.lu.values[i*n:i*n+i])
+		l.values[i*n+i] = 1
+	}
+	return l
+}
+
+// U return upper triangular factor.
+func (d *LU) U() *Matrix {
+	n := d.lu.rows
+	u := wrap(n, n, make([]float64, n*n))
+	for i := 0; i < n; i++ {
+		copy(u.values[i*n+i:(i+1)*n], d.lu.values[i*n+i:(i+1)*n])
+	}
+	return u
+}
+
+// P return permutation Matrix.
+func (d *LU) P() *Matrix {
+	n := d.lu.rows
+	p := wrap(n, n, make([]float64, n*n))
+	for i, j := range d.pivot {
+		p.values[i*n+j] = 1
+	}
+	return p
+}
+
+// Det return determinant of decomposed Matrix.
+func (d *LU) Det() float64 {
+	n := d.lu.rows
+	det := d.sign
+	for i := 0; i < n; i++ {
+		det *= d.lu.values[i*n+i]
+	}
+	return det
+}
+
+// IsSingular return true if decomposed Matrix is singular or ill-conditioned.
+func (d *LU) IsSingular() bool {
+	n := d.lu.rows
+	diagonal := make([]float64, n)
+	for i := range diagonal {
+		diagonal[i] = d.lu.values[i*n+i]
+	}
+	return illConditioned(diagonal, n)
+}
+
+// Solve return X solving A*X = B for decomposed A and given B, B rows count must match A size. B may have
+// several cols, each col is solved separately.
+//
+// Throws ErrExec error, ErrSingular is its cause for singular or ill-conditioned A.
+func (d *LU) Solve(b *Matrix) (x *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	n := d.lu.rows
+	if b == nil {
+		return nil, fmt.Errorf("no right-hand side provided: %v", b)
+	} else if b.rows != n {
+		return nil, fmt.Errorf("right-hand side rows count mismatches matrix size: %d != %d", b.rows, n)
+	} else if d.IsSingular() {
+		return nil, fmt.Errorf("can not solve %dx%d system: %w", n, n, ErrSingular)
+	}
+
+	x, err = b.Order(d.pivot)
+	if err != nil {
+		return nil, err
+	}
+	// L*Y = P*B
+	for i := 0; i < n; i++ {
+		rowI := x.row(i)
+		for k, l := range d.lu.values[i*n : i*n+i] {
+			for j, value := range x.row(k) {
+				rowI[j] -= l * value
+			}
+		}
+	}
+	// U*X = Y
+	for i := n - 1; i >= 0; i-- {
+		rowI := x.row(i)
+		for k := i + 1; k < n; k++ {
+			u := d.lu.values[i*n+k]
+			for j, value := range x.row(k) {
+				rowI[j] -= u * value
+			}
+		}
+		for j := range rowI {
+			rowI[j] /= d.lu.values[i*n+i]
+		}
+	}
+	return x, nil
+}
+
+// QR holds QR decomposition of Matrix A with rows count not less than cols count: A = Q*R, where Q has orthonormal
+// cols and R is square upper triangular Matrix. It is computed by Householder reflections.
+type QR struct {
+	qr    *Matrix   // Householder vectors on and below diagonal and R above diagonal
+	rDiag []float64 // diagonal of R
+}
+
+// QR return QR decomposition of this Matrix, its rows count must not be less than its cols count. Decomposition
+// exists for rank deficient Matrix too, it is reported by QR.Solve.
+//
+// Throws ErrExec error.
+func (m *Matrix) QR() (qr *QR, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if m.rows < m.cols {
+		return nil, fmt.Errorf("matrix rows count is less than cols count: %dx%d", m.rows, m.cols)
+	}
+
+	rows, cols := m.rows, m.cols
+	a := m.Copy()
+	rDiag := make([]float64, cols)
+	for k := 0; k < cols; k++ {
+		norm := 0.0
+		for i := k; i < rows; i++ {
+			norm = math.Hypot(norm, a.values[i*cols+k])
+		}
+		if norm != 0 {
+			if a.values[k*cols+k] < 0 {
+				norm = -norm
+			}
+			for i := k; i < rows; i++ {
+				a.values[i*cols+k] /= norm
+			}
+			a.values[k*cols+k]++
+
+			// apply reflection to the rest of cols
+			for j := k + 1; j < cols; j++ {
+				s := 0.0
+				for i := k; i < rows; i++ {
+					s += a.values[i*cols+k] * a.values[i*cols+j]
+				}
+				s = -s / a.values[k*cols+k]
+				for i := k; i < rows; i++ {
+					a.values[i*cols+j] += s * a.values[i*cols+k]
+				}
+			}
+		}
+		rDiag[k] = -norm
+	}
+
+	return &QR{qr: a, rDiag: rDiag}, nil
+}
+
+// Q return factor with orthonormal cols, it is sized as decomposed Matrix.
+func (d *QR) Q() *Matrix {
+	rows, cols := d.qr.rows, d.qr.cols
+	q := wrap(rows, cols, make([]float64, rows*cols))
+	for k := cols - 1; k >= 0; k-- {
+		q.values[k*cols+k] = 1
+		for j := k; j < cols; j++ {
+			if d.qr.values[k*cols+k] == 0 {
+				continue
+			}
+			s := 0.0
+			for i := k; i < rows; i++ {
+				s += d.qr.values[i*cols+k] * q.values[i*cols+j]
+			}
+			s = -s / d.qr.values[k*cols+k]
+			for i := k; i < rows; i++ {
+				q.values[i*cols+j] += s * d.qr.values[i*cols+k]
+			}
+		}
+	}
+	return q
+}
+
+// R return square upper triangular factor.
+func (d *QR) R() *Matrix {
+	cols := d.qr.cols
+	r := wrap(cols, cols, make([]float64, cols*cols))
+	for i := 0; i < cols; i++ {
+		r.values[i*cols+i] = d.rDiag[i]
+		copy(r.values[i*cols+i+1:(i+1)*cols], d.qr.values[i*cols+i+1:(i+1)*cols])
+	}
+	return r
+}
+
+// IsFullRank return true if decomposed Matrix has full col rank and is not ill-conditioned.
+func (d *QR) IsFullRank() bool {
+	return !illConditioned(d.rDiag, d.qr.rows)
+}
+
+// Solve return X minimizing Frobenius norm of A*X - B for decomposed A and given B (least squares solution), B rows
+// count must match A rows count. X is exact solution for square A.
+//
+// Throws ErrExec error, ErrSingular is its cause for rank deficient or ill-conditioned A.
+func (d *QR) Solve(b *Matrix) (x *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	rows, cols := d.qr.rows, d.qr.cols
+	if b == nil {
+		return nil, fmt.Errorf("no right-hand side provided: %v", b)
+	} else if b.rows != rows {
+		return nil, fmt.Errorf("right-hand side rows count mismatches matrix rows count: %d != %d", b.rows, rows)
+	} else if !d.IsFullRank() {
+		return nil, fmt.Errorf("can not solve %dx%d system: %w", rows, cols, ErrSingular)
+	}
+
+	y := b.Copy()
+	// Y = Q^T * B
+	for k := 0; k < cols; k++ {
+		for j := 0; j < y.cols; j++ {
+			s := 0.0
+			for i := k; i < rows; i++ {
+				s += d.qr.values[i*cols+k] * y.values[i*y.cols+j]
+			}
+			s = -s / d.qr.values[k*cols+k]
+			for i := k; i < rows; i++ {
+				y.values[i*y.cols+j] += s * d.qr.values[i*cols+k]
+			}
+		}
+	}
+
+	// R*X = Y
+	x = wrap(cols, y.cols, make([]float64, cols*y.cols))
+	copy(x.values, y.values)
+	for k := cols - 1; k >= 0; k-- {
+		rowK := x.row(k)
+		for j := range rowK {
+			rowK[j] /= d.rDiag[k]
+		}
+		for i := 0; i < k; i++ {
+			r := d.qr.values[i*cols+k]
+			rowI := x.row(i)
+			for j, value := range rowK {
+				rowI[j] -= value * r
+			}
+		}
+	}
+	return x, nil
+}
+
+// Cholesky return lower triangular Matrix L such that L*L^T is this symmetric positive definite Matrix.
+//
+// Throws ErrExec error, ErrSingular is its cause for Matrix not positive definite or ill-conditioned.
+//
+// Example:
+//     | 4 2 |.Cholesky() = | 2 0 |
+//     | 2 5 |              | 1 2 |
+func (m *Matrix) Cholesky() (l *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if err = m.checkSymmetric(); err != nil {
+		return nil, err
+	}
+
+	n := m.rows
+	maxDiagonal := 0.0
+	for i := 0; i < n; i++ {
+		maxDiagonal = math.Max(maxDiagonal, math.Abs(m.values[i*n+i]))
+	}
+	tolerance := maxDiagonal * float64(n) * machineEpsilon
+
+	l = wrap(n, n, make([]float64, n*n))
+	for j := 0; j < n; j++ {
+		rowJ := l.row(j)
+		d := m.values[j*n+j]
+		for _, value := range rowJ[:j] {
+			d -= value * value
+		}
+		if d <= tolerance {
+			return nil, fmt.Errorf("matrix is not positive definite, %d'th pivot is %v: %w", j, d, ErrSingular)
+		}
+		rowJ[j] = math.Sqrt(d)
+
+		for i := j + 1; i < n; i++ {
+			rowI := l.row(i)
+			s := m.values[i*n+j]
+			for k, value := range rowJ[:j] {
+				s -= rowI[k] * value
+			}
+			rowI[j] = s / rowJ[j]
+		}
+	}
+	return l, nil
+}
+
+// Solve return X solving A*X = B for this square Matrix A and given B using LU decomposition (see LU.Solve).
+//
+// Throws ErrExec error, ErrSingular is its cause for singular or ill-conditioned Matrix.
+func (m *Matrix) Solve(b *Matrix) (x *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	lu, err := m.LU()
+	if err != nil {
+		return nil, err
+	}
+	return lu.Solve(b)
+}
+
+// Inverse return inverse of this square Matrix.
+//
+// Throws ErrExec error, ErrSingular is its cause for singular or ill-conditioned Matrix.
+//
+// Example:
+//     | 2 0 |.Inverse() = | 0.5    0 |
+//     | 0 4 |             |   0 0.25 |
+func (m *Matrix) Inverse() (inv *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if err = m.checkSquare(); err != nil {
+		return nil, err
+	}
+	identity, err := Identity(m.rows)
+	if err != nil {
+		return nil, err
+	}
+	return m.Solve(identity)
+}
+
+// Det return determinant of this square Matrix, it is zero for singular Matrix.
+//
+// Throws ErrExec error.
+func (m *Matrix) Det() (det float64, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	lu, err := m.LU()
+	if err != nil {
+		return 0, err
+	}
+	return lu.Det(), nil
+}
+
+// LeastSquares return X minimizing Frobenius norm of A*X - B for this Matrix A and given B using QR decomposition
+// (see QR.Solve). This Matrix rows count must not be less than its cols count.
+//
+// Throws ErrExec error, ErrSingular is its cause for rank deficient or ill-conditioned Matrix.
+//
+// Example (fitting line y = 1 + 2x to points (0, 1), (1, 3), (2, 5)):
+//     | 1 0 |.LeastSquares(| 1 |) = | 1 |
+//     | 1 1 |              | 3 |    | 2 |
+//     | 1 2 |              | 5 |
+func (m *Matrix) LeastSquares(b *Matrix) (x *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	qr, err := m.QR()
+	if err != nil {
+		return nil, err
+	}
+	return qr.Solve(b)
+}
@@ -0,0 +1,265 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// requireClose asserts matrices are equal up to given tolerance.
+func requireClose(t *testing.T, expected, actual *Matrix, tolerance float64) {
+	require.Equal(t, expected.Rows(), actual.Rows())
+	require.Equal(t, expected.Cols(), actual.Cols())
+	for i, value := range expected.values {
+		require.InDelta(t, value, actual.values[i], tolerance, "%s != %s", expected, actual)
+	}
+}
+
+func mustMatMul(t *testing.T, a, b *Matrix) *Matrix {
+	res, err := a.MatMul(b)
+	require.NoError(t, err)
+	return res
+}
+
+// singular is 3x3 Matrix with linearly dependent rows.
+var singular = matrixInput{in: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, rows: 3, cols: 3}
+
+func TestMatrix_LU(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       matrixInput
+		det      float64
+		singular bool
+		err      error
+	}{
+		{name: "1x1", in: matrixInput{in: []float64{5}, rows: 1, cols: 1}, det: 5},
+		{name: "2x2 with pivoting", in: matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2}, det: -2},
+		{name: "zero leading value", in: matrixInput{in: []float64{0, 1, 1, 0}, rows: 2, cols: 2}, det: -1},
+		{name: "3x3", in: matrixInput{in: []float64{2, -1, 0, -1, 2, -1, 0, -1, 2}, rows: 3, cols: 3}, det: 4},
+		{name: "singular", in: singular, singular: true},
+		{name: "zero", in: matrixInput{in: []float64{0, 0, 0, 0}, rows: 2, cols: 2}, singular: true},
+		{name: "non-square", in: matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3}, err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMatrix(t, test.in)
+			lu, err := m.LU()
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			requireClose(t, mustMatMul(t, lu.P(), m), mustMatMul(t, lu.L(), lu.U()), 1e-12)
+			require.Equal(t, test.singular, lu.IsSingular())
+			require.InDelta(t, test.det, lu.Det(), 1e-12)
+
+			det, err := m.Det()
+			require.NoError(t, err)
+			require.InDelta(t, test.det, det, 1e-12)
+		})
+	}
+}
+
+func TestMatrix_Solve(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     matrixInput
+		expected []float64
+		err      error
+	}{
+		{
+			name:     "2x2",
+			a:        matrixInput{in: []float64{2, 1, 1, 3}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{3, 5}, rows: 2, cols: 1},
+			expected: []float64{0.8, 1.4},
+		},
+		{
+			name:     "several right-hand sides",
+			a:        matrixInput{in: []float64{0, 2, 1, 0}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{2, 4, 3, 5}, rows: 2, cols: 2},
+			expected: []float64{3, 5, 1, 2},
+		},
+		{
+			name: "singular",
+			a:    singular,
+			b:    matrixInput{in: []float64{1, 2, 3}, rows: 3, cols: 1},
+			err:  ErrSingular,
+		},
+		{
+			name: "ill-conditioned",
+			a:    matrixInput{in: []float64{1, 1, 1, 1 + 1e-17}, rows: 2, cols: 2},
+			b:    matrixInput{in: []float64{1, 2}, rows: 2, cols: 1},
+			err:  ErrSingular,
+		},
+		{
+			name: "right-hand side size mismatch",
+			a:    matrixInput{in: []float64{2, 1, 1, 3}, rows: 2, cols: 2},
+			b:    matrixInput{in: []float64{1, 2, 3}, rows: 3, cols: 1},
+			err:  ErrExec,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := newMatrix(t, test.a), newMatrix(t, test.b)
+			x, err := a.Solve(b)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				require.ErrorIs(t, err, ErrExec)
+				return
+			}
+			require.NoError(t, err)
+			requireClose(t, newMatrix(t, matrixInput{in: test.expected, rows: b.rows, cols: b.cols}), x, 1e-12)
+			requireClose(t, b, mustMatMul(t, a, x), 1e-12)
+		})
+	}
+}
+
+func TestMatrix_Inverse(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{4, 7, 2, 6}, rows: 2, cols: 2})
+	inv, err := m.Inverse()
+	require.NoError(t, err)
+	requireClose(t, newMatrix(t, matrixInput{in: []float64{0.6, -0.7, -0.2, 0.4}, rows: 2, cols: 2}), inv, 1e-12)
+
+	random := randomMatrix(6, 6).AddNumInPlace(-0.5)
+	inv, err = random.Inverse()
+	require.NoError(t, err)
+	identity, err := Identity(6)
+	require.NoError(t, err)
+	requireClose(t, identity, mustMatMul(t, random, inv), 1e-9)
+	requireClose(t, identity, mustMatMul(t, inv, random), 1e-9)
+
+	_, err = newMatrix(t, singular).Inverse()
+	require.ErrorIs(t, err, ErrSingular)
+	_, err = randomMatrix(2, 3).Inverse()
+	require.ErrorIs(t, err, ErrExec)
+	var nilMatrix *Matrix
+	_, err = nilMatrix.Inverse()
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_QR(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       *Matrix
+		fullRank bool
+	}{
+		{name: "square", in: newMatrix(t, matrixInput{in: []float64{12, -51, 4, 6, 167, -68, -4, 24, -41}, rows: 3, cols: 3}), fullRank: true},
+		{name: "tall random", in: randomMatrix(7, 4), fullRank: true},
+		{name: "rank deficient", in: newMatrix(t, matrixInput{in: []float64{1, 2, 2, 4, 3, 6}, rows: 3, cols: 2})},
+		{name: "zero col", in: newMatrix(t, matrixInput{in: []float64{1, 0, 2, 0, 3, 0}, rows: 3, cols: 2})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			qr, err := test.in.QR()
+			require.NoError(t, err)
+			q, r := qr.Q(), qr.R()
+			require.Equal(t, test.fullRank, qr.IsFullRank())
+
+			requireClose(t, test.in, mustMatMul(t, q, r), 1e-10)
+			for i := 0; i < r.rows; i++ {
+				for j := 0; j < i; j++ {
+					require.Zero(t, r.values[i*r.cols+j])
+				}
+			}
+			if test.fullRank {
+				identity, err := Identity(q.cols)
+				require.NoError(t, err)
+				qtq, err := q.TMatMul(q)
+				require.NoError(t, err)
+				requireClose(t, identity, qtq, 1e-10)
+			}
+		})
+	}
+
+	_, err := randomMatrix(2, 3).QR()
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_LeastSquares(t *testing.T) {
+	// points of line y = 1 + 2x
+	a := newMatrix(t, matrixInput{in: []float64{1, 0, 1, 1, 1, 2}, rows: 3, cols: 2})
+	b := newMatrix(t, matrixInput{in: []float64{1, 3, 5}, rows: 3, cols: 1})
+	x, err := a.LeastSquares(b)
+	require.NoError(t, err)
+	requireClose(t, newMatrix(t, matrixInput{in: []float64{1, 2}, rows: 2, cols: 1}), x, 1e-12)
+
+	// noisy points: residual is orthogonal to cols of A
+	a = newMatrix(t, matrixInput{in: []float64{1, 0, 1, 1, 1, 2, 1, 3}, rows: 4, cols: 2})
+	b = newMatrix(t, matrixInput{in: []float64{1.1, 2.9, 5.2, 6.8}, rows: 4, cols: 1})
+	x, err = a.LeastSquares(b)
+	require.NoError(t, err)
+	residual, err := mustMatMul(t, a, x).Sub(b)
+	require.NoError(t, err)
+	orthogonality, err := a.TMatMul(residual)
+	require.NoError(t, err)
+	requireClose(t, newMatrix(t, matrixInput{in: []float64{0, 0}, rows: 2, cols: 1}), orthogonality, 1e-12)
+
+	// square system is solved exactly
+	square := randomMatrix(5, 5)
+	rhs := randomMatrix(5, 2)
+	x, err = square.LeastSquares(rhs)
+	require.NoError(t, err)
+	requireClose(t, rhs, mustMatMul(t, square, x), 1e-9)
+
+	rankDeficient := newMatrix(t, matrixInput{in: []float64{1, 2, 2, 4, 3, 6}, rows: 3, cols: 2})
+	_, err = rankDeficient.LeastSquares(b)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = rankDeficient.LeastSquares(newMatrix(t, matrixInput{in: []float64{1, 2, 3}, rows: 3, cols: 1}))
+	require.ErrorIs(t, err, ErrSingular)
+	_, err = randomMatrix(2, 3).LeastSquares(randomMatrix(2, 1))
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_Cholesky(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       matrixInput
+		expected []float64
+		err      error
+	}{
+		{
+			name:     "2x2",
+			in:       matrixInput{in: []float64{4, 2, 2, 5}, rows: 2, cols: 2},
+			expected: []float64{2, 0, 1, 2},
+		},
+		{
+			name:     "3x3",
+			in:       matrixInput{in: []float64{25, 15, -5, 15, 18, 0, -5, 0, 11}, rows: 3, cols: 3},
+			expected: []float64{5, 0, 0, 3, 3, 0, -1, 1, 3},
+		},
+		{name: "not symmetric", in: matrixInput{in: []float64{4, 2, 1, 5}, rows: 2, cols: 2}, err: ErrExec},
+		{name: "indefinite", in: matrixInput{in: []float64{1, 2, 2, 1}, rows: 2, cols: 2}, err: ErrSingular},
+		{name: "semi-definite", in: matrixInput{in: []float64{1, 1, 1, 1}, rows: 2, cols: 2}, err: ErrSingular},
+		{name: "non-square", in: matrixInput{in: []float64{1, 2}, rows: 1, cols: 2}, err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMatrix(t, test.in)
+			l, err := m.Cholesky()
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				require.ErrorIs(t, err, ErrExec)
+				return
+			}
+			require.NoError(t, err)
+			requireClose(t, newMatrix(t, matrixInput{in: test.expected, rows: m.rows, cols: m.cols}), l, 1e-12)
+			llt, err := l.MatMulT(l)
+			require.NoError(t, err)
+			requireClose(t, m, llt, 1e-12)
+		})
+	}
+
+	// Gram matrix of random matrix with more rows than cols is positive definite
+	random := randomMatrix(10, 4)
+	gram, err := random.TMatMul(random)
+	require.NoError(t, err)
+	l, err := gram.Cholesky()
+	require.NoError(t, err)
+	llt, err := l.MatMulT(l)
+	require.NoError(t, err)
+	requireClose(t, gram, llt, 1e-10)
+}
@@ -1,4 +1,4 @@
-// Package matrix provides functionality for Matrix (wrap on slice of vector.Vector).
+// Package matrix provides functionality for Matrix (wrap on flat row-major slice of floats).
 package matrix
 
 import (
@@ -8,7 +8,8 @@ import (
 	"strings"
 )
 
-// Matrix holds slice of vector.Vector. It provides some useful methods. It can be treated as immutable by using Copy().
+// Matrix holds values in single slice of floats row by row: value of i'th row and j'th col is stored at index
+// i*cols + j. It provides some useful methods. It can be treated as immutable by using Copy().
 //
 // Example:
 //
@@ -16,9 +17,19 @@ import (
 //    | 1 2 3 |
 //    | 4 5 6 |
 type Matrix struct {
-	vectors []*vector.Vector
-	rows    int
-	cols    int
+	values []float64
+	rows   int
+	cols   int
+}
+
+// wrap wraps given values with no checks and no copying, values must be sized rows*cols.
+func wrap(rows, cols int, values []float64) *Matrix {
+	return &Matrix{values: values, rows: rows, cols: cols}
+}
+
+// row return slice of i'th row of Matrix's values (no copying).
+func (m *Matrix) row(i int) []float64 {
+	return m.values[i*m.cols : (i+1)*m.cols]
 }
 
 // NewMatrix creates Matrix from given slice of vectors. There must be at least one value in slice.
@@ -42,17 +53,15 @@ func NewMatrix(vectors []*vector.Vector) (m *Matrix, err error) {
 
 	cols := vectors[0].Size()
 
+	values := make([]float64, 0, rows*cols)
 	for i, row := range vectors {
 		if row.Size() != cols {
 			return nil, fmt.Errorf("%d'th row length mismatch first row length: %d != %d", i, row.Size(), cols)
 		}
+		values = append(values, row.Raw()...)
 	}
 
-	return &Matrix{
-		vectors: vectors,
-		rows:    rows,
-		cols:    cols,
-	}, nil
+	return wrap(rows, cols, values), nil
 }
 
 // NewMatrixFlat creates Matrix from given Vector with given rows and cols count. Rows and cols must be
@@ -64,26 +73,9 @@ func NewMatrixFlat(rows, cols int, flat *vector.Vector) (m *Matrix, err error) {
 
 	if flat == nil {
 		return nil, fmt.Errorf("no vector provided: %v", flat)
-	} else if rows < 1 {
-		return nil, fmt.Errorf("negative or zero rows count: %d", rows)
-	} else if cols < 1 {
-		return nil, fmt.Errorf("negative or zero cols count: %d", cols)
-	} else if flat.Size() != rows*cols {
-		return nil, fmt.Errorf("wrong vectors count provided for rows*cols matrix: %d != %d*%d=%d",
-			flat.Size(), rows, cols, rows*cols)
 	}
 
-	vectors, err := flat.Split(cols)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(vectors) != rows {
-		return nil, fmt.Errorf("split parts count of vector sized %d for matrix sized %dx%d mismatches: %d != %d",
-			flat.Size(), rows, cols, len(vectors), rows)
-	}
-
-	return NewMatrix(vectors)
+	return NewMatrixRawFlat(rows, cols, flat.Raw())
 }
 
 // NewMatrixRaw creates Matrix from given slice of slice of floats. There must be at least one value in slice.
@@ -96,27 +88,61 @@ func NewMatrixRaw(values [][]float64) (m *Matrix, err error) {
 	if len(values) < 1 {
 		return nil, fmt.Errorf("no values provided: %v", values)
 	}
-	var vectors []*vector.Vector
-	for _, row := range values {
-		vec, err := vector.NewVector(row)
-		if err != nil {
-			return nil, err
+
+	rows, cols := len(values), len(values[0])
+	flat := make([]float64, 0, rows*cols)
+	for i, row := range values {
+		if len(row) < 1 {
+			return nil, fmt.Errorf("no values provided for %d'th row: %v", i, row)
+		} else if len(row) != cols {
+			return nil, fmt.Errorf("%d'th row length mismatch first row length: %d != %d", i, len(row), cols)
 		}
-		vectors = append(vectors, vec)
+		flat = append(flat, row...)
 	}
-	return NewMatrix(vectors)
+
+	return wrap(rows, cols, flat), nil
 }
 
 // NewMatrixRawFlat creates Matrix from given slice of floats with given rows and cols count. Rows and cols must be
 // non-zero positive values. Size of slice must be `rows*cols`.
 //
 // Throws ErrCreate error.
-func NewMatrixRawFlat(rows, cols int, values []float64) (*Matrix, error) {
-	vec, err := vector.NewVector(values)
-	if err != nil {
-		return nil, wraperr.NewWrapErr(ErrCreate, err)
+func NewMatrixRawFlat(rows, cols int, values []float64) (m *Matrix, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if len(values) < 1 {
+		return nil, fmt.Errorf("no values provided: %v", values)
+	} else if rows < 1 {
+		return nil, fmt.Errorf("negative or zero rows count: %d", rows)
+	} else if cols < 1 {
+		return nil, fmt.Errorf("negative or zero cols count: %d", cols)
+	} else if len(values) != rows*cols {
+		return nil, fmt.Errorf("wrong values count provided for rows*cols matrix: %d != %d*%d=%d",
+			len(values), rows, cols, rows*cols)
 	}
-	return NewMatrixFlat(rows, cols, vec)
+
+	flat := make([]float64, len(values))
+	copy(flat, values)
+	return wrap(rows, cols, flat), nil
+}
+
+// NewMatrixShared creates Matrix using given slice as its values without copying, so changes of the slice change
+// Matrix and vice versa. It lets other packages (e.g. tensor) view their data as Matrix. Rows and cols must be
+// non-zero positive values. Size of slice must be `rows*cols`.
+//
+// Throws ErrCreate error.
+func NewMatrixShared(rows, cols int, values []float64) (m *Matrix, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if rows < 1 {
+		return nil, fmt.Errorf("negative or zero rows count: %d", rows)
+	} else if cols < 1 {
+		return nil, fmt.Errorf("negative or zero cols count: %d", cols)
+	} else if len(values) != rows*cols {
+		return nil, fmt.Errorf("wrong values count provided for rows*cols matrix: %d != %d*%d=%d",
+			len(values), rows, cols, rows*cols)
+	}
+	return wrap(rows, cols, values), nil
 }
 
 // NewMatrixOf creates Matrix with given rows and cols count filled with given value. Rows and cols must be
@@ -127,19 +153,45 @@ func NewMatrixRawFlat(rows, cols int, values []float64) (*Matrix, error) {
 // Example:
 //     NewMatrixOf(2, 1, 3) = | 3 |
 //                            | 3 |
-func NewMatrixOf(rows, cols int, value float64) (*Matrix, error) {
-	flat, err := vector.NewVectorOf(value, rows*cols)
-	if err != nil {
-		return nil, wraperr.NewWrapErr(ErrCreate, err)
+func NewMatrixOf(rows, cols int, value float64) (m *Matrix, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if rows < 1 {
+		return nil, fmt.Errorf("negative or zero rows count: %d", rows)
+	} else if cols < 1 {
+		return nil, fmt.Errorf("negative or zero cols count: %d", cols)
 	}
 
-	return NewMatrixFlat(rows, cols, flat)
+	values := make([]float64, rows*cols)
+	if value != 0 {
+		for i := range values {
+			values[i] = value
+		}
+	}
+	return wrap(rows, cols, values), nil
 }
 
 func Zeros(rows, cols int) (*Matrix, error) {
 	return NewMatrixOf(rows, cols, 0)
 }
 
+// Identity creates square identity Matrix of given size.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     Identity(2) = | 1 0 |
+//                   | 0 1 |
+func Identity(size int) (m *Matrix, err error) {
+	if m, err = Zeros(size, size); err != nil {
+		return nil, err
+	}
+	for i := 0; i < size; i++ {
+		m.values[i*size+i] = 1
+	}
+	return m, nil
+}
+
 // Example:
 //     | 1 2 3 |.String() = `[[1 2 3] [4 5 6]]`
 //     | 4 5 6 |
@@ -148,8 +200,8 @@ func (m *Matrix) String() string {
 		return "<nil>"
 	}
 	vecStrings := make([]string, m.rows)
-	for i, vec := range m.vectors {
-		vecStrings[i] = vec.String()
+	for i := range vecStrings {
+		vecStrings[i] = fmt.Sprintf("%v", m.row(i))
 	}
 	return fmt.Sprintf("[%s]", strings.Join(vecStrings, " "))
 }
@@ -214,12 +266,7 @@ func (m *Matrix) Get(row, col int) (value float64, err error) {
 		return 0, fmt.Errorf("wrong row and col for matrix %dx%d: %d, %d", m.rows, m.cols, row, col)
 	}
 
-	value, err = m.vectors[row].Get(col)
-	if err != nil {
-		return 0, err
-	}
-
-	return value, nil
+	return m.values[row*m.cols+col], nil
 }
 
 // Raw return Matrix as slice of slices of floats
@@ -228,8 +275,9 @@ func (m *Matrix) Raw() [][]float64 {
 		return nil
 	}
 	values := make([][]float64, m.rows)
-	for i, row := range m.vectors {
-		values[i] = row.Raw()
+	for i := range values {
+		values[i] = make([]float64, m.cols)
+		copy(values[i], m.row(i))
 	}
 	return values
 }
@@ -239,23 +287,26 @@ func (m *Matrix) RawFlat() []float64 {
 	if m == nil {
 		return nil
 	}
-	values := make([]float64, m.rows*m.cols)
-	for i, row := range m.vectors {
-		rawRow := row.Raw()
-		for j, value := range rawRow {
-			values[i*m.cols+j] = value
-		}
-	}
+	values := make([]float64, len(m.values))
+	copy(values, m.values)
 	return values
 }
 
+// Data return values of Matrix in row-major order without copying: changes of returned slice change Matrix. It lets
+// kernels from other packages (e.g. sparse, tensor) read operands and write results into reused matrices.
+func (m *Matrix) Data() []float64 {
+	if m == nil {
+		return nil
+	}
+	return m.values
+}
+
 // Copy return deep copy of Matrix
 func (m *Matrix) Copy() *Matrix {
 	if m == nil {
 		return nil
 	}
-	matrix, _ := NewMatrixRaw(m.Raw())
-	return matrix
+	return wrap(m.rows, m.cols, m.RawFlat())
 }
 
 // Size return rows and cols count of Matrix
@@ -291,7 +342,7 @@ func (m *Matrix) GetRow(row int) (vec *vector.Vector, err error) {
 		return nil, fmt.Errorf("can not get %d'th row of %dx%d matrix", row, m.rows, m.cols)
 	}
 
-	return m.vectors[row].Copy(), nil
+	return vector.NewVector(m.row(row))
 }
 
 // GetRow return col as vector.
@@ -307,12 +358,8 @@ func (m *Matrix) GetCol(col int) (vec *vector.Vector, err error) {
 	}
 
 	values := make([]float64, m.rows)
-	for i := 0; i < m.rows; i++ {
-		value, err := m.vectors[i].Get(col)
-		if err != nil {
-			return nil, err
-		}
-		values[i] = value
+	for i := range values {
+		values[i] = m.values[i*m.cols+col]
 	}
 
 	return vector.NewVector(values)
@@ -325,7 +372,7 @@ func (m *Matrix) CheckEqualShape(matrix *Matrix) error {
 	} else if matrix == nil {
 		return fmt.Errorf("no matrix provided: %v", matrix)
 	} else if m.rows != matrix.rows || m.cols != matrix.cols {
-		return fmt.Errorf("matrix sizes mismatch: %dx%d != %dx%d", m.rows, matrix.rows, m.cols, matrix.cols)
+		return fmt.Errorf("matrix sizes mismatch: %dx%d != %dx%d", m.rows, m.cols, matrix.rows, matrix.cols)
 	}
 	return nil
 }
@@ -0,0 +1,318 @@
+package matrix
+
+import (
+	"fmt"
+	"nn/pkg/wraperr"
+)
+
+// Matrix32 is single precision (float32) variant of Matrix, used to store parameters of large networks: it takes
+// half of Matrix memory. Matrix32 is a storage type: it has no arithmetic of its own, Matrix provides operations
+// taking Matrix32 operand (named with "32Into" suffix, computed in double precision) or writing Matrix32 result
+// (named with "To32" suffix, computed in single precision), other computations are done on Matrix got by Float64.
+//
+// Matrix32 values are stored the same way as Matrix ones and share the same multiplication kernels.
+type Matrix32 struct {
+	values []float32
+	rows   int
+	cols   int
+}
+
+// wrap32 wraps given values with no checks and no copying, values must be sized rows*cols.
+func wrap32(rows, cols int, values []float32) *Matrix32 {
+	return &Matrix32{values: values, rows: rows, cols: cols}
+}
+
+// NewMatrix32RawFlat creates Matrix32 from given slice of floats with given rows and cols count. Rows and cols must
+// be non-zero positive values. Size of slice must be `rows*cols`.
+//
+// Throws ErrCreate error.
+func NewMatrix32RawFlat(rows, cols int, values []float32) (m *Matrix32, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if len(values) < 1 {
+		return nil, fmt.Errorf("no values provided: %v", values)
+	} else if rows < 1 {
+		return nil, fmt.Errorf("negative or zero rows count: %d", rows)
+	} else if cols < 1 {
+		return nil, fmt.Errorf("negative or zero cols count: %d", cols)
+	} else if len(values) != rows*cols {
+		return nil, fmt.Errorf("wrong values count provided for rows*cols matrix: %d != %d*%d=%d",
+			len(values), rows, cols, rows*cols)
+	}
+
+	flat := make([]float32, len(values))
+	copy(flat, values)
+	return wrap32(rows, cols, flat), nil
+}
+
+// Float32 return this Matrix rounded to single precision.
+func (m *Matrix) Float32() *Matrix32 {
+	return m.Float32Into(nil)
+}
+
+// Float32Into is "into" variant of Float32.
+func (m *Matrix) Float32Into(dst *Matrix32) *Matrix32 {
+	if m == nil {
+		return nil
+	}
+	dst = reuse32(dst, m.rows, m.cols)
+	for i, value := range m.values {
+		dst.values[i] = float32(value)
+	}
+	return dst
+}
+
+// Float64 return this Matrix32 as Matrix, conversion is exact.
+func (m *Matrix32) Float64() *Matrix {
+	return m.Float64Into(nil)
+}
+
+// Float64Into is "into" variant of Float64.
+func (m *Matrix32) Float64Into(dst *Matrix) *Matrix {
+	if m == nil {
+		return nil
+	}
+	dst = reuse(dst, m.rows, m.cols)
+	for i, value := range m.values {
+		dst.values[i] = float64(value)
+	}
+	return dst
+}
+
+// Example:
+//     | 1 2 3 |.String() = `[[1 2 3] [4 5 6]]`
+//     | 4 5 6 |
+func (m *Matrix32) String() string {
+	if m == nil {
+		return "<nil>"
+	}
+	return m.Float64().String()
+}
+
+func (m *Matrix32) PrettyString() string {
+	if m == nil {
+		return "<nil>"
+	}
+	return m.Float64().PrettyString()
+}
+
+// Example:
+//     | 1 2 3 |.ShortString() = `matrix32 2x3`
+//     | 4 5 6 |
+func (m *Matrix32) ShortString() string {
+	if m == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("matrix32 %dx%d", m.rows, m.cols)
+}
+
+// RawFlat return Matrix32 as slice of floats
+func (m *Matrix32) RawFlat() []float32 {
+	if m == nil {
+		return nil
+	}
+	values := make([]float32, len(m.values))
+	copy(values, m.values)
+	return values
+}
+
+// Copy return deep copy of Matrix32
+func (m *Matrix32) Copy() *Matrix32 {
+	if m == nil {
+		return nil
+	}
+	return wrap32(m.rows, m.cols, m.RawFlat())
+}
+
+// CopyInto return copy of this Matrix32 written to <dst> if it is shaped as this Matrix32.
+func (m *Matrix32) CopyInto(dst *Matrix32) *Matrix32 {
+	if m == nil {
+		return nil
+	}
+	dst = reuse32(dst, m.rows, m.cols)
+	copy(dst.values, m.values)
+	return dst
+}
+
+// Size return rows and cols count of Matrix32
+func (m *Matrix32) Size() (rows int, cols int) {
+	return m.Rows(), m.Cols()
+}
+
+// Rows return rows count of Matrix32
+func (m *Matrix32) Rows() int {
+	if m == nil {
+		return 0
+	}
+	return m.rows
+}
+
+// Cols return cols count of Matrix32
+func (m *Matrix32) Cols() int {
+	if m == nil {
+		return 0
+	}
+	return m.cols
+}
+
+// Equal return true if matrices have the same shape and values.
+func (m *Matrix32) Equal(matrix *Matrix32) bool {
+	if m == nil || matrix == nil {
+		return m == nil && matrix == nil
+	} else if m.rows != matrix.rows || m.cols != matrix.cols {
+		return false
+	}
+	for i, value := range m.values {
+		if value != matrix.values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualApprox return true if matrices have the same shape and values, values are compared as Matrix.EqualApprox
+// does.
+func (m *Matrix32) EqualApprox(matrix *Matrix32) bool {
+	if m == nil || matrix == nil {
+		return m == nil && matrix == nil
+	}
+	return m.Float64().EqualApprox(matrix.Float64())
+}
+
+// CheckEqualShape return error if provided matrix size mismatch this size. Function return nil if sizes match.
+func (m *Matrix32) CheckEqualShape(matrix *Matrix32) error {
+	if m == nil {
+		return ErrNil
+	} else if matrix == nil {
+		return fmt.Errorf("no matrix provided: %v", matrix)
+	} else if m.rows != matrix.rows || m.cols != matrix.cols {
+		return fmt.Errorf("matrix sizes mismatch: %dx%d != %dx%d", m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+	return nil
+}
+
+// AddRow32Into writes sum of each row of this Matrix and given Matrix32 row to <dst>, <dst> may be this Matrix.
+//
+// Throws ErrExec error.
+func (m *Matrix) AddRow32Into(dst *Matrix, row *Matrix32) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if row == nil {
+		return nil, fmt.Errorf("no row provided: %v", row)
+	} else if row.rows != 1 || row.cols != m.cols {
+		return nil, fmt.Errorf("matrix size mismatces: %dx%d != 1x%d", row.rows, row.cols, m.cols)
+	}
+
+	if dst = reuse(dst, m.rows, m.cols); shares(dst, m) && !aliases(dst, m) {
+		dst = wrap(m.rows, m.cols, make([]float64, len(m.values)))
+	}
+	for i := 0; i < m.rows; i++ {
+		res := dst.values[i*m.cols : (i+1)*m.cols]
+		for j, value := range m.values[i*m.cols : (i+1)*m.cols] {
+			res[j] = value + float64(row.values[j])
+		}
+	}
+	return dst, nil
+}
+
+// SumColsTo32 writes row of sums of each col of this Matrix to <dst> (see Matrix.SumAxedM with Vertical axis), sums
+// are computed in single precision.
+//
+// Throws ErrExec error.
+func (m *Matrix) SumColsTo32(dst *Matrix32) (mat *Matrix32, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+
+	dst = reuse32(dst, 1, m.cols)
+	for j := range dst.values {
+		dst.values[j] = 0
+	}
+	for i := 0; i < m.rows; i++ {
+		for j, value := range m.values[i*m.cols : (i+1)*m.cols] {
+			dst.values[j] += float32(value)
+		}
+	}
+	return dst, nil
+}
+
+// MatMul32Into is variant of MatMulInto with Matrix32 second operand, product is computed in double precision.
+//
+// Throws ErrExec error.
+func (m *Matrix) MatMul32Into(dst *Matrix, matrix *Matrix32) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.cols != matrix.rows {
+		return nil, fmt.Errorf("can't Mul matrices sized %dx%d and %dx%d", m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+
+	// Matrix32 operand never shares values with Matrix, so only this Matrix is checked
+	dst = m.reuseProduct(dst, m, m.rows, matrix.cols)
+	if m.rows*m.cols*matrix.cols >= ParallelThreshold {
+		inParallel(m.rows, func(start, stop int) {
+			matMulRows(m.values, matrix.values, dst.values, m.cols, matrix.cols, start, stop)
+		})
+	} else {
+		matMulRows(m.values, matrix.values, dst.values, m.cols, matrix.cols, 0, m.rows)
+	}
+	return dst, nil
+}
+
+// MatMulT32Into is variant of MatMulTInto with Matrix32 second operand, product is computed in double precision.
+//
+// Throws ErrExec error.
+func (m *Matrix) MatMulT32Into(dst *Matrix, matrix *Matrix32) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.cols != matrix.cols {
+		return nil, fmt.Errorf("can't Mul matrix sized %dx%d and transposed %dx%d",
+			m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+
+	dst = m.reuseProduct(dst, m, m.rows, matrix.rows)
+	matMulT(m.values, matrix.values, dst.values, m.rows, m.cols, matrix.rows)
+	return dst, nil
+}
+
+// TMatMulTo32 is variant of TMatMulInto with Matrix32 result, product is computed in single precision.
+//
+// Throws ErrExec error.
+func (m *Matrix) TMatMulTo32(dst *Matrix32, matrix *Matrix) (mat *Matrix32, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.rows != matrix.rows {
+		return nil, fmt.Errorf("can't Mul transposed matrix sized %dx%d and %dx%d",
+			m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+
+	dst = reuse32(dst, m.cols, matrix.cols)
+	for i := range dst.values {
+		dst.values[i] = 0
+	}
+	tMatMul(m.values, matrix.values, dst.values, m.cols, m.rows, matrix.cols)
+	return dst, nil
+}
+
+// reuse32 return <dst> if it is sized rows x cols, otherwise new zero Matrix32.
+func reuse32(dst *Matrix32, rows, cols int) *Matrix32 {
+	if dst != nil && dst.rows == rows && dst.cols == cols {
+		return dst
+	}
+	return wrap32(rows, cols, make([]float32, rows*cols))
+}
@@ -0,0 +1,124 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMatrix32_Conversion(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{1, 2.5, -3, 0.1, 5, 6}, rows: 2, cols: 3})
+
+	m32 := m.Float32()
+	require.Equal(t, []float32{1, 2.5, -3, 0.1, 5, 6}, m32.RawFlat())
+	rows, cols := m32.Size()
+	require.Equal(t, []int{2, 3}, []int{rows, cols})
+
+	back := m32.Float64()
+	require.True(t, m.EqualApprox(back))
+	// values representable in float32 are converted exactly
+	require.Equal(t, []float64{1, 2.5, -3, float64(float32(0.1)), 5, 6}, back.RawFlat())
+
+	dst := m32.Copy()
+	require.Same(t, dst, m.MulNum(2).Float32Into(dst))
+	require.Equal(t, []float32{2, 5, -6, 0.2, 10, 12}, dst.RawFlat())
+	// destination of other shape is not reused
+	other := m.T()
+	require.NotSame(t, other, m32.Float64Into(other))
+
+	var nilMatrix *Matrix
+	require.Nil(t, nilMatrix.Float32())
+
+	_, err := NewMatrix32RawFlat(2, 2, []float32{1, 2, 3})
+	require.ErrorIs(t, err, ErrCreate)
+}
+
+func TestMatrix32_MixedOperations(t *testing.T) {
+	a, b := randomMatrix(5, 7), randomMatrix(7, 3)
+	c, row := randomMatrix(5, 3), randomMatrix(1, 3)
+	b32, row32 := b.Float32(), row.Float32()
+	// operands rounded to float32 the same way as Matrix32 ones
+	b, row = b32.Float64(), row32.Float64()
+
+	tests := []struct {
+		name     string
+		f        func() (*Matrix, error)
+		expected func() (*Matrix, error)
+	}{
+		{
+			name:     "matmul",
+			f:        func() (*Matrix, error) { return a.MatMul32Into(nil, b32) },
+			expected: func() (*Matrix, error) { return a.MatMul(b) },
+		},
+		{
+			name:     "matmul transposed",
+			f:        func() (*Matrix, error) { return c.MatMulT32Into(nil, b32) },
+			expected: func() (*Matrix, error) { return c.MatMulT(b) },
+		},
+		{
+			name: "transposed matmul",
+			f: func() (*Matrix, error) {
+				res, err := a.TMatMulTo32(nil, c)
+				return res.Float64(), err
+			},
+			expected: func() (*Matrix, error) { return a.TMatMul(c) },
+		},
+		{
+			name:     "add row",
+			f:        func() (*Matrix, error) { return c.AddRow32Into(nil, row32) },
+			expected: func() (*Matrix, error) { return c.AddRowM(row) },
+		},
+		{
+			name: "sum cols",
+			f: func() (*Matrix, error) {
+				res, err := a.SumColsTo32(nil)
+				return res.Float64(), err
+			},
+			expected: func() (*Matrix, error) { return a.SumAxedM(Vertical) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected, err := test.expected()
+			require.NoError(t, err)
+			res, err := test.f()
+			require.NoError(t, err)
+			require.True(t, expected.EqualApprox(res), "%s != %s", expected, res)
+		})
+	}
+
+	// products with Matrix32 operand are computed in double precision, so they are exact as Matrix ones
+	product, err := a.MatMul(b)
+	require.NoError(t, err)
+	res, err := a.MatMul32Into(nil, b32)
+	require.NoError(t, err)
+	require.True(t, product.Equal(res))
+
+	_, err = a.MatMul32Into(nil, a.Float32())
+	require.ErrorIs(t, err, ErrExec)
+	_, err = c.AddRow32Into(nil, b32)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = a.TMatMulTo32(nil, b)
+	require.ErrorIs(t, err, ErrExec)
+
+	dst := c.Copy()
+	res, err = a.MatMul32Into(dst, b32)
+	require.NoError(t, err)
+	require.Same(t, dst, res)
+	require.True(t, product.Equal(res))
+
+	dst32 := b32.Copy()
+	res32, err := a.TMatMulTo32(dst32, c)
+	require.NoError(t, err)
+	require.Same(t, dst32, res32)
+
+	// row is added in place, destination sharing values with operand otherwise is not reused
+	inPlace := c.Copy()
+	res, err = inPlace.AddRow32Into(inPlace, row32)
+	require.NoError(t, err)
+	require.Same(t, inPlace, res)
+	square := randomMatrix(3, 3)
+	res, err = square.MatMul32Into(square, square.Float32())
+	require.NoError(t, err)
+	require.NotSame(t, square, res)
+}
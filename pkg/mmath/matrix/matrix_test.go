@@ -34,7 +34,7 @@ func TestNewMatrix(t *testing.T) {
 				require.Equal(t, len(test.in[0]), matrix.cols)
 				for i, row := range test.in {
 					for j, value := range row {
-						actual, err := matrix.vectors[i].Get(j)
+						actual, err := matrix.Get(i, j)
 						require.NoError(t, err)
 						require.Equal(t, value, actual)
 					}
@@ -72,7 +72,7 @@ func TestNewMatrixFlat(t *testing.T) {
 				require.Equal(t, test.cols, matrix.cols)
 				for i := 0; i < test.rows; i++ {
 					for j := 0; j < test.cols; j++ {
-						actual, err := matrix.vectors[i].Get(j)
+						actual, err := matrix.Get(i, j)
 						expected := test.in[i*test.rows+j]
 						require.NoError(t, err)
 						require.Equal(t, expected, actual)
@@ -113,7 +113,7 @@ func TestNewMatrixRaw(t *testing.T) {
 				require.Equal(t, len(test.in[0]), matrix.cols)
 				for i, row := range test.in {
 					for j, value := range row {
-						actual, err := matrix.vectors[i].Get(j)
+						actual, err := matrix.Get(i, j)
 						require.NoError(t, err)
 						require.Equal(t, value, actual)
 					}
@@ -150,7 +150,7 @@ func TestNewMatrixRawFlat(t *testing.T) {
 				require.Equal(t, test.cols, matrix.cols)
 				for i := 0; i < test.rows; i++ {
 					for j := 0; j < test.cols; j++ {
-						actual, err := matrix.vectors[i].Get(j)
+						actual, err := matrix.Get(i, j)
 						expected := test.in[i*test.rows+j]
 						require.NoError(t, err)
 						require.Equal(t, expected, actual)
@@ -164,6 +164,25 @@ func TestNewMatrixRawFlat(t *testing.T) {
 	}
 }
 
+func TestNewMatrixShared(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6}
+	matrix, err := NewMatrixShared(2, 3, values)
+	require.NoError(t, err)
+	require.Equal(t, 2, matrix.rows)
+	require.Equal(t, 3, matrix.cols)
+
+	// matrix shares values with given slice
+	values[5] = 10
+	value, err := matrix.Get(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, 10.0, value)
+
+	_, err = NewMatrixShared(2, 2, values)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = NewMatrixShared(0, 6, values)
+	require.ErrorIs(t, err, ErrCreate)
+}
+
 func TestNewMatrixOf(t *testing.T) {
 	matrix, err := NewMatrixOf(2, 3, 2)
 	require.NoError(t, err)
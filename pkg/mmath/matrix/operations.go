@@ -5,6 +5,7 @@ import (
 	"math"
 	"nn/pkg/mmath/vector"
 	"nn/pkg/wraperr"
+	"runtime"
 	"sync"
 )
 
@@ -24,18 +25,44 @@ func (m *Matrix) T() *Matrix {
 	if m == nil {
 		return nil
 	}
-	cols := make([]*vector.Vector, m.cols)
+	values := make([]float64, len(m.values))
+	for i := 0; i < m.rows; i++ {
+		for j, value := range m.row(i) {
+			values[j*m.rows+i] = value
+		}
+	}
+
+	return wrap(m.cols, m.rows, values)
+}
 
-	for j := 0; j < m.cols; j++ {
-		col, _ := m.GetCol(j)
-		cols[j] = col
+// Reshape return Matrix with given rows and cols count holding the same values in the same (row by row) order.
+// Rows*cols must match count of Matrix values.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 3 |.Reshape(3, 2) = | 1 2 |
+//     | 4 5 6 |                 | 3 4 |
+//                               | 5 6 |
+func (m *Matrix) Reshape(rows, cols int) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if rows*cols != m.rows*m.cols {
+		return nil, fmt.Errorf("can not reshape %dx%d matrix to %dx%d", m.rows, m.cols, rows, cols)
 	}
 
-	matrix, _ := NewMatrix(cols)
-	return matrix
+	return wrap(rows, cols, m.RawFlat()), nil
 }
 
-const ParallelThreshold = 64
+// ParallelThreshold is min count of multiply-add operations (rows * inner size * cols) of matrix multiplication
+// to split its result rows between goroutines, count of goroutines is limited by runtime.GOMAXPROCS. It is not safe
+// to change it concurrently with multiplications.
+var ParallelThreshold = 1 << 16
+
+// blockSize is size of square blocks of matrix multiplication, processed at once to reuse cached values.
+const blockSize = 64
 
 // MatMul perform matrix multiplication. This matrix cols count must match given matrix rows count.
 //
@@ -56,64 +83,117 @@ func (m *Matrix) MatMul(matrix *Matrix) (mat *Matrix, err error) {
 		return nil, fmt.Errorf("can't Mul matrices sized %dx%d and %dx%d", m.rows, m.cols, matrix.rows, matrix.cols)
 	}
 
-	//if m.rows*matrix.cols > ParallelThreshold {
-	//	return m.matMulImplMulti(matrix)
-	//}
-	return m.matMulImplSingle(matrix)
+	values := make([]float64, m.rows*matrix.cols)
+	m.matMulInto(matrix, values)
+	return wrap(m.rows, matrix.cols, values), nil
 }
 
-func (m *Matrix) matMulImplSingle(matrix *Matrix) (mat *Matrix, err error) {
-	N, M := m.rows, matrix.cols
+// matMulInto writes matrix multiplication to <values> (zeroed, sized m.rows x matrix.cols).
+func (m *Matrix) matMulInto(matrix *Matrix, values []float64) {
+	if m.rows*m.cols*matrix.cols >= ParallelThreshold {
+		m.matMulImplMulti(matrix, values)
+	} else {
+		m.matMulImplSingle(matrix, values)
+	}
+}
 
-	rows := m.vectors
-	cols := matrix.T().vectors
+func (m *Matrix) matMulImplSingle(matrix *Matrix, values []float64) {
+	matMulRows(m.values, matrix.values, values, m.cols, matrix.cols, 0, m.rows)
+}
 
-	values := make([][]float64, N)
-	var value float64
-	var rawRow []float64
-	for i, row := range rows {
-		rawRow = make([]float64, M)
-		for j, col := range cols {
-			value, err = row.MulScalar(col)
-			if err != nil {
-				return nil, err
-			}
-			rawRow[j] = value
-		}
-		values[i] = rawRow
+func (m *Matrix) matMulImplMulti(matrix *Matrix, values []float64) {
+	inParallel(m.rows, func(start, stop int) {
+		matMulRows(m.values, matrix.values, values, m.cols, matrix.cols, start, stop)
+	})
+}
+
+// MatMulT perform matrix multiplication of this matrix and transposed given matrix with no transposition:
+// m.MatMulT(matrix) is equal to m.MatMul(matrix.T()). This and given matrix cols counts must match.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 |.MatMulT(| 5 6 |) = | 1*5 + 2*6 1*7 + 2*8 | = | 17 23 |
+//     | 3 4 |         | 7 8 |    | 3*5 + 4*6 3*7 + 4*8 |   | 39 53 |
+func (m *Matrix) MatMulT(matrix *Matrix) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.cols != matrix.cols {
+		return nil, fmt.Errorf("can't Mul matrix sized %dx%d and transposed %dx%d",
+			m.rows, m.cols, matrix.rows, matrix.cols)
 	}
 
-	return NewMatrixRaw(values)
+	values := make([]float64, m.rows*matrix.rows)
+	m.matMulTInto(matrix, values)
+	return wrap(m.rows, matrix.rows, values), nil
 }
 
-func (m *Matrix) matMulImplMulti(matrix *Matrix) (mat *Matrix, err error) {
-	N, M := m.rows, matrix.cols
+// matMulTInto writes multiplication of this and transposed given matrix to <values> (sized m.rows x matrix.rows).
+func (m *Matrix) matMulTInto(matrix *Matrix, values []float64) {
+	matMulT(m.values, matrix.values, values, m.rows, m.cols, matrix.rows)
+}
 
-	rows := m.vectors
-	cols := matrix.T().vectors
+// TMatMul perform matrix multiplication of transposed this matrix and given matrix with no transposition:
+// m.TMatMul(matrix) is equal to m.T().MatMul(matrix). This and given matrix rows counts must match.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 |.TMatMul(| 5 6 |) = | 1*5 + 3*7 1*6 + 3*8 | = | 26 30 |
+//     | 3 4 |         | 7 8 |    | 2*5 + 4*7 2*6 + 4*8 |   | 38 44 |
+func (m *Matrix) TMatMul(matrix *Matrix) (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
 
-	values := make([][]float64, N)
-	var value float64
-	var rawRow []float64
+	if m == nil {
+		return nil, ErrNil
+	} else if matrix == nil {
+		return nil, fmt.Errorf("no second matrix provided for matrix multiplication: %v", matrix)
+	} else if m.rows != matrix.rows {
+		return nil, fmt.Errorf("can't Mul transposed matrix sized %dx%d and %dx%d",
+			m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+
+	values := make([]float64, m.cols*matrix.cols)
+	m.tMatMulInto(matrix, values)
+	return wrap(m.cols, matrix.cols, values), nil
+}
+
+// tMatMulInto writes multiplication of transposed this and given matrix to <values> (zeroed, sized m.cols x
+// matrix.cols).
+func (m *Matrix) tMatMulInto(matrix *Matrix, values []float64) {
+	tMatMul(m.values, matrix.values, values, m.cols, m.rows, matrix.cols)
+}
+
+// inParallel splits [0; rows) to consecutive ranges, one per goroutine up to runtime.GOMAXPROCS, and calls f for
+// each range concurrently. Ranges do not intersect, so f may write rows of shared result with no synchronization.
+func inParallel(rows int, f func(start, stop int)) {
+	workers := minInt(runtime.GOMAXPROCS(0), rows)
+	if workers < 2 {
+		f(0, rows)
+		return
+	}
+
+	chunk := (rows + workers - 1) / workers
 	wg := sync.WaitGroup{}
-	for i, row := range rows {
+	for start := 0; start < rows; start += chunk {
 		wg.Add(1)
-		rawRow = make([]float64, M)
-		values[i] = rawRow
-		go func(row *vector.Vector, arr []float64) {
+		go func(start, stop int) {
 			defer wg.Done()
-			for j, col := range cols {
-				value, err = row.MulScalar(col)
-				if err != nil {
-					panic(err)
-				}
-				arr[j] = value
-			}
-		}(row, rawRow)
+			f(start, stop)
+		}(start, minInt(start+chunk, rows))
 	}
-
 	wg.Wait()
-	return NewMatrixRaw(values)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
 }
 
 // Add return a + b
@@ -145,60 +225,151 @@ func (m *Matrix) ApplyFunc(operation UnaryOperation) *Matrix {
 	if m == nil {
 		return nil
 	}
-	vectors := make([]*vector.Vector, m.rows)
-	wrap := func(a float64) float64 {
-		return operation(a)
-	} // wrap to conform vector.UnaryOperation
-
-	for i, row := range m.vectors {
-		vectors[i] = row.ApplyFunc(wrap)
+	values := make([]float64, len(m.values))
+	for i, value := range m.values {
+		values[i] = operation(value)
 	}
 
-	matrix, err := NewMatrix(vectors)
-	if err != nil {
-		panic(err)
-	}
+	return wrap(m.rows, m.cols, values)
+}
 
-	return matrix
+// apply return Matrix of given BinaryOperation applied to values of this Matrix and values of <row> repeated for
+// each row and <col> repeated for each col. Row must be sized as Matrix's cols or be nil, col must be sized as
+// Matrix's rows or be nil. Only one of row and col is used, value of number is used if both are nil.
+func (m *Matrix) apply(operation BinaryOperation, row, col []float64, number float64) *Matrix {
+	values := make([]float64, len(m.values))
+	m.applyInto(values, operation, row, col, number)
+	return wrap(m.rows, m.cols, values)
 }
 
-func (m *Matrix) apply(operation BinaryOperation, provider func(row, col int) (float64, error)) (*Matrix, error) {
-	values := make([][]float64, m.rows)
+// applyInto writes result of apply to <values> sized as Matrix, <values> may be values of this Matrix.
+func (m *Matrix) applyInto(values []float64, operation BinaryOperation, row, col []float64, number float64) {
 	for i := 0; i < m.rows; i++ {
-		rawRow := make([]float64, m.cols)
-		for j := 0; j < m.cols; j++ {
-			a, _ := m.Get(i, j)
-			b, err := provider(i, j)
-			if err != nil {
-				return nil, err
+		res, src := values[i*m.cols:(i+1)*m.cols], m.row(i)
+		switch {
+		case row != nil:
+			for j, a := range src {
+				res[j] = operation(a, row[j])
+			}
+		case col != nil:
+			for j, a := range src {
+				res[j] = operation(a, col[i])
+			}
+		default:
+			for j, a := range src {
+				res[j] = operation(a, number)
 			}
-			rawRow[j] = operation(a, b)
 		}
-		values[i] = rawRow
 	}
-
-	return NewMatrixRaw(values)
 }
 
-// ApplyFuncMat applies given BinaryOperation to this and given Matrix. Matrices must have same shape.
+// ApplyFuncMat applies given BinaryOperation to this and given Matrix with broadcasting: rows counts of matrices
+// must be equal or one of them must be 1, the same is for cols counts. Matrix with single row (col) is repeated for
+// each row (col) of result, so rows, cols and single values broadcast automatically.
 //
 // Throws ErrExec error.
 //
 // Example:
-//     | 1 2 |.ApplyFuncMat(| 5 6 |, Add) = | 1+2 2+6 | = |  3  8 |
+//     | 1 2 |.ApplyFuncMat(| 5 6 |, Add) = | 1+5 2+6 | = |  6  8 |
 //     | 3 4 |              | 7 8 |         | 3+7 4+8 |   | 10 12 |
+//
+//     | 1 2 |.ApplyFuncMat(| 5 6 |, Add) = | 1+5 2+6 | = | 6  8 |
+//     | 3 4 |                              | 3+5 4+6 |   | 8 10 |
+//
+//     | 1 2 |.ApplyFuncMat(| 5 |, Add) = | 1+5 2+5 | = | 6  7 |
+//     | 3 4 |              | 6 |         | 3+6 4+6 |   | 9 10 |
+//
+//     | 1 2 |.ApplyFuncMat(| 5 |, Add) = | 1+5 2+5 | = |  6  7 |
+//                          | 6 |         | 1+6 2+6 |   |  7  8 |
 func (m *Matrix) ApplyFuncMat(matrix *Matrix, operation BinaryOperation) (mat *Matrix, err error) {
 	defer wraperr.WrapError(ErrExec, &err)
 
 	if m == nil {
 		return nil, ErrNil
-	} else if matrix == nil {
-		return nil, fmt.Errorf("no second matrix provided: %v", matrix)
-	} else if matrix.Rows() != m.rows || matrix.Cols() != m.cols {
-		return nil, fmt.Errorf("matrix size mismatces: %dx%d != %dx%d", m.rows, m.cols, matrix.Rows(), matrix.Cols())
+	}
+	return m.applyMatInto(nil, matrix, operation)
+}
+
+// broadcastShape return shape of result of element-wise operation on this and given Matrix with broadcasting.
+func (m *Matrix) broadcastShape(matrix *Matrix) (rows, cols int, err error) {
+	if matrix == nil {
+		return 0, 0, fmt.Errorf("no second matrix provided: %v", matrix)
+	}
+	rows, okRows := broadcastDim(m.rows, matrix.rows)
+	cols, okCols := broadcastDim(m.cols, matrix.cols)
+	if !okRows || !okCols {
+		return 0, 0, fmt.Errorf("matrix shapes are not broadcastable: %dx%d and %dx%d",
+			m.rows, m.cols, matrix.rows, matrix.cols)
+	}
+	return rows, cols, nil
+}
+
+func broadcastDim(a, b int) (int, bool) {
+	switch {
+	case a == b || b == 1:
+		return a, true
+	case a == 1:
+		return b, true
+	default:
+		return 0, false
+	}
+}
+
+// applyMatInto return result of ApplyFuncMat written to <dst> if it is shaped as the result and does not share
+// values with broadcast operand (result may be written over operand shaped as the result).
+func (m *Matrix) applyMatInto(dst, matrix *Matrix, operation BinaryOperation) (*Matrix, error) {
+	rows, cols, err := m.broadcastShape(matrix)
+	if err != nil {
+		return nil, err
+	}
+
+	dst = reuse(dst, rows, cols)
+	if (shares(dst, m) && !aliases(dst, m)) || (shares(dst, matrix) && !aliases(dst, matrix)) {
+		dst = wrap(rows, cols, make([]float64, rows*cols))
+	}
+	m.applyBroadcastInto(dst.values, matrix, rows, cols, operation)
+	return dst, nil
+}
+
+// applyBroadcastInto writes given BinaryOperation applied to this and given Matrix broadcast to rows x cols to
+// <values>, <values> may be values of operand shaped as the result.
+func (m *Matrix) applyBroadcastInto(values []float64, matrix *Matrix, rows, cols int, operation BinaryOperation) {
+	if m.rows == rows && m.cols == cols {
+		switch {
+		case matrix.rows == rows && matrix.cols == cols:
+			for i, a := range m.values {
+				values[i] = operation(a, matrix.values[i])
+			}
+		case matrix.rows == rows:
+			m.applyInto(values, operation, nil, matrix.values, 0)
+		case matrix.cols == cols:
+			m.applyInto(values, operation, matrix.values, nil, 0)
+		default:
+			m.applyInto(values, operation, nil, nil, matrix.values[0])
+		}
+		return
 	}
 
-	return m.apply(operation, func(i, j int) (float64, error) { return matrix.Get(i, j) })
+	mRow, mCol := m.broadcastStrides()
+	row, col := matrix.broadcastStrides()
+	for i := 0; i < rows; i++ {
+		res := values[i*cols : (i+1)*cols]
+		for j := range res {
+			res[j] = operation(m.values[i*mRow+j*mCol], matrix.values[i*row+j*col])
+		}
+	}
+}
+
+// broadcastStrides return distances between values of neighbour rows and cols of Matrix, they are zero for single
+// row and col as it is repeated on broadcasting.
+func (m *Matrix) broadcastStrides() (row, col int) {
+	if m.rows > 1 {
+		row = m.cols
+	}
+	if m.cols > 1 {
+		col = 1
+	}
+	return row, col
 }
 
 // ApplyFuncNum applies given BinaryOperation to this and given float
@@ -211,16 +382,11 @@ func (m *Matrix) ApplyFuncNum(number float64, operation BinaryOperation) *Matrix
 		return nil
 	}
 
-	matrix, err := m.apply(operation, func(i, j int) (float64, error) { return number, nil })
-	if err != nil {
-		panic(err)
-	}
-
-	return matrix
+	return m.apply(operation, nil, nil, number)
 }
 
-// ApplyFuncMatRow applies given BinaryOperation to this and row as Matrix (row.Rows() == 1).
-// This and row's cols count must match.
+// ApplyFuncMatRow applies given BinaryOperation to this and row as Matrix (row.Rows() == 1), it is ApplyFuncMat
+// restricted to row of this Matrix's cols count.
 //
 // Throws ErrExec error.
 //
@@ -232,66 +398,65 @@ func (m *Matrix) ApplyFuncMatRow(row *Matrix, operation BinaryOperation) (mat *M
 
 	if m == nil {
 		return nil, ErrNil
-	} else if row == nil {
-		return nil, fmt.Errorf("no row provided: %v", row)
-	} else if row.rows != 1 {
-		return nil, fmt.Errorf("matrix is not row: %dx%d", row.rows, row.cols)
-	} else if row.cols != m.cols {
-		return nil, fmt.Errorf("matrix size mismatces: %dx%d != %dx%d", m.rows, m.cols, row.rows, row.cols)
+	} else if err = m.checkRow(row); err != nil {
+		return nil, err
 	}
-
-	return m.apply(operation, func(i, j int) (float64, error) { return row.Get(0, j) })
+	return m.applyMatInto(nil, row, operation)
 }
 
-// ApplyFuncMatCol applies given BinaryOperation to this and col as Matrix (col.Cols() == 1).
-// This and col's rows count must match.
+// ApplyFuncMatCol applies given BinaryOperation to this and col as Matrix (col.Cols() == 1), it is ApplyFuncMat
+// restricted to col of this Matrix's rows count.
 //
 // Throws ErrExec error.
 //
 // Example:
-//     | 1 2 |.ApplyFuncMatRow(| 5 |, Add) = | 1+5 2+5 | = | 6  7 |
+//     | 1 2 |.ApplyFuncMatCol(| 5 |, Add) = | 1+5 2+5 | = | 6  7 |
 //     | 3 4 |                 | 6 |         | 3+6 4+6 |   | 9 10 |
 func (m *Matrix) ApplyFuncMatCol(col *Matrix, operation BinaryOperation) (mat *Matrix, err error) {
 	defer wraperr.WrapError(ErrExec, &err)
 
-	if col == nil {
-		return nil, fmt.Errorf("no col provided: %v", col)
-	} else if col.cols != 1 {
-		return nil, fmt.Errorf("matrix is not col: %dx%d", col.rows, col.cols)
-	} else if col.rows != m.rows {
-		return nil, fmt.Errorf("matrix size mismatces: %dx%d != %dx%d", m.rows, m.cols, col.rows, col.cols)
+	if m == nil {
+		return nil, ErrNil
+	} else if err = m.checkCol(col); err != nil {
+		return nil, err
 	}
-	return m.apply(operation, func(i, j int) (float64, error) { return col.Get(i, 0) })
+	return m.applyMatInto(nil, col, operation)
 }
 
-// ApplyFuncVecRow is same as ApplyFuncMatCol
+// ApplyFuncVecRow is same as ApplyFuncMatRow
 func (m *Matrix) ApplyFuncVecRow(row *vector.Vector, operation BinaryOperation) (mat *Matrix, err error) {
-	defer wraperr.WrapError(ErrExec, &err)
-
-	if m == nil {
-		return nil, ErrNil
-	} else if row == nil {
-		return nil, fmt.Errorf("no row provided: %v", row)
-	} else if row.Size() != m.cols {
-		return nil, fmt.Errorf("matrix size mismatces: %dx%d != %dx%d", m.rows, m.cols, row.Size(), 1)
+	if row == nil {
+		return m.ApplyFuncMatRow(nil, operation)
 	}
-
-	return m.apply(operation, func(i, j int) (float64, error) { return row.Get(j) })
+	return m.ApplyFuncMatRow(wrap(1, row.Size(), row.Raw()), operation)
 }
 
 // ApplyFuncVecCol is same as ApplyFuncMatCol
 func (m *Matrix) ApplyFuncVecCol(col *vector.Vector, operation BinaryOperation) (mat *Matrix, err error) {
-	defer wraperr.WrapError(ErrExec, &err)
+	if col == nil {
+		return m.ApplyFuncMatCol(nil, operation)
+	}
+	return m.ApplyFuncMatCol(wrap(col.Size(), 1, col.Raw()), operation)
+}
 
-	if m == nil {
-		return nil, ErrNil
-	} else if col == nil {
-		return nil, fmt.Errorf("no col provided: %v", col)
-	} else if col.Size() != m.rows {
-		return nil, fmt.Errorf("matrix size mismatces: %dx%d != %dx%d", m.rows, m.cols, col.Size(), 1)
+// checkRow return error if given Matrix is not row of this Matrix's cols count.
+func (m *Matrix) checkRow(row *Matrix) error {
+	if row == nil {
+		return fmt.Errorf("no row provided: %v", row)
+	} else if row.rows != 1 || row.cols != m.cols {
+		return fmt.Errorf("matrix %dx%d is not row of matrix %dx%d", row.rows, row.cols, m.rows, m.cols)
 	}
+	return nil
+}
 
-	return m.apply(operation, func(i, j int) (float64, error) { return col.Get(i) })
+// checkCol return error if given Matrix is not col of this Matrix's rows count.
+func (m *Matrix) checkCol(col *Matrix) error {
+	if col == nil {
+		return fmt.Errorf("no col provided: %v", col)
+	} else if col.cols != 1 || col.rows != m.rows {
+		return fmt.Errorf("matrix %dx%d is not col of matrix %dx%d", col.rows, col.cols, m.rows, m.cols)
+	}
+	return nil
 }
 
 // See ApplyFuncMat and Add
@@ -440,20 +605,11 @@ func (m *Matrix) ReduceAxed(axis Axis, operation BinaryOperation) (vec *vector.V
 		return nil, ErrNil
 	}
 
-	switch axis {
-	case Horizontal:
-		values := make([]float64, m.rows)
-		for i, row := range m.vectors {
-			values[i] = row.Reduce(func(a, b float64) float64 {
-				return operation(a, b)
-			})
-		}
-		return vector.NewVector(values)
-	case Vertical:
-		return m.T().ReduceAxed(Horizontal, operation)
-	default:
-		return nil, fmt.Errorf("unknown axis: %d", axis)
+	values, err := m.reduceAxed(axis, operation)
+	if err != nil {
+		return nil, err
 	}
+	return vector.NewVector(values)
 }
 
 // ReduceAxedM same as ReduceAxed but result is Matrix (not Vector)
@@ -464,29 +620,53 @@ func (m *Matrix) ReduceAxedM(axis Axis, operation BinaryOperation) (mat *Matrix,
 		return nil, ErrNil
 	}
 
-	wrap := func(a, b float64) float64 {
-		return operation(a, b)
+	values, err := m.reduceAxed(axis, operation)
+	if err != nil {
+		return nil, err
+	} else if axis == Horizontal {
+		return wrap(m.rows, 1, values), nil
 	}
+	return wrap(1, m.cols, values), nil
+}
 
+// reduceAxed return values of reduced rows (Horizontal) or cols (Vertical), each reduced starting from its first value.
+func (m *Matrix) reduceAxed(axis Axis, operation BinaryOperation) ([]float64, error) {
 	switch axis {
 	case Horizontal:
 		values := make([]float64, m.rows)
-		for i, row := range m.vectors {
-			values[i] = row.Reduce(wrap)
-		}
-		return NewMatrixRawFlat(m.rows, 1, values)
+		m.reduceAxedInto(values, axis, operation)
+		return values, nil
 	case Vertical:
 		values := make([]float64, m.cols)
-		t := m.T()
-		for j, col := range t.vectors {
-			values[j] = col.Reduce(wrap)
-		}
-		return NewMatrixRawFlat(1, m.cols, values)
+		m.reduceAxedInto(values, axis, operation)
+		return values, nil
 	default:
 		return nil, fmt.Errorf("unknown axis: %d", axis)
 	}
 }
 
+// reduceAxedInto writes result of reduceAxed with known axis to <values> sized as Matrix's rows (Horizontal) or
+// cols (Vertical).
+func (m *Matrix) reduceAxedInto(values []float64, axis Axis, operation BinaryOperation) {
+	if axis == Horizontal {
+		for i := range values {
+			row := m.row(i)
+			res := row[0]
+			for _, value := range row[1:] {
+				res = operation(res, value)
+			}
+			values[i] = res
+		}
+		return
+	}
+	copy(values, m.row(0))
+	for i := 1; i < m.rows; i++ {
+		for j, value := range m.row(i) {
+			values[j] = operation(values[j], value)
+		}
+	}
+}
+
 // Reduce applies given BinaryOperation to Horizontal and Vertical axes for ReduceAxed resulting in a single float value
 func (m *Matrix) Reduce(operation BinaryOperation) float64 {
 	vec, _ := m.ReduceAxed(Horizontal, operation)
@@ -613,17 +793,30 @@ func (m *Matrix) SubMatrix(rowsStart, rowsStop, rowsStep, colsStart, colsStop, c
 		return nil, fmt.Errorf("zero resulting Sub matrix length with start %d stop %d step %d rows %d", rowsStart, rowsStop, rowsStep, m.rows)
 	}
 
-	values := make([]*vector.Vector, resLen)
+	colsLen := int(math.Ceil(float64(colsStop-colsStart) / float64(colsStep)))
+	if colsStop-colsStart < 1 {
+		return nil, fmt.Errorf("wrong start and stop col indicies for Sub matrix: %d >= %d", colsStart, colsStop)
+	} else if colsStart < 0 {
+		return nil, fmt.Errorf("negative start col index for Sub matrix: %d", colsStart)
+	} else if colsStep < 1 {
+		return nil, fmt.Errorf("negative or zero col step for Sub matrix: %d", colsStep)
+	} else if m.cols < colsStop {
+		return nil, fmt.Errorf("stop col index for Sub matrix out of matrix cols count: %d > %d", colsStop, m.cols)
+	}
 
+	values := make([]float64, 0, resLen*colsLen)
 	for i, cnt := rowsStart, 0; i < rowsStop && cnt < resLen; i, cnt = i+rowsStep, cnt+1 {
-		slice, err := m.vectors[i].Slice(colsStart, colsStop, colsStep)
-		if err != nil {
-			return nil, err
+		row := m.row(i)
+		if colsStep == 1 {
+			values = append(values, row[colsStart:colsStop]...)
+			continue
+		}
+		for j := colsStart; j < colsStop; j += colsStep {
+			values = append(values, row[j])
 		}
-		values[cnt] = slice
 	}
 
-	return NewMatrix(values)
+	return wrap(resLen, colsLen, values), nil
 }
 
 // HStack stacks this and given matrices by Horizontal Axis. This and given matrices must have same rows count.
@@ -642,22 +835,26 @@ func (m *Matrix) HStack(matrices []*Matrix) (mat *Matrix, err error) {
 		return nil, fmt.Errorf("no matrices provided for horizontal stacking")
 	}
 
-	vectors := m.Copy().vectors
-	for k := 0; k < len(matrices); k++ {
-		if matrices[k].rows != m.rows {
+	cols := m.cols
+	for k, matrix := range matrices {
+		if matrix == nil {
+			return nil, fmt.Errorf("%d'th matrix is nil: %v", k, matrix)
+		} else if matrix.rows != m.rows {
 			return nil, fmt.Errorf("%d'th matrix rows count mismatch first matrix rows count: %d != %d",
-				k, matrices[k].rows, m.rows)
+				k, matrix.rows, m.rows)
 		}
+		cols += matrix.cols
+	}
 
-		for i := 0; i < m.rows; i++ {
-			vectors[i], err = vectors[i].Concatenate(matrices[k].vectors[i])
-			if err != nil {
-				return nil, err
-			}
+	values := make([]float64, 0, m.rows*cols)
+	for i := 0; i < m.rows; i++ {
+		values = append(values, m.row(i)...)
+		for _, matrix := range matrices {
+			values = append(values, matrix.row(i)...)
 		}
 	}
 
-	return NewMatrix(vectors)
+	return wrap(m.rows, cols, values), nil
 }
 
 // VStack stacks this and given matrices by Vertical Axis. This and given matrices must have same cols count.
@@ -680,12 +877,24 @@ func (m *Matrix) VStack(matrices []*Matrix) (mat *Matrix, err error) {
 		return nil, fmt.Errorf("no matrices provided for vertical stacking")
 	}
 
-	vectors := m.Copy().vectors
-	for k := 0; k < len(matrices); k++ {
-		vectors = append(vectors, matrices[k].Copy().vectors...)
+	rows := m.rows
+	for k, matrix := range matrices {
+		if matrix == nil {
+			return nil, fmt.Errorf("%d'th matrix is nil: %v", k, matrix)
+		} else if matrix.cols != m.cols {
+			return nil, fmt.Errorf("%d'th matrix cols count mismatch first matrix cols count: %d != %d",
+				k, matrix.cols, m.cols)
+		}
+		rows += matrix.rows
+	}
+
+	values := make([]float64, 0, rows*m.cols)
+	values = append(values, m.values...)
+	for _, matrix := range matrices {
+		values = append(values, matrix.values...)
 	}
 
-	return NewMatrix(vectors)
+	return wrap(rows, m.cols, values), nil
 }
 
 func (m *Matrix) Equal(matrix *Matrix) bool {
@@ -695,12 +904,12 @@ func (m *Matrix) Equal(matrix *Matrix) bool {
 		} else {
 			return true // nil == nil
 		}
-	} else if m.rows != matrix.rows {
+	} else if m.rows != matrix.rows || m.cols != matrix.cols {
 		return false
 	}
 
-	for i := 0; i < m.rows; i++ {
-		if !m.vectors[i].Equal(matrix.vectors[i]) {
+	for i, value := range m.values {
+		if value != matrix.values[i] {
 			return false
 		}
 	}
@@ -715,12 +924,12 @@ func (m *Matrix) EqualApprox(matrix *Matrix) bool {
 		} else {
 			return true // nil == nil
 		}
-	} else if m.rows != matrix.rows {
+	} else if m.rows != matrix.rows || m.cols != matrix.cols {
 		return false
 	}
 
-	for i := 0; i < m.rows; i++ {
-		if !m.vectors[i].EqualApprox(matrix.vectors[i]) {
+	for i, value := range m.values {
+		if math.Abs(value-matrix.values[i]) > vector.Epsilon {
 			return false
 		}
 	}
@@ -762,12 +971,12 @@ func (m *Matrix) Order(indices []int) (mat *Matrix, err error) {
 		}
 	}
 
-	vectors := make([]*vector.Vector, m.rows)
-	for i, index := range indices {
-		vectors[i] = m.vectors[index].Copy()
+	values := make([]float64, 0, len(m.values))
+	for _, index := range indices {
+		values = append(values, m.row(index)...)
 	}
 
-	return NewMatrix(vectors)
+	return wrap(m.rows, m.cols, values), nil
 }
 
 // CartesianProduct perform cartesian multiplication. There is no restrictions to size of vectors.
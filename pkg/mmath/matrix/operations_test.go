@@ -1,10 +1,12 @@
 package matrix
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/require"
 	"math"
 	"math/rand"
 	"nn/pkg/mmath/vector"
+	"runtime"
 	"testing"
 )
 
@@ -46,6 +48,39 @@ func TestMatrix_T(t *testing.T) {
 	}
 }
 
+func TestMatrix_Reshape(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         []float64
+		rows, cols int
+		newRows    int
+		newCols    int
+		err        error
+	}{
+		{name: "2x3 to 3x2", in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3, newRows: 3, newCols: 2},
+		{name: "2x3 to 1x6", in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3, newRows: 1, newCols: 6},
+		{name: "2x3 to 6x1", in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3, newRows: 6, newCols: 1},
+		{name: "size mismatch", in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3, newRows: 4, newCols: 2, err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matrix, err := NewMatrixRawFlat(test.rows, test.cols, test.in)
+			require.NoError(t, err)
+
+			reshaped, err := matrix.Reshape(test.newRows, test.newCols)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.newRows, reshaped.Rows())
+			require.Equal(t, test.newCols, reshaped.Cols())
+			require.Equal(t, test.in, reshaped.RawFlat())
+		})
+	}
+}
+
 func TestMatrix_MatMul(t *testing.T) {
 	tests := []struct {
 		testBase
@@ -101,6 +136,115 @@ func TestMatrix_MatMul(t *testing.T) {
 	}
 }
 
+func TestMatrix_MatMulT(t *testing.T) {
+	tests := []twoMatrixTest{
+		{
+			testBase: testBase{name: "2x2 matmul transposed 2x2", expected: []float64{17, 23, 39, 53}},
+			a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{5, 6, 7, 8}, rows: 2, cols: 2},
+		},
+		{
+			testBase: testBase{name: "2x3 matmul transposed 4x3", expected: []float64{14, 32, 50, 68, 32, 77, 122, 167}},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			b:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, rows: 4, cols: 3},
+		},
+		{
+			testBase: testBase{name: "2x3 matmul transposed 3x4, error", err: ErrExec},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			b:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, rows: 3, cols: 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matrix, err := newMatrix(t, test.a).MatMulT(newMatrix(t, test.b))
+			makeAssertions(t, matrixInput{in: test.expected, rows: test.a.rows, cols: test.b.rows}, test.err, err, matrix)
+		})
+	}
+
+	_, err := newMatrix(t, tests[0].a).MatMulT(nil)
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_TMatMul(t *testing.T) {
+	tests := []twoMatrixTest{
+		{
+			testBase: testBase{name: "transposed 2x2 matmul 2x2", expected: []float64{26, 30, 38, 44}},
+			a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{5, 6, 7, 8}, rows: 2, cols: 2},
+		},
+		{
+			testBase: testBase{name: "transposed 3x2 matmul 3x4", expected: []float64{61, 70, 79, 88, 76, 88, 100, 112}},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 3, cols: 2},
+			b:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, rows: 3, cols: 4},
+		},
+		{
+			testBase: testBase{name: "transposed 2x3 matmul 3x4, error", err: ErrExec},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			b:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, rows: 3, cols: 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matrix, err := newMatrix(t, test.a).TMatMul(newMatrix(t, test.b))
+			makeAssertions(t, matrixInput{in: test.expected, rows: test.a.cols, cols: test.b.cols}, test.err, err, matrix)
+		})
+	}
+
+	_, err := newMatrix(t, tests[0].a).TMatMul(nil)
+	require.ErrorIs(t, err, ErrExec)
+}
+
+// TestMatrix_MatMulParallel checks blocked and parallel multiplications give exactly the same values as naive
+// multiplication, run it with -race to check goroutines write no shared values.
+func TestMatrix_MatMulParallel(t *testing.T) {
+	threshold, procs := ParallelThreshold, runtime.GOMAXPROCS(4)
+	ParallelThreshold = 1
+	defer func() {
+		ParallelThreshold = threshold
+		runtime.GOMAXPROCS(procs)
+	}()
+
+	// naive return product of a and b computed value by value
+	naive := func(a, b *Matrix) []float64 {
+		values := make([]float64, a.rows*b.cols)
+		for i := 0; i < a.rows; i++ {
+			for j := 0; j < b.cols; j++ {
+				var sum float64
+				for k := 0; k < a.cols; k++ {
+					sum += a.values[i*a.cols+k] * b.values[k*b.cols+j]
+				}
+				values[i*b.cols+j] = sum
+			}
+		}
+		return values
+	}
+
+	for _, size := range [][3]int{{1, 1, 1}, {3, 70, 5}, {65, 1, 130}, {100, 129, 67}, {7, 200, 3}} {
+		a, b := randomMatrix(size[0], size[1]), randomMatrix(size[1], size[2])
+		t.Run(fmt.Sprintf("%dx%d matmul %dx%d", size[0], size[1], size[1], size[2]), func(t *testing.T) {
+			expected := naive(a, b)
+
+			single := make([]float64, a.rows*b.cols)
+			a.matMulImplSingle(b, single)
+			require.Equal(t, expected, single)
+
+			multi, err := a.MatMul(b)
+			require.NoError(t, err)
+			require.Equal(t, expected, multi.values)
+
+			transposed, err := a.MatMulT(b.T())
+			require.NoError(t, err)
+			require.Equal(t, expected, transposed.values)
+
+			transposed, err = a.T().TMatMul(b)
+			require.NoError(t, err)
+			require.Equal(t, expected, transposed.values)
+		})
+	}
+}
+
 func randomMatrix(rows, cols int) *Matrix {
 	values := make([]float64, rows*cols)
 	for i := 0; i < rows*cols; i++ {
@@ -125,22 +269,91 @@ func BenchmarkMatrix_MatMul(b *testing.B) {
 		{name: "20x30 matmul 30x40", rowsA: 20, colsA: 30, rowsB: 30, colsB: 40},
 		{name: "64x2 matmul 2x16", rowsA: 64, colsA: 2, rowsB: 2, colsB: 16},
 		{name: "200x300 matmul 300x400", rowsA: 200, colsA: 300, rowsB: 300, colsB: 400},
+		{name: "512x512 matmul 512x512", rowsA: 512, colsA: 512, rowsB: 512, colsB: 512},
 	}
 
 	for _, test := range tests {
 		one := randomMatrix(test.rowsA, test.colsA)
 		two := randomMatrix(test.rowsB, test.colsB)
+		oneT, twoT := one.T(), two.T()
 
 		b.Run("single goroutine # "+test.name, func(b *testing.B) {
+			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				one.matMulImplSingle(two)
+				one.matMulImplSingle(two, make([]float64, test.rowsA*test.colsB))
 			}
 		})
 		b.Run("multiple goroutines # "+test.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				one.matMulImplMulti(two, make([]float64, test.rowsA*test.colsB))
+			}
+		})
+		b.Run("matmul into # "+test.name, func(b *testing.B) {
+			dst, _ := one.MatMul(two)
+			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				one.matMulImplMulti(two)
+				dst, _ = one.MatMulInto(dst, two)
 			}
 		})
+		b.Run("transpose and matmul # "+test.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				one.MatMul(twoT.T())
+			}
+		})
+		b.Run("matmul transposed # "+test.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				one.MatMulT(twoT)
+			}
+		})
+		b.Run("transposed matmul # "+test.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				oneT.TMatMul(two)
+			}
+		})
+	}
+}
+
+// BenchmarkMatrix_Operations reports time and allocations of operations dominating training: element-wise, axed and
+// shape operations.
+func BenchmarkMatrix_Operations(b *testing.B) {
+	for _, size := range []int{16, 64, 256} {
+		one := randomMatrix(size, size)
+		two := randomMatrix(size, size)
+		row := randomMatrix(1, size)
+		perm := rand.Perm(size)
+		name := func(op string) string {
+			return fmt.Sprintf("%s # %dx%d", op, size, size)
+		}
+
+		benchmarks := []struct {
+			name string
+			f    func()
+		}{
+			{name: name("ApplyFunc"), f: func() { one.ApplyFunc(math.Tanh) }},
+			{name: name("Add"), f: func() { _, _ = one.Add(two) }},
+			{name: name("MulNum"), f: func() { one.MulNum(2) }},
+			{name: name("AddRowM"), f: func() { _, _ = one.AddRowM(row) }},
+			{name: name("SumAxedM vertical"), f: func() { _, _ = one.SumAxedM(Vertical) }},
+			{name: name("T"), f: func() { one.T() }},
+			{name: name("Copy"), f: func() { one.Copy() }},
+			{name: name("SubMatrix"), f: func() { _, _ = one.SubMatrix(0, size, 2, 0, size, 2) }},
+			{name: name("HStack"), f: func() { _, _ = one.HStack([]*Matrix{two}) }},
+			{name: name("VStack"), f: func() { _, _ = one.VStack([]*Matrix{two}) }},
+			{name: name("Order"), f: func() { _, _ = one.Order(perm) }},
+			{name: name("MatMul"), f: func() { _, _ = one.MatMul(two) }},
+		}
+		for _, bm := range benchmarks {
+			b.Run(bm.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					bm.f()
+				}
+			})
+		}
 	}
 }
 
@@ -210,8 +423,8 @@ func TestMatrix_Add(t *testing.T) {
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 		{
-			testBase: testBase{name: "1x1 + 2x2, error", err: ErrExec},
-			a:        matrixInput{in: []float64{1}, rows: 1, cols: 1},
+			testBase: testBase{name: "2x3 + 2x2, error", err: ErrExec},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 	}
@@ -240,8 +453,8 @@ func TestMatrix_Sub(t *testing.T) {
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 		{
-			testBase: testBase{name: "1x1 - 2x2, error", err: ErrExec},
-			a:        matrixInput{in: []float64{1}, rows: 1, cols: 1},
+			testBase: testBase{name: "2x3 - 2x2, error", err: ErrExec},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 	}
@@ -270,8 +483,8 @@ func TestMatrix_Mul(t *testing.T) {
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 		{
-			testBase: testBase{name: "1x1 * 2x2, error", err: ErrExec},
-			a:        matrixInput{in: []float64{1}, rows: 1, cols: 1},
+			testBase: testBase{name: "2x3 * 2x2, error", err: ErrExec},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 	}
@@ -300,8 +513,8 @@ func TestMatrix_Div(t *testing.T) {
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 		{
-			testBase: testBase{name: "1x1 / 2x2, error", err: ErrExec},
-			a:        matrixInput{in: []float64{1}, rows: 1, cols: 1},
+			testBase: testBase{name: "2x3 / 2x2, error", err: ErrExec},
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
 			b:        matrixInput{in: []float64{4, 5, 6, 7}, rows: 2, cols: 2},
 		},
 	}
@@ -324,6 +537,92 @@ func newVector(t *testing.T, in []float64) *vector.Vector {
 	return vec
 }
 
+func TestMatrix_ApplyFuncMat_Broadcast(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     matrixInput
+		expected matrixInput
+		err      string
+	}{
+		{
+			name:     "row",
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			b:        matrixInput{in: []float64{10, 20, 30}, rows: 1, cols: 3},
+			expected: matrixInput{in: []float64{11, 22, 33, 14, 25, 36}, rows: 2, cols: 3},
+		},
+		{
+			name:     "col",
+			a:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			b:        matrixInput{in: []float64{10, 20}, rows: 2, cols: 1},
+			expected: matrixInput{in: []float64{11, 12, 13, 24, 25, 26}, rows: 2, cols: 3},
+		},
+		{
+			name:     "single value",
+			a:        matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2},
+			b:        matrixInput{in: []float64{10}, rows: 1, cols: 1},
+			expected: matrixInput{in: []float64{11, 12, 13, 14}, rows: 2, cols: 2},
+		},
+		{
+			name:     "row to matrix",
+			a:        matrixInput{in: []float64{10, 20, 30}, rows: 1, cols: 3},
+			b:        matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			expected: matrixInput{in: []float64{11, 22, 33, 14, 25, 36}, rows: 2, cols: 3},
+		},
+		{
+			name:     "col and row",
+			a:        matrixInput{in: []float64{10, 20}, rows: 2, cols: 1},
+			b:        matrixInput{in: []float64{1, 2, 3}, rows: 1, cols: 3},
+			expected: matrixInput{in: []float64{11, 12, 13, 21, 22, 23}, rows: 2, cols: 3},
+		},
+		{
+			name: "rows mismatch",
+			a:    matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			b:    matrixInput{in: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}, rows: 3, cols: 3},
+			err:  "2x3 and 3x3",
+		},
+		{
+			name: "cols mismatch",
+			a:    matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3},
+			b:    matrixInput{in: []float64{1, 2}, rows: 1, cols: 2},
+			err:  "2x3 and 1x2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := newMatrix(t, test.a), newMatrix(t, test.b)
+			res, err := a.ApplyFuncMat(b, Add)
+			if test.err != "" {
+				require.ErrorIs(t, err, ErrExec)
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, newMatrix(t, test.expected).Equal(res), "%s", res)
+
+			into, err := a.ApplyFuncMatInto(randomMatrix(test.expected.rows, test.expected.cols), b, Add)
+			require.NoError(t, err)
+			require.True(t, res.Equal(into))
+
+			if a.rows == res.rows && a.cols == res.cols {
+				_, err = a.ApplyFuncMatInPlace(b, Add)
+				require.NoError(t, err)
+				require.True(t, res.Equal(a))
+			} else {
+				_, err = a.ApplyFuncMatInPlace(b, Add)
+				require.ErrorIs(t, err, ErrExec)
+			}
+		})
+	}
+
+	// per-shape methods check shape of operand
+	a := newMatrix(t, matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 2, cols: 3})
+	_, err := a.AddRowM(newMatrix(t, matrixInput{in: []float64{1}, rows: 1, cols: 1}))
+	require.ErrorContains(t, err, "1x1 is not row of matrix 2x3")
+	_, err = a.AddColM(newMatrix(t, matrixInput{in: []float64{1, 2, 3}, rows: 1, cols: 3}))
+	require.ErrorContains(t, err, "1x3 is not col of matrix 2x3")
+}
+
 func TestMatrix_AddRow(t *testing.T) {
 	tests := []matrixVectorTest{
 		{
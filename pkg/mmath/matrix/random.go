@@ -0,0 +1,87 @@
+package matrix
+
+import (
+	"math/rand"
+	"nn/pkg/mmath/internal/random"
+	"nn/pkg/wraperr"
+)
+
+// randMatrix creates rows x cols Matrix from values produced by given sampler.
+func randMatrix(rows, cols int, sample func(size int) ([]float64, error)) (m *Matrix, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	size := rows * cols
+	if rows < 1 || cols < 1 {
+		size = 0
+	}
+	values, err := sample(size)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(rows, cols, values), nil
+}
+
+// RandNormal creates rows x cols Matrix of values drawn from normal distribution with given mean and standard
+// deviation.
+//
+// Throws ErrCreate error.
+func RandNormal(rng *rand.Rand, rows, cols int, mean, std float64) (*Matrix, error) {
+	return randMatrix(rows, cols, func(size int) ([]float64, error) {
+		return random.Normal(rng, size, mean, std)
+	})
+}
+
+// RandTruncNormal creates rows x cols Matrix of values drawn from normal distribution with given mean and standard
+// deviation, values farther than 2 standard deviations from the mean are redrawn.
+//
+// Throws ErrCreate error.
+func RandTruncNormal(rng *rand.Rand, rows, cols int, mean, std float64) (*Matrix, error) {
+	return randMatrix(rows, cols, func(size int) ([]float64, error) {
+		return random.TruncNormal(rng, size, mean, std)
+	})
+}
+
+// RandUniform creates rows x cols Matrix of values drawn from uniform distribution on [low; high).
+//
+// Throws ErrCreate error.
+func RandUniform(rng *rand.Rand, rows, cols int, low, high float64) (*Matrix, error) {
+	return randMatrix(rows, cols, func(size int) ([]float64, error) {
+		return random.Uniform(rng, size, low, high)
+	})
+}
+
+// RandBernoulli creates rows x cols Matrix of values equal to 1 with probability <p> and to 0 otherwise.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     RandBernoulli(rng, 2, 3, 0.5) = | 1 0 0 |
+//                                     | 1 1 0 |
+func RandBernoulli(rng *rand.Rand, rows, cols int, p float64) (*Matrix, error) {
+	return randMatrix(rows, cols, func(size int) ([]float64, error) {
+		return random.Bernoulli(rng, size, p)
+	})
+}
+
+// RandPermutation return copy of given Matrix with randomly reordered rows and used permutation (see Order).
+//
+// Throws ErrExec error.
+//
+// Example:
+//     RandPermutation(rng, | 1 2 |) = | 5 6 |, [2 0 1]
+//                          | 3 4 |    | 1 2 |
+//                          | 5 6 |    | 3 4 |
+func RandPermutation(rng *rand.Rand, m *Matrix) (mat *Matrix, perm []int, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, nil, ErrNil
+	}
+	if perm, err = random.Permutation(rng, m.rows); err != nil {
+		return nil, nil, err
+	}
+	if mat, err = m.Order(perm); err != nil {
+		return nil, nil, err
+	}
+	return mat, perm, nil
+}
@@ -0,0 +1,87 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestRand(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func(rng *rand.Rand) (*Matrix, error)
+		check  func(value float64) bool
+		err    error
+	}{
+		{
+			name:   "normal",
+			create: func(rng *rand.Rand) (*Matrix, error) { return RandNormal(rng, 2, 3, 0, 1) },
+			check:  func(value float64) bool { return true },
+		},
+		{
+			name:   "truncated normal",
+			create: func(rng *rand.Rand) (*Matrix, error) { return RandTruncNormal(rng, 2, 3, 10, 1) },
+			check:  func(value float64) bool { return value >= 8 && value <= 12 },
+		},
+		{
+			name:   "uniform",
+			create: func(rng *rand.Rand) (*Matrix, error) { return RandUniform(rng, 2, 3, 1, 2) },
+			check:  func(value float64) bool { return value >= 1 && value < 2 },
+		},
+		{
+			name:   "bernoulli",
+			create: func(rng *rand.Rand) (*Matrix, error) { return RandBernoulli(rng, 2, 3, 0.5) },
+			check:  func(value float64) bool { return value == 0 || value == 1 },
+		},
+		{
+			name:   "zero rows",
+			create: func(rng *rand.Rand) (*Matrix, error) { return RandNormal(rng, 0, 3, 0, 1) },
+			err:    ErrCreate,
+		},
+		{
+			name:   "negative cols",
+			create: func(rng *rand.Rand) (*Matrix, error) { return RandUniform(rng, 2, -3, 0, 1) },
+			err:    ErrCreate,
+		},
+		{
+			name:   "no generator",
+			create: func(rng *rand.Rand) (*Matrix, error) { return RandBernoulli(nil, 2, 3, 0.5) },
+			err:    ErrCreate,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := test.create(rand.New(rand.NewSource(1)))
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 2, m.Rows())
+			require.Equal(t, 3, m.Cols())
+			for _, value := range m.RawFlat() {
+				require.True(t, test.check(value), value)
+			}
+
+			again, err := test.create(rand.New(rand.NewSource(1)))
+			require.NoError(t, err)
+			require.True(t, m.Equal(again))
+		})
+	}
+}
+
+func TestRandPermutation(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{1, 2, 3, 4, 5, 6}, rows: 3, cols: 2})
+	shuffled, perm, err := RandPermutation(rand.New(rand.NewSource(1)), m)
+	require.NoError(t, err)
+	ordered, err := m.Order(perm)
+	require.NoError(t, err)
+	require.True(t, ordered.Equal(shuffled))
+	require.Equal(t, []float64{1, 2, 3, 4, 5, 6}, m.RawFlat())
+
+	_, _, err = RandPermutation(nil, m)
+	require.ErrorIs(t, err, ErrExec)
+	_, _, err = RandPermutation(rand.New(rand.NewSource(1)), nil)
+	require.ErrorIs(t, err, ErrNil)
+}
@@ -0,0 +1,348 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+	"sort"
+)
+
+// maxSweeps limits count of Jacobi sweeps, they converge quadratically, so several dozens of sweeps are reached for
+// pathological inputs only.
+const maxSweeps = 100
+
+// Eigen holds eigen-decomposition of symmetric Matrix A: A = V*diag(values)*V^T, where V has orthonormal eigenvectors
+// as cols. Eigenvalues are sorted in descending order.
+type Eigen struct {
+	values  []float64
+	vectors *Matrix
+}
+
+// Eigen return eigen-decomposition of this symmetric Matrix computed by cyclic Jacobi rotations.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 2 1 |.Eigen(): values = [3 1], vectors = | 0.71  0.71 |
+//     | 1 2 |                                    | 0.71 -0.71 |
+func (m *Matrix) Eigen() (eigen *Eigen, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if err = m.checkSymmetric(); err != nil {
+		return nil, err
+	}
+
+	n := m.rows
+	a := m.Copy()
+	// rows of vt are eigenvectors, so rotations touch contiguous memory
+	vt, err := Identity(n)
+	if err != nil {
+		return nil, err
+	}
+
+	for sweep := 0; ; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				app, aqq, apq := a.values[p*n+p], a.values[q*n+q], a.values[p*n+q]
+				if apq == 0 || math.Abs(apq) <= machineEpsilon*math.Sqrt(math.Abs(app*aqq)) {
+					continue
+				}
+				rotated = true
+				c, s := jacobiRotation(app, aqq, apq)
+				for k := 0; k < n; k++ {
+					akp, akq := a.values[k*n+p], a.values[k*n+q]
+					a.values[k*n+p], a.values[k*n+q] = c*akp-s*akq, s*akp+c*akq
+				}
+				rotateRows(a.row(p), a.row(q), c, s)
+				rotateRows(vt.row(p), vt.row(q), c, s)
+			}
+		}
+		if !rotated {
+			break
+		} else if sweep == maxSweeps {
+			return nil, fmt.Errorf("eigen-decomposition did not converge in %d sweeps", maxSweeps)
+		}
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = a.values[i*n+i]
+	}
+	order := descendingOrder(values)
+	eigen = &Eigen{values: make([]float64, n), vectors: wrap(n, n, make([]float64, n*n))}
+	for j, index := range order {
+		eigen.values[j] = values[index]
+		for i, value := range vt.row(index) {
+			eigen.vectors.values[i*n+j] = value
+		}
+	}
+	return eigen, nil
+}
+
+// Values return eigenvalues in descending order.
+func (e *Eigen) Values() *vector.Vector {
+	values, _ := vector.NewVector(e.values)
+	return values
+}
+
+// Vectors return Matrix with eigenvectors as cols, i'th col corresponds to i'th eigenvalue.
+func (e *Eigen) Vectors() *Matrix {
+	return e.vectors.Copy()
+}
+
+// SVD holds thin singular value decomposition of Matrix A of size RxC: A = U*diag(values)*V^T, where U of size RxK and
+// V of size CxK have orthonormal cols, K = min(R, C). Singular values are sorted in descending order. Cols of U and V
+// corresponding to zero singular values are zero.
+type SVD struct {
+	u, v   *Matrix
+	values []float64
+}
+
+// SVD return thin singular value decomposition of this Matrix computed by one-sided Jacobi rotations.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 3 0 |.SVD(): U = | 0 1 |, values = [4 3], V = | 0 1 |
+//     | 0 4 |            | 1 0 |                      | 1 0 |
+func (m *Matrix) SVD() (svd *SVD, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if m.rows < m.cols {
+		// A^T = U*S*V^T => A = V*S*U^T
+		if svd, err = m.T().SVD(); err != nil {
+			return nil, err
+		}
+		svd.u, svd.v = svd.v, svd.u
+		return svd, nil
+	}
+
+	// rows of w are cols of A*V, rows of vt are cols of V
+	w := m.T()
+	n := w.rows
+	vt, err := Identity(n)
+	if err != nil {
+		return nil, err
+	}
+
+	tolerance := float64(m.rows) * machineEpsilon
+	for sweep := 0; ; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				wp, wq := w.row(p), w.row(q)
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for k, value := range wp {
+					alpha += value * value
+					beta += wq[k] * wq[k]
+					gamma += value * wq[k]
+				}
+				if gamma == 0 || math.Abs(gamma) <= tolerance*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				c, s := jacobiRotation(alpha, beta, gamma)
+				rotateRows(wp, wq, c, s)
+				rotateRows(vt.row(p), vt.row(q), c, s)
+			}
+		}
+		if !rotated {
+			break
+		} else if sweep == maxSweeps {
+			return nil, fmt.Errorf("singular value decomposition did not converge in %d sweeps", maxSweeps)
+		}
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		row := w.row(i)
+		norm := 0.0
+		for _, value := range row {
+			norm = math.Hypot(norm, value)
+		}
+		values[i] = norm
+		if norm != 0 {
+			for k := range row {
+				row[k] /= norm
+			}
+		}
+	}
+
+	order := descendingOrder(values)
+	rows := m.rows
+	svd = &SVD{u: wrap(rows, n, make([]float64, rows*n)), v: wrap(n, n, make([]float64, n*n)), values: make([]float64, n)}
+	for j, index := range order {
+		svd.values[j] = values[index]
+		if values[index] == 0 {
+			continue
+		}
+		for i, value := range w.row(index) {
+			svd.u.values[i*n+j] = value
+		}
+		for i, value := range vt.row(index) {
+			svd.v.values[i*n+j] = value
+		}
+	}
+	return svd, nil
+}
+
+// U return left singular vectors as cols.
+func (d *SVD) U() *Matrix {
+	return d.u.Copy()
+}
+
+// Values return singular values in descending order.
+func (d *SVD) Values() *vector.Vector {
+	values, _ := vector.NewVector(d.values)
+	return values
+}
+
+// V return right singular vectors as cols.
+func (d *SVD) V() *Matrix {
+	return d.v.Copy()
+}
+
+// tolerance return threshold below which singular values are treated as zero.
+func (d *SVD) tolerance() float64 {
+	if len(d.values) == 0 {
+		return 0
+	}
+	return math.Max(float64(d.u.rows), float64(d.v.rows)) * d.values[0] * machineEpsilon
+}
+
+// Rank return count of singular values not negligible comparing to the largest one.
+func (d *SVD) Rank() int {
+	tolerance := d.tolerance()
+	rank := 0
+	for _, value := range d.values {
+		if value > tolerance {
+			rank++
+		}
+	}
+	return rank
+}
+
+// ConditionNumber return ratio of the largest and the smallest singular values, it is +Inf for rank deficient Matrix.
+func (d *SVD) ConditionNumber() float64 {
+	if len(d.values) == 0 || d.values[len(d.values)-1] == 0 {
+		return math.Inf(1)
+	}
+	return d.values[0] / d.values[len(d.values)-1]
+}
+
+// PseudoInverse return Moore-Penrose pseudo-inverse V*diag(1/values)*U^T of decomposed Matrix, negligible singular
+// values are treated as zero (see SVD.Rank).
+func (d *SVD) PseudoInverse() *Matrix {
+	tolerance := d.tolerance()
+	// scale cols of V by inverted singular values
+	scaled := d.v.Copy()
+	for i := 0; i < scaled.rows; i++ {
+		row := scaled.row(i)
+		for j, value := range d.values {
+			if value > tolerance {
+				row[j] /= value
+			} else {
+				row[j] = 0
+			}
+		}
+	}
+	inv, _ := scaled.MatMulT(d.u)
+	return inv
+}
+
+// Rank return rank of this Matrix computed by its SVD (see SVD.Rank).
+//
+// Throws ErrExec error.
+func (m *Matrix) Rank() (rank int, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	svd, err := m.SVD()
+	if err != nil {
+		return 0, err
+	}
+	return svd.Rank(), nil
+}
+
+// ConditionNumber return 2-norm condition number of this Matrix computed by its SVD (see SVD.ConditionNumber).
+//
+// Throws ErrExec error.
+func (m *Matrix) ConditionNumber() (cond float64, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	svd, err := m.SVD()
+	if err != nil {
+		return 0, err
+	}
+	return svd.ConditionNumber(), nil
+}
+
+// PseudoInverse return Moore-Penrose pseudo-inverse of this Matrix computed by its SVD (see SVD.PseudoInverse).
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 0 |.PseudoInverse() = | 1   0 0 |
+//     | 0 2 |                   | 0 0.5 0 |
+//     | 0 0 |
+func (m *Matrix) PseudoInverse() (inv *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	svd, err := m.SVD()
+	if err != nil {
+		return nil, err
+	}
+	return svd.PseudoInverse(), nil
+}
+
+// Orthonormalize return Matrix with orthonormal cols (rows for Matrix with less rows than cols) closest to this one
+// in Frobenius norm: U*V^T for its SVD. It is used for orthogonal initialization of weights.
+//
+// Throws ErrExec error, ErrSingular is its cause for rank deficient Matrix.
+func (m *Matrix) Orthonormalize() (res *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	svd, err := m.SVD()
+	if err != nil {
+		return nil, err
+	}
+	if svd.Rank() < len(svd.values) {
+		return nil, fmt.Errorf("can not orthonormalize %dx%d matrix: %w", m.rows, m.cols, ErrSingular)
+	}
+	return svd.u.MatMulT(svd.v)
+}
+
+// jacobiRotation return cosine and sine of rotation annihilating off-diagonal element of symmetric 2x2 Matrix
+// | app apq |
+// | apq aqq |.
+func jacobiRotation(app, aqq, apq float64) (c, s float64) {
+	theta := (aqq - app) / (2 * apq)
+	t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+	if theta < 0 {
+		t = -t
+	}
+	c = 1 / math.Sqrt(t*t+1)
+	return c, t * c
+}
+
+// rotateRows apply plane rotation to pair of rows.
+func rotateRows(p, q []float64, c, s float64) {
+	for k, value := range p {
+		p[k], q[k] = c*value-s*q[k], s*value+c*q[k]
+	}
+}
+
+// descendingOrder return indices of values sorted in descending order.
+func descendingOrder(values []float64) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] > values[order[j]]
+	})
+	return order
+}
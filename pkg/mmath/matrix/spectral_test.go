@@ -0,0 +1,213 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+// diag return square Matrix with given values on diagonal.
+func diag(values []float64) *Matrix {
+	n := len(values)
+	m := wrap(n, n, make([]float64, n*n))
+	for i, value := range values {
+		m.values[i*n+i] = value
+	}
+	return m
+}
+
+// requireOrthonormalCols asserts cols of Matrix are orthonormal.
+func requireOrthonormalCols(t *testing.T, m *Matrix) {
+	identity, err := Identity(m.cols)
+	require.NoError(t, err)
+	mtm, err := m.TMatMul(m)
+	require.NoError(t, err)
+	requireClose(t, identity, mtm, 1e-10)
+}
+
+func TestMatrix_Eigen(t *testing.T) {
+	gram := randomMatrix(8, 5)
+	gram, err := gram.TMatMul(gram)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		in     *Matrix
+		values []float64
+		err    error
+	}{
+		{name: "2x2", in: newMatrix(t, matrixInput{in: []float64{2, 1, 1, 2}, rows: 2, cols: 2}), values: []float64{3, 1}},
+		{name: "diagonal", in: diag([]float64{1, 5, -2}), values: []float64{5, 1, -2}},
+		{name: "indefinite", in: newMatrix(t, matrixInput{in: []float64{0, 2, 2, 0}, rows: 2, cols: 2}), values: []float64{2, -2}},
+		{
+			name:   "repeated eigenvalue",
+			in:     newMatrix(t, matrixInput{in: []float64{2, 0, 0, 0, 3, 1, 0, 1, 3}, rows: 3, cols: 3}),
+			values: []float64{4, 2, 2},
+		},
+		{name: "gram", in: gram},
+		{name: "not symmetric", in: newMatrix(t, matrixInput{in: []float64{1, 2, 3, 4}, rows: 2, cols: 2}), err: ErrExec},
+		{name: "non-square", in: randomMatrix(2, 3), err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eigen, err := test.in.Eigen()
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			values, vectors := eigen.Values().Raw(), eigen.Vectors()
+			if test.values != nil {
+				require.InDeltaSlice(t, test.values, values, 1e-12)
+			}
+			for i := 1; i < len(values); i++ {
+				require.GreaterOrEqual(t, values[i-1], values[i])
+			}
+			requireOrthonormalCols(t, vectors)
+
+			// A*V = V*diag(values)
+			av := mustMatMul(t, test.in, vectors)
+			requireClose(t, mustMatMul(t, vectors, diag(values)), av, 1e-10)
+		})
+	}
+}
+
+func TestMatrix_SVD(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     *Matrix
+		values []float64
+		rank   int
+	}{
+		{name: "diagonal", in: newMatrix(t, matrixInput{in: []float64{3, 0, 0, 4}, rows: 2, cols: 2}), values: []float64{4, 3}, rank: 2},
+		{
+			name:   "tall",
+			in:     newMatrix(t, matrixInput{in: []float64{3, 0, 0, 4, 0, 0}, rows: 3, cols: 2}),
+			values: []float64{4, 3},
+			rank:   2,
+		},
+		{
+			name:   "wide rank one",
+			in:     newMatrix(t, matrixInput{in: []float64{1, 2, 2, 2, 4, 4}, rows: 2, cols: 3}),
+			values: []float64{math.Sqrt(45), 0},
+			rank:   1,
+		},
+		{name: "zero", in: newMatrix(t, matrixInput{in: []float64{0, 0, 0, 0}, rows: 2, cols: 2}), values: []float64{0, 0}},
+		{name: "tall random", in: randomMatrix(9, 4), rank: 4},
+		{name: "wide random", in: randomMatrix(3, 7), rank: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svd, err := test.in.SVD()
+			require.NoError(t, err)
+
+			u, values, v := svd.U(), svd.Values().Raw(), svd.V()
+			k := minInt(test.in.rows, test.in.cols)
+			require.Equal(t, []int{test.in.rows, k}, []int{u.rows, u.cols})
+			require.Equal(t, []int{test.in.cols, k}, []int{v.rows, v.cols})
+			if test.values != nil {
+				require.InDeltaSlice(t, test.values, values, 1e-12)
+			}
+			for i := 1; i < len(values); i++ {
+				require.GreaterOrEqual(t, values[i-1], values[i])
+			}
+			require.Equal(t, test.rank, svd.Rank())
+			if test.rank == k {
+				requireOrthonormalCols(t, u)
+				requireOrthonormalCols(t, v)
+			}
+
+			usv, err := mustMatMul(t, u, diag(values)).MatMulT(v)
+			require.NoError(t, err)
+			requireClose(t, test.in, usv, 1e-10)
+
+			rank, err := test.in.Rank()
+			require.NoError(t, err)
+			require.Equal(t, test.rank, rank)
+		})
+	}
+
+	var nilMatrix *Matrix
+	_, err := nilMatrix.SVD()
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_ConditionNumber(t *testing.T) {
+	cond, err := newMatrix(t, matrixInput{in: []float64{3, 0, 0, 0.5}, rows: 2, cols: 2}).ConditionNumber()
+	require.NoError(t, err)
+	require.InDelta(t, 6, cond, 1e-12)
+
+	cond, err = randomMatrix(4, 4).ConditionNumber()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, cond, 1.0)
+
+	cond, err = newMatrix(t, matrixInput{in: []float64{1, 2, 2, 4}, rows: 2, cols: 2}).ConditionNumber()
+	require.NoError(t, err)
+	require.Greater(t, cond, 1e15)
+
+	cond, err = newMatrix(t, matrixInput{in: []float64{0, 0, 0, 0}, rows: 2, cols: 2}).ConditionNumber()
+	require.NoError(t, err)
+	require.True(t, math.IsInf(cond, 1))
+}
+
+func TestMatrix_PseudoInverse(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       *Matrix
+		expected []float64
+	}{
+		{
+			name:     "tall",
+			in:       newMatrix(t, matrixInput{in: []float64{1, 0, 0, 2, 0, 0}, rows: 3, cols: 2}),
+			expected: []float64{1, 0, 0, 0, 0.5, 0},
+		},
+		{
+			name:     "rank one",
+			in:       newMatrix(t, matrixInput{in: []float64{1, 1, 1, 1}, rows: 2, cols: 2}),
+			expected: []float64{0.25, 0.25, 0.25, 0.25},
+		},
+		{name: "tall random", in: randomMatrix(6, 3)},
+		{name: "wide random", in: randomMatrix(3, 6)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inv, err := test.in.PseudoInverse()
+			require.NoError(t, err)
+			require.Equal(t, []int{test.in.cols, test.in.rows}, []int{inv.rows, inv.cols})
+			if test.expected != nil {
+				requireClose(t, newMatrix(t, matrixInput{in: test.expected, rows: inv.rows, cols: inv.cols}), inv, 1e-12)
+			}
+
+			// Moore-Penrose conditions: A*A+*A = A and A+*A*A+ = A+
+			requireClose(t, test.in, mustMatMul(t, mustMatMul(t, test.in, inv), test.in), 1e-10)
+			requireClose(t, inv, mustMatMul(t, mustMatMul(t, inv, test.in), inv), 1e-10)
+		})
+	}
+
+	// pseudo-inverse of invertible Matrix is its inverse
+	square := randomMatrix(5, 5)
+	pinv, err := square.PseudoInverse()
+	require.NoError(t, err)
+	inv, err := square.Inverse()
+	require.NoError(t, err)
+	requireClose(t, inv, pinv, 1e-8)
+}
+
+func TestMatrix_Orthonormalize(t *testing.T) {
+	for _, in := range []*Matrix{randomMatrix(6, 3), randomMatrix(4, 4)} {
+		q, err := in.Orthonormalize()
+		require.NoError(t, err)
+		requireOrthonormalCols(t, q)
+	}
+
+	q, err := randomMatrix(3, 6).Orthonormalize()
+	require.NoError(t, err)
+	requireOrthonormalCols(t, q.T())
+
+	_, err = newMatrix(t, matrixInput{in: []float64{1, 2, 2, 4}, rows: 2, cols: 2}).Orthonormalize()
+	require.ErrorIs(t, err, ErrSingular)
+}
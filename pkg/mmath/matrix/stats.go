@@ -0,0 +1,346 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// line return copy of values of i'th line of given axis: i'th row (Horizontal) or col (Vertical).
+func (m *Matrix) line(axis Axis, i int) []float64 {
+	if axis == Horizontal {
+		return append([]float64(nil), m.row(i)...)
+	}
+	values := make([]float64, m.rows)
+	for j := range values {
+		values[j] = m.values[j*m.cols+i]
+	}
+	return values
+}
+
+// reduceLines return vector of given function applied to copies of each line of given axis.
+func (m *Matrix) reduceLines(axis Axis, f func(values []float64) float64) (vec *vector.Vector, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+	count, _, _, err := m.lines(axis)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = f(m.line(axis, i))
+	}
+	return vector.NewVector(values)
+}
+
+// variance return variance of given values: sum of squared deviations from the mean divided by (count - ddof), so
+// ddof 0 gives population variance and ddof 1 gives sample (unbiased) one.
+func variance(values []float64, ddof int) float64 {
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	res := 0.0
+	for _, value := range values {
+		res += (value - mean) * (value - mean)
+	}
+	return res / float64(len(values)-ddof)
+}
+
+// quantile return q'th quantile of given values sorted in place, linearly interpolated between neighbour values.
+func quantile(values []float64, q float64) float64 {
+	sort.Float64s(values)
+	pos := q * float64(len(values)-1)
+	lo := int(math.Floor(pos))
+	if lo == len(values)-1 {
+		return values[lo]
+	}
+	return values[lo] + (pos-float64(lo))*(values[lo+1]-values[lo])
+}
+
+func checkQuantile(q float64) error {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return fmt.Errorf("quantile must be in [0; 1]: %v", q)
+	}
+	return nil
+}
+
+// VarAxed return population variances of values in given axis: of each row (Horizontal) or col (Vertical).
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 3 |.VarAxed(Horizontal) = | 2/3 |
+//     | 2 4 6 |                       | 8/3 |
+func (m *Matrix) VarAxed(axis Axis) (*vector.Vector, error) {
+	return m.reduceLines(axis, func(values []float64) float64 {
+		return variance(values, 0)
+	})
+}
+
+// Var return population variance of all values of Matrix, NaN for nil Matrix.
+func (m *Matrix) Var() float64 {
+	if m == nil {
+		return math.NaN()
+	}
+	return variance(m.values, 0)
+}
+
+// StdAxed return population standard deviations of values in given axis (see VarAxed).
+//
+// Throws ErrExec error.
+func (m *Matrix) StdAxed(axis Axis) (*vector.Vector, error) {
+	return m.reduceLines(axis, func(values []float64) float64 {
+		return math.Sqrt(variance(values, 0))
+	})
+}
+
+// Std return population standard deviation of all values of Matrix, NaN for nil Matrix.
+func (m *Matrix) Std() float64 {
+	return math.Sqrt(m.Var())
+}
+
+// QuantileAxed return q'th quantiles (q in [0; 1]) of values in given axis, linearly interpolated between values
+// as numpy.quantile does.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1 2 3 4 |.QuantileAxed(0.25, Horizontal) = | 1.75 |
+//     | 8 6 4 2 |                                  | 3.5  |
+func (m *Matrix) QuantileAxed(q float64, axis Axis) (vec *vector.Vector, err error) {
+	if err = checkQuantile(q); err != nil {
+		return nil, wraperr.NewWrapErr(ErrExec, err)
+	}
+	return m.reduceLines(axis, func(values []float64) float64 {
+		return quantile(values, q)
+	})
+}
+
+// Quantile return q'th quantile (q in [0; 1]) of all values of Matrix (see QuantileAxed).
+//
+// Throws ErrExec error.
+func (m *Matrix) Quantile(q float64) (res float64, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return 0, ErrNil
+	} else if err = checkQuantile(q); err != nil {
+		return 0, err
+	}
+	return quantile(m.RawFlat(), q), nil
+}
+
+// See QuantileAxed
+func (m *Matrix) MedianAxed(axis Axis) (*vector.Vector, error) {
+	return m.QuantileAxed(0.5, axis)
+}
+
+// See Quantile
+func (m *Matrix) Median() float64 {
+	median, _ := m.Quantile(0.5)
+	return median
+}
+
+// Cov return sample covariance Matrix of Matrix's cols treated as variables and rows treated as observations (as
+// numpy.cov(m, rowvar=False) does): value of i'th row and j'th col is covariance of i'th and j'th cols divided by
+// rows-1. Matrix must have at least 2 rows.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1  2 |.Cov() = |  1 -2 |
+//     | 2  0 |         | -2  4 |
+//     | 3 -2 |
+func (m *Matrix) Cov() (mat *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	} else if m.rows < 2 {
+		return nil, fmt.Errorf("at least 2 rows are required for covariance: %d", m.rows)
+	}
+
+	means, err := m.AvgAxed(Vertical)
+	if err != nil {
+		return nil, err
+	}
+	centered, err := m.SubRow(means)
+	if err != nil {
+		return nil, err
+	}
+	cov, err := centered.TMatMul(centered)
+	if err != nil {
+		return nil, err
+	}
+	return cov.DivNumInPlace(float64(m.rows - 1)), nil
+}
+
+// Corr return Pearson correlation Matrix of Matrix's cols (see Cov). Correlations with col of equal values are NaN.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1  2 |.Corr() = |  1 -1 |
+//     | 2  0 |          | -1  1 |
+//     | 3 -2 |
+func (m *Matrix) Corr() (mat *Matrix, err error) {
+	cov, err := m.Cov()
+	if err != nil {
+		return nil, err
+	}
+
+	n := cov.rows
+	std := make([]float64, n)
+	for i := range std {
+		std[i] = math.Sqrt(cov.values[i*n+i])
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			switch {
+			case std[i] == 0 || std[j] == 0:
+				cov.values[i*n+j] = math.NaN()
+			case i == j:
+				cov.values[i*n+j] = 1
+			default:
+				cov.values[i*n+j] = math.Max(-1, math.Min(1, cov.values[i*n+j]/(std[i]*std[j])))
+			}
+		}
+	}
+	return cov, nil
+}
+
+// SpearmanCorr return Spearman rank correlation Matrix of Matrix's cols: Pearson correlation (see Corr) of ranks of
+// values in each col, equal values get average of their ranks.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 1  1 |.SpearmanCorr() = | 1 1 |
+//     | 2  4 |                  | 1 1 |
+//     | 3 27 |
+func (m *Matrix) SpearmanCorr() (mat *Matrix, err error) {
+	if m == nil {
+		return nil, wraperr.NewWrapErr(ErrExec, ErrNil)
+	}
+
+	ranks := wrap(m.rows, m.cols, make([]float64, len(m.values)))
+	order := make([]int, m.rows)
+	for j := 0; j < m.cols; j++ {
+		col := m.line(Vertical, j)
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return col[order[a]] < col[order[b]]
+		})
+		for start := 0; start < m.rows; {
+			stop := start + 1
+			for stop < m.rows && col[order[stop]] == col[order[start]] {
+				stop++
+			}
+			rank := float64(start+stop+1) / 2 // average of 1-based ranks start+1, ..., stop
+			for _, i := range order[start:stop] {
+				ranks.values[i*m.cols+j] = rank
+			}
+			start = stop
+		}
+	}
+	return ranks.Corr()
+}
+
+// Description holds summary statistics of Matrix's cols (see Describe), each Vector is sized as Matrix's cols.
+type Description struct {
+	Count  int
+	Mean   *vector.Vector
+	Std    *vector.Vector
+	Min    *vector.Vector
+	Q25    *vector.Vector
+	Median *vector.Vector
+	Q75    *vector.Vector
+	Max    *vector.Vector
+}
+
+// Describe return summary statistics of each col of Matrix: count, mean, sample standard deviation (NaN for single
+// row), min, quartiles and max (as pandas.DataFrame.describe does).
+//
+// Throws ErrExec error.
+func (m *Matrix) Describe() (d *Description, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if m == nil {
+		return nil, ErrNil
+	}
+
+	d = &Description{Count: m.rows}
+	if d.Mean, err = m.AvgAxed(Vertical); err != nil {
+		return nil, err
+	} else if d.Std, err = m.reduceLines(Vertical, func(values []float64) float64 {
+		return math.Sqrt(variance(values, 1))
+	}); err != nil {
+		return nil, err
+	} else if d.Min, err = m.MinAxed(Vertical); err != nil {
+		return nil, err
+	} else if d.Q25, err = m.QuantileAxed(0.25, Vertical); err != nil {
+		return nil, err
+	} else if d.Median, err = m.MedianAxed(Vertical); err != nil {
+		return nil, err
+	} else if d.Q75, err = m.QuantileAxed(0.75, Vertical); err != nil {
+		return nil, err
+	} else if d.Max, err = m.MaxAxed(Vertical); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Example:
+//     | 1 4 |.Describe().String() = `       0   1
+//     | 2 5 |                        count  3   3
+//     | 3 9 |                        mean   2   6
+//                                    std    1   2.65
+//                                    min    1   4
+//                                    25%    1.5 4.5
+//                                    50%    2   5
+//                                    75%    2.5 7
+//                                    max    3   9`
+func (d *Description) String() string {
+	if d == nil {
+		return "<nil>"
+	}
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
+	cols := d.Mean.Size()
+	header := make([]string, cols)
+	counts := make([]string, cols)
+	for j := range header {
+		header[j] = fmt.Sprintf("%d", j)
+		counts[j] = fmt.Sprintf("%d", d.Count)
+	}
+	_, _ = fmt.Fprintf(w, "\t%s\n", strings.Join(header, "\t"))
+	_, _ = fmt.Fprintf(w, "count\t%s\n", strings.Join(counts, "\t"))
+	for _, row := range []struct {
+		name string
+		vec  *vector.Vector
+	}{
+		{"mean", d.Mean}, {"std", d.Std}, {"min", d.Min}, {"25%", d.Q25},
+		{"50%", d.Median}, {"75%", d.Q75}, {"max", d.Max},
+	} {
+		values := make([]string, cols)
+		for j, value := range row.vec.Raw() {
+			values[j] = fmt.Sprintf("%.3g", value)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", row.name, strings.Join(values, "\t"))
+	}
+	_ = w.Flush()
+	return strings.TrimRight(sb.String(), "\n")
+}
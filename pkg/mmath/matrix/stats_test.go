@@ -0,0 +1,161 @@
+package matrix
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"nn/pkg/mmath/vector"
+	"testing"
+)
+
+func TestMatrix_VarAxed(t *testing.T) {
+	m := matrixInput{in: []float64{1, 2, 3, 2, 4, 6}, rows: 2, cols: 3}
+	tests := []struct {
+		name     string
+		reduce   func(m *Matrix) (*vector.Vector, error)
+		expected []float64
+		err      error
+	}{
+		{name: "var horizontal", reduce: func(m *Matrix) (*vector.Vector, error) { return m.VarAxed(Horizontal) }, expected: []float64{2.0 / 3, 8.0 / 3}},
+		{name: "var vertical", reduce: func(m *Matrix) (*vector.Vector, error) { return m.VarAxed(Vertical) }, expected: []float64{0.25, 1, 2.25}},
+		{name: "std vertical", reduce: func(m *Matrix) (*vector.Vector, error) { return m.StdAxed(Vertical) }, expected: []float64{0.5, 1, 1.5}},
+		{name: "median horizontal", reduce: func(m *Matrix) (*vector.Vector, error) { return m.MedianAxed(Horizontal) }, expected: []float64{2, 4}},
+		{name: "median vertical", reduce: func(m *Matrix) (*vector.Vector, error) { return m.MedianAxed(Vertical) }, expected: []float64{1.5, 3, 4.5}},
+		{name: "quantile horizontal", reduce: func(m *Matrix) (*vector.Vector, error) { return m.QuantileAxed(0.25, Horizontal) }, expected: []float64{1.5, 3}},
+		{name: "quantile max", reduce: func(m *Matrix) (*vector.Vector, error) { return m.QuantileAxed(1, Vertical) }, expected: []float64{2, 4, 6}},
+		{name: "quantile out of range", reduce: func(m *Matrix) (*vector.Vector, error) { return m.QuantileAxed(1.5, Vertical) }, err: ErrExec},
+		{name: "unknown axis", reduce: func(m *Matrix) (*vector.Vector, error) { return m.VarAxed(Axis(5)) }, err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vec, err := test.reduce(newMatrix(t, m))
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.InDeltaSlice(t, test.expected, vec.Raw(), 1e-12)
+		})
+	}
+}
+
+func TestMatrix_Var(t *testing.T) {
+	m := newMatrix(t, matrixInput{in: []float64{4, 1, 3, 2}, rows: 2, cols: 2})
+	require.InDelta(t, 1.25, m.Var(), 1e-12)
+	require.InDelta(t, math.Sqrt(1.25), m.Std(), 1e-12)
+	require.Equal(t, 2.5, m.Median())
+
+	q, err := m.Quantile(0.75)
+	require.NoError(t, err)
+	require.Equal(t, 3.25, q)
+	_, err = m.Quantile(-0.1)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = (*Matrix)(nil).Quantile(0.5)
+	require.ErrorIs(t, err, ErrNil)
+	require.True(t, math.IsNaN((*Matrix)(nil).Var()))
+	require.True(t, math.IsNaN((*Matrix)(nil).Std()))
+
+	// values are not reordered
+	require.Equal(t, []float64{4, 1, 3, 2}, m.RawFlat())
+}
+
+func TestMatrix_Cov(t *testing.T) {
+	tests := []struct {
+		name     string
+		cov      func(m *Matrix) (*Matrix, error)
+		in       matrixInput
+		expected []float64
+		err      error
+	}{
+		{
+			name:     "cov",
+			cov:      (*Matrix).Cov,
+			in:       matrixInput{in: []float64{1, 2, 2, 0, 3, -2}, rows: 3, cols: 2},
+			expected: []float64{1, -2, -2, 4},
+		},
+		{
+			name:     "pearson",
+			cov:      (*Matrix).Corr,
+			in:       matrixInput{in: []float64{1, 2, 1, 2, 0, 2, 3, -2, 6}, rows: 3, cols: 3},
+			expected: []float64{1, -1, 5 / math.Sqrt(28), -1, 1, -5 / math.Sqrt(28), 5 / math.Sqrt(28), -5 / math.Sqrt(28), 1},
+		},
+		{
+			name:     "pearson constant col",
+			cov:      (*Matrix).Corr,
+			in:       matrixInput{in: []float64{1, 5, 2, 5}, rows: 2, cols: 2},
+			expected: []float64{1, math.NaN(), math.NaN(), math.NaN()},
+		},
+		{
+			name:     "spearman monotonic",
+			cov:      (*Matrix).SpearmanCorr,
+			in:       matrixInput{in: []float64{1, 1, 9, 2, 4, 4, 3, 27, 1}, rows: 3, cols: 3},
+			expected: []float64{1, 1, -1, 1, 1, -1, -1, -1, 1},
+		},
+		{
+			name:     "spearman ties",
+			cov:      (*Matrix).SpearmanCorr,
+			in:       matrixInput{in: []float64{1, 5, 2, 5, 3, 7, 4, 9}, rows: 4, cols: 2},
+			expected: []float64{1, 4.5 / math.Sqrt(22.5), 4.5 / math.Sqrt(22.5), 1},
+		},
+		{
+			name: "single row",
+			cov:  (*Matrix).Cov,
+			in:   matrixInput{in: []float64{1, 2}, rows: 1, cols: 2},
+			err:  ErrExec,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mat, err := test.cov(newMatrix(t, test.in))
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.in.cols, mat.Rows())
+			require.Equal(t, test.in.cols, mat.Cols())
+			for i, value := range mat.RawFlat() {
+				if math.IsNaN(test.expected[i]) {
+					require.True(t, math.IsNaN(value), i)
+				} else {
+					require.InDelta(t, test.expected[i], value, 1e-12, i)
+				}
+			}
+		})
+	}
+
+	_, err := (*Matrix)(nil).SpearmanCorr()
+	require.ErrorIs(t, err, ErrNil)
+}
+
+func TestMatrix_Describe(t *testing.T) {
+	d, err := newMatrix(t, matrixInput{in: []float64{1, 4, 2, 5, 3, 9}, rows: 3, cols: 2}).Describe()
+	require.NoError(t, err)
+	require.Equal(t, 3, d.Count)
+	require.Equal(t, []float64{2, 6}, d.Mean.Raw())
+	require.Equal(t, []float64{1, 4}, d.Min.Raw())
+	require.Equal(t, []float64{1.5, 4.5}, d.Q25.Raw())
+	require.Equal(t, []float64{2, 5}, d.Median.Raw())
+	require.Equal(t, []float64{2.5, 7}, d.Q75.Raw())
+	require.Equal(t, []float64{3, 9}, d.Max.Raw())
+	require.Equal(t, ""+
+		"      0   1\n"+
+		"count 3   3\n"+
+		"mean  2   6\n"+
+		"std   1   2.65\n"+
+		"min   1   4\n"+
+		"25%   1.5 4.5\n"+
+		"50%   2   5\n"+
+		"75%   2.5 7\n"+
+		"max   3   9", d.String())
+
+	require.InDeltaSlice(t, []float64{1, math.Sqrt(7)}, d.Std.Raw(), 1e-12) // sample std as pandas gives
+
+	d, err = newMatrix(t, matrixInput{in: []float64{1, 4}, rows: 1, cols: 2}).Describe()
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(d.Std.Raw()[0]))
+
+	_, err = (*Matrix)(nil).Describe()
+	require.ErrorIs(t, err, ErrNil)
+}
@@ -0,0 +1,9 @@
+package sparse
+
+import "errors"
+
+var (
+	ErrCreate = errors.New("can not create sparse matrix")
+	ErrExec   = errors.New("can not perform operation to sparse matrix")
+	ErrNil    = errors.New("calling nil sparse matrix")
+)
@@ -0,0 +1,267 @@
+package sparse
+
+import (
+	"fmt"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+// MatMul return dense product of this Matrix and given matrix.Matrix: S*B.
+//
+// Throws ErrExec error.
+func (s *Matrix) MatMul(b *matrix.Matrix) (*matrix.Matrix, error) {
+	return s.MatMulInto(nil, b)
+}
+
+// MatMulInto is MatMul writing result to <dst> if it is sized properly and does not share values with <b>.
+//
+// Throws ErrExec error.
+func (s *Matrix) MatMulInto(dst, b *matrix.Matrix) (res *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if s == nil {
+		return nil, ErrNil
+	} else if b == nil {
+		return nil, fmt.Errorf("no dense matrix provided: %v", b)
+	} else if b.Rows() != s.cols {
+		return nil, fmt.Errorf("sparse matrix cols count mismatches dense matrix rows count: %d != %d", s.cols, b.Rows())
+	}
+
+	k := b.Cols()
+	res = reuse(dst, s.rows, k, b)
+	values, bValues := res.Data(), b.Data()
+	for i := 0; i < s.rows; i++ {
+		row := values[i*k : (i+1)*k]
+		for p := s.rowPtr[i]; p < s.rowPtr[i+1]; p++ {
+			v, j := s.values[p], s.colIdx[p]
+			for c, value := range bValues[j*k : (j+1)*k] {
+				row[c] += v * value
+			}
+		}
+	}
+	return res, nil
+}
+
+// TMatMul return dense product of transposed this Matrix and given matrix.Matrix: S^T*B.
+//
+// Throws ErrExec error.
+func (s *Matrix) TMatMul(b *matrix.Matrix) (*matrix.Matrix, error) {
+	return s.TMatMulInto(nil, b)
+}
+
+// TMatMulInto is TMatMul writing result to <dst> if it is sized properly and does not share values with <b>.
+//
+// Throws ErrExec error.
+func (s *Matrix) TMatMulInto(dst, b *matrix.Matrix) (res *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if s == nil {
+		return nil, ErrNil
+	} else if b == nil {
+		return nil, fmt.Errorf("no dense matrix provided: %v", b)
+	} else if b.Rows() != s.rows {
+		return nil, fmt.Errorf("sparse matrix rows count mismatches dense matrix rows count: %d != %d", s.rows, b.Rows())
+	}
+
+	k := b.Cols()
+	res = reuse(dst, s.cols, k, b)
+	values, bValues := res.Data(), b.Data()
+	for i := 0; i < s.rows; i++ {
+		bRow := bValues[i*k : (i+1)*k]
+		for p := s.rowPtr[i]; p < s.rowPtr[i+1]; p++ {
+			v, j := s.values[p], s.colIdx[p]
+			row := values[j*k : (j+1)*k]
+			for c, value := range bRow {
+				row[c] += v * value
+			}
+		}
+	}
+	return res, nil
+}
+
+// DenseMatMul return dense product of given matrix.Matrix and Matrix: A*S.
+//
+// Throws ErrExec error.
+func DenseMatMul(a *matrix.Matrix, s *Matrix) (*matrix.Matrix, error) {
+	return DenseMatMulInto(nil, a, s)
+}
+
+// DenseMatMulInto is DenseMatMul writing result to <dst> if it is sized properly and does not share values with <a>.
+//
+// Throws ErrExec error.
+func DenseMatMulInto(dst, a *matrix.Matrix, s *Matrix) (res *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if s == nil {
+		return nil, ErrNil
+	} else if a == nil {
+		return nil, fmt.Errorf("no dense matrix provided: %v", a)
+	} else if a.Cols() != s.rows {
+		return nil, fmt.Errorf("dense matrix cols count mismatches sparse matrix rows count: %d != %d", a.Cols(), s.rows)
+	}
+
+	n := a.Rows()
+	res = reuse(dst, n, s.cols, a)
+	values, aValues := res.Data(), a.Data()
+	for t := 0; t < n; t++ {
+		row := values[t*s.cols : (t+1)*s.cols]
+		for i, x := range aValues[t*s.rows : (t+1)*s.rows] {
+			if x == 0 {
+				continue
+			}
+			for p := s.rowPtr[i]; p < s.rowPtr[i+1]; p++ {
+				row[s.colIdx[p]] += x * s.values[p]
+			}
+		}
+	}
+	return res, nil
+}
+
+// DenseMatMulT return dense product of given matrix.Matrix and transposed Matrix: A*S^T.
+//
+// Throws ErrExec error.
+func DenseMatMulT(a *matrix.Matrix, s *Matrix) (*matrix.Matrix, error) {
+	return DenseMatMulTInto(nil, a, s)
+}
+
+// DenseMatMulTInto is DenseMatMulT writing result to <dst> if it is sized properly and does not share values with
+// <a>.
+//
+// Throws ErrExec error.
+func DenseMatMulTInto(dst, a *matrix.Matrix, s *Matrix) (res *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if s == nil {
+		return nil, ErrNil
+	} else if a == nil {
+		return nil, fmt.Errorf("no dense matrix provided: %v", a)
+	} else if a.Cols() != s.cols {
+		return nil, fmt.Errorf("dense matrix cols count mismatches sparse matrix cols count: %d != %d", a.Cols(), s.cols)
+	}
+
+	n := a.Rows()
+	res = reuse(dst, n, s.rows, a)
+	values, aValues := res.Data(), a.Data()
+	for t := 0; t < n; t++ {
+		row, aRow := values[t*s.rows:(t+1)*s.rows], aValues[t*s.cols:(t+1)*s.cols]
+		for i := range row {
+			sum := 0.0
+			for p := s.rowPtr[i]; p < s.rowPtr[i+1]; p++ {
+				sum += aRow[s.colIdx[p]] * s.values[p]
+			}
+			row[i] = sum
+		}
+	}
+	return res, nil
+}
+
+// SampledTMatMul return product of transposed given matrices A^T*B computed at stored positions of this Matrix only,
+// result has the same pattern. It is gradient of sparse parameter S in product A*S by output gradient B.
+//
+// Throws ErrExec error.
+func (s *Matrix) SampledTMatMul(a, b *matrix.Matrix) (*Matrix, error) {
+	return s.SampledTMatMulInto(nil, a, b)
+}
+
+// SampledTMatMulInto is SampledTMatMul writing result to <dst> if it has the same pattern.
+//
+// Throws ErrExec error.
+func (s *Matrix) SampledTMatMulInto(dst *Matrix, a, b *matrix.Matrix) (res *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if s == nil {
+		return nil, ErrNil
+	} else if a == nil || b == nil {
+		return nil, fmt.Errorf("no dense matrices provided: %v, %v", a, b)
+	} else if a.Rows() != b.Rows() || a.Cols() != s.rows || b.Cols() != s.cols {
+		return nil, fmt.Errorf("dense matrices %dx%d and %dx%d mismatch sparse matrix %dx%d",
+			a.Rows(), a.Cols(), b.Rows(), b.Cols(), s.rows, s.cols)
+	}
+
+	if dst == nil || dst == s || s.CheckPattern(dst) != nil {
+		dst = s.withValues(make([]float64, len(s.values)))
+	} else {
+		for p := range dst.values {
+			dst.values[p] = 0
+		}
+	}
+	aValues, bValues := a.Data(), b.Data()
+	for t := 0; t < a.Rows(); t++ {
+		bRow := bValues[t*s.cols : (t+1)*s.cols]
+		for i, x := range aValues[t*s.rows : (t+1)*s.rows] {
+			if x == 0 {
+				continue
+			}
+			for p := s.rowPtr[i]; p < s.rowPtr[i+1]; p++ {
+				dst.values[p] += x * bRow[s.colIdx[p]]
+			}
+		}
+	}
+	return dst, nil
+}
+
+// MulNum return copy of this Matrix with values multiplied by given number.
+//
+// Example:
+//     | 0 2 |.MulNum(3) = | 0 6 |
+//     | 1 0 |             | 3 0 |
+func (s *Matrix) MulNum(number float64) *Matrix {
+	if s == nil {
+		return nil
+	}
+	res := s.withValues(make([]float64, len(s.values)))
+	for p, value := range s.values {
+		res.values[p] = value * number
+	}
+	return res
+}
+
+// Mul return element-wise product of this Matrix and given matrix.Matrix of the same shape, result has the same
+// pattern as this Matrix.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     | 0 2 |.Mul(| 5 6 |) = | 0 12 |
+//     | 1 0 |    | 7 8 |    | 7  0 |
+func (s *Matrix) Mul(m *matrix.Matrix) (res *Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if s == nil {
+		return nil, ErrNil
+	} else if m == nil {
+		return nil, fmt.Errorf("no dense matrix provided: %v", m)
+	} else if m.Rows() != s.rows || m.Cols() != s.cols {
+		return nil, fmt.Errorf("matrix size mismatches: %dx%d != %dx%d", s.rows, s.cols, m.Rows(), m.Cols())
+	}
+
+	res = s.withValues(make([]float64, len(s.values)))
+	values := m.Data()
+	for i := 0; i < s.rows; i++ {
+		for p := s.rowPtr[i]; p < s.rowPtr[i+1]; p++ {
+			res.values[p] = s.values[p] * values[i*s.cols+s.colIdx[p]]
+		}
+	}
+	return res, nil
+}
+
+// withValues return Matrix with the same pattern and given values. Pattern is never modified, so it is shared.
+func (s *Matrix) withValues(values []float64) *Matrix {
+	return &Matrix{rows: s.rows, cols: s.cols, rowPtr: s.rowPtr, colIdx: s.colIdx, values: values}
+}
+
+// reuse return zeroed <dst> if it is sized rows x cols and does not share values with <operand>, otherwise new zero
+// matrix.Matrix.
+func reuse(dst *matrix.Matrix, rows, cols int, operand *matrix.Matrix) *matrix.Matrix {
+	if dst != nil && dst.Rows() == rows && dst.Cols() == cols {
+		values, operandValues := dst.Data(), operand.Data()
+		if len(values) > 0 && len(operandValues) > 0 && &values[0] != &operandValues[0] {
+			for i := range values {
+				values[i] = 0
+			}
+			return dst
+		}
+	}
+	res, _ := matrix.Zeros(rows, cols)
+	return res
+}
@@ -0,0 +1,314 @@
+// Package sparse provides functionality for sparse Matrix stored in compressed sparse row (CSR) format and its
+// multiplication with dense matrix.Matrix.
+package sparse
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+	"sort"
+	"strings"
+)
+
+// Matrix holds non-zero values row by row in compressed sparse row format: values of i'th row are
+// values[rowPtr[i]:rowPtr[i+1]], their cols are colIdx[rowPtr[i]:rowPtr[i+1]] sorted in ascending order. Set of
+// stored positions (pattern) is fixed after creation, operations change stored values only.
+//
+// Example:
+//
+// Matrix 2x3
+//    | 0 2 0 |
+//    | 1 0 3 |
+// is stored as rowPtr = [0 1 3], colIdx = [1 0 2], values = [2 1 3].
+type Matrix struct {
+	rows, cols int
+	rowPtr     []int
+	colIdx     []int
+	values     []float64
+}
+
+// Triplet is single non-zero value of Matrix with its position.
+type Triplet struct {
+	Row, Col int
+	Value    float64
+}
+
+// NewMatrix creates Matrix of given rows and cols count from given triplets in any order. Values of triplets with the
+// same position are summed, zero values are not stored. Rows and cols must be non-zero positive values.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     NewMatrix(2, 3, [{1 2 3} {0 1 2} {1 0 1}]) = | 0 2 0 |
+//                                                  | 1 0 3 |
+func NewMatrix(rows, cols int, triplets []Triplet) (s *Matrix, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if rows < 1 {
+		return nil, fmt.Errorf("negative or zero rows count: %d", rows)
+	} else if cols < 1 {
+		return nil, fmt.Errorf("negative or zero cols count: %d", cols)
+	}
+	for _, t := range triplets {
+		if t.Row < 0 || t.Row >= rows || t.Col < 0 || t.Col >= cols {
+			return nil, fmt.Errorf("wrong row and col for matrix %dx%d: %d, %d", rows, cols, t.Row, t.Col)
+		}
+	}
+
+	sorted := make([]Triplet, len(triplets))
+	copy(sorted, triplets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Row < sorted[j].Row || (sorted[i].Row == sorted[j].Row && sorted[i].Col < sorted[j].Col)
+	})
+
+	s = &Matrix{rows: rows, cols: cols, rowPtr: make([]int, rows+1)}
+	for i := 0; i < len(sorted); {
+		t := sorted[i]
+		for i++; i < len(sorted) && sorted[i].Row == t.Row && sorted[i].Col == t.Col; i++ {
+			t.Value += sorted[i].Value
+		}
+		if t.Value != 0 {
+			s.colIdx = append(s.colIdx, t.Col)
+			s.values = append(s.values, t.Value)
+			s.rowPtr[t.Row+1]++
+		}
+	}
+	for i := 0; i < rows; i++ {
+		s.rowPtr[i+1] += s.rowPtr[i]
+	}
+	return s, nil
+}
+
+// FromDense creates Matrix from values of given matrix.Matrix with absolute value greater than given non-negative
+// threshold, so zero threshold keeps all non-zero values. Greater threshold prunes small values.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     FromDense(| 0.1   2 |, 0.5) = | 0 2 |
+//               |   1 0.3 |         | 1 0 |
+func FromDense(m *matrix.Matrix, threshold float64) (s *Matrix, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if m == nil {
+		return nil, fmt.Errorf("no matrix provided: %v", m)
+	} else if threshold < 0 || math.IsNaN(threshold) {
+		return nil, fmt.Errorf("negative threshold provided: %v", threshold)
+	}
+
+	rows, cols := m.Size()
+	s = &Matrix{rows: rows, cols: cols, rowPtr: make([]int, rows+1)}
+	values := m.Data()
+	for i := 0; i < rows; i++ {
+		for j, value := range values[i*cols : (i+1)*cols] {
+			if value != 0 && math.Abs(value) > threshold {
+				s.colIdx = append(s.colIdx, j)
+				s.values = append(s.values, value)
+			}
+		}
+		s.rowPtr[i+1] = len(s.values)
+	}
+	return s, nil
+}
+
+// Dense return dense matrix.Matrix with the same values.
+func (s *Matrix) Dense() *matrix.Matrix {
+	if s == nil {
+		return nil
+	}
+	m, _ := matrix.Zeros(s.rows, s.cols)
+	values := m.Data()
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowPtr[i]; k < s.rowPtr[i+1]; k++ {
+			values[i*s.cols+s.colIdx[k]] = s.values[k]
+		}
+	}
+	return m
+}
+
+// Triplets return stored values with their positions ordered by row and col.
+func (s *Matrix) Triplets() []Triplet {
+	if s == nil {
+		return nil
+	}
+	triplets := make([]Triplet, 0, len(s.values))
+	for i := 0; i < s.rows; i++ {
+		for k := s.rowPtr[i]; k < s.rowPtr[i+1]; k++ {
+			triplets = append(triplets, Triplet{Row: i, Col: s.colIdx[k], Value: s.values[k]})
+		}
+	}
+	return triplets
+}
+
+// Values return copy of stored values ordered by row and col.
+func (s *Matrix) Values() *vector.Vector {
+	if s == nil || len(s.values) == 0 {
+		return nil
+	}
+	values, _ := vector.NewVector(s.values)
+	return values
+}
+
+// Data return stored values ordered by row and col without copying: changes of returned slice change Matrix.
+func (s *Matrix) Data() []float64 {
+	if s == nil {
+		return nil
+	}
+	return s.values
+}
+
+// SetValues replaces stored values by given ones ordered by row and col, pattern stays the same.
+//
+// Throws ErrExec error.
+func (s *Matrix) SetValues(values []float64) (err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if s == nil {
+		return ErrNil
+	} else if len(values) != len(s.values) {
+		return fmt.Errorf("values count mismatches stored values count: %d != %d", len(values), len(s.values))
+	}
+	copy(s.values, values)
+	return nil
+}
+
+// Size return rows and cols count of Matrix
+func (s *Matrix) Size() (rows int, cols int) {
+	return s.Rows(), s.Cols()
+}
+
+// Rows return rows count of Matrix
+func (s *Matrix) Rows() int {
+	if s == nil {
+		return 0
+	}
+	return s.rows
+}
+
+// Cols return cols count of Matrix
+func (s *Matrix) Cols() int {
+	if s == nil {
+		return 0
+	}
+	return s.cols
+}
+
+// NNZ return count of stored values
+func (s *Matrix) NNZ() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.values)
+}
+
+// Density return ratio of stored values count to rows*cols
+func (s *Matrix) Density() float64 {
+	if s == nil {
+		return 0
+	}
+	return float64(len(s.values)) / float64(s.rows*s.cols)
+}
+
+// Copy return deep copy of Matrix
+func (s *Matrix) Copy() *Matrix {
+	if s == nil {
+		return nil
+	}
+	res := &Matrix{rows: s.rows, cols: s.cols}
+	res.rowPtr = append([]int(nil), s.rowPtr...)
+	res.colIdx = append([]int(nil), s.colIdx...)
+	res.values = append([]float64(nil), s.values...)
+	return res
+}
+
+// Example:
+//     | 0 2 0 |.String() = `[(0, 1): 2 (1, 0): 1 (1, 2): 3]`
+//     | 1 0 3 |
+func (s *Matrix) String() string {
+	if s == nil {
+		return "<nil>"
+	}
+	triplets := s.Triplets()
+	strs := make([]string, len(triplets))
+	for i, t := range triplets {
+		strs[i] = fmt.Sprintf("(%d, %d): %v", t.Row, t.Col, t.Value)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(strs, " "))
+}
+
+// PrettyString return matrix.Matrix.PrettyString() of dense Matrix.
+func (s *Matrix) PrettyString() string {
+	if s == nil {
+		return "<nil>"
+	}
+	return s.Dense().PrettyString()
+}
+
+// Example:
+//     | 0 2 0 |.ShortString() = `sparse matrix 2x3 (3 non-zero)`
+//     | 1 0 3 |
+func (s *Matrix) ShortString() string {
+	if s == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("sparse matrix %dx%d (%d non-zero)", s.rows, s.cols, len(s.values))
+}
+
+// Equal return true if matrices have the same shape, pattern and values.
+func (s *Matrix) Equal(matrix *Matrix) bool {
+	return s.equal(matrix, func(a, b float64) bool {
+		return a == b
+	})
+}
+
+// EqualApprox return true if matrices have the same shape and pattern and values differ no more than vector.Epsilon.
+func (s *Matrix) EqualApprox(matrix *Matrix) bool {
+	return s.equal(matrix, func(a, b float64) bool {
+		return math.Abs(a-b) <= vector.Epsilon
+	})
+}
+
+func (s *Matrix) equal(matrix *Matrix, equal func(a, b float64) bool) bool {
+	if s == nil || matrix == nil {
+		return s == nil && matrix == nil
+	} else if s.rows != matrix.rows || s.cols != matrix.cols || len(s.values) != len(matrix.values) {
+		return false
+	}
+	for i, ptr := range s.rowPtr {
+		if matrix.rowPtr[i] != ptr {
+			return false
+		}
+	}
+	for k, col := range s.colIdx {
+		if matrix.colIdx[k] != col || !equal(s.values[k], matrix.values[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckPattern return error if given Matrix has shape or pattern different from this one.
+func (s *Matrix) CheckPattern(matrix *Matrix) error {
+	if s == nil || matrix == nil {
+		return ErrNil
+	} else if s.rows != matrix.rows || s.cols != matrix.cols {
+		return fmt.Errorf("matrix size mismatches: %dx%d != %dx%d", s.rows, s.cols, matrix.rows, matrix.cols)
+	}
+	if &s.rowPtr[0] == &matrix.rowPtr[0] && len(s.colIdx) == len(matrix.colIdx) &&
+		(len(s.colIdx) == 0 || &s.colIdx[0] == &matrix.colIdx[0]) {
+		return nil // shared pattern
+	}
+	same := len(s.values) == len(matrix.values)
+	for i := 0; same && i < len(s.rowPtr); i++ {
+		same = s.rowPtr[i] == matrix.rowPtr[i]
+	}
+	for k := 0; same && k < len(s.colIdx); k++ {
+		same = s.colIdx[k] == matrix.colIdx[k]
+	}
+	if !same {
+		return fmt.Errorf("matrix patterns mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package sparse
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+func newDense(t testing.TB, rows, cols int, values ...float64) *matrix.Matrix {
+	m, err := matrix.NewMatrixRawFlat(rows, cols, values)
+	require.NoError(t, err)
+	return m
+}
+
+// randomSparse return random dense Matrix with about given density of non-zero values and its sparse copy.
+func randomSparse(t testing.TB, rows, cols int, density float64) (*matrix.Matrix, *Matrix) {
+	values := make([]float64, rows*cols)
+	for i := range values {
+		if rand.Float64() < density {
+			values[i] = rand.NormFloat64()
+		}
+	}
+	m := newDense(t, rows, cols, values...)
+	s, err := FromDense(m, 0)
+	require.NoError(t, err)
+	return m, s
+}
+
+func randomDense(t testing.TB, rows, cols int) *matrix.Matrix {
+	m, _ := randomSparse(t, rows, cols, 1)
+	return m
+}
+
+func TestNewMatrix(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows, cols int
+		triplets   []Triplet
+		expected   []float64
+		nnz        int
+		err        error
+	}{
+		{
+			name: "unordered",
+			rows: 2, cols: 3,
+			triplets: []Triplet{{Row: 1, Col: 2, Value: 3}, {Row: 0, Col: 1, Value: 2}, {Row: 1, Col: 0, Value: 1}},
+			expected: []float64{0, 2, 0, 1, 0, 3},
+			nnz:      3,
+		},
+		{
+			name: "duplicates are summed",
+			rows: 2, cols: 2,
+			triplets: []Triplet{{Row: 0, Col: 0, Value: 1}, {Row: 1, Col: 1, Value: 2}, {Row: 0, Col: 0, Value: 4}},
+			expected: []float64{5, 0, 0, 2},
+			nnz:      2,
+		},
+		{
+			name: "zeros are not stored",
+			rows: 2, cols: 2,
+			triplets: []Triplet{{Row: 0, Col: 1, Value: 0}, {Row: 1, Col: 0, Value: 1}, {Row: 1, Col: 0, Value: -1}},
+			expected: []float64{0, 0, 0, 0},
+		},
+		{name: "empty", rows: 3, cols: 1, expected: []float64{0, 0, 0}},
+		{name: "out of range", rows: 2, cols: 2, triplets: []Triplet{{Row: 2, Col: 0, Value: 1}}, err: ErrCreate},
+		{name: "negative index", rows: 2, cols: 2, triplets: []Triplet{{Row: 0, Col: -1, Value: 1}}, err: ErrCreate},
+		{name: "zero rows", rows: 0, cols: 2, err: ErrCreate},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := NewMatrix(test.rows, test.cols, test.triplets)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.nnz, s.NNZ())
+			require.Equal(t, test.expected, s.Dense().RawFlat())
+
+			fromDense, err := FromDense(s.Dense(), 0)
+			require.NoError(t, err)
+			require.True(t, s.Equal(fromDense))
+		})
+	}
+}
+
+func TestFromDense(t *testing.T) {
+	m := newDense(t, 2, 2, 0.1, 2, 1, -0.3)
+	s, err := FromDense(m, 0.5)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0, 2, 1, 0}, s.Dense().RawFlat())
+	require.Equal(t, []Triplet{{Row: 0, Col: 1, Value: 2}, {Row: 1, Col: 0, Value: 1}}, s.Triplets())
+	require.Equal(t, 0.5, s.Density())
+	require.Equal(t, "[(0, 1): 2 (1, 0): 1]", s.String())
+	require.Equal(t, "sparse matrix 2x2 (2 non-zero)", s.ShortString())
+
+	_, err = FromDense(m, -1)
+	require.ErrorIs(t, err, ErrCreate)
+	_, err = FromDense(nil, 0)
+	require.ErrorIs(t, err, ErrCreate)
+}
+
+func TestMatrix_SetValues(t *testing.T) {
+	s, err := NewMatrix(2, 2, []Triplet{{Row: 0, Col: 1, Value: 2}, {Row: 1, Col: 0, Value: 1}})
+	require.NoError(t, err)
+	copied := s.Copy()
+
+	require.NoError(t, s.SetValues([]float64{5, 6}))
+	require.Equal(t, []float64{0, 5, 6, 0}, s.Dense().RawFlat())
+	require.Equal(t, []float64{5, 6}, s.Values().Raw())
+	require.False(t, s.Equal(copied))
+	require.NoError(t, s.CheckPattern(copied))
+
+	require.ErrorIs(t, s.SetValues([]float64{1}), ErrExec)
+	other, err := NewMatrix(2, 2, []Triplet{{Row: 0, Col: 0, Value: 2}, {Row: 1, Col: 0, Value: 1}})
+	require.NoError(t, err)
+	require.Error(t, s.CheckPattern(other))
+}
+
+func TestMatrix_MatMul(t *testing.T) {
+	for _, density := range []float64{0, 0.1, 0.5, 1} {
+		dense, s := randomSparse(t, 7, 5, density)
+		b, a := randomDense(t, 5, 4), randomDense(t, 3, 7)
+
+		expected, err := dense.MatMul(b)
+		require.NoError(t, err)
+		actual, err := s.MatMul(b)
+		require.NoError(t, err)
+		require.True(t, expected.EqualApprox(actual), "%s != %s", expected, actual)
+
+		bt := randomDense(t, 7, 4)
+		expected, err = dense.TMatMul(bt)
+		require.NoError(t, err)
+		actual, err = s.TMatMul(bt)
+		require.NoError(t, err)
+		require.True(t, expected.EqualApprox(actual), "%s != %s", expected, actual)
+
+		expected, err = a.MatMul(dense)
+		require.NoError(t, err)
+		actual, err = DenseMatMul(a, s)
+		require.NoError(t, err)
+		require.True(t, expected.EqualApprox(actual), "%s != %s", expected, actual)
+
+		at := randomDense(t, 3, 5)
+		expected, err = at.MatMulT(dense)
+		require.NoError(t, err)
+		actual, err = DenseMatMulT(at, s)
+		require.NoError(t, err)
+		require.True(t, expected.EqualApprox(actual), "%s != %s", expected, actual)
+
+		// gradient of A*S by S sampled at pattern of S
+		dy := randomDense(t, 3, 5)
+		full, err := a.TMatMul(dy)
+		require.NoError(t, err)
+		sampled, err := s.SampledTMatMul(a, dy)
+		require.NoError(t, err)
+		require.NoError(t, s.CheckPattern(sampled))
+		for _, triplet := range sampled.Triplets() {
+			value, err := full.Get(triplet.Row, triplet.Col)
+			require.NoError(t, err)
+			require.InDelta(t, value, triplet.Value, 1e-12)
+		}
+	}
+
+	s, err := NewMatrix(2, 3, nil)
+	require.NoError(t, err)
+	_, err = s.MatMul(randomDense(t, 2, 2))
+	require.ErrorIs(t, err, ErrExec)
+	_, err = s.TMatMul(randomDense(t, 3, 2))
+	require.ErrorIs(t, err, ErrExec)
+	_, err = DenseMatMul(randomDense(t, 2, 3), s)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = DenseMatMulT(randomDense(t, 2, 2), s)
+	require.ErrorIs(t, err, ErrExec)
+	_, err = s.SampledTMatMul(randomDense(t, 2, 3), randomDense(t, 2, 3))
+	require.ErrorIs(t, err, ErrExec)
+	var nilMatrix *Matrix
+	_, err = nilMatrix.MatMul(randomDense(t, 2, 2))
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func TestMatrix_Into(t *testing.T) {
+	dense, s := randomSparse(t, 4, 6, 0.3)
+	a := randomDense(t, 5, 4)
+
+	dst, err := DenseMatMul(a, s)
+	require.NoError(t, err)
+	reused, err := DenseMatMulInto(dst, a, s)
+	require.NoError(t, err)
+	require.Same(t, dst, reused)
+	expected, err := a.MatMul(dense)
+	require.NoError(t, err)
+	require.True(t, expected.EqualApprox(reused))
+
+	// destination sharing values with operand is not reused
+	square, sq := randomSparse(t, 4, 4, 0.5)
+	b := randomDense(t, 4, 4)
+	expected, err = b.MatMul(square)
+	require.NoError(t, err)
+	res, err := DenseMatMulInto(b, b, sq)
+	require.NoError(t, err)
+	require.NotSame(t, b, res)
+	require.True(t, expected.EqualApprox(res))
+
+	dy := randomDense(t, 5, 6)
+	grad, err := s.SampledTMatMul(a, dy)
+	require.NoError(t, err)
+	reusedGrad, err := s.SampledTMatMulInto(grad, a, dy)
+	require.NoError(t, err)
+	require.Same(t, grad, reusedGrad)
+	fresh, err := s.SampledTMatMul(a, dy)
+	require.NoError(t, err)
+	require.True(t, fresh.Equal(reusedGrad))
+}
+
+func TestMatrix_Scale(t *testing.T) {
+	s, err := NewMatrix(2, 2, []Triplet{{Row: 0, Col: 1, Value: 2}, {Row: 1, Col: 0, Value: 1}})
+	require.NoError(t, err)
+
+	require.Equal(t, []float64{0, 6, 3, 0}, s.MulNum(3).Dense().RawFlat())
+	require.Equal(t, []float64{0, 2, 1, 0}, s.Dense().RawFlat())
+
+	product, err := s.Mul(newDense(t, 2, 2, 5, 6, 7, 8))
+	require.NoError(t, err)
+	require.Equal(t, []float64{0, 12, 7, 0}, product.Dense().RawFlat())
+	require.NoError(t, s.CheckPattern(product))
+
+	_, err = s.Mul(newDense(t, 1, 2, 5, 6))
+	require.ErrorIs(t, err, ErrExec)
+}
+
+func BenchmarkDenseMatMul(b *testing.B) {
+	a := randomDense(b, 64, 256)
+	for _, density := range []float64{1, 0.3, 0.1} {
+		dense, s := randomSparse(b, 256, 256, density)
+		b.Run(fmt.Sprintf("dense %v", density), func(b *testing.B) {
+			var dst *matrix.Matrix
+			for i := 0; i < b.N; i++ {
+				dst, _ = a.MatMulInto(dst, dense)
+			}
+		})
+		b.Run(fmt.Sprintf("sparse %v", density), func(b *testing.B) {
+			var dst *matrix.Matrix
+			for i := 0; i < b.N; i++ {
+				dst, _ = DenseMatMulInto(dst, a, s)
+			}
+		})
+	}
+}
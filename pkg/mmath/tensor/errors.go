@@ -0,0 +1,10 @@
+package tensor
+
+import "errors"
+
+var (
+	ErrCreate   = errors.New("can not create tensor")
+	ErrNotFound = errors.New("can not find value in tensor")
+	ErrExec     = errors.New("can not perform operation to tensor")
+	ErrNil      = errors.New("calling nil tensor")
+)
@@ -0,0 +1,273 @@
+package tensor
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/wraperr"
+)
+
+// ApplyFunc return contiguous Tensor of given matrix.UnaryOperation applied to values of this Tensor.
+//
+// Example:
+//     [[-1 2] [3 -4]].ApplyFunc(math.Abs) = [[1 2] [3 4]]
+func (t *Tensor) ApplyFunc(operation matrix.UnaryOperation) *Tensor {
+	if t == nil {
+		return nil
+	}
+	res := t.Copy()
+	for i, value := range res.data {
+		res.data[i] = operation(value)
+	}
+	return res
+}
+
+// ApplyFuncTensor applies given matrix.BinaryOperation to this and given Tensor with broadcasting: shapes are aligned
+// by trailing dimensions, each pair of sizes must be equal or one of them must be 1 (or missing), and Tensor with size
+// 1 is repeated along such dimension. Result is contiguous Tensor of broadcast shape.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     [[1 2 3] [4 5 6]].ApplyFuncTensor([10 20 30], Add) = [[11 22 33] [14 25 36]]
+//     [[1 2 3] [4 5 6]].ApplyFuncTensor([[10] [20]], Add) = [[11 12 13] [24 25 26]]
+func (t *Tensor) ApplyFuncTensor(tensor *Tensor, operation matrix.BinaryOperation) (res *Tensor, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if t == nil {
+		return nil, ErrNil
+	} else if tensor == nil {
+		return nil, fmt.Errorf("no second tensor provided: %v", tensor)
+	}
+
+	shape, err := broadcastShapes(t.shape, tensor.shape)
+	if err != nil {
+		return nil, err
+	}
+
+	size, _ := checkShape(shape)
+	values := make([]float64, 0, size)
+	strides := [][]int{t.broadcastStrides(shape), tensor.broadcastStrides(shape)}
+	iterate(shape, strides, []int{t.offset, tensor.offset}, func(offsets []int) {
+		values = append(values, operation(t.data[offsets[0]], tensor.data[offsets[1]]))
+	})
+	return wrap(values, shape), nil
+}
+
+// Add return this Tensor plus given one with broadcasting (see ApplyFuncTensor).
+//
+// Throws ErrExec error.
+func (t *Tensor) Add(tensor *Tensor) (*Tensor, error) {
+	return t.ApplyFuncTensor(tensor, matrix.Add)
+}
+
+// Sub return this Tensor minus given one with broadcasting (see ApplyFuncTensor).
+//
+// Throws ErrExec error.
+func (t *Tensor) Sub(tensor *Tensor) (*Tensor, error) {
+	return t.ApplyFuncTensor(tensor, matrix.Sub)
+}
+
+// Mul return element-wise product of this Tensor and given one with broadcasting (see ApplyFuncTensor).
+//
+// Throws ErrExec error.
+func (t *Tensor) Mul(tensor *Tensor) (*Tensor, error) {
+	return t.ApplyFuncTensor(tensor, matrix.Mul)
+}
+
+// Div return element-wise quotient of this Tensor and given one with broadcasting (see ApplyFuncTensor).
+//
+// Throws ErrExec error.
+func (t *Tensor) Div(tensor *Tensor) (*Tensor, error) {
+	return t.ApplyFuncTensor(tensor, matrix.Div)
+}
+
+// broadcastShapes return shape of broadcasting result of tensors with given shapes.
+func broadcastShapes(a, b []int) ([]int, error) {
+	long, short := a, b
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+	shape := append([]int(nil), long...)
+	for i := 1; i <= len(short); i++ {
+		dimLong, dimShort := long[len(long)-i], short[len(short)-i]
+		switch {
+		case dimLong == dimShort || dimShort == 1:
+		case dimLong == 1:
+			shape[len(long)-i] = dimShort
+		default:
+			return nil, fmt.Errorf("tensor shapes are not broadcastable: %v and %v", a, b)
+		}
+	}
+	return shape, nil
+}
+
+// broadcastStrides return strides of Tensor viewed as Tensor of given broadcast shape: repeated dimensions have zero
+// stride.
+func (t *Tensor) broadcastStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	shift := len(shape) - len(t.shape)
+	for i := range t.shape {
+		if t.shape[i] == shape[i+shift] {
+			strides[i+shift] = t.strides[i]
+		}
+	}
+	return strides
+}
+
+// Reduce folds values of Tensor along given axis with given matrix.BinaryOperation starting from <init>. Negative
+// axis counts from the last one. Reduced axis is kept with size 1 if <keepDims> is true and removed otherwise, reducing
+// of the only axis always keeps it.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     [[1 2 3] [4 5 6]].Reduce(0, false, 0, Add) = [5 7 9]
+//     [[1 2 3] [4 5 6]].Reduce(-1, true, 0, Add) = [[6] [15]]
+func (t *Tensor) Reduce(axis int, keepDims bool, init float64, operation matrix.BinaryOperation) (res *Tensor, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if t == nil {
+		return nil, ErrNil
+	}
+	if axis < 0 {
+		axis += len(t.shape)
+	}
+	if axis < 0 || axis >= len(t.shape) {
+		return nil, fmt.Errorf("wrong axis for tensor of shape %v: %d", t.shape, axis)
+	}
+
+	kept := t.Shape()
+	kept[axis] = 1
+	res, _ = Zeros(kept...)
+	for i := range res.data {
+		res.data[i] = init
+	}
+	strides := [][]int{t.strides, res.broadcastStrides(t.shape)}
+	iterate(t.shape, strides, []int{t.offset, 0}, func(offsets []int) {
+		res.data[offsets[1]] = operation(res.data[offsets[1]], t.data[offsets[0]])
+	})
+
+	if !keepDims && len(kept) > 1 {
+		res.shape = append(kept[:axis:axis], kept[axis+1:]...)
+		res.strides = contiguousStrides(res.shape)
+	}
+	return res, nil
+}
+
+// Sum return sums of values along given axis (see Reduce).
+//
+// Throws ErrExec error.
+func (t *Tensor) Sum(axis int, keepDims bool) (*Tensor, error) {
+	return t.Reduce(axis, keepDims, 0, matrix.Add)
+}
+
+// Mean return means of values along given axis (see Reduce).
+//
+// Throws ErrExec error.
+func (t *Tensor) Mean(axis int, keepDims bool) (*Tensor, error) {
+	res, err := t.Sum(axis, keepDims)
+	if err != nil {
+		return nil, err
+	}
+	count := float64(t.Size() / res.Size())
+	for i := range res.data {
+		res.data[i] /= count
+	}
+	return res, nil
+}
+
+// Max return max values along given axis (see Reduce).
+//
+// Throws ErrExec error.
+func (t *Tensor) Max(axis int, keepDims bool) (*Tensor, error) {
+	return t.Reduce(axis, keepDims, math.Inf(-1), math.Max)
+}
+
+// Min return min values along given axis (see Reduce).
+//
+// Throws ErrExec error.
+func (t *Tensor) Min(axis int, keepDims bool) (*Tensor, error) {
+	return t.Reduce(axis, keepDims, math.Inf(1), math.Min)
+}
+
+// MatMul return batched matrix product of this and given Tensor: tensors of shapes [..., n, k] and [..., k, m] are
+// stacks of matrices multiplied pairwise to result of shape [..., n, m]. Batch dimensions (all but last two) are
+// broadcast (see ApplyFuncTensor), so stack of matrices may be multiplied by single matrix. 2-dimensional tensors are
+// multiplied by matrix.Matrix kernels.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     Tensor 4x2x3.MatMul(Tensor 3x5) = Tensor 4x2x5
+func (t *Tensor) MatMul(tensor *Tensor) (res *Tensor, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if t == nil {
+		return nil, ErrNil
+	} else if tensor == nil {
+		return nil, fmt.Errorf("no second tensor provided: %v", tensor)
+	} else if len(t.shape) < 2 || len(tensor.shape) < 2 {
+		return nil, fmt.Errorf("tensors must have at least 2 dimensions: %v and %v", t.shape, tensor.shape)
+	}
+
+	aDims, bDims := len(t.shape), len(tensor.shape)
+	n, k, m := t.shape[aDims-2], t.shape[aDims-1], tensor.shape[bDims-1]
+	if tensor.shape[bDims-2] != k {
+		return nil, fmt.Errorf("tensor shapes mismatch for matrix product: %v and %v", t.shape, tensor.shape)
+	}
+
+	if aDims == 2 && bDims == 2 {
+		return t.matMul2D(tensor)
+	}
+
+	batch, err := broadcastShapes(t.shape[:aDims-2], tensor.shape[:bDims-2])
+	if err != nil {
+		return nil, err
+	}
+	res, _ = Zeros(append(batch, n, m)...)
+
+	a := &Tensor{shape: t.shape[:aDims-2], strides: t.strides[:aDims-2]}
+	b := &Tensor{shape: tensor.shape[:bDims-2], strides: tensor.strides[:bDims-2]}
+	strides := [][]int{a.broadcastStrides(batch), b.broadcastStrides(batch), contiguousStrides(batch)}
+	for i := range strides[2] {
+		strides[2][i] *= n * m
+	}
+
+	aRow, aCol := t.strides[aDims-2], t.strides[aDims-1]
+	bRow, bCol := tensor.strides[bDims-2], tensor.strides[bDims-1]
+	iterate(batch, strides, []int{t.offset, tensor.offset, 0}, func(offsets []int) {
+		out := res.data[offsets[2] : offsets[2]+n*m]
+		for i := 0; i < n; i++ {
+			row := out[i*m : (i+1)*m]
+			for p := 0; p < k; p++ {
+				value := t.data[offsets[0]+i*aRow+p*aCol]
+				if value == 0 {
+					continue
+				}
+				bOffset := offsets[1] + p*bRow
+				for j := range row {
+					row[j] += value * tensor.data[bOffset+j*bCol]
+				}
+			}
+		}
+	})
+	return res, nil
+}
+
+// matMul2D return matrix product of 2-dimensional tensors computed by matrix.Matrix.
+func (t *Tensor) matMul2D(tensor *Tensor) (*Tensor, error) {
+	a, err := t.ToMatrix()
+	if err != nil {
+		return nil, err
+	}
+	b, err := tensor.ToMatrix()
+	if err != nil {
+		return nil, err
+	}
+	product, err := a.MatMul(b)
+	if err != nil {
+		return nil, err
+	}
+	return FromMatrix(product)
+}
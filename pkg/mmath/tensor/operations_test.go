@@ -0,0 +1,237 @@
+package tensor
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTensor_ApplyFuncTensor(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     *Tensor
+		shape    []int
+		expected []float64
+		err      error
+	}{
+		{
+			name:     "same shape",
+			a:        newTensor(t, 2, 2),
+			b:        newTensor(t, 2, 2),
+			shape:    []int{2, 2},
+			expected: []float64{2, 4, 6, 8},
+		},
+		{
+			name:     "row",
+			a:        newTensor(t, 2, 3),
+			b:        newTensor(t, 3),
+			shape:    []int{2, 3},
+			expected: []float64{2, 4, 6, 5, 7, 9},
+		},
+		{
+			name:     "col",
+			a:        newTensor(t, 2, 3),
+			b:        newTensor(t, 2, 1),
+			shape:    []int{2, 3},
+			expected: []float64{2, 3, 4, 6, 7, 8},
+		},
+		{
+			name:     "outer",
+			a:        newTensor(t, 3, 1),
+			b:        newTensor(t, 1, 2),
+			shape:    []int{3, 2},
+			expected: []float64{2, 3, 3, 4, 4, 5},
+		},
+		{
+			name:     "batch",
+			a:        newTensor(t, 2, 1, 2),
+			b:        newTensor(t, 3, 1),
+			shape:    []int{2, 3, 2},
+			expected: []float64{2, 3, 3, 4, 4, 5, 4, 5, 5, 6, 6, 7},
+		},
+		{name: "mismatch", a: newTensor(t, 2, 3), b: newTensor(t, 2), err: ErrExec},
+		{name: "nil", a: newTensor(t, 2, 3), err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := test.a.Add(test.b)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.shape, res.Shape())
+			require.Equal(t, test.expected, res.Values())
+
+			// broadcasting is symmetric for commutative operation
+			swapped, err := test.b.Add(test.a)
+			require.NoError(t, err)
+			require.True(t, res.Equal(swapped))
+		})
+	}
+
+	// error names both shapes
+	_, err := newTensor(t, 2, 3).Mul(newTensor(t, 4, 1, 2))
+	require.ErrorContains(t, err, "[2 3] and [4 1 2]")
+
+	// views are broadcast by their strides
+	transposed, err := newTensor(t, 2, 3).Transpose()
+	require.NoError(t, err)
+	res, err := transposed.Sub(newTensor(t, 2))
+	require.NoError(t, err)
+	require.Equal(t, []float64{0, 2, 1, 3, 2, 4}, res.Values())
+	res, err = newTensor(t, 2, 2).Div(newTensor(t, 2, 2))
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 1, 1, 1}, res.Values())
+
+	require.Equal(t, []float64{1, 0, 0, 1, 1, 0}, transposed.ApplyFunc(func(a float64) float64 {
+		return math.Mod(a, 2)
+	}).Values())
+}
+
+func TestTensor_Reduce(t *testing.T) {
+	tests := []struct {
+		name     string
+		reduce   func(t *Tensor) (*Tensor, error)
+		shape    []int
+		expected []float64
+		err      error
+	}{
+		{
+			name:     "sum rows",
+			reduce:   func(t *Tensor) (*Tensor, error) { return t.Sum(0, false) },
+			shape:    []int{3, 4},
+			expected: []float64{14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36},
+		},
+		{
+			name:     "sum middle keep dims",
+			reduce:   func(t *Tensor) (*Tensor, error) { return t.Sum(1, true) },
+			shape:    []int{2, 1, 4},
+			expected: []float64{15, 18, 21, 24, 51, 54, 57, 60},
+		},
+		{
+			name:     "mean last",
+			reduce:   func(t *Tensor) (*Tensor, error) { return t.Mean(-1, false) },
+			shape:    []int{2, 3},
+			expected: []float64{2.5, 6.5, 10.5, 14.5, 18.5, 22.5},
+		},
+		{
+			name:     "max",
+			reduce:   func(t *Tensor) (*Tensor, error) { return t.Max(1, false) },
+			shape:    []int{2, 4},
+			expected: []float64{9, 10, 11, 12, 21, 22, 23, 24},
+		},
+		{
+			name:     "min",
+			reduce:   func(t *Tensor) (*Tensor, error) { return t.Min(-3, false) },
+			shape:    []int{3, 4},
+			expected: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		},
+		{name: "wrong axis", reduce: func(t *Tensor) (*Tensor, error) { return t.Sum(3, false) }, err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := test.reduce(newTensor(t, 2, 3, 4))
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.shape, res.Shape())
+			require.Equal(t, test.expected, res.Values())
+		})
+	}
+
+	// reducing of the only axis keeps it
+	sum, err := newTensor(t, 4).Sum(0, false)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, sum.Shape())
+	require.Equal(t, []float64{10}, sum.Values())
+
+	// reduction of transposed view
+	transposed, err := newTensor(t, 2, 3).Transpose()
+	require.NoError(t, err)
+	sum, err = transposed.Sum(1, false)
+	require.NoError(t, err)
+	require.Equal(t, []float64{5, 7, 9}, sum.Values())
+}
+
+func TestTensor_MatMul(t *testing.T) {
+	random := func(shape ...int) *Tensor {
+		res := newTensor(t, shape...)
+		for i := range res.data {
+			res.data[i] = rand.NormFloat64()
+		}
+		return res
+	}
+	// matMulEach return stack of products of matrices of given stacks computed by matrix.Matrix.
+	matMulEach := func(a, b *Tensor) []float64 {
+		var values []float64
+		for i := 0; i < a.shape[0]; i++ {
+			aI, bI := a, b
+			if a.Dims() == 3 {
+				aI, _ = a.Slice(0, i, i+1)
+				aI, _ = aI.Reshape(a.shape[1:]...)
+			}
+			if b.Dims() == 3 {
+				bI, _ = b.Slice(0, i, i+1)
+				bI, _ = bI.Reshape(b.shape[1:]...)
+			}
+			mA, err := aI.ToMatrix()
+			require.NoError(t, err)
+			mB, err := bI.ToMatrix()
+			require.NoError(t, err)
+			product, err := mA.MatMul(mB)
+			require.NoError(t, err)
+			values = append(values, product.RawFlat()...)
+		}
+		return values
+	}
+
+	a, b := random(4, 2, 3), random(4, 3, 5)
+	w := random(3, 5)
+	bT, err := random(4, 5, 3).Transpose(0, 2, 1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		a, b  *Tensor
+		shape []int
+	}{
+		{name: "batch", a: a, b: b, shape: []int{4, 2, 5}},
+		{name: "broadcast weight", a: a, b: w, shape: []int{4, 2, 5}},
+		{name: "transposed view", a: a, b: bT, shape: []int{4, 2, 5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := test.a.MatMul(test.b)
+			require.NoError(t, err)
+			require.Equal(t, test.shape, res.Shape())
+			expected, err := New(matMulEach(test.a, test.b), test.shape...)
+			require.NoError(t, err)
+			require.True(t, expected.EqualApprox(res), "%s != %s", expected, res)
+		})
+	}
+
+	// 2-dimensional tensors are multiplied as matrices
+	m, err := newTensor(t, 2, 3).MatMul(newTensor(t, 3, 2))
+	require.NoError(t, err)
+	require.Equal(t, []float64{22, 28, 49, 64}, m.Values())
+
+	// batch dimensions are broadcast
+	res, err := random(2, 1, 2, 3).MatMul(random(3, 3, 4))
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3, 2, 4}, res.Shape())
+
+	for _, shapes := range [][2][]int{{{2, 3}, {2, 3}}, {{3}, {3, 2}}, {{2, 2, 3}, {3, 3, 2}}} {
+		_, err = newTensor(t, shapes[0]...).MatMul(newTensor(t, shapes[1]...))
+		require.ErrorIs(t, err, ErrExec, fmt.Sprintf("%v x %v", shapes[0], shapes[1]))
+	}
+	_, err = a.MatMul(nil)
+	require.ErrorIs(t, err, ErrExec)
+}
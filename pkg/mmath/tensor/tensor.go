@@ -0,0 +1,476 @@
+// Package tensor provides functionality for N-dimensional Tensor (strided view on flat slice of floats).
+package tensor
+
+import (
+	"fmt"
+	"math"
+	"nn/pkg/mmath/matrix"
+	"nn/pkg/mmath/vector"
+	"nn/pkg/wraperr"
+	"strings"
+)
+
+// Tensor is N-dimensional array viewing flat slice of floats: value with index [i0, i1, ...] is stored at
+// offset + i0*strides[0] + i1*strides[1] + ... . Tensors created by constructors are contiguous (row-major), views
+// (Reshape, Transpose, Slice) share data with original Tensor, so changes of one are visible in another.
+//
+// Example:
+//
+// Tensor 2x3 of 6 floats [1, 2, 3, 4, 5, 6] has strides [3, 1], its transposition 3x2 views the same data with
+// strides [1, 3]:
+//    | 1 2 3 |   | 1 4 |
+//    | 4 5 6 |   | 2 5 |
+//                | 3 6 |
+type Tensor struct {
+	data    []float64
+	shape   []int
+	strides []int
+	offset  int
+}
+
+// checkShape return count of values of Tensor with given shape, it fails on empty shape or non-positive sizes.
+func checkShape(shape []int) (size int, err error) {
+	if len(shape) == 0 {
+		return 0, fmt.Errorf("no shape provided: %v", shape)
+	}
+	size = 1
+	for _, dim := range shape {
+		if dim < 1 {
+			return 0, fmt.Errorf("negative or zero size in shape: %v", shape)
+		}
+		size *= dim
+	}
+	return size, nil
+}
+
+// contiguousStrides return strides of row-major Tensor of given shape.
+func contiguousStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= shape[i]
+	}
+	return strides
+}
+
+// wrap wraps given values as contiguous Tensor with no checks and no copying.
+func wrap(values []float64, shape []int) *Tensor {
+	return &Tensor{data: values, shape: shape, strides: contiguousStrides(shape)}
+}
+
+// New creates contiguous Tensor of given shape from copy of given values in row-major order. Shape must have at least
+// one dimension, all sizes must be non-zero positive values. Count of values must be product of sizes.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     New([1 2 3 4 5 6], 2, 3) = [[1 2 3] [4 5 6]]
+func New(values []float64, shape ...int) (t *Tensor, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	size, err := checkShape(shape)
+	if err != nil {
+		return nil, err
+	} else if len(values) != size {
+		return nil, fmt.Errorf("wrong values count provided for shape %v: %d != %d", shape, len(values), size)
+	}
+
+	data := make([]float64, size)
+	copy(data, values)
+	return wrap(data, append([]int(nil), shape...)), nil
+}
+
+// Zeros creates contiguous Tensor of given shape filled with zeros.
+//
+// Throws ErrCreate error.
+func Zeros(shape ...int) (t *Tensor, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	size, err := checkShape(shape)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(make([]float64, size), append([]int(nil), shape...)), nil
+}
+
+// FromMatrix creates 2-dimensional Tensor sharing values with given matrix.Matrix (no copying).
+//
+// Throws ErrCreate error.
+func FromMatrix(m *matrix.Matrix) (t *Tensor, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if m == nil {
+		return nil, fmt.Errorf("no matrix provided: %v", m)
+	}
+	return wrap(m.Data(), []int{m.Rows(), m.Cols()}), nil
+}
+
+// ToMatrix return matrix.Matrix of 2-dimensional Tensor. Matrix shares values with contiguous Tensor (no copying),
+// non-contiguous Tensor (e.g. transposed view) is copied.
+//
+// Throws ErrExec error.
+func (t *Tensor) ToMatrix() (m *matrix.Matrix, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if t == nil {
+		return nil, ErrNil
+	} else if len(t.shape) != 2 {
+		return nil, fmt.Errorf("can not convert tensor of shape %v to matrix", t.shape)
+	}
+	c := t.Contiguous()
+	return matrix.NewMatrixShared(c.shape[0], c.shape[1], c.data[c.offset:c.offset+c.Size()])
+}
+
+// Shape return copy of Tensor's shape
+func (t *Tensor) Shape() []int {
+	if t == nil {
+		return nil
+	}
+	return append([]int(nil), t.shape...)
+}
+
+// Strides return copy of Tensor's strides
+func (t *Tensor) Strides() []int {
+	if t == nil {
+		return nil
+	}
+	return append([]int(nil), t.strides...)
+}
+
+// Dims return count of Tensor's dimensions
+func (t *Tensor) Dims() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.shape)
+}
+
+// Size return count of Tensor's values
+func (t *Tensor) Size() int {
+	if t == nil {
+		return 0
+	}
+	size := 1
+	for _, dim := range t.shape {
+		size *= dim
+	}
+	return size
+}
+
+// IsContiguous return true if Tensor's values are stored in row-major order with no gaps.
+func (t *Tensor) IsContiguous() bool {
+	if t == nil {
+		return false
+	}
+	stride := 1
+	for i := len(t.shape) - 1; i >= 0; i-- {
+		if t.shape[i] != 1 && t.strides[i] != stride {
+			return false
+		}
+		stride *= t.shape[i]
+	}
+	return true
+}
+
+// offsetOf return offset of value with given index.
+func (t *Tensor) offsetOf(index []int) (int, error) {
+	if len(index) != len(t.shape) {
+		return 0, fmt.Errorf("wrong index for tensor of shape %v: %v", t.shape, index)
+	}
+	offset := t.offset
+	for i, value := range index {
+		if value < 0 || value >= t.shape[i] {
+			return 0, fmt.Errorf("wrong index for tensor of shape %v: %v", t.shape, index)
+		}
+		offset += value * t.strides[i]
+	}
+	return offset, nil
+}
+
+// Get return value with given index.
+//
+// Throws ErrNotFound error.
+func (t *Tensor) Get(index ...int) (value float64, err error) {
+	defer wraperr.WrapError(ErrNotFound, &err)
+
+	if t == nil {
+		return 0, ErrNil
+	}
+	offset, err := t.offsetOf(index)
+	if err != nil {
+		return 0, err
+	}
+	return t.data[offset], nil
+}
+
+// Set replaces value with given index, so change is visible in all views of Tensor's data.
+//
+// Throws ErrNotFound error.
+func (t *Tensor) Set(value float64, index ...int) (err error) {
+	defer wraperr.WrapError(ErrNotFound, &err)
+
+	if t == nil {
+		return ErrNil
+	}
+	offset, err := t.offsetOf(index)
+	if err != nil {
+		return err
+	}
+	t.data[offset] = value
+	return nil
+}
+
+// Values return copy of Tensor's values in row-major order.
+func (t *Tensor) Values() []float64 {
+	if t == nil {
+		return nil
+	}
+	return t.Copy().data
+}
+
+// Copy return contiguous deep copy of Tensor
+func (t *Tensor) Copy() *Tensor {
+	if t == nil {
+		return nil
+	}
+	values := make([]float64, 0, t.Size())
+	iterate(t.shape, [][]int{t.strides}, []int{t.offset}, func(offsets []int) {
+		values = append(values, t.data[offsets[0]])
+	})
+	return wrap(values, t.Shape())
+}
+
+// Contiguous return this Tensor if it is contiguous, otherwise its contiguous copy.
+func (t *Tensor) Contiguous() *Tensor {
+	if t == nil || t.IsContiguous() {
+		return t
+	}
+	return t.Copy()
+}
+
+// Reshape return Tensor of given shape with the same values in row-major order. One of sizes may be -1, it is
+// inferred from count of values. Result is view of contiguous Tensor, non-contiguous Tensor is copied.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     [[1 2 3] [4 5 6]].Reshape(3, -1) = [[1 2] [3 4] [5 6]]
+func (t *Tensor) Reshape(shape ...int) (res *Tensor, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if t == nil {
+		return nil, ErrNil
+	}
+
+	shape = append([]int(nil), shape...)
+	inferred, known := -1, 1
+	for i, dim := range shape {
+		if dim == -1 && inferred == -1 {
+			inferred = i
+		} else {
+			known *= dim
+		}
+	}
+	if inferred != -1 && known > 0 && t.Size()%known == 0 {
+		shape[inferred] = t.Size() / known
+	}
+	if size, err := checkShape(shape); err != nil {
+		return nil, err
+	} else if size != t.Size() {
+		return nil, fmt.Errorf("can not reshape tensor of shape %v to %v", t.shape, shape)
+	}
+
+	c := t.Contiguous()
+	return &Tensor{data: c.data, shape: shape, strides: contiguousStrides(shape), offset: c.offset}, nil
+}
+
+// Transpose return view of Tensor with permuted axes: i'th axis of result is axes[i]'th axis of this Tensor. No axes
+// reverse their order, so 2-dimensional Tensor is transposed as matrix.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     [[1 2 3] [4 5 6]].Transpose() = [[1 4] [2 5] [3 6]]
+func (t *Tensor) Transpose(axes ...int) (res *Tensor, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if t == nil {
+		return nil, ErrNil
+	}
+	dims := len(t.shape)
+	if len(axes) == 0 {
+		axes = make([]int, dims)
+		for i := range axes {
+			axes[i] = dims - 1 - i
+		}
+	} else if len(axes) != dims {
+		return nil, fmt.Errorf("wrong axes for tensor of shape %v: %v", t.shape, axes)
+	}
+
+	res = &Tensor{data: t.data, shape: make([]int, dims), strides: make([]int, dims), offset: t.offset}
+	used := make([]bool, dims)
+	for i, axis := range axes {
+		if axis < 0 || axis >= dims || used[axis] {
+			return nil, fmt.Errorf("wrong axes for tensor of shape %v: %v", t.shape, axes)
+		}
+		used[axis] = true
+		res.shape[i], res.strides[i] = t.shape[axis], t.strides[axis]
+	}
+	return res, nil
+}
+
+// Slice return view of Tensor with indices of given axis from start (inclusive) to stop (exclusive).
+//
+// Throws ErrExec error.
+//
+// Example:
+//     [[1 2 3] [4 5 6]].Slice(1, 1, 3) = [[2 3] [5 6]]
+func (t *Tensor) Slice(axis, start, stop int) (res *Tensor, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if t == nil {
+		return nil, ErrNil
+	} else if axis < 0 || axis >= len(t.shape) {
+		return nil, fmt.Errorf("wrong axis for tensor of shape %v: %d", t.shape, axis)
+	} else if start < 0 || stop > t.shape[axis] || start >= stop {
+		return nil, fmt.Errorf("wrong range for axis %d of tensor of shape %v: [%d; %d)", axis, t.shape, start, stop)
+	}
+
+	res = &Tensor{data: t.data, shape: t.Shape(), strides: t.Strides(), offset: t.offset + start*t.strides[axis]}
+	res.shape[axis] = stop - start
+	return res, nil
+}
+
+// Example:
+//     Tensor 2x3.String() = `[[1 2 3] [4 5 6]]`
+func (t *Tensor) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+	return t.format(" ", func(int) string { return "" })
+}
+
+// Example:
+//     Tensor 2x2x2.PrettyString() = `[[[1 2]
+//                                      [3 4]]
+//
+//                                     [[5 6]
+//                                      [7 8]]]`
+func (t *Tensor) PrettyString() string {
+	if t == nil {
+		return "<nil>"
+	}
+	return t.format("\n", func(depth int) string {
+		return strings.Repeat("\n", len(t.shape)-depth-2) + strings.Repeat(" ", depth+1)
+	})
+}
+
+// format return values nested in brackets by dimensions, sub-tensors of depth'th dimension are separated by
+// separator followed by indent(depth).
+func (t *Tensor) format(separator string, indent func(depth int) string) string {
+	values := t.Values()
+	var sb strings.Builder
+	var write func(depth, offset, size int)
+	write = func(depth, offset, size int) {
+		sb.WriteString("[")
+		if depth == len(t.shape)-1 {
+			for i := 0; i < t.shape[depth]; i++ {
+				if i > 0 {
+					sb.WriteString(" ")
+				}
+				sb.WriteString(fmt.Sprintf("%v", values[offset+i]))
+			}
+		} else {
+			size /= t.shape[depth]
+			for i := 0; i < t.shape[depth]; i++ {
+				if i > 0 {
+					sb.WriteString(separator + indent(depth))
+				}
+				write(depth+1, offset+i*size, size)
+			}
+		}
+		sb.WriteString("]")
+	}
+	write(0, 0, len(values))
+	return sb.String()
+}
+
+// Example:
+//     Tensor 2x3x4.ShortString() = `tensor 2x3x4`
+func (t *Tensor) ShortString() string {
+	if t == nil {
+		return "<nil>"
+	}
+	dims := make([]string, len(t.shape))
+	for i, dim := range t.shape {
+		dims[i] = fmt.Sprintf("%d", dim)
+	}
+	return fmt.Sprintf("tensor %s", strings.Join(dims, "x"))
+}
+
+// Equal return true if tensors have the same shape and values, strides may differ.
+func (t *Tensor) Equal(tensor *Tensor) bool {
+	return t.equal(tensor, func(a, b float64) bool {
+		return a == b
+	})
+}
+
+// EqualApprox return true if tensors have the same shape and values differing no more than vector.Epsilon.
+func (t *Tensor) EqualApprox(tensor *Tensor) bool {
+	return t.equal(tensor, func(a, b float64) bool {
+		return math.Abs(a-b) <= vector.Epsilon
+	})
+}
+
+func (t *Tensor) equal(tensor *Tensor, equal func(a, b float64) bool) bool {
+	if t == nil || tensor == nil {
+		return t == nil && tensor == nil
+	} else if !sameShape(t.shape, tensor.shape) {
+		return false
+	}
+	res := true
+	iterate(t.shape, [][]int{t.strides, tensor.strides}, []int{t.offset, tensor.offset}, func(offsets []int) {
+		res = res && equal(t.data[offsets[0]], tensor.data[offsets[1]])
+	})
+	return res
+}
+
+func sameShape(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, dim := range a {
+		if b[i] != dim {
+			return false
+		}
+	}
+	return true
+}
+
+// iterate calls f for each index of given shape in row-major order with offsets of value with this index in operands
+// with given strides and start offsets.
+func iterate(shape []int, strides [][]int, offsets []int, f func(offsets []int)) {
+	size := 1
+	for _, dim := range shape {
+		size *= dim
+	}
+	index := make([]int, len(shape))
+	current := append([]int(nil), offsets...)
+	for n := 0; n < size; n++ {
+		f(current)
+		for d := len(shape) - 1; d >= 0; d-- {
+			index[d]++
+			for k := range current {
+				current[k] += strides[k][d]
+			}
+			if index[d] < shape[d] {
+				break
+			}
+			for k := range current {
+				current[k] -= strides[k][d] * shape[d]
+			}
+			index[d] = 0
+		}
+	}
+}
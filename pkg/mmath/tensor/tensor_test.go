@@ -0,0 +1,236 @@
+package tensor
+
+import (
+	"github.com/stretchr/testify/require"
+	"nn/pkg/mmath/matrix"
+	"testing"
+)
+
+// newTensor return contiguous Tensor of given shape filled with 1, 2, 3, ...
+func newTensor(t testing.TB, shape ...int) *Tensor {
+	res, err := Zeros(shape...)
+	require.NoError(t, err)
+	for i := range res.data {
+		res.data[i] = float64(i + 1)
+	}
+	return res
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		shape   []int
+		strides []int
+		err     error
+	}{
+		{name: "vector", values: []float64{1, 2, 3}, shape: []int{3}, strides: []int{1}},
+		{name: "matrix", values: []float64{1, 2, 3, 4, 5, 6}, shape: []int{2, 3}, strides: []int{3, 1}},
+		{name: "3d", values: make([]float64, 24), shape: []int{2, 3, 4}, strides: []int{12, 4, 1}},
+		{name: "wrong values count", values: []float64{1, 2, 3}, shape: []int{2, 2}, err: ErrCreate},
+		{name: "zero size", values: []float64{}, shape: []int{2, 0}, err: ErrCreate},
+		{name: "no shape", values: []float64{1}, err: ErrCreate},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := New(test.values, test.shape...)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.shape, res.Shape())
+			require.Equal(t, test.strides, res.Strides())
+			require.Equal(t, len(test.shape), res.Dims())
+			require.Equal(t, len(test.values), res.Size())
+			require.Equal(t, test.values, res.Values())
+			require.True(t, res.IsContiguous())
+
+			// values are copied
+			if len(test.values) > 0 {
+				test.values[0] = 100
+				require.NotEqual(t, test.values, res.Values())
+			}
+		})
+	}
+}
+
+func TestTensor_GetSet(t *testing.T) {
+	tensor := newTensor(t, 2, 3, 4)
+	value, err := tensor.Get(1, 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, 24., value)
+	value, err = tensor.Get(1, 0, 2)
+	require.NoError(t, err)
+	require.Equal(t, 15., value)
+
+	require.NoError(t, tensor.Set(-1, 0, 1, 0))
+	value, err = tensor.Get(0, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, -1., value)
+
+	_, err = tensor.Get(2, 0, 0)
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = tensor.Get(0, 0)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, tensor.Set(1, 0, -1, 0), ErrNotFound)
+	_, err = (*Tensor)(nil).Get(0)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestTensor_Views(t *testing.T) {
+	tests := []struct {
+		name       string
+		view       func(t *Tensor) (*Tensor, error)
+		shape      []int
+		expected   []float64
+		contiguous bool
+		err        error
+	}{
+		{
+			name:       "reshape",
+			view:       func(t *Tensor) (*Tensor, error) { return t.Reshape(3, 2) },
+			shape:      []int{3, 2},
+			expected:   []float64{1, 2, 3, 4, 5, 6},
+			contiguous: true,
+		},
+		{
+			name:       "reshape inferred",
+			view:       func(t *Tensor) (*Tensor, error) { return t.Reshape(-1, 1, 2) },
+			shape:      []int{3, 1, 2},
+			expected:   []float64{1, 2, 3, 4, 5, 6},
+			contiguous: true,
+		},
+		{
+			name:     "transpose",
+			view:     func(t *Tensor) (*Tensor, error) { return t.Transpose() },
+			shape:    []int{3, 2},
+			expected: []float64{1, 4, 2, 5, 3, 6},
+		},
+		{
+			name:     "slice cols",
+			view:     func(t *Tensor) (*Tensor, error) { return t.Slice(1, 1, 3) },
+			shape:    []int{2, 2},
+			expected: []float64{2, 3, 5, 6},
+		},
+		{
+			name:       "slice rows",
+			view:       func(t *Tensor) (*Tensor, error) { return t.Slice(0, 1, 2) },
+			shape:      []int{1, 3},
+			expected:   []float64{4, 5, 6},
+			contiguous: true,
+		},
+		{name: "reshape wrong size", view: func(t *Tensor) (*Tensor, error) { return t.Reshape(4, -1) }, err: ErrExec},
+		{name: "reshape two inferred", view: func(t *Tensor) (*Tensor, error) { return t.Reshape(-1, -1) }, err: ErrExec},
+		{name: "transpose wrong axes", view: func(t *Tensor) (*Tensor, error) { return t.Transpose(0, 0) }, err: ErrExec},
+		{name: "slice wrong range", view: func(t *Tensor) (*Tensor, error) { return t.Slice(1, 2, 2) }, err: ErrExec},
+		{name: "slice wrong axis", view: func(t *Tensor) (*Tensor, error) { return t.Slice(2, 0, 1) }, err: ErrExec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tensor := newTensor(t, 2, 3)
+			view, err := test.view(tensor)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.shape, view.Shape())
+			require.Equal(t, test.expected, view.Values())
+			require.Equal(t, test.contiguous, view.IsContiguous())
+
+			// view shares data with original tensor
+			index := make([]int, view.Dims())
+			require.NoError(t, view.Set(100, index...))
+			require.Contains(t, tensor.Values(), 100.)
+		})
+	}
+
+	// reshape of non-contiguous view copies it
+	transposed, err := newTensor(t, 2, 3).Transpose()
+	require.NoError(t, err)
+	flat, err := transposed.Reshape(6)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 4, 2, 5, 3, 6}, flat.Values())
+	require.NoError(t, flat.Set(100, 0))
+	value, err := transposed.Get(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1., value)
+
+	// transposition of 3d tensor by axes
+	permuted, err := newTensor(t, 2, 3, 4).Transpose(2, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 2, 3}, permuted.Shape())
+	require.Equal(t, []int{1, 12, 4}, permuted.Strides())
+	value, err = permuted.Get(3, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, 24., value)
+}
+
+func TestTensor_Matrix(t *testing.T) {
+	m, err := matrix.NewMatrixRawFlat(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+
+	// tensor shares values with matrix
+	tensor, err := FromMatrix(m)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3}, tensor.Shape())
+	require.NoError(t, tensor.Set(10, 1, 2))
+	require.Equal(t, []float64{1, 2, 3, 4, 5, 10}, m.RawFlat())
+
+	// matrix shares values with contiguous tensor
+	back, err := tensor.ToMatrix()
+	require.NoError(t, err)
+	require.True(t, m.Equal(back))
+	back.Data()[0] = -1
+	value, err := tensor.Get(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, -1., value)
+
+	// non-contiguous tensor is copied
+	transposed, err := tensor.Transpose()
+	require.NoError(t, err)
+	mT, err := transposed.ToMatrix()
+	require.NoError(t, err)
+	require.True(t, m.T().Equal(mT))
+	mT.Data()[1] = 100
+	require.Equal(t, []float64{-1, 2, 3, 4, 5, 10}, m.RawFlat())
+
+	_, err = newTensor(t, 2, 3, 4).ToMatrix()
+	require.ErrorIs(t, err, ErrExec)
+	_, err = FromMatrix(nil)
+	require.ErrorIs(t, err, ErrCreate)
+}
+
+func TestTensor_String(t *testing.T) {
+	tensor := newTensor(t, 2, 2, 2)
+	require.Equal(t, "[[[1 2] [3 4]] [[5 6] [7 8]]]", tensor.String())
+	require.Equal(t, "[[[1 2]\n  [3 4]]\n\n [[5 6]\n  [7 8]]]", tensor.PrettyString())
+	require.Equal(t, "tensor 2x2x2", tensor.ShortString())
+
+	matrixLike := newTensor(t, 2, 3)
+	require.Equal(t, "[[1 2 3] [4 5 6]]", matrixLike.String())
+	require.Equal(t, "[[1 2 3]\n [4 5 6]]", matrixLike.PrettyString())
+	require.Equal(t, "[1 2 3]", newTensor(t, 3).PrettyString())
+	require.Equal(t, "<nil>", (*Tensor)(nil).String())
+}
+
+func TestTensor_Equal(t *testing.T) {
+	a := newTensor(t, 3, 2)
+	transposed, err := newTensor(t, 2, 3).Transpose()
+	require.NoError(t, err)
+	contiguous := transposed.Copy()
+
+	require.True(t, transposed.Equal(contiguous))
+	require.False(t, a.Equal(transposed))
+	require.False(t, a.Equal(newTensor(t, 6)))
+
+	b := a.Copy()
+	b.data[0] += 1e-9
+	require.False(t, a.Equal(b))
+	require.True(t, a.EqualApprox(b))
+	require.False(t, a.Equal(nil))
+	require.True(t, (*Tensor)(nil).Equal(nil))
+}
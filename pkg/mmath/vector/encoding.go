@@ -0,0 +1,115 @@
+package vector
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"nn/pkg/mmath/internal/codec"
+	"nn/pkg/wraperr"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Vector)(nil)
+	_ encoding.BinaryUnmarshaler = (*Vector)(nil)
+	_ json.Marshaler             = (*Vector)(nil)
+	_ json.Unmarshaler           = (*Vector)(nil)
+)
+
+// MarshalBinary return Vector encoded as little-endian header with size and dtype followed by float64 values.
+//
+// Throws ErrExec error.
+func (v *Vector) MarshalBinary() (data []byte, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if v == nil {
+		return nil, ErrNil
+	}
+	return codec.EncodeBinary([]int{v.size}, v.values, codec.Float64)
+}
+
+// UnmarshalBinary replaces Vector with one decoded from MarshalBinary result, float32 values are accepted too.
+//
+// Throws ErrCreate error.
+func (v *Vector) UnmarshalBinary(data []byte) (err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if v == nil {
+		return ErrNil
+	}
+	shape, values, err := codec.DecodeBinary(data)
+	if err != nil {
+		return err
+	} else if len(shape) != 1 {
+		return fmt.Errorf("encoded array is not vector: shape %v", shape)
+	}
+	v.values, v.size = values, len(values)
+	return nil
+}
+
+// MarshalJSON return Vector encoded as JSON array.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     [1 2 3].MarshalJSON() = `[1,2,3]`
+func (v *Vector) MarshalJSON() (data []byte, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if v == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v.values)
+}
+
+// UnmarshalJSON replaces Vector with one decoded from JSON array.
+//
+// Throws ErrCreate error.
+func (v *Vector) UnmarshalJSON(data []byte) (err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if v == nil {
+		return ErrNil
+	}
+	var values []float64
+	if err = json.Unmarshal(data, &values); err != nil {
+		return err
+	} else if len(values) < 1 {
+		return fmt.Errorf("no values provided: %v", values)
+	}
+	v.values, v.size = values, len(values)
+	return nil
+}
+
+// WriteNpy writes Vector to w in NumPy .npy format as 1-dimensional float64 array.
+//
+// Throws ErrExec error.
+func (v *Vector) WriteNpy(w io.Writer) (err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if v == nil {
+		return ErrNil
+	} else if w == nil {
+		return fmt.Errorf("no writer provided: %v", w)
+	}
+	return codec.WriteNpy(w, []int{v.size}, v.values)
+}
+
+// ReadNpy creates Vector from 1-dimensional array read from r in NumPy .npy format. Float, integer and bool arrays
+// in any byte order are supported.
+//
+// Throws ErrCreate error.
+func ReadNpy(r io.Reader) (v *Vector, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	if r == nil {
+		return nil, fmt.Errorf("no reader provided: %v", r)
+	}
+	shape, values, err := codec.ReadNpy(r)
+	if err != nil {
+		return nil, err
+	} else if len(shape) != 1 {
+		return nil, fmt.Errorf("npy array is not vector: shape %v", shape)
+	}
+	return &Vector{values: values, size: len(values)}, nil
+}
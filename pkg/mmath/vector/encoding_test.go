@@ -0,0 +1,69 @@
+package vector
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestVector_Encoding(t *testing.T) {
+	v, err := NewVector([]float64{1, -2.5, 3})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		encode func(v *Vector) ([]byte, error)
+		decode func(data []byte) (*Vector, error)
+	}{
+		{
+			name:   "binary",
+			encode: (*Vector).MarshalBinary,
+			decode: func(data []byte) (*Vector, error) {
+				res := &Vector{}
+				return res, res.UnmarshalBinary(data)
+			},
+		},
+		{
+			name:   "json",
+			encode: func(v *Vector) ([]byte, error) { return json.Marshal(v) },
+			decode: func(data []byte) (*Vector, error) {
+				res := &Vector{}
+				return res, res.UnmarshalJSON(data)
+			},
+		},
+		{
+			name: "npy",
+			encode: func(v *Vector) ([]byte, error) {
+				buf := new(bytes.Buffer)
+				err := v.WriteNpy(buf)
+				return buf.Bytes(), err
+			},
+			decode: func(data []byte) (*Vector, error) { return ReadNpy(bytes.NewReader(data)) },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.encode(v)
+			require.NoError(t, err)
+			decoded, err := test.decode(data)
+			require.NoError(t, err)
+			require.Equal(t, v.Raw(), decoded.Raw())
+			require.Equal(t, v.Size(), decoded.Size())
+
+			_, err = test.decode(data[:len(data)-2])
+			require.ErrorIs(t, err, ErrCreate)
+		})
+	}
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `[1,-2.5,3]`, string(data))
+	decoded := &Vector{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Equal(t, v.Raw(), decoded.Raw())
+	require.ErrorIs(t, (&Vector{}).UnmarshalJSON([]byte(`[]`)), ErrCreate)
+	_, err = (*Vector)(nil).MarshalBinary()
+	require.ErrorIs(t, err, ErrExec)
+}
@@ -0,0 +1,81 @@
+package vector
+
+import (
+	"math/rand"
+	"nn/pkg/mmath/internal/random"
+	"nn/pkg/wraperr"
+)
+
+// randVector creates Vector of given size from values produced by given sampler.
+func randVector(size int, sample func(size int) ([]float64, error)) (v *Vector, err error) {
+	defer wraperr.WrapError(ErrCreate, &err)
+
+	values, err := sample(size)
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{values: values, size: size}, nil
+}
+
+// RandNormal creates Vector of values drawn from normal distribution with given mean and standard deviation.
+//
+// Throws ErrCreate error.
+func RandNormal(rng *rand.Rand, size int, mean, std float64) (*Vector, error) {
+	return randVector(size, func(size int) ([]float64, error) {
+		return random.Normal(rng, size, mean, std)
+	})
+}
+
+// RandTruncNormal creates Vector of values drawn from normal distribution with given mean and standard deviation,
+// values farther than 2 standard deviations from the mean are redrawn.
+//
+// Throws ErrCreate error.
+func RandTruncNormal(rng *rand.Rand, size int, mean, std float64) (*Vector, error) {
+	return randVector(size, func(size int) ([]float64, error) {
+		return random.TruncNormal(rng, size, mean, std)
+	})
+}
+
+// RandUniform creates Vector of values drawn from uniform distribution on [low; high).
+//
+// Throws ErrCreate error.
+func RandUniform(rng *rand.Rand, size int, low, high float64) (*Vector, error) {
+	return randVector(size, func(size int) ([]float64, error) {
+		return random.Uniform(rng, size, low, high)
+	})
+}
+
+// RandBernoulli creates Vector of values equal to 1 with probability <p> and to 0 otherwise.
+//
+// Throws ErrCreate error.
+//
+// Example:
+//     RandBernoulli(rng, 4, 0.5) = [1 0 0 1]
+func RandBernoulli(rng *rand.Rand, size int, p float64) (*Vector, error) {
+	return randVector(size, func(size int) ([]float64, error) {
+		return random.Bernoulli(rng, size, p)
+	})
+}
+
+// RandPermutation return copy of given Vector with randomly reordered values and used permutation: i'th value of
+// result is perm[i]'th value of given Vector.
+//
+// Throws ErrExec error.
+//
+// Example:
+//     RandPermutation(rng, [1 2 3]) = [3 1 2], [2 0 1]
+func RandPermutation(rng *rand.Rand, v *Vector) (vec *Vector, perm []int, err error) {
+	defer wraperr.WrapError(ErrExec, &err)
+
+	if v == nil {
+		return nil, nil, ErrNil
+	}
+	if perm, err = random.Permutation(rng, v.size); err != nil {
+		return nil, nil, err
+	}
+	values := make([]float64, v.size)
+	for i, index := range perm {
+		values[i] = v.values[index]
+	}
+	return &Vector{values: values, size: v.size}, perm, nil
+}
@@ -0,0 +1,82 @@
+package vector
+
+import (
+	"github.com/stretchr/testify/require"
+	"math/rand"
+	"testing"
+)
+
+func TestRand(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func(rng *rand.Rand) (*Vector, error)
+		check  func(value float64) bool
+		err    error
+	}{
+		{
+			name:   "normal",
+			create: func(rng *rand.Rand) (*Vector, error) { return RandNormal(rng, 5, 0, 1) },
+			check:  func(value float64) bool { return true },
+		},
+		{
+			name:   "truncated normal",
+			create: func(rng *rand.Rand) (*Vector, error) { return RandTruncNormal(rng, 5, 0, 0.1) },
+			check:  func(value float64) bool { return value >= -0.2 && value <= 0.2 },
+		},
+		{
+			name:   "uniform",
+			create: func(rng *rand.Rand) (*Vector, error) { return RandUniform(rng, 5, -1, 0) },
+			check:  func(value float64) bool { return value >= -1 && value < 0 },
+		},
+		{
+			name:   "bernoulli",
+			create: func(rng *rand.Rand) (*Vector, error) { return RandBernoulli(rng, 5, 1) },
+			check:  func(value float64) bool { return value == 1 },
+		},
+		{
+			name:   "zero size",
+			create: func(rng *rand.Rand) (*Vector, error) { return RandNormal(rng, 0, 0, 1) },
+			err:    ErrCreate,
+		},
+		{
+			name:   "wrong probability",
+			create: func(rng *rand.Rand) (*Vector, error) { return RandBernoulli(rng, 5, -0.5) },
+			err:    ErrCreate,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := test.create(rand.New(rand.NewSource(1)))
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 5, v.Size())
+			for _, value := range v.Raw() {
+				require.True(t, test.check(value), value)
+			}
+
+			again, err := test.create(rand.New(rand.NewSource(1)))
+			require.NoError(t, err)
+			require.Equal(t, v.Raw(), again.Raw())
+		})
+	}
+}
+
+func TestRandPermutation(t *testing.T) {
+	v, err := NewVector([]float64{10, 20, 30, 40})
+	require.NoError(t, err)
+	shuffled, perm, err := RandPermutation(rand.New(rand.NewSource(1)), v)
+	require.NoError(t, err)
+	for i, index := range perm {
+		require.Equal(t, v.Raw()[index], shuffled.Raw()[i])
+	}
+	require.ElementsMatch(t, v.Raw(), shuffled.Raw())
+
+	_, _, err = RandPermutation(nil, v)
+	require.ErrorIs(t, err, ErrExec)
+	_, _, err = RandPermutation(rand.New(rand.NewSource(1)), nil)
+	require.ErrorIs(t, err, ErrNil)
+}